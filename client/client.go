@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/base64"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httputil"
+	"os"
 	"time"
 
 	"golang.org/x/net/context"
@@ -28,6 +30,12 @@ type (
 		UserAgent string
 		// Dump indicates whether to dump request response.
 		Dump bool
+		// Warnings receives the warnings of the client, e.g. the requests made to deprecated
+		// actions, when the request context has no logger. Defaults to os.Stderr.
+		Warnings io.Writer
+		// Header lists headers set in the requests made by the client that do not already
+		// define them, for example the header that selects the API version.
+		Header http.Header
 	}
 )
 
@@ -44,6 +52,14 @@ func New(c *http.Client) *Client {
 // The logger should be in the context.
 func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
 	req.Header.Set("User-Agent", c.UserAgent)
+	for k, vals := range c.Header {
+		if req.Header.Get(k) != "" {
+			continue
+		}
+		for _, v := range vals {
+			req.Header.Add(k, v)
+		}
+	}
 	startedAt := time.Now()
 	id := shortID()
 	goa.LogInfo(ctx, "started", "id", id, req.Method, req.URL.String())
@@ -62,6 +78,29 @@ func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, err
 	return resp, err
 }
 
+// WarnDeprecated reports a request made to a deprecated action. It logs the warning with the
+// logger of the context if there is one and writes it to the Warnings writer otherwise so that it
+// does not go unnoticed. sunset is the value of the Sunset header set by the action if any.
+func (c *Client) WarnDeprecated(ctx context.Context, resource, action, sunset string) {
+	if goa.ContextLogger(ctx) != nil {
+		keyvals := []interface{}{"resource", resource, "action", action}
+		if sunset != "" {
+			keyvals = append(keyvals, "sunset", sunset)
+		}
+		goa.LogInfo(ctx, "deprecated action", keyvals...)
+		return
+	}
+	w := c.Warnings
+	if w == nil {
+		w = os.Stderr
+	}
+	msg := fmt.Sprintf("warning: the %s action of the %s resource is deprecated", action, resource)
+	if sunset != "" {
+		msg += fmt.Sprintf(" and will be removed on %s", sunset)
+	}
+	fmt.Fprintln(w, msg)
+}
+
 // Dump request if needed.
 func (c *Client) dumpRequest(ctx context.Context, req *http.Request) {
 	reqBody, err := dumpReqBody(req)
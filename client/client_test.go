@@ -0,0 +1,50 @@
+package client_test
+
+import (
+	"bytes"
+	"log"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+)
+
+var _ = Describe("Client", func() {
+	Describe("WarnDeprecated", func() {
+		var ctx context.Context
+		var warnings bytes.Buffer
+		var c *client.Client
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			warnings.Reset()
+			c = client.New(nil)
+			c.Warnings = &warnings
+		})
+
+		JustBeforeEach(func() {
+			c.WarnDeprecated(ctx, "bottle", "show", "Sun, 01 Nov 2026 00:00:00 GMT")
+		})
+
+		It("writes the warning when the context has no logger", func() {
+			Ω(warnings.String()).Should(Equal("warning: the show action of the bottle resource is deprecated and will be removed on Sun, 01 Nov 2026 00:00:00 GMT\n"))
+		})
+
+		Context("with a logger in the context", func() {
+			var logs bytes.Buffer
+
+			BeforeEach(func() {
+				logs.Reset()
+				ctx = goa.WithLogger(ctx, goa.NewLogger(log.New(&logs, "", 0)))
+			})
+
+			It("logs the warning", func() {
+				Ω(warnings.String()).Should(BeEmpty())
+				Ω(logs.String()).Should(ContainSubstring("deprecated action"))
+				Ω(logs.String()).Should(ContainSubstring("action=show"))
+			})
+		})
+	})
+})
@@ -0,0 +1,108 @@
+package client
+
+import (
+	"net/http"
+	"regexp"
+
+	"golang.org/x/net/context"
+)
+
+// nextLinkRegex matches the URL of the "next" link in a Link header value.
+var nextLinkRegex = regexp.MustCompile(`<([^>]*)>\s*;\s*rel="?next"?`)
+
+// PageIterator iterates over the pages of the responses of a paginated action. The first page is
+// retrieved by calling the function given to NewPageIterator, the following pages are retrieved by
+// following the "next" link of the Link response header. Typical usage:
+//
+//	it := c.ListBottlePages(ctx, path, 1, 20)
+//	for it.Next() {
+//		bottles, err := c.DecodeBottleCollection(it.Response())
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+type PageIterator struct {
+	client *Client
+	ctx    context.Context
+	first  func() (*http.Response, error)
+	next   *http.Request
+	resp   *http.Response
+	err    error
+	done   bool
+}
+
+// NewPageIterator returns an iterator whose first page is retrieved by calling first.
+func NewPageIterator(ctx context.Context, c *Client, first func() (*http.Response, error)) *PageIterator {
+	return &PageIterator{client: c, ctx: ctx, first: first}
+}
+
+// Next retrieves the next page. It returns false when there is no more page or if the request
+// failed in which case Err returns the error. Next closes the body of the previous response.
+func (it *PageIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.resp != nil {
+		it.resp.Body.Close()
+	}
+	var resp *http.Response
+	var err error
+	if it.first != nil {
+		resp, err = it.first()
+		it.first = nil
+	} else if it.next != nil {
+		resp, err = it.client.Do(it.ctx, it.next)
+	} else {
+		it.done = true
+		return false
+	}
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.resp = resp
+	it.next = nil
+	if resp.StatusCode < 300 && resp.Request != nil {
+		it.next, it.err = nextPageRequest(resp)
+	}
+	return true
+}
+
+// Response returns the response containing the current page.
+func (it *PageIterator) Response() *http.Response {
+	return it.resp
+}
+
+// Err returns the error that caused Next to return false if any.
+func (it *PageIterator) Err() error {
+	return it.err
+}
+
+// nextPageRequest returns the request that retrieves the page linked to by the "next" link of the
+// response, nil if there isn't one. The request uses the same headers as the request that produced
+// the response.
+func nextPageRequest(resp *http.Response) (*http.Request, error) {
+	var link string
+	for _, v := range resp.Header["Link"] {
+		if m := nextLinkRegex.FindStringSubmatch(v); m != nil {
+			link = m[1]
+			break
+		}
+	}
+	if link == "" {
+		return nil, nil
+	}
+	u, err := resp.Request.URL.Parse(link)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range resp.Request.Header {
+		req.Header[k] = v
+	}
+	return req, nil
+}
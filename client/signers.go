@@ -35,6 +35,9 @@ type (
 	APIKeySigner struct {
 		// Header is the name of the HTTP header that contains the API key.
 		Header string
+		// Cookie is the name of the cookie that contains the API key. The key is sent in
+		// the cookie instead of the header if set.
+		Cookie string
 
 		// key stores the actual key.
 		key string
@@ -96,8 +99,12 @@ func (s *BasicSigner) RegisterFlags(app *cobra.Command) {
 	app.Flags().StringVar(&s.Password, "pass", "", "Basic Auth password")
 }
 
-// Sign adds the API key header to the request.
+// Sign adds the API key header or cookie to the request.
 func (s *APIKeySigner) Sign(ctx context.Context, req *http.Request) error {
+	if s.Cookie != "" {
+		req.AddCookie(&http.Cookie{Name: s.Cookie, Value: s.key})
+		return nil
+	}
 	header := s.Header
 	if header == "" {
 		header = "Authorization"
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Event is a server-sent event read from a text/event-stream response body.
+type Event struct {
+	// ID is the value of the event id field, empty if the event has none.
+	ID string
+	// Event is the event type, "message" if the event does not specify one.
+	Event string
+	// Data is the event data.
+	Data []byte
+}
+
+// EventReader reads server-sent events from a text/event-stream response body.
+type EventReader struct {
+	body        io.ReadCloser
+	reader      *bufio.Reader
+	lastEventID string
+}
+
+// NewEventReader returns a reader that reads the events from the given response body.
+func NewEventReader(body io.ReadCloser) *EventReader {
+	return &EventReader{body: body, reader: bufio.NewReader(body)}
+}
+
+// Next returns the next event sent by the server. It returns io.EOF when the stream ends. Comments
+// and fields other than id, event and data are ignored.
+func (r *EventReader) Next() (*Event, error) {
+	var (
+		data  bytes.Buffer
+		event string
+		found bool
+	)
+	for {
+		line, err := r.reader.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if !found {
+				continue
+			}
+			if event == "" {
+				event = "message"
+			}
+			return &Event{ID: r.lastEventID, Event: event, Data: bytes.TrimSuffix(data.Bytes(), []byte("\n"))}, nil
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value := line, ""
+		if i := strings.Index(line, ":"); i >= 0 {
+			field, value = line[:i], strings.TrimPrefix(line[i+1:], " ")
+		}
+		switch field {
+		case "id":
+			r.lastEventID = value
+			found = true
+		case "event":
+			event = value
+			found = true
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			found = true
+		}
+	}
+}
+
+// LastEventID returns the ID of the last event read, it should be sent in the Last-Event-ID header
+// when reconnecting.
+func (r *EventReader) LastEventID() string {
+	return r.lastEventID
+}
+
+// Close closes the underlying response body.
+func (r *EventReader) Close() error {
+	return r.body.Close()
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/goadesign/goa"
+	"golang.org/x/net/websocket"
+)
+
+type (
+	// WebSocketConn is a client WebSocket connection that exchanges encoded messages. The
+	// messages are encoded with the encoder registered for the Content-Type header of the
+	// handshake request and decoded with the decoder registered for its Accept header, JSON by
+	// default.
+	WebSocketConn struct {
+		ws          *websocket.Conn
+		contentType string
+		encoder     goa.EncoderFunc
+		decoder     goa.DecoderFunc
+		closes      *closeRecorder
+		done        chan struct{}
+		once        sync.Once
+	}
+
+	// closeRecorder wraps the network connection of a client WebSocket connection and records
+	// the close frame sent by the server, the WebSocket package does not expose it.
+	closeRecorder struct {
+		net.Conn
+
+		lock sync.Mutex
+		// handshake holds the last bytes of the handshake response read so far, it is nil
+		// once the response has been read.
+		handshake []byte
+		// header holds the bytes of the header of the current frame read so far.
+		header []byte
+		// remaining is the number of payload bytes of the current frame left to read.
+		remaining uint64
+		// opcode is the opcode of the current frame.
+		opcode byte
+		// payload holds the payload of the current close frame.
+		payload []byte
+		// closeErr is the error built from the close frame sent by the server if any.
+		closeErr *goa.WebSocketCloseError
+	}
+)
+
+// pingCodec writes WebSocket ping frames.
+var pingCodec = websocket.Codec{Marshal: func(interface{}) ([]byte, byte, error) {
+	return nil, websocket.PingFrame, nil
+}}
+
+// NewWebSocketConn wraps the given client WebSocket connection. keepAlive is the interval between
+// the ping frames sent to the server, no ping frame is sent if it is zero. The ping frames sent by
+// the server are answered automatically. The connection exchanges JSON messages, use
+// DialWebSocket to use other encodings and to get the close codes sent by the server.
+func NewWebSocketConn(ws *websocket.Conn, keepAlive time.Duration) *WebSocketConn {
+	c := &WebSocketConn{
+		ws:          ws,
+		contentType: "application/json",
+		encoder:     goa.NewJSONEncoder,
+		decoder:     goa.NewJSONDecoder,
+		done:        make(chan struct{}),
+	}
+	if keepAlive > 0 {
+		go c.keepAlive(keepAlive)
+	}
+	return c
+}
+
+// DialWebSocket opens a WebSocket connection using the given configuration. The messages are
+// encoded with encoder and decoded with decoder, the Content-Type and Accept headers of the
+// configuration must be set to the corresponding content types so that the server uses the same
+// encodings. keepAlive is the interval between the ping frames sent to the server, no ping frame is
+// sent if it is zero.
+func DialWebSocket(cfg *websocket.Config, keepAlive time.Duration, encoder goa.EncoderFunc, decoder goa.DecoderFunc) (*WebSocketConn, error) {
+	conn, err := dialWebSocket(cfg)
+	if err != nil {
+		return nil, &websocket.DialError{Config: cfg, Err: err}
+	}
+	closes := &closeRecorder{Conn: conn, handshake: []byte{}}
+	ws, err := websocket.NewClient(cfg, closes)
+	if err != nil {
+		conn.Close()
+		return nil, &websocket.DialError{Config: cfg, Err: err}
+	}
+	c := NewWebSocketConn(ws, keepAlive)
+	c.contentType = cfg.Header.Get("Content-Type")
+	c.encoder, c.decoder, c.closes = encoder, decoder, closes
+	return c, nil
+}
+
+// Conn returns the underlying WebSocket connection.
+func (c *WebSocketConn) Conn() *websocket.Conn {
+	return c.ws
+}
+
+// Recv reads the next message and decodes it into v. It validates the decoded message if v
+// implements Validate. Recv returns io.EOF when the server closes the connection normally and a
+// *goa.WebSocketCloseError holding the close code and reason sent by the server otherwise.
+func (c *WebSocketConn) Recv(v interface{}) error {
+	var data []byte
+	if err := websocket.Message.Receive(c.ws, &data); err != nil {
+		if err == io.EOF && c.closes != nil {
+			if cerr := c.closes.closeError(); cerr != nil && cerr.Code != goa.WSCloseNormal {
+				return cerr
+			}
+		}
+		return err
+	}
+	if err := c.decoder(bytes.NewReader(data)).Decode(v); err != nil {
+		return err
+	}
+	if val, ok := v.(interface {
+		Validate() error
+	}); ok {
+		return val.Validate()
+	}
+	return nil
+}
+
+// Send encodes v and writes it as a single message. Messages encoded using a textual content type
+// such as JSON are written as text frames, other messages as binary frames.
+func (c *WebSocketConn) Send(v interface{}) error {
+	var buf bytes.Buffer
+	if err := c.encoder(&buf).Encode(v); err != nil {
+		return err
+	}
+	if goa.TextContentType(c.contentType) {
+		return websocket.Message.Send(c.ws, buf.String())
+	}
+	return websocket.Message.Send(c.ws, buf.Bytes())
+}
+
+// Close closes the connection using the normal close code.
+func (c *WebSocketConn) Close() error {
+	c.once.Do(func() { close(c.done) })
+	return c.ws.Close()
+}
+
+// keepAlive writes a ping frame at each period until the connection is closed.
+func (c *WebSocketConn) keepAlive(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := pingCodec.Send(c.ws, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// dialWebSocket opens the network connection to the WebSocket server of the given configuration.
+func dialWebSocket(cfg *websocket.Config) (net.Conn, error) {
+	host := cfg.Location.Host
+	switch cfg.Location.Scheme {
+	case "ws":
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "80")
+		}
+		return net.Dial("tcp", host)
+	case "wss":
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, "443")
+		}
+		return tls.Dial("tcp", host, cfg.TlsConfig)
+	}
+	return nil, websocket.ErrBadScheme
+}
+
+// Read reads from the underlying connection and records the close frame sent by the server.
+func (r *closeRecorder) Read(b []byte) (int, error) {
+	n, err := r.Conn.Read(b)
+	if n > 0 {
+		r.lock.Lock()
+		r.scan(b[:n])
+		r.lock.Unlock()
+	}
+	return n, err
+}
+
+// closeError returns the error built from the close frame sent by the server, nil if the server
+// has not sent one.
+func (r *closeRecorder) closeError() *goa.WebSocketCloseError {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.closeErr
+}
+
+// scan skips the handshake response and parses the frame headers of the given data.
+func (r *closeRecorder) scan(data []byte) {
+	for len(data) > 0 {
+		if r.handshake != nil {
+			r.handshake = append(r.handshake, data[0])
+			data = data[1:]
+			if len(r.handshake) > 4 {
+				r.handshake = r.handshake[1:]
+			}
+			if string(r.handshake) == "\r\n\r\n" {
+				r.handshake = nil
+			}
+			continue
+		}
+		if r.remaining > 0 {
+			n := uint64(len(data))
+			if n > r.remaining {
+				n = r.remaining
+			}
+			if r.opcode == websocket.CloseFrame {
+				r.payload = append(r.payload, data[:n]...)
+			}
+			data = data[n:]
+			r.remaining -= n
+			if r.remaining == 0 {
+				r.endFrame()
+			}
+			continue
+		}
+		r.header = append(r.header, data[0])
+		data = data[1:]
+		size, ok := r.headerSize()
+		if !ok || len(r.header) < size {
+			continue
+		}
+		r.opcode = r.header[0] & 0x0f
+		switch l := r.header[1] & 0x7f; l {
+		case 126:
+			r.remaining = uint64(binary.BigEndian.Uint16(r.header[2:4]))
+		case 127:
+			r.remaining = binary.BigEndian.Uint64(r.header[2:10])
+		default:
+			r.remaining = uint64(l)
+		}
+		r.header = r.header[:0]
+		if r.remaining == 0 {
+			r.endFrame()
+		}
+	}
+}
+
+// headerSize returns the size of the header of the current frame once its length byte has been
+// read.
+func (r *closeRecorder) headerSize() (int, bool) {
+	if len(r.header) < 2 {
+		return 0, false
+	}
+	size := 2
+	switch r.header[1] & 0x7f {
+	case 126:
+		size += 2
+	case 127:
+		size += 8
+	}
+	if r.header[1]&0x80 != 0 {
+		// Masking key, servers do not mask their frames but be lenient.
+		size += 4
+	}
+	return size, true
+}
+
+// endFrame records the close error once the payload of a close frame has been read.
+func (r *closeRecorder) endFrame() {
+	if r.opcode != websocket.CloseFrame {
+		return
+	}
+	if len(r.payload) >= 2 {
+		r.closeErr = &goa.WebSocketCloseError{
+			Code:   int(binary.BigEndian.Uint16(r.payload)),
+			Reason: string(r.payload[2:]),
+		}
+	} else if r.closeErr == nil {
+		// A close frame without status code is a normal closure.
+		r.closeErr = &goa.WebSocketCloseError{Code: goa.WSCloseNormal}
+	}
+	r.payload = nil
+}
@@ -0,0 +1,127 @@
+package client_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/client"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+)
+
+// message is the message type used to test WebSocket connections.
+type message struct {
+	Body string `json:"body"`
+}
+
+var _ = Describe("WebSocketConn", func() {
+	var handler func(*goa.WebSocketConn) error
+	var contentType, accept string
+	var encoder goa.EncoderFunc
+	var decoder goa.DecoderFunc
+	var requests chan http.Header
+	var server *httptest.Server
+	var conn *client.WebSocketConn
+
+	BeforeEach(func() {
+		contentType, accept = "application/json", "application/json"
+		encoder, decoder = goa.NewJSONEncoder, goa.NewJSONDecoder
+		requests = make(chan http.Header, 1)
+		handler = func(c *goa.WebSocketConn) error {
+			for {
+				var msg message
+				if err := c.Recv(&msg); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				if msg.Body == "bye" {
+					return &goa.WebSocketCloseError{Code: goa.WSClosePolicyViolation, Reason: "go away"}
+				}
+				if err := c.Send(&msg); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	JustBeforeEach(func() {
+		service := goa.New("test")
+		service.Decoder(goa.NewJSONDecoder, "application/json", "*/*")
+		service.Decoder(goa.NewGobDecoder, "application/gob")
+		service.Encoder(goa.NewJSONEncoder, "application/json", "*/*")
+		service.Encoder(goa.NewGobEncoder, "application/gob")
+		handler, requests := handler, requests
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			requests <- req.Header
+			ctx := goa.NewContext(context.Background(), rw, req, nil)
+			goa.ServeWebSocket(ctx, service, 0, handler)
+		}))
+		url := "ws" + strings.TrimPrefix(server.URL, "http")
+		cfg, err := websocket.NewConfig(url, server.URL)
+		Ω(err).ShouldNot(HaveOccurred())
+		cfg.Header.Set("Content-Type", contentType)
+		cfg.Header.Set("Accept", accept)
+		conn, err = client.DialWebSocket(cfg, 0, encoder, decoder)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		conn.Close()
+		server.Close()
+	})
+
+	It("exchanges JSON messages", func() {
+		Ω(conn.Send(&message{Body: "hello"})).ShouldNot(HaveOccurred())
+		var msg message
+		Ω(conn.Recv(&msg)).ShouldNot(HaveOccurred())
+		Ω(msg.Body).Should(Equal("hello"))
+	})
+
+	It("sends the Content-Type and Accept headers", func() {
+		var header http.Header
+		Eventually(requests).Should(Receive(&header))
+		Ω(header.Get("Content-Type")).Should(Equal("application/json"))
+		Ω(header.Get("Accept")).Should(Equal("application/json"))
+	})
+
+	It("returns the close code sent by the server", func() {
+		Ω(conn.Send(&message{Body: "bye"})).ShouldNot(HaveOccurred())
+		var msg message
+		err := conn.Recv(&msg)
+		Ω(err).Should(Equal(&goa.WebSocketCloseError{Code: goa.WSClosePolicyViolation, Reason: "go away"}))
+	})
+
+	Context("with a server that closes the connection normally", func() {
+		BeforeEach(func() {
+			handler = func(c *goa.WebSocketConn) error {
+				return nil
+			}
+		})
+
+		It("returns io.EOF", func() {
+			var msg message
+			Ω(conn.Recv(&msg)).Should(Equal(io.EOF))
+		})
+	})
+
+	Context("using gob", func() {
+		BeforeEach(func() {
+			contentType, accept = "application/gob", "application/gob"
+			encoder, decoder = goa.NewGobEncoder, goa.NewGobDecoder
+		})
+
+		It("exchanges gob messages", func() {
+			Ω(conn.Send(&message{Body: "hello"})).ShouldNot(HaveOccurred())
+			var msg message
+			Ω(conn.Recv(&msg)).ShouldNot(HaveOccurred())
+			Ω(msg.Body).Should(Equal("hello"))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package goa
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// SetETag sets the ETag response header to the given entity tag, the tag is quoted if needed. For
+// requests made with a method other than GET or HEAD SetETag also evaluates the If-Match request
+// header against the tag and returns ErrPreconditionFailed if it does not match. Actions should
+// call SetETag with the current tag of the resource prior to modifying it.
+func SetETag(ctx context.Context, etag string) error {
+	etag = QuoteETag(etag)
+	ContextResponse(ctx).Header().Set("ETag", etag)
+	req := ContextRequest(ctx)
+	if safeMethod(req.Method) {
+		return nil
+	}
+	if im := req.Header.Get("If-Match"); im != "" && !MatchETag(im, etag, false) {
+		return ErrPreconditionFailed("If-Match precondition failed for entity tag %s", etag)
+	}
+	return nil
+}
+
+// SetLastModified sets the Last-Modified response header to the given time. For requests made with
+// a method other than GET or HEAD SetLastModified also evaluates the If-Unmodified-Since request
+// header and returns ErrPreconditionFailed if the resource was modified since.
+func SetLastModified(ctx context.Context, t time.Time) error {
+	t = t.UTC().Truncate(time.Second)
+	ContextResponse(ctx).Header().Set("Last-Modified", t.Format(http.TimeFormat))
+	req := ContextRequest(ctx)
+	if safeMethod(req.Method) || req.Header.Get("If-Match") != "" {
+		return nil
+	}
+	if ius, err := http.ParseTime(req.Header.Get("If-Unmodified-Since")); err == nil && t.After(ius) {
+		return ErrPreconditionFailed("resource was modified since %s", ius.Format(http.TimeFormat))
+	}
+	return nil
+}
+
+// QuoteETag returns the given entity tag quoted as required by the ETag header. Tags that are
+// already quoted including weak tags are returned unchanged.
+func QuoteETag(etag string) string {
+	if strings.HasPrefix(etag, `"`) || strings.HasPrefix(etag, `W/"`) {
+		return etag
+	}
+	return `"` + etag + `"`
+}
+
+// MatchETag returns true if the given If-Match or If-None-Match header value matches the entity
+// tag. The header value may list multiple tags or consist of "*" which matches any tag. Weak
+// comparison ignores the weak indicator of the tags as required by If-None-Match, strong
+// comparison never matches weak tags as required by If-Match.
+func MatchETag(header, etag string, weak bool) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if weak {
+			t = strings.TrimPrefix(t, "W/")
+		}
+		if t == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// safeMethod returns true if the given HTTP method is GET or HEAD.
+func safeMethod(method string) bool {
+	return method == "GET" || method == "HEAD"
+}
@@ -0,0 +1,125 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SetETag", func() {
+	var method, ifMatch string
+	var rw *httptest.ResponseRecorder
+	var err error
+
+	BeforeEach(func() {
+		method = "GET"
+		ifMatch = ""
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest(method, "/bottles/1", nil)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		rw = httptest.NewRecorder()
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		err = goa.SetETag(ctx, "v1")
+	})
+
+	It("sets the quoted ETag header", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("ETag")).Should(Equal(`"v1"`))
+	})
+
+	Context("with a PUT request that matches", func() {
+		BeforeEach(func() {
+			method = "PUT"
+			ifMatch = `"v0", "v1"`
+		})
+
+		It("does not return an error", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with a PUT request that does not match", func() {
+		BeforeEach(func() {
+			method = "PUT"
+			ifMatch = `"v0"`
+		})
+
+		It("returns a precondition failed error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(*goa.Error).Status).Should(Equal(412))
+		})
+	})
+
+	Context("with a GET request that does not match", func() {
+		BeforeEach(func() {
+			ifMatch = `"v0"`
+		})
+
+		It("ignores the If-Match header", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("SetLastModified", func() {
+	var lastModified = time.Date(2016, time.May, 1, 12, 0, 0, 0, time.UTC)
+	var ifUnmodifiedSince time.Time
+	var rw *httptest.ResponseRecorder
+	var err error
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest("DELETE", "/bottles/1", nil)
+		req.Header.Set("If-Unmodified-Since", ifUnmodifiedSince.Format(http.TimeFormat))
+		rw = httptest.NewRecorder()
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		err = goa.SetLastModified(ctx, lastModified)
+	})
+
+	Context("with a resource that was not modified", func() {
+		BeforeEach(func() {
+			ifUnmodifiedSince = lastModified
+		})
+
+		It("sets the Last-Modified header", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Header().Get("Last-Modified")).Should(Equal("Sun, 01 May 2016 12:00:00 GMT"))
+		})
+	})
+
+	Context("with a resource that was modified", func() {
+		BeforeEach(func() {
+			ifUnmodifiedSince = lastModified.Add(-time.Hour)
+		})
+
+		It("returns a precondition failed error", func() {
+			Ω(err).Should(HaveOccurred())
+			Ω(err.(*goa.Error).Status).Should(Equal(412))
+		})
+	})
+})
+
+var _ = Describe("MatchETag", func() {
+	It("matches any tag with *", func() {
+		Ω(goa.MatchETag("*", `"a"`, false)).Should(BeTrue())
+	})
+
+	It("matches tags in lists", func() {
+		Ω(goa.MatchETag(`"a", "b"`, `"b"`, false)).Should(BeTrue())
+		Ω(goa.MatchETag(`"a", "b"`, `"c"`, false)).Should(BeFalse())
+	})
+
+	It("compares weak tags using the weak comparison only", func() {
+		Ω(goa.MatchETag(`W/"a"`, `"a"`, true)).Should(BeTrue())
+		Ω(goa.MatchETag(`W/"a"`, `W/"a"`, false)).Should(BeFalse())
+	})
+})
@@ -85,31 +85,33 @@ var (
 	// KnownEncoders contains the list of encoding packages and factories known by goa indexed
 	// by MIME type.
 	KnownEncoders = map[string]string{
-		"application/json":      "github.com/goadesign/goa",
-		"application/xml":       "github.com/goadesign/goa",
-		"application/gob":       "github.com/goadesign/goa",
-		"application/x-gob":     "github.com/goadesign/goa",
-		"application/binc":      "github.com/goadesign/goa/encoding/binc",
-		"application/x-binc":    "github.com/goadesign/goa/encoding/binc",
-		"application/cbor":      "github.com/goadesign/goa/encoding/cbor",
-		"application/x-cbor":    "github.com/goadesign/goa/encoding/cbor",
-		"application/msgpack":   "github.com/goadesign/goa/encoding/msgpack",
-		"application/x-msgpack": "github.com/goadesign/goa/encoding/msgpack",
+		"application/json":                  "github.com/goadesign/goa",
+		"application/xml":                   "github.com/goadesign/goa",
+		"application/gob":                   "github.com/goadesign/goa",
+		"application/x-gob":                 "github.com/goadesign/goa",
+		"application/binc":                  "github.com/goadesign/goa/encoding/binc",
+		"application/x-binc":                "github.com/goadesign/goa/encoding/binc",
+		"application/cbor":                  "github.com/goadesign/goa/encoding/cbor",
+		"application/x-cbor":                "github.com/goadesign/goa/encoding/cbor",
+		"application/msgpack":               "github.com/goadesign/goa/encoding/msgpack",
+		"application/x-msgpack":             "github.com/goadesign/goa/encoding/msgpack",
+		"application/x-www-form-urlencoded": "github.com/goadesign/goa",
 	}
 
 	// KnownEncoderFunctions contains the list of encoding encoder and decoder functions known
 	// by goa indexed by MIME type.
 	KnownEncoderFunctions = map[string][2]string{
-		"application/json":      {"NewJSONEncoder", "NewJSONDecoder"},
-		"application/xml":       {"NewXMLEncoder", "NewXMLDecoder"},
-		"application/gob":       {"NewGobEncoder", "NewGobDecoder"},
-		"application/x-gob":     {"NewGobEncoder", "NewGobDecoder"},
-		"application/binc":      {"NewEncoder", "NewDecoder"},
-		"application/x-binc":    {"NewEncoder", "NewDecoder"},
-		"application/cbor":      {"NewEncoder", "NewDecoder"},
-		"application/x-cbor":    {"NewEncoder", "NewDecoder"},
-		"application/msgpack":   {"NewEncoder", "NewDecoder"},
-		"application/x-msgpack": {"NewEncoder", "NewDecoder"},
+		"application/json":                  {"NewJSONEncoder", "NewJSONDecoder"},
+		"application/xml":                   {"NewXMLEncoder", "NewXMLDecoder"},
+		"application/gob":                   {"NewGobEncoder", "NewGobDecoder"},
+		"application/x-gob":                 {"NewGobEncoder", "NewGobDecoder"},
+		"application/binc":                  {"NewEncoder", "NewDecoder"},
+		"application/x-binc":                {"NewEncoder", "NewDecoder"},
+		"application/cbor":                  {"NewEncoder", "NewDecoder"},
+		"application/x-cbor":                {"NewEncoder", "NewDecoder"},
+		"application/msgpack":               {"NewEncoder", "NewDecoder"},
+		"application/x-msgpack":             {"NewEncoder", "NewDecoder"},
+		"application/x-www-form-urlencoded": {"NewFormEncoder", "NewFormDecoder"},
 	}
 
 	// JSONContentTypes list the Content-Type header values that cause goa to encode or decode
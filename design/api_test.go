@@ -120,3 +120,67 @@ var _ = Describe("MediaTypeRoot", func() {
 		Ω(sets[0][1]).Should(Equal(root["bar"]))
 	})
 })
+
+var _ = Describe("Restrict", func() {
+	var api *design.APIDefinition
+	var names []string
+	var err error
+
+	BeforeEach(func() {
+		api = &design.APIDefinition{
+			Name: "test",
+			Resources: map[string]*design.ResourceDefinition{
+				"account": {Name: "account"},
+				"bottle":  {Name: "bottle", ParentName: "account"},
+				"health":  {Name: "health"},
+			},
+			Versions: []*design.APIVersionDefinition{
+				{Name: "v2", Resources: map[string]*design.ResourceDefinition{
+					"health": {Name: "health"},
+					"review": {Name: "review"},
+				}},
+			},
+		}
+		names = nil
+	})
+
+	JustBeforeEach(func() {
+		err = api.Restrict(names...)
+	})
+
+	Context("with resources and their parents", func() {
+		BeforeEach(func() {
+			names = []string{"account", "bottle", "review"}
+		})
+
+		It("removes the other resources of the API and its versions", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(api.Resources).Should(HaveLen(2))
+			Ω(api.Resources).Should(HaveKey("account"))
+			Ω(api.Resources).Should(HaveKey("bottle"))
+			Ω(api.Versions[0].Resources).Should(HaveLen(1))
+			Ω(api.Versions[0].Resources).Should(HaveKey("review"))
+		})
+	})
+
+	Context("with a resource without its parent", func() {
+		BeforeEach(func() {
+			names = []string{"bottle"}
+		})
+
+		It("returns an error and keeps the resources", func() {
+			Ω(err).Should(MatchError(`resource "bottle" has parent resource "account" which must also be listed`))
+			Ω(api.Resources).Should(HaveLen(3))
+		})
+	})
+
+	Context("with an unknown resource", func() {
+		BeforeEach(func() {
+			names = []string{"account", "wine"}
+		})
+
+		It("returns an error", func() {
+			Ω(err).Should(MatchError(`API "test" does not define resource "wine"`))
+		})
+	})
+})
@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"time"
 	"unicode"
 
 	"github.com/goadesign/goa/design"
@@ -193,6 +194,29 @@ func Headers(params ...interface{}) {
 	}
 }
 
+// Cookies describes the request cookies used by the action. Each cookie is described via the
+// `Cookie` function which uses the same DSL as the Attribute DSL, cookies must use primitive types.
+// Here is an example:
+//
+//	Cookies(func() {
+//		Cookie("session", String, func() {
+//			MinLength(32)
+//		})
+//		Cookie("theme", String)
+//		Required("session")
+//	})
+//
+// The cookie values are available in fields of the generated action context.
+func Cookies(dsl func()) {
+	if a, ok := actionDefinition(); ok {
+		cookies := newAttribute(a.Parent.MediaType)
+		cookies.Type = make(design.Object)
+		if dslengine.Execute(dsl, cookies) {
+			a.Cookies = cookies
+		}
+	}
+}
+
 // Params describe the action parameters, either path parameters identified via wildcards or query
 // string parameters. Each parameter is described via the `Param` function which uses the same DSL
 // as the Attribute DSL. Here is an example:
@@ -260,7 +284,7 @@ func Payload(p interface{}, dsls ...func()) {
 		case design.DataStructure:
 			att = design.DupAtt(actual.Definition())
 		case string:
-			ut, ok := design.Design.Types[actual]
+			ut, ok := lookupType(actual)
 			if !ok {
 				dslengine.ReportError("unknown payload type %s", actual)
 			}
@@ -293,6 +317,252 @@ func Payload(p interface{}, dsls ...func()) {
 	}
 }
 
+// MultipartForm implements the action payload multipart form DSL. An action multipart form
+// indicates that the HTTP requests made to the action endpoint must use the "multipart/form-data"
+// content type. The payload members correspond to the form fields, members of type File
+// correspond to uploaded files. Example:
+//
+//	Action("upload", func() {
+//		Routing(POST("/upload"))
+//		Payload(func() {
+//			Member("file", File, "Uploaded file")
+//			Member("description", String)
+//			Required("file")
+//		})
+//		MultipartForm()
+//	})
+//
+// MultipartForm must appear in Action and requires the action to define an object payload.
+func MultipartForm() {
+	if a, ok := actionDefinition(); ok {
+		a.PayloadMultipart = true
+	}
+}
+
+// Paginated indicates that the action responses are paginated using the given style, one of
+// "offset" (design.OffsetPagination) or "cursor" (design.CursorPagination). Paginated adds the
+// "limit" query string parameter as well as the "page" parameter for the offset style or the
+// "cursor" parameter for the cursor style. It also adds the Link header and, for the offset style,
+// the X-Total-Count header to the OK response. Example:
+//
+//	Action("list", func() {
+//		Routing(GET(""))
+//		Paginated(OffsetPagination)
+//		Response(OK, func() {
+//			Media(CollectionOf(Bottle))
+//		})
+//	})
+//
+// The generated action context exposes the PageRequest and SetPageLinks helper methods and the
+// generated client a method that returns an iterator over all the pages.
+func Paginated(style string) {
+	if style != design.OffsetPagination && style != design.CursorPagination {
+		dslengine.ReportError("invalid pagination style %#v, must be %#v or %#v", style, design.OffsetPagination, design.CursorPagination)
+		return
+	}
+	if a, ok := actionDefinition(); ok {
+		a.Pagination = &design.PaginationDefinition{Style: style}
+	}
+}
+
+// Fields indicates that the action supports field selection (sparse fieldsets): clients may list
+// the fields of the OK response media type to render using the "fields" query string parameter.
+// Nested fields are selected using their dot separated path. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Fields()
+//		Response(OK, Bottle)	// GET /bottles/1?fields=id,name,account.name
+//	})
+//
+// The parameter only accepts the paths of the media type attributes. The generated OK response
+// helpers only render the selected fields when the parameter is set.
+func Fields() {
+	if a, ok := actionDefinition(); ok {
+		a.Fields = &design.FieldsDefinition{}
+	}
+}
+
+// Stream indicates that the action streams server-sent events (text/event-stream) to the client.
+// The first argument is the media type of the events data given either as a media type definition
+// or a media type identifier. The optional second argument is the value of the event field of the
+// events, events use the default "message" type if omitted. Example:
+//
+//	Action("watch", func() {
+//		Routing(GET("/:id/watch"))
+//		Stream(DashboardUpdate, "update")
+//	})
+//
+// The generated action context exposes the Send and Close methods that write the events and the
+// LastEventID method that returns the ID of the last event received by a reconnecting client.
+// The context is canceled when the client disconnects. The generated client returns a reader that
+// decodes the events. Streaming actions must use GET routes.
+func Stream(mediaType interface{}, event ...string) {
+	if a, ok := actionDefinition(); ok {
+		var identifier string
+		if m, ok := mediaType.(*design.MediaTypeDefinition); ok {
+			if m != nil {
+				identifier = m.Identifier
+			}
+		} else if id, ok := mediaType.(string); ok {
+			identifier = id
+		} else {
+			dslengine.ReportError("media type must be a string or a pointer to MediaTypeDefinition, got %#v", mediaType)
+			return
+		}
+		if len(event) > 1 {
+			dslengine.ReportError("too many arguments given to Stream")
+			return
+		}
+		a.Stream = &design.StreamDefinition{MediaType: identifier}
+		if len(event) > 0 {
+			a.Stream.Event = event[0]
+		}
+	}
+}
+
+// Messages describes the messages exchanged over the connection of a WebSocket action. The DSL
+// lists the type of the messages sent by the client (Inbound), the type of the messages sent by the
+// server (Outbound) and the interval between the ping frames sent by the server (KeepAlive).
+// Example:
+//
+//	Action("chat", func() {
+//		Routing(GET("/:id/chat"))
+//		Scheme("ws")
+//		Messages(func() {
+//			Inbound(ChatPost)
+//			Outbound(ChatMessage)
+//			KeepAlive(30 * time.Second)
+//		})
+//	})
+//
+// The generated action context exposes the Serve method that upgrades the connection and hands
+// a typed connection to the given handler. The connection Recv method decodes and validates the
+// inbound messages and the Send method encodes the outbound messages using the service decoders
+// and encoders. The generated client returns a typed connection.
+func Messages(dsl func()) {
+	if a, ok := actionDefinition(); ok {
+		m := &design.MessagesDefinition{Parent: a, DSLFunc: dsl}
+		if !dslengine.Execute(dsl, m) {
+			return
+		}
+		a.Messages = m
+	}
+}
+
+// Inbound sets the type of the messages sent by the client over the connection of a WebSocket
+// action. The argument is a type, a media type, the name of a type or the identifier of a media
+// type. See Messages.
+func Inbound(t interface{}) {
+	if m, ok := messagesDefinition(); ok {
+		m.Inbound = messageType(t)
+	}
+}
+
+// Outbound sets the type of the messages sent by the server over the connection of a WebSocket
+// action. The argument is a type, a media type, the name of a type or the identifier of a media
+// type. See Messages.
+func Outbound(t interface{}) {
+	if m, ok := messagesDefinition(); ok {
+		m.Outbound = messageType(t)
+	}
+}
+
+// KeepAlive sets the interval between the ping frames sent by the server and by the generated
+// client over the connection of a WebSocket action. The server connection context is canceled when
+// a ping frame cannot be written. See Messages.
+func KeepAlive(period time.Duration) {
+	if period <= 0 {
+		dslengine.ReportError("keep-alive period must be greater than 0, got %s", period)
+		return
+	}
+	if m, ok := messagesDefinition(); ok {
+		m.KeepAlive = period
+	}
+}
+
+// messageType returns the data type described by the argument of Inbound or Outbound.
+func messageType(t interface{}) design.DataType {
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		return actual
+	case *design.UserTypeDefinition:
+		return actual
+	case string:
+		if ut, ok := lookupType(actual); ok {
+			return ut
+		}
+		if mt := design.Design.MediaTypeWithIdentifier(actual); mt != nil {
+			return mt
+		}
+		dslengine.ReportError("unknown message type %#v", actual)
+	default:
+		dslengine.ReportError("invalid message type, must be a type, a media type, a type name or a media type identifier, got %#v", t)
+	}
+	return nil
+}
+
+// Cacheable indicates that the action supports HTTP conditional requests. Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"), PUT("/:id"))
+//		Cacheable()
+//	})
+//
+// The generated action context exposes the SetETag and SetLastModified methods that set the ETag
+// and Last-Modified response headers. The generated controller responds to GET and HEAD requests
+// with 304 Not Modified when the If-None-Match or If-Modified-Since request headers match the
+// response, the entity tag is computed from the response body if the action does not set it.
+// For other requests SetETag and SetLastModified return an error if the If-Match or
+// If-Unmodified-Since preconditions fail, actions should call them prior to modifying the
+// resource.
+func Cacheable() {
+	if a, ok := actionDefinition(); ok {
+		a.Cacheable = true
+	}
+}
+
+// Deprecated marks the action, attribute or parameter being defined as deprecated. The optional
+// argument is the sunset date after which the action or attribute may be removed, it must use the
+// RFC3339 full-date format (e.g. "2017-01-01"). Example:
+//
+//	Action("show", func() {
+//		Routing(GET("/:id"))
+//		Deprecated("2017-01-01")
+//		Params(func() {
+//			Param("id", Integer)
+//			Param("format", String, func() {
+//				Deprecated()
+//			})
+//		})
+//	})
+//
+// The generated controller sets the Deprecation and Sunset response headers on requests made to
+// deprecated actions.
+func Deprecated(sunset ...string) {
+	if len(sunset) > 1 {
+		dslengine.ReportError("too many arguments given to Deprecated")
+		return
+	}
+	dep := &design.DeprecationDefinition{}
+	if len(sunset) == 1 {
+		t, err := time.Parse("2006-01-02", sunset[0])
+		if err != nil {
+			dslengine.ReportError("invalid sunset date %#v, must use the YYYY-MM-DD format", sunset[0])
+			return
+		}
+		dep.Sunset = t
+	}
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		def.Deprecation = dep
+	case *design.AttributeDefinition:
+		def.Deprecation = dep
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
+
 // newAttribute creates a new attribute definition using the media type with the given identifier
 // as base type.
 func newAttribute(baseMT string) *design.AttributeDefinition {
@@ -2,7 +2,9 @@ package apidsl_test
 
 import (
 	"strconv"
+	"time"
 
+	"github.com/goadesign/goa"
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -277,4 +279,593 @@ var _ = Describe("Payload", func() {
 		})
 	})
 
+	Context("with a multipart form", func() {
+		var file bool
+
+		BeforeEach(func() {
+			dslengine.Reset()
+			file = true
+		})
+
+		JustBeforeEach(func() {
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(func() {
+						if file {
+							Member("file", File)
+						}
+						Member("name")
+						Required("name")
+					})
+					MultipartForm()
+				})
+			})
+			dslengine.Run()
+		})
+
+		It("sets the payload multipart flag", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["foo"].Actions["bar"].PayloadMultipart).Should(BeTrue())
+			Ω(Design.Resources["foo"].Actions["bar"].Payload.Type.ToObject()).Should(HaveKey("file"))
+			Ω(Design.Resources["foo"].Actions["bar"].Payload.Type.ToObject()["file"].Type).Should(Equal(File))
+		})
+
+		Context("without files", func() {
+			BeforeEach(func() {
+				file = false
+			})
+
+			It("sets the payload multipart flag", func() {
+				Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+				Ω(Design.Resources["foo"].Actions["bar"].PayloadMultipart).Should(BeTrue())
+			})
+		})
+	})
+
+	Context("with a file member and no multipart form", func() {
+		BeforeEach(func() {
+			dslengine.Reset()
+
+			Resource("foo", func() {
+				Action("bar", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Member("file", File)
+					})
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			dslengine.Run()
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+})
+
+var _ = Describe("Deprecated", func() {
+	var sunset []string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		sunset = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("bar", func() {
+				Routing(GET("/:id"))
+				Deprecated(sunset...)
+				Params(func() {
+					Param("id", Integer)
+					Param("format", String, func() {
+						Deprecated()
+					})
+				})
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("marks the action and the parameter as deprecated", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		action := Design.Resources["foo"].Actions["bar"]
+		Ω(action.Deprecation).ShouldNot(BeNil())
+		Ω(action.Deprecation.Sunset.IsZero()).Should(BeTrue())
+		Ω(action.Deprecation.SunsetHeader()).Should(BeEmpty())
+		params := action.Params.Type.ToObject()
+		Ω(params["format"].Deprecation).ShouldNot(BeNil())
+		Ω(params["id"].Deprecation).Should(BeNil())
+	})
+
+	Context("with a sunset date", func() {
+		BeforeEach(func() {
+			sunset = []string{"2017-01-01"}
+		})
+
+		It("sets the sunset date", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			deprecation := Design.Resources["foo"].Actions["bar"].Deprecation
+			Ω(deprecation).ShouldNot(BeNil())
+			Ω(deprecation.SunsetHeader()).Should(Equal("Sun, 01 Jan 2017 00:00:00 GMT"))
+			Ω(deprecation.Message()).Should(Equal("it will be removed after 2017-01-01"))
+		})
+	})
+
+	Context("with an invalid sunset date", func() {
+		BeforeEach(func() {
+			sunset = []string{"01/01/2017"}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Stream", func() {
+	var stream func()
+	var verb string
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		verb = "GET"
+		MediaType("application/vnd.tick+json", func() {
+			Attributes(func() {
+				Attribute("seq", Integer)
+			})
+			View("default", func() {
+				Attribute("seq")
+			})
+		})
+		stream = func() {
+			Stream("application/vnd.tick+json", "tick")
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("ticker", func() {
+			Action("watch", func() {
+				if verb == "GET" {
+					Routing(GET(""))
+				} else {
+					Routing(POST(""))
+				}
+				stream()
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("stores the stream definition", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		action := Design.Resources["ticker"].Actions["watch"]
+		Ω(action.Stream).ShouldNot(BeNil())
+		Ω(action.Stream.Event).Should(Equal("tick"))
+		Ω(action.Stream.EventMediaType()).ShouldNot(BeNil())
+		Ω(action.Stream.EventMediaType().TypeName).Should(Equal("Tick"))
+	})
+
+	Context("with an unknown media type", func() {
+		BeforeEach(func() {
+			stream = func() {
+				Stream("application/vnd.unknown+json")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a route that does not use GET", func() {
+		BeforeEach(func() {
+			verb = "POST"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a cacheable action", func() {
+		BeforeEach(func() {
+			stream = func() {
+				Stream("application/vnd.tick+json")
+				Cacheable()
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Cacheable", func() {
+	var cacheable bool
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		cacheable = true
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("show", func() {
+				Routing(GET("/:id"), PUT("/:id"))
+				if cacheable {
+					Cacheable()
+				}
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("marks the action as cacheable", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Resources["foo"].Actions["show"].Cacheable).Should(BeTrue())
+	})
+
+	Context("without Cacheable", func() {
+		BeforeEach(func() {
+			cacheable = false
+		})
+
+		It("does not mark the action as cacheable", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["foo"].Actions["show"].Cacheable).Should(BeFalse())
+		})
+	})
+})
+
+var _ = Describe("Cookies", func() {
+	var cookies func()
+	var params func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		cookies = nil
+		params = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("show", func() {
+				Routing(GET(""))
+				if params != nil {
+					Params(params)
+				}
+				Cookies(cookies)
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with primitive cookies", func() {
+		BeforeEach(func() {
+			cookies = func() {
+				Cookie("session", String)
+				Cookie("visits", Integer)
+				Required("session")
+			}
+		})
+
+		It("stores the cookies", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["foo"].Actions["show"]
+			Ω(action.Cookies).ShouldNot(BeNil())
+			Ω(action.Cookies.Type.ToObject()).Should(HaveLen(2))
+			Ω(action.Cookies.Type.ToObject()["visits"].Type).Should(Equal(Integer))
+			Ω(action.Cookies.IsRequired("session")).Should(BeTrue())
+		})
+	})
+
+	Context("with a non primitive cookie", func() {
+		BeforeEach(func() {
+			cookies = func() {
+				Cookie("prefs", HashOf(String, String))
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a cookie that has the name of a parameter", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param("session", String)
+			}
+			cookies = func() {
+				Cookie("session", String)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Paginated", func() {
+	var style string
+	var params func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		style = OffsetPagination
+		params = nil
+	})
+
+	JustBeforeEach(func() {
+		Resource("foo", func() {
+			Action("list", func() {
+				Routing(GET(""))
+				Paginated(style)
+				if params != nil {
+					Params(params)
+				}
+				Response(OK)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("adds the page and limit parameters and the pagination headers", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		action := Design.Resources["foo"].Actions["list"]
+		Ω(action.Pagination).ShouldNot(BeNil())
+		Ω(action.Pagination.Style).Should(Equal(OffsetPagination))
+		Ω(action.QueryParams.Type.ToObject()).Should(HaveKey("page"))
+		limit := action.QueryParams.Type.ToObject()["limit"]
+		Ω(limit).ShouldNot(BeNil())
+		Ω(*limit.Validation.Minimum).Should(Equal(1.0))
+		Ω(*limit.Validation.Maximum).Should(Equal(float64(MaxPageLimit)))
+		Ω(limit.DefaultValue).Should(Equal(goa.DefaultPageLimit))
+		headers := action.Responses["OK"].Headers.Type.ToObject()
+		Ω(headers).Should(HaveKey("Link"))
+		Ω(headers).Should(HaveKey("X-Total-Count"))
+	})
+
+	Context("using cursors", func() {
+		BeforeEach(func() {
+			style = CursorPagination
+		})
+
+		It("adds the cursor and limit parameters", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			action := Design.Resources["foo"].Actions["list"]
+			Ω(action.QueryParams.Type.ToObject()).Should(HaveKey("cursor"))
+			Ω(action.QueryParams.Type.ToObject()).Should(HaveKey("limit"))
+			Ω(action.QueryParams.Type.ToObject()).ShouldNot(HaveKey("page"))
+			Ω(action.Responses["OK"].Headers.Type.ToObject()).ShouldNot(HaveKey("X-Total-Count"))
+		})
+	})
+
+	Context("with other parameters", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param("sort", String)
+			}
+		})
+
+		It("keeps the action parameters", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			params := Design.Resources["foo"].Actions["list"].QueryParams.Type.ToObject()
+			Ω(params).Should(HaveKey("sort"))
+			Ω(params).Should(HaveKey("page"))
+		})
+	})
+
+	Context("with a parameter that conflicts with the pagination", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param("limit", String)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an invalid style", func() {
+		BeforeEach(func() {
+			style = "pages"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Fields", func() {
+	var params func()
+	var response func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		params = nil
+		response = func() {
+			Response(OK)
+		}
+	})
+
+	JustBeforeEach(func() {
+		Account := MediaType("application/vnd.account+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+		})
+		Bottle := MediaType("application/vnd.bottle+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("account", Account)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+		})
+		Resource("bottle", func() {
+			DefaultMedia(Bottle)
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Fields()
+				if params != nil {
+					Params(params)
+				}
+				response()
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("adds the fields parameter", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		action := Design.Resources["bottle"].Actions["show"]
+		Ω(action.Fields).ShouldNot(BeNil())
+		Ω(Design.MediaTypeWithIdentifier(action.Fields.MediaType).TypeName).Should(Equal("Bottle"))
+		params := action.QueryParams.Type.ToObject()
+		Ω(params).Should(HaveKey(FieldsParam))
+		Ω(params[FieldsParam].Type.IsArray()).Should(BeTrue())
+		elem := params[FieldsParam].Type.ToArray().ElemType
+		Ω(elem.Validation.Values).Should(Equal([]interface{}{"account", "account.id", "account.name", "id"}))
+	})
+
+	Context("with a parameter that conflicts with the fields parameter", func() {
+		BeforeEach(func() {
+			params = func() {
+				Param(FieldsParam, String)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with no OK response", func() {
+		BeforeEach(func() {
+			response = func() {
+				Response(NoContent)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must define an OK response"))
+		})
+	})
+})
+
+var _ = Describe("Messages", func() {
+	var scheme string
+	var messages func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		scheme = "ws"
+		Type("ChatPost", func() {
+			Attribute("body", String)
+			Required("body")
+		})
+		MediaType("application/vnd.chat.message+json", func() {
+			Attributes(func() {
+				Attribute("author", String)
+				Attribute("body", String)
+			})
+			View("default", func() {
+				Attribute("author")
+				Attribute("body")
+			})
+		})
+		messages = func() {
+			Inbound("ChatPost")
+			Outbound("application/vnd.chat.message+json")
+			KeepAlive(30 * time.Second)
+		}
+	})
+
+	JustBeforeEach(func() {
+		Resource("room", func() {
+			Action("chat", func() {
+				Routing(GET("/:id/chat"))
+				Scheme(scheme)
+				Messages(messages)
+			})
+		})
+		dslengine.Run()
+	})
+
+	It("stores the messages definition", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		action := Design.Resources["room"].Actions["chat"]
+		Ω(action.Messages).ShouldNot(BeNil())
+		Ω(action.Messages.Parent).Should(Equal(action))
+		Ω(action.Messages.InboundType().TypeName).Should(Equal("ChatPost"))
+		Ω(action.Messages.OutboundType().TypeName).Should(Equal("ChatMessage"))
+		Ω(action.Messages.KeepAlive).Should(Equal(30 * time.Second))
+	})
+
+	Context("with an action that does not use WebSocket", func() {
+		BeforeEach(func() {
+			scheme = "http"
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must use the ws or wss scheme"))
+		})
+	})
+
+	Context("with no message type", func() {
+		BeforeEach(func() {
+			messages = func() {
+				KeepAlive(time.Minute)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("must define the inbound or outbound messages"))
+		})
+	})
+
+	Context("with an unknown message type", func() {
+		BeforeEach(func() {
+			messages = func() {
+				Inbound("Unknown")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("unknown message type"))
+		})
+	})
+
+	Context("with an invalid keep-alive period", func() {
+		BeforeEach(func() {
+			messages = func() {
+				Outbound("application/vnd.chat.message+json")
+				KeepAlive(0)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
 })
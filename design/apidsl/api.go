@@ -93,12 +93,188 @@ func Version(ver string) {
 	}
 }
 
+// VersionHeader sets the name of the request header that selects the API version, see APIVersion.
+// VersionHeader may only appear in API. Example:
+//
+//	VersionHeader("X-API-Version")	// Requests select version "v2" with "X-API-Version: v2"
+func VersionHeader(name string) {
+	if api, ok := apiDefinition(); ok {
+		api.VersionHeader = name
+	}
+}
+
+// VersionParam sets the name of the Accept header media type parameter that selects the API
+// version, see APIVersion. VersionParam may only appear in API. Example:
+//
+//	VersionParam("version")	// Requests select version "v2" with "Accept: application/json; version=v2"
+func VersionParam(name string) {
+	if api, ok := apiDefinition(); ok {
+		api.VersionParam = name
+	}
+}
+
+// APIVersion defines a version of the API. A version exposes the resources of the previous
+// version - or the resources defined at the top level of the design for the first version - and
+// may override some of these resources or define new ones using Resource. The version DSL may also
+// set a description and a base path that replaces the API base path for all the version
+// endpoints. Example:
+//
+//	var _ = APIVersion("v2", func() {
+//		Description("Second version of the API")
+//		BasePath("/v2")	// Optional, defaults to the API base path
+//
+//		Resource("bottle", func() {	// Overrides the "bottle" resource of the previous version
+//			// ... Resource DSL
+//		})
+//	})
+//
+// Requests select the versions that define their own base path with their path, the other versions
+// with the header set by VersionHeader or the Accept header parameter set by VersionParam. goagen
+// generates the code for each version in a sub-package of the application package named after the
+// version and the generated main function serves the versions using a goa.VersionMux. The swagger
+// and client generators also produce a spec and a client package per version.
+func APIVersion(name string, dsl func()) *design.APIVersionDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	if name == "" {
+		dslengine.ReportError("API version name cannot be empty")
+		return nil
+	}
+	var previous *design.APIVersionDefinition
+	for _, v := range design.Design.Versions {
+		if v.Name == name {
+			dslengine.ReportError("API version %#v is defined twice", name)
+			return nil
+		}
+		previous = v
+	}
+	version := &design.APIVersionDefinition{
+		Name:     name,
+		Previous: previous,
+		DSLFunc:  dsl,
+	}
+	design.Design.Versions = append(design.Design.Versions, version)
+	return version
+}
+
+// Import composes the API with the resources, types and media types defined with DesignPackage by
+// the design package with the given import path. The package must also be imported by the design
+// package that defines the API - usually with a blank import - so that its definitions get
+// registered. Import may only appear in API. The optional DSL may set a namespace prepended to the
+// names of the package types and media types, a base path prepended to the paths of the package
+// resources and the subset of the package resources exposed by the API. Example:
+//
+//	import (
+//		_ "github.com/acme/billing/design"
+//		_ "github.com/acme/shipping/design"
+//	)
+//
+//	var _ = API("acme", func() {
+//		BasePath("/api")
+//		Import("github.com/acme/billing/design", func() {
+//			Namespace("Billing")		// Generates BillingInvoice for type "Invoice"
+//			BasePath("/billing")		// Invoice endpoints are served under /api/billing
+//		})
+//		Import("github.com/acme/shipping/design", func() {
+//			Resources("shipment")		// Only expose the "shipment" resource
+//		})
+//	})
+//
+// The designs of packages imported with a namespace must refer to their types and media types
+// using the values returned by Type and MediaType rather than their names. Importing packages is
+// optional: goagen reports an error when two packages define types, media types or resources with
+// the same name unless the conflict is resolved with Import.
+func Import(path string, dsl ...func()) *design.ImportDefinition {
+	api, ok := apiDefinition()
+	if !ok {
+		return nil
+	}
+	if path == "" {
+		dslengine.ReportError("import path cannot be empty")
+		return nil
+	}
+	if api.PackageImport(path) != nil {
+		dslengine.ReportError("design package %#v is imported twice", path)
+		return nil
+	}
+	imp := &design.ImportDefinition{Path: path, Parent: api}
+	if len(dsl) > 0 {
+		imp.DSLFunc = dsl[0]
+		if !dslengine.Execute(imp.DSLFunc, imp) {
+			return nil
+		}
+	}
+	api.Imports = append(api.Imports, imp)
+	return imp
+}
+
+// DesignPackage records that the types, media types and resources defined by dsl belong to the
+// design package with the given import path so that the API may compose them with Import.
+// DesignPackage must appear at the top level of the design package and dsl may only contain top
+// level definitions. Example:
+//
+//	package design
+//
+//	var Invoice *design.UserTypeDefinition
+//
+//	var _ = DesignPackage("github.com/acme/billing/design", func() {
+//		Invoice = Type("Invoice", func() {
+//			Attribute("amount", Number)
+//		})
+//		Resource("invoice", func() {
+//			// ... Resource dsl
+//		})
+//	})
+//
+// The definitions made outside of DesignPackage belong to the package that defines the API.
+func DesignPackage(path string, dsl func()) *design.PackageDefinition {
+	if !dslengine.IsTopLevelDefinition() {
+		dslengine.IncompatibleDSL()
+		return nil
+	}
+	if currentPackage != "" {
+		dslengine.ReportError("DesignPackage %#v cannot appear in DesignPackage %#v", path, currentPackage)
+		return nil
+	}
+	if path == "" {
+		dslengine.ReportError("design package path cannot be empty")
+		return nil
+	}
+	if dsl != nil {
+		inPackage(path, dsl)()
+	}
+	return design.Design.Package(path)
+}
+
+// Namespace sets the prefix prepended to the Go type names of the types and media types defined
+// by the design package being imported. Namespace may only appear in Import. name must be a valid
+// Go identifier.
+func Namespace(name string) {
+	if imp, ok := importDefinition(); ok {
+		imp.Namespace = name
+	}
+}
+
+// Resources lists the names of the resources of the design package being imported that the API
+// exposes, the other resources of the package are ignored. All the package resources are exposed
+// by default. Resources may only appear in Import. Use the goagen --resources flag to generate the
+// code of a sub-API made of some of the resources of the current design instead.
+func Resources(names ...string) {
+	if imp, ok := importDefinition(); ok {
+		imp.Resources = append(imp.Resources, names...)
+	}
+}
+
 // Description sets the definition description.
-// Description can be called inside API, Resource, Action or MediaType.
+// Description can be called inside API, APIVersion, Resource, Action or MediaType.
 func Description(d string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
 		def.Description = d
+	case *design.APIVersionDefinition:
+		def.Description = d
 	case *design.ResourceDefinition:
 		def.Description = d
 	case *design.ActionDefinition:
@@ -119,12 +295,18 @@ func Description(d string) {
 }
 
 // BasePath defines the API base path, i.e. the common path prefix to all the API actions.
+// BasePath may also appear in APIVersion to override the API base path for the version actions or
+// in Import to prefix the paths of the imported resources.
 // The path may define wildcards (see Routing for a description of the wildcard syntax).
 // The corresponding parameters must be described using BaseParams.
 func BasePath(val string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition:
 		def.BasePath = val
+	case *design.APIVersionDefinition:
+		def.BasePath = val
+	case *design.ImportDefinition:
+		def.BasePath = val
 	case *design.ResourceDefinition:
 		def.BasePath = val
 		awcs := design.ExtractWildcards(design.Design.BasePath)
@@ -585,6 +767,26 @@ func apiDefinition() (*design.APIDefinition, bool) {
 	return a, ok
 }
 
+// importDefinition returns true and current context if it is an ImportDefinition,
+// nil and false otherwise.
+func importDefinition() (*design.ImportDefinition, bool) {
+	i, ok := dslengine.CurrentDefinition().(*design.ImportDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return i, ok
+}
+
+// messagesDefinition returns true and current context if it is a MessagesDefinition,
+// nil and false otherwise.
+func messagesDefinition() (*design.MessagesDefinition, bool) {
+	m, ok := dslengine.CurrentDefinition().(*design.MessagesDefinition)
+	if !ok {
+		dslengine.IncompatibleDSL()
+	}
+	return m, ok
+}
+
 // encodingDefinition returns true and current context if it is an EncodingDefinition,
 // nil and false otherwise.
 func encodingDefinition() (*design.EncodingDefinition, bool) {
@@ -333,3 +333,127 @@ var _ = Describe("API", func() {
 	})
 
 })
+
+var _ = Describe("APIVersion", func() {
+	var v1, v2 *APIVersionDefinition
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("test", func() {
+			BasePath("/api")
+			VersionHeader("X-API-Version")
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/bottles/:id"))
+			})
+		})
+		Resource("account", func() {
+			Action("show", func() {
+				Routing(GET("/accounts/:id"))
+			})
+		})
+		v1 = APIVersion("v1", nil)
+		v2 = APIVersion("v2", func() {
+			Description("second version")
+			BasePath("/api/v2")
+			Resource("bottle", func() {
+				Action("list", func() {
+					Routing(GET("/bottles"))
+				})
+			})
+			Resource("winery", func() {
+				Parent("account")
+				Action("show", func() {
+					Routing(GET("/wineries/:wid"))
+				})
+			})
+		})
+	})
+
+	JustBeforeEach(func() {
+		dslengine.Run()
+	})
+
+	It("defines the versions", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(Design.Versions).Should(Equal([]*APIVersionDefinition{v1, v2}))
+		Ω(v2.Previous).Should(Equal(v1))
+		Ω(v2.Description).Should(Equal("second version"))
+		Ω(v2.BasePath).Should(Equal("/api/v2"))
+	})
+
+	It("selects the versions that define their own base path with the request path", func() {
+		Ω(Design.VersionHeader).Should(Equal("X-API-Version"))
+		Ω(v1.SelectedByPath()).Should(BeFalse())
+		Ω(v2.SelectedByPath()).Should(BeTrue())
+	})
+
+	It("inherits the resources of the previous version", func() {
+		Ω(v1.AllResources()).Should(HaveLen(2))
+		all := v2.AllResources()
+		Ω(all).Should(HaveLen(3))
+		Ω(all["account"]).Should(Equal(Design.Resources["account"]))
+		Ω(all["bottle"]).ShouldNot(Equal(Design.Resources["bottle"]))
+		Ω(all["bottle"].Actions).Should(HaveKey("list"))
+		Ω(all["winery"].Parent()).Should(Equal(Design.Resources["account"]))
+	})
+
+	It("computes the version API definition", func() {
+		api := v2.API()
+		Ω(api.Version).Should(Equal("v2"))
+		Ω(api.BasePath).Should(Equal("/api/v2"))
+		Ω(api.Resources).Should(HaveLen(3))
+		Ω(api.Versions).Should(BeEmpty())
+	})
+
+	It("computes the paths of the version resources with the version base path", func() {
+		api := v2.API()
+		Ω(api.Resources["account"].Actions["show"].Routes[0].FullPath()).Should(Equal("/api/v2/accounts/:id"))
+		Ω(api.Resources["winery"].Actions["show"].Routes[0].FullPath()).Should(Equal("/api/v2/accounts/:id/wineries/:wid"))
+		Ω(api.Resources["bottle"].Actions["list"].Routes[0].FullPath()).Should(Equal("/api/v2/bottles"))
+		Ω(Design.Resources["account"].Actions["show"].Routes[0].FullPath()).Should(Equal("/api/accounts/:id"))
+	})
+
+	Context("with a duplicate version", func() {
+		var dup *APIVersionDefinition
+
+		BeforeEach(func() {
+			dup = APIVersion("v2", nil)
+		})
+
+		It("does not define it", func() {
+			Ω(dup).Should(BeNil())
+			Ω(Design.Versions).Should(HaveLen(2))
+		})
+	})
+
+	Context("with a version that requests cannot select", func() {
+		BeforeEach(func() {
+			base := Design.DSLFunc
+			Design.DSLFunc = func() {
+				base()
+				Design.VersionHeader = ""
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("use VersionHeader or VersionParam"))
+		})
+	})
+
+	Context("with a version resource whose parent does not exist", func() {
+		BeforeEach(func() {
+			APIVersion("v3", func() {
+				Resource("cellar", func() {
+					Parent("unknown")
+				})
+			})
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -2,6 +2,7 @@ package apidsl
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/format"
 )
 
 // Attribute implements the attribute definition DSL. An attribute describes a data structure
@@ -17,7 +19,8 @@ import (
 // attributes may include other attributes. At the basic level an attribute has a name,
 // a type and optionally a default value and validation rules. The type of an attribute can be one of:
 //
-// * The primitive types Boolean, Integer, Number, DateTime, UUID or String.
+// * The primitive types Boolean, Integer, Number, DateTime, UUID, File, String, Date, Duration,
+// Bytes or Decimal.
 //
 // * A type defined via the Type function.
 //
@@ -104,6 +107,14 @@ func Attribute(name string, args ...interface{}) {
 				baseAttr = design.DupAtt(att)
 			}
 		}
+		if baseAttr == nil {
+			for _, base := range parent.Bases {
+				if att, ok := base.ToObject()[name]; ok {
+					baseAttr = design.DupAtt(att)
+					break
+				}
+			}
+		}
 
 		dataType, description, dsl := parseAttributeArgs(baseAttr, args...)
 		if baseAttr != nil {
@@ -142,7 +153,7 @@ func parseAttributeArgs(baseAttr *design.AttributeDefinition, args ...interface{
 	parseDataType := func(expected string, index int) {
 		if name, ok2 := args[index].(string); ok2 {
 			// Lookup type by name
-			if dataType, ok = design.Design.Types[name]; !ok {
+			if dataType, ok = lookupType(name); !ok {
 				if dataType = design.Design.MediaTypeWithIdentifier(name); dataType == nil {
 					dslengine.InvalidArgError(expected, args[index])
 				}
@@ -214,6 +225,24 @@ func Header(name string, args ...interface{}) {
 	Attribute(name, args...)
 }
 
+// Cookie is an alias of Attribute for the most part. It is used in Cookies to describe a request
+// cookie, cookie attributes must use a primitive type.
+//
+// Within an APIKeySecurity definition, Cookie defines that an implementation must check the given
+// cookie to get the API Key. In this case, no `args` parameter is necessary.
+func Cookie(name string, args ...interface{}) {
+	if _, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if len(args) != 0 {
+			dslengine.ReportError("do not specify args")
+			return
+		}
+		inCookie(name)
+		return
+	}
+
+	Attribute(name, args...)
+}
+
 // Member is an alias of Attribute.
 func Member(name string, args ...interface{}) {
 	Attribute(name, args...)
@@ -342,6 +371,9 @@ var SupportedValidationFormats = []string{
 // "cidr": RFC4632 or RFC4291 CIDR notation IP address
 //
 // "regexp": RE2 regular expression
+//
+// Format also accepts the custom formats registered with goa.RegisterFormat prior to running the
+// DSL, see design.RegisterFormatExample for customizing the examples generated for such formats.
 func Format(f string) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.StringKind {
@@ -354,6 +386,9 @@ func Format(f string) {
 					break
 				}
 			}
+			if !supported {
+				supported = format.IsRegistered(f)
+			}
 			if !supported {
 				dslengine.ReportError("unsupported format %#v, supported formats are: %s",
 					f, strings.Join(SupportedValidationFormats, ", "))
@@ -390,63 +425,112 @@ func Pattern(p string) {
 // Minimum adds a "minimum" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor21.
 func Minimum(val interface{}) {
+	minimum(val, false)
+}
+
+// ExclusiveMinimum adds a "minimum" validation with "exclusiveMinimum" set to the attribute:
+// valid values must be strictly greater than val.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor21.
+func ExclusiveMinimum(val interface{}) {
+	minimum(val, true)
+}
+
+// Maximum adds a "maximum" validation to the attribute.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+func Maximum(val interface{}) {
+	maximum(val, false)
+}
+
+// ExclusiveMaximum adds a "maximum" validation with "exclusiveMaximum" set to the attribute:
+// valid values must be strictly lesser than val.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor17.
+func ExclusiveMaximum(val interface{}) {
+	maximum(val, true)
+}
+
+// MultipleOf adds a "multipleOf" validation to the attribute: valid values must be a multiple of
+// val. val must be strictly greater than 0 and must be an integer if the attribute is an integer.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor14.
+func MultipleOf(val interface{}) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
+			incompatibleAttributeType("multiple of", a.Type.Name(), "an integer or a number")
+			return
+		}
+		f, ok := numberValue(val)
+		if !ok {
+			return
+		}
+		if f <= 0 {
+			dslengine.ReportError("invalid multiple of value %#v, must be strictly greater than 0", val)
+			return
+		}
+		if a.Type != nil && a.Type.Kind() == design.IntegerKind && f != math.Trunc(f) {
+			dslengine.ReportError("invalid multiple of value %#v, must be an integer", val)
+			return
+		}
+		if a.Validation == nil {
+			a.Validation = &dslengine.ValidationDefinition{}
+		}
+		a.Validation.MultipleOf = &f
+	}
+}
+
+func minimum(val interface{}, exclusive bool) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
 			incompatibleAttributeType("minimum", a.Type.Name(), "an integer or a number")
 		} else {
-			var f float64
-			switch v := val.(type) {
-			case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-				f = reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float()
-			case string:
-				var err error
-				f, err = strconv.ParseFloat(v, 64)
-				if err != nil {
-					dslengine.ReportError("invalid number value %#v", v)
-					return
-				}
-			default:
-				dslengine.ReportError("invalid number value %#v", v)
+			f, ok := numberValue(val)
+			if !ok {
 				return
 			}
 			if a.Validation == nil {
 				a.Validation = &dslengine.ValidationDefinition{}
 			}
 			a.Validation.Minimum = &f
+			a.Validation.ExclusiveMinimum = exclusive
 		}
 	}
 }
 
-// Maximum adds a "maximum" validation to the attribute.
-// See http://json-schema.org/latest/json-schema-validation.html#anchor17.
-func Maximum(val interface{}) {
+func maximum(val interface{}, exclusive bool) {
 	if a, ok := attributeDefinition(); ok {
 		if a.Type != nil && a.Type.Kind() != design.IntegerKind && a.Type.Kind() != design.NumberKind {
 			incompatibleAttributeType("maximum", a.Type.Name(), "an integer or a number")
 		} else {
-			var f float64
-			switch v := val.(type) {
-			case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
-				f = reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float()
-			case string:
-				var err error
-				f, err = strconv.ParseFloat(v, 64)
-				if err != nil {
-					dslengine.ReportError("invalid number value %#v", v)
-					return
-				}
-			default:
-				dslengine.ReportError("invalid number value %#v", v)
+			f, ok := numberValue(val)
+			if !ok {
 				return
 			}
 			if a.Validation == nil {
 				a.Validation = &dslengine.ValidationDefinition{}
 			}
 			a.Validation.Maximum = &f
+			a.Validation.ExclusiveMaximum = exclusive
 		}
 	}
 }
 
+// numberValue converts val to a float64. It reports an error and returns false if val is not a
+// number or a string representing a number.
+func numberValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float32, float64, int, int8, int16, int32, int64, uint8, uint16, uint32, uint64:
+		return reflect.ValueOf(v).Convert(reflect.TypeOf(float64(0.0))).Float(), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			dslengine.ReportError("invalid number value %#v", v)
+			return 0, false
+		}
+		return f, true
+	default:
+		dslengine.ReportError("invalid number value %#v", v)
+		return 0, false
+	}
+}
+
 // MinLength adss a "minItems" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor45.
 func MinLength(val int) {
@@ -477,6 +561,54 @@ func MaxLength(val int) {
 	}
 }
 
+// UniqueItems adds a "uniqueItems" validation to the attribute: the elements of valid arrays must
+// all be different.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor49.
+func UniqueItems() {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.ArrayKind {
+			incompatibleAttributeType("unique items", a.Type.Name(), "an array")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.UniqueItems = true
+		}
+	}
+}
+
+// MinProperties adds a "minProperties" validation to the attribute: valid hashes must contain at
+// least val keys.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor57.
+func MinProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("minimum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MinProperties = &val
+		}
+	}
+}
+
+// MaxProperties adds a "maxProperties" validation to the attribute: valid hashes must contain at
+// most val keys.
+// See http://json-schema.org/latest/json-schema-validation.html#anchor54.
+func MaxProperties(val int) {
+	if a, ok := attributeDefinition(); ok {
+		if a.Type != nil && a.Type.Kind() != design.HashKind {
+			incompatibleAttributeType("maximum properties", a.Type.Name(), "a hash")
+		} else {
+			if a.Validation == nil {
+				a.Validation = &dslengine.ValidationDefinition{}
+			}
+			a.Validation.MaxProperties = &val
+		}
+	}
+}
+
 // Required adds a "required" validation to the attribute.
 // See http://json-schema.org/latest/json-schema-validation.html#anchor61.
 func Required(names ...string) {
@@ -515,6 +647,14 @@ func qualifiedTypeName(t design.DataType) string {
 	switch t.Kind() {
 	case design.DateTimeKind:
 		return "datetime"
+	case design.DateKind:
+		return "date"
+	case design.DurationKind:
+		return "duration"
+	case design.BytesKind:
+		return "bytes"
+	case design.DecimalKind:
+		return "decimal"
 	case design.ArrayKind:
 		return fmt.Sprintf("%s<%s>", t.Name(), qualifiedTypeName(t.ToArray().ElemType.Type))
 	case design.HashKind:
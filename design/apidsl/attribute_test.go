@@ -1,6 +1,7 @@
 package apidsl_test
 
 import (
+	"github.com/goadesign/goa"
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
 	"github.com/goadesign/goa/dslengine"
@@ -213,6 +214,32 @@ var _ = Describe("Attribute", func() {
 		})
 	})
 
+	Context("with a name and a DSL defining a custom format validation", func() {
+		BeforeEach(func() {
+			goa.RegisterFormat("semver", func(string) error { return nil })
+			name = "foo"
+			dsl = func() { Format("semver") }
+		})
+
+		It("produces an attribute with a format validation", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := parent.Type.(Object)
+			Ω(o[name].Validation).ShouldNot(BeNil())
+			Ω(o[name].Validation.Format).Should(Equal("semver"))
+		})
+	})
+
+	Context("with a name and a DSL defining an unknown format validation", func() {
+		BeforeEach(func() {
+			name = "foo"
+			dsl = func() { Format("unknown") }
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with a name, type integer and a DSL defining an enum validation", func() {
 		BeforeEach(func() {
 			name = "foo"
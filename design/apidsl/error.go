@@ -0,0 +1,83 @@
+package apidsl
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Error defines a typed error. It may appear in API, Resource or Action: an error defined in API
+// may be returned by all the API actions, one defined in Resource by the resource actions and one
+// defined in Action by the action only. The first argument is the error code and the second the
+// HTTP status code of the responses that carry the error. The optional DSL describes the error
+// fields in addition to the code, status and detail fields common to all errors. Example:
+//
+//	var _ = Resource("bottle", func() {
+//		Error("not_found", 404, func() {
+//			Description("The bottle does not exist")
+//			Attribute("id", Integer, "ID of the bottle")
+//			Required("id")
+//		})
+//	})
+//
+// goagen generates a NotFoundError type and a NewNotFoundError function that creates instances of
+// it in the application package. The contexts of the actions that may return the error expose a
+// NotFoundError method that sends the corresponding response, the error handler middleware
+// also sends the response when an action returns the error. The generated client package defines
+// the same types and the DecodeErrorResponse function that decodes error responses into them.
+func Error(name string, status int, dsl ...func()) {
+	if name == "" {
+		dslengine.ReportError("error name cannot be empty")
+		return
+	}
+	if len(dsl) > 1 {
+		dslengine.ReportError("too many arguments given to Error")
+		return
+	}
+	var errors *map[string]*design.ErrorDefinition
+	parent := dslengine.CurrentDefinition()
+	switch def := parent.(type) {
+	case *design.APIDefinition:
+		errors = &def.Errors
+	case *design.ResourceDefinition:
+		errors = &def.Errors
+	case *design.ActionDefinition:
+		errors = &def.Errors
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	att := &design.AttributeDefinition{Type: design.Object{}}
+	if len(dsl) == 1 {
+		if !dslengine.Execute(dsl[0], att) {
+			return
+		}
+	}
+	obj := att.Type.ToObject()
+	if obj == nil {
+		dslengine.ReportError("error %#v must be an object", name)
+		return
+	}
+	for n, field := range design.ErrorFields() {
+		if _, ok := obj[n]; ok {
+			dslengine.ReportError("attribute %#v of error %#v conflicts with the field common to all errors", n, name)
+			return
+		}
+		obj[n] = field
+	}
+	if att.Validation == nil {
+		att.Validation = &dslengine.ValidationDefinition{}
+	}
+	att.Validation.AddRequired([]string{"code", "status", "detail"})
+	if *errors == nil {
+		*errors = make(map[string]*design.ErrorDefinition)
+	}
+	(*errors)[name] = &design.ErrorDefinition{
+		Name:   name,
+		Status: status,
+		Type: &design.UserTypeDefinition{
+			AttributeDefinition: att,
+			TypeName:            camelize(name) + "Error",
+		},
+		Parent: parent,
+	}
+}
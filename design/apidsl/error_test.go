@@ -0,0 +1,123 @@
+package apidsl_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Error", func() {
+	var apiDSL, resourceDSL, actionDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resourceDSL = nil
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("cellar", apiDSL)
+		Resource("bottle", func() {
+			if resourceDSL != nil {
+				resourceDSL()
+			}
+			Action("show", func() {
+				Routing(GET("/:id"))
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+			Action("list", func() {
+				Routing(GET(""))
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("defined at all levels", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("unavailable", 503)
+			}
+			resourceDSL = func() {
+				Error("not_found", 404, func() {
+					Description("The bottle does not exist")
+					Attribute("id", Integer)
+					Required("id")
+				})
+			}
+			actionDSL = func() {
+				Error("locked", 423)
+			}
+		})
+
+		It("stores the errors", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			nf := Design.Resources["bottle"].Errors["not_found"]
+			Ω(nf).ShouldNot(BeNil())
+			Ω(nf.Status).Should(Equal(404))
+			Ω(nf.Description()).Should(Equal("The bottle does not exist"))
+			Ω(nf.Type.TypeName).Should(Equal("NotFoundError"))
+			Ω(nf.Type.Type.ToObject()).Should(HaveKey("id"))
+			Ω(nf.Type.Type.ToObject()).Should(HaveKey("code"))
+			Ω(nf.Type.AllRequired()).Should(ConsistOf("id", "code", "status", "detail"))
+		})
+
+		It("computes the errors returned by each action", func() {
+			var names []string
+			for _, e := range Design.Resources["bottle"].Actions["show"].AllErrors() {
+				names = append(names, e.Name)
+			}
+			Ω(names).Should(Equal([]string{"locked", "not_found", "unavailable"}))
+			names = nil
+			for _, e := range Design.Resources["bottle"].Actions["list"].AllErrors() {
+				names = append(names, e.Name)
+			}
+			Ω(names).Should(Equal([]string{"not_found", "unavailable"}))
+		})
+	})
+
+	Context("with an attribute named after a common field", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Error("not_found", 404, func() {
+					Attribute("detail", String)
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with an invalid status", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Error("not_found", 200)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with errors defined twice", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Error("not_found", 404)
+			}
+			actionDSL = func() {
+				Error("not_found", 410)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -57,10 +57,6 @@ var mediaTypeCount int
 //
 // This function returns the media type definition so it can be referred to throughout the apidsl.
 func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
-	if design.Design.MediaTypes == nil {
-		design.Design.MediaTypes = make(map[string]*design.MediaTypeDefinition)
-	}
-
 	if !dslengine.IsTopLevelDefinition() {
 		dslengine.IncompatibleDSL()
 		return nil
@@ -77,7 +73,8 @@ func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
 	}
 	canonicalID := design.CanonicalIdentifier(identifier)
 	// Validate that media type identifier doesn't clash
-	if _, ok := design.Design.MediaTypes[canonicalID]; ok {
+	pkg := currentPackage
+	if design.Design.Package(pkg).MediaType(canonicalID) != nil {
 		dslengine.ReportError("media type %#v is defined twice", identifier)
 		return nil
 	}
@@ -119,8 +116,9 @@ func MediaType(identifier string, apidsl func()) *design.MediaTypeDefinition {
 		typeName = fmt.Sprintf("MediaType%d", mediaTypeCount)
 	}
 	// Now save the type in the API media types map
-	mt := design.NewMediaTypeDefinition(typeName, identifier, apidsl)
-	design.Design.MediaTypes[canonicalID] = mt
+	mt := design.NewMediaTypeDefinition(typeName, identifier, inPackage(pkg, apidsl))
+	mt.Package = pkg
+	design.Design.RegisterMediaType(mt)
 	return mt
 
 }
@@ -192,9 +190,9 @@ func Reference(t design.DataType) {
 func TypeName(name string) {
 	switch def := dslengine.CurrentDefinition().(type) {
 	case *design.MediaTypeDefinition:
-		def.TypeName = name
+		def.TypeName = design.Design.PackageTypeName(def.Package, name)
 	case *design.UserTypeDefinition:
-		def.TypeName = name
+		def.TypeName = design.Design.PackageTypeName(def.Package, name)
 	default:
 		dslengine.IncompatibleDSL()
 	}
@@ -20,7 +20,8 @@ import (
 //
 // `struct:tag:xxx`: sets the struct field tag xxx on generated Go structs.  Overrides tags that
 // goagen would otherwise set.  If the metadata value is a slice then the strings are joined with
-// the space character as separator.
+// the space character as separator.  Fields whose json tag is set this way also get a form tag
+// with the attribute name so that form bodies keep using the attribute names.
 // Applicable to attributes only.
 //
 //        Metadata("struct:tag:json", "myName,omitempty")
@@ -0,0 +1,51 @@
+package apidsl
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// RateLimit defines the maximum number of requests each principal may make in the given period of
+// time. It may appear in API, Resource or Action. The rate limit applies to all the actions of the
+// API or resource it is defined in that do not define their own, the requests made to these
+// actions are counted together. Example:
+//
+//	var _ = API("cellar", func() {
+//		RateLimit(1000, time.Hour)
+//	})
+//
+//	var _ = Resource("bottle", func() {
+//		Action("create", func() {
+//			Routing(POST(""))
+//			RateLimit(10, time.Minute)
+//		})
+//	})
+//
+// The generated controllers enforce the rate limits using the middleware/ratelimit package and the
+// generated Swagger specification documents the 429 Too Many Requests responses.
+func RateLimit(n int, per time.Duration) {
+	if n <= 0 {
+		dslengine.ReportError("rate limit must be greater than 0, got %d", n)
+		return
+	}
+	if per <= 0 {
+		dslengine.ReportError("rate limit period must be greater than 0, got %s", per)
+		return
+	}
+	def := &design.RateLimitDefinition{Limit: n, Period: per}
+	switch parent := dslengine.CurrentDefinition().(type) {
+	case *design.ActionDefinition:
+		def.Scope = design.Design.Name + "/" + parent.Parent.Name + "#" + parent.Name
+		parent.RateLimit = def
+	case *design.ResourceDefinition:
+		def.Scope = design.Design.Name + "/" + parent.Name
+		parent.RateLimit = def
+	case *design.APIDefinition:
+		def.Scope = parent.Name
+		parent.RateLimit = def
+	default:
+		dslengine.IncompatibleDSL()
+	}
+}
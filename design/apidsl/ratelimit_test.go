@@ -0,0 +1,98 @@
+package apidsl_test
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimit", func() {
+	var apiDSL, resourceDSL, actionDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+		resourceDSL = nil
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("cellar", apiDSL)
+		Resource("bottle", func() {
+			if resourceDSL != nil {
+				resourceDSL()
+			}
+			Action("list", func() {
+				Routing(GET(""))
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+			})
+		})
+		dslengine.Run()
+	})
+
+	Context("with no rate limit", func() {
+		It("does not limit actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Resources["bottle"].Actions["list"].EffectiveRateLimit()).Should(BeNil())
+		})
+	})
+
+	Context("defined on the API", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				RateLimit(1000, time.Hour)
+			}
+		})
+
+		It("applies to all the actions", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			rl := Design.Resources["bottle"].Actions["show"].EffectiveRateLimit()
+			Ω(rl).ShouldNot(BeNil())
+			Ω(rl.Limit).Should(Equal(1000))
+			Ω(rl.Period).Should(Equal(time.Hour))
+			Ω(rl.Scope).Should(Equal("cellar"))
+		})
+	})
+
+	Context("defined on the resource and an action", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				RateLimit(100, time.Minute)
+			}
+			actionDSL = func() {
+				RateLimit(10, time.Second)
+			}
+		})
+
+		It("uses the closest definition", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			list := Design.Resources["bottle"].Actions["list"].EffectiveRateLimit()
+			Ω(list.Limit).Should(Equal(10))
+			Ω(list.Scope).Should(Equal("cellar/bottle#list"))
+			show := Design.Resources["bottle"].Actions["show"].EffectiveRateLimit()
+			Ω(show.Limit).Should(Equal(100))
+			Ω(show.Scope).Should(Equal("cellar/bottle"))
+		})
+	})
+
+	Context("with an invalid limit", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				RateLimit(0, time.Second)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
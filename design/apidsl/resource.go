@@ -14,6 +14,9 @@ import (
 // The resource dsl also allows listing the supported resource collection and resource collection
 // item actions. Each action corresponds to a specific API endpoint. See Action.
 //
+// Resource may also appear in APIVersion to define or override a resource of a specific version of
+// the API, see APIVersion.
+//
 // The resource dsl can also specify a parent resource. Parent resources have two effects.
 // First, they set the prefix of all resource action paths to the parent resource href. Note that
 // actions can override the path using an absolute path (that is a path starting with "//").
@@ -44,20 +47,32 @@ import (
 //		})
 //	})
 func Resource(name string, dsl func()) *design.ResourceDefinition {
-	if design.Design.Resources == nil {
-		design.Design.Resources = make(map[string]*design.ResourceDefinition)
+	if v, ok := dslengine.CurrentDefinition().(*design.APIVersionDefinition); ok {
+		if v.Resources == nil {
+			v.Resources = make(map[string]*design.ResourceDefinition)
+		}
+		if _, ok := v.Resources[name]; ok {
+			dslengine.ReportError("resource %#v is defined twice", name)
+			return nil
+		}
+		resource := design.NewResourceDefinition(name, dsl)
+		resource.Version = v
+		v.Resources[name] = resource
+		return resource
 	}
 	if !dslengine.IsTopLevelDefinition() {
 		dslengine.IncompatibleDSL()
 		return nil
 	}
 
-	if _, ok := design.Design.Resources[name]; ok {
+	pkg := currentPackage
+	if design.Design.Package(pkg).Resource(name) != nil {
 		dslengine.ReportError("resource %#v is defined twice", name)
 		return nil
 	}
-	resource := design.NewResourceDefinition(name, dsl)
-	design.Design.Resources[name] = resource
+	resource := design.NewResourceDefinition(name, inPackage(pkg, dsl))
+	resource.Package = pkg
+	design.Design.RegisterResource(resource)
 	return resource
 }
 
@@ -126,6 +126,8 @@ func securitySchemeRedefined(name string) bool {
 //          Header("Authorization")
 //    })
 //
+// The key may also be read from a query string parameter using Query or from a cookie using
+// Cookie.
 func APIKeySecurity(name string, dsl ...func()) *design.SecuritySchemeDefinition {
 	switch dslengine.CurrentDefinition().(type) {
 	case *design.APIDefinition, *dslengine.TopLevelDefinition:
@@ -278,7 +280,7 @@ func inHeader(headerName string) {
 	if parent, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
 		if parent.Kind == design.APIKeySecurityKind || parent.Kind == design.JWTSecurityKind {
 			if parent.In != "" {
-				dslengine.ReportError("'In' previously defined through Header or Query")
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
 				return
 			}
 			parent.In = "header"
@@ -289,13 +291,29 @@ func inHeader(headerName string) {
 	dslengine.IncompatibleDSL()
 }
 
+// inCookie is called by `Cookie()`, see documentation there.
+func inCookie(cookieName string) {
+	if parent, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
+		if parent.Kind == design.APIKeySecurityKind {
+			if parent.In != "" {
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
+				return
+			}
+			parent.In = "cookie"
+			parent.Name = cookieName
+			return
+		}
+	}
+	dslengine.IncompatibleDSL()
+}
+
 // Query defines that an APIKeySecurity or JWTSecurity implementation must check in the query
 // parameter named "parameterName" to get the api key.
 func Query(parameterName string) {
 	if parent, ok := dslengine.CurrentDefinition().(*design.SecuritySchemeDefinition); ok {
 		if parent.Kind == design.APIKeySecurityKind || parent.Kind == design.JWTSecurityKind {
 			if parent.In != "" {
-				dslengine.ReportError("'In' previously defined through Header or Query")
+				dslengine.ReportError("'In' previously defined through Header, Query or Cookie")
 				return
 			}
 			parent.In = "query"
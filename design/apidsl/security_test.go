@@ -70,6 +70,32 @@ var _ = Describe("Security", func() {
 		Ω(Design.SecuritySchemes[3].Scopes).Should(HaveLen(2))
 	})
 
+	Context("with API key security", func() {
+		It("should support keys stored in cookies", func() {
+			API("", func() {
+				APIKeySecurity("cookie_key", func() {
+					Cookie("session")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.SecuritySchemes).Should(HaveLen(1))
+			Ω(Design.SecuritySchemes[0].In).Should(Equal("cookie"))
+			Ω(Design.SecuritySchemes[0].Name).Should(Equal("session"))
+		})
+
+		It("should fail because of duplicate In declaration", func() {
+			API("", func() {
+				APIKeySecurity("broken_key", func() {
+					Header("X-Key")
+					Cookie("session")
+				})
+			})
+			dslengine.Run()
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
 	Context("with basic security", func() {
 		It("should fail because of duplicate In declaration", func() {
 			API("", func() {
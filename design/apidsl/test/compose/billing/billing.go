@@ -0,0 +1,43 @@
+// Package billing is a design package used to test the composition of designs.
+package billing
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+// Define registers the billing design definitions.
+func Define() {
+	DesignPackage("github.com/goadesign/goa/design/apidsl/test/compose/billing", func() {
+		Type("Address", func() {
+			Attribute("street", String)
+		})
+		Type("Invoice", func() {
+			Attribute("amount", Number)
+			Attribute("address", "Address")
+		})
+		BillMedia := MediaType("application/vnd.bill+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+		})
+		Resource("invoice", func() {
+			BasePath("/invoices")
+			DefaultMedia(BillMedia)
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Response(OK)
+			})
+		})
+		Resource("health", func() {
+			BasePath("/billing_health")
+			Action("check", func() {
+				Routing(GET(""))
+				Response(OK)
+			})
+		})
+	})
+}
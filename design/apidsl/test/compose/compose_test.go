@@ -0,0 +1,165 @@
+package compose_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/design/apidsl/test/compose/billing"
+	"github.com/goadesign/goa/design/apidsl/test/compose/shipping"
+	"github.com/goadesign/goa/dslengine"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	billingPath  = "github.com/goadesign/goa/design/apidsl/test/compose/billing"
+	shippingPath = "github.com/goadesign/goa/design/apidsl/test/compose/shipping"
+)
+
+var _ = Describe("Import", func() {
+	var apiDSL func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		apiDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("acme", func() {
+			BasePath("/api")
+			if apiDSL != nil {
+				apiDSL()
+			}
+		})
+		billing.Define()
+		shipping.Define()
+		dslengine.Run()
+	})
+
+	Context("with no import", func() {
+		It("reports the conflicts", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`type "Address" is defined by both design packages "` + billingPath + `" and "` + shippingPath + `"`))
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`resource "health" is defined by both design packages "` + billingPath + `" and "` + shippingPath + `"`))
+		})
+
+		It("records the package of each definition", func() {
+			Ω(Design.Packages).Should(HaveLen(2))
+			Ω(Design.Packages[billingPath].Types).Should(HaveLen(2))
+			Ω(Design.Packages[billingPath].MediaTypes).Should(HaveLen(1))
+			Ω(Design.Packages[shippingPath].Resources).Should(HaveLen(2))
+			Ω(Design.Resources["shipment"].Package).Should(Equal(shippingPath))
+		})
+	})
+
+	Context("with a namespace, a base path and a subset of resources", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Import(billingPath, func() {
+					Namespace("Billing")
+					BasePath("/billing")
+					Resources("invoice")
+				})
+			}
+		})
+
+		It("composes the designs", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			Ω(Design.Types).Should(HaveLen(3))
+			Ω(Design.Types).Should(HaveKey("BillingAddress"))
+			Ω(Design.Types).Should(HaveKey("BillingInvoice"))
+			Ω(Design.Types["Address"].Package).Should(Equal(shippingPath))
+			invoice := Design.Types["BillingInvoice"].Type.ToObject()
+			Ω(invoice["address"].Type).Should(Equal(Design.Types["BillingAddress"]))
+			shipment := Design.Resources["shipment"].Actions["create"]
+			Ω(shipment.Payload.TypeName).Should(Equal("CreateShipmentPayload"))
+			Ω(shipment.Payload.Type.ToObject()).Should(HaveKey("city"))
+			mt := Design.MediaTypeWithIdentifier("application/vnd.bill+json")
+			Ω(mt).ShouldNot(BeNil())
+			Ω(mt.TypeName).Should(Equal("BillingBill"))
+		})
+
+		It("exposes the imported resources under the import base path", func() {
+			Ω(Design.Resources).Should(HaveLen(3))
+			Ω(Design.Resources["health"].Package).Should(Equal(shippingPath))
+			Ω(Design.Resources["invoice"].FullPath()).Should(Equal("/api/billing/invoices"))
+			Ω(Design.Resources["shipment"].FullPath()).Should(Equal("/api/shipments"))
+		})
+	})
+
+	Context("with an unknown resource", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Import(billingPath, func() {
+					Namespace("Billing")
+					Resources("invoice", "refund")
+				})
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`design package "` + billingPath + `" does not define resource "refund"`))
+		})
+	})
+
+	Context("with a package that defines nothing", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Import("github.com/acme/unknown/design")
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring(
+				`design package "github.com/acme/unknown/design" does not define any resource, type or media type`))
+		})
+	})
+
+	Context("with a package imported twice", func() {
+		BeforeEach(func() {
+			apiDSL = func() {
+				Import(billingPath)
+				Import(billingPath)
+			}
+		})
+
+		It("reports an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+			Ω(dslengine.Errors.Error()).Should(ContainSubstring("is imported twice"))
+		})
+	})
+})
+
+var _ = Describe("DesignPackage", func() {
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	It("records the package of the definitions it contains only", func() {
+		API("acme", func() {})
+		var inside, outside *UserTypeDefinition
+		DesignPackage(billingPath, func() {
+			inside = Type("Inside", func() {
+				Attribute("a", String)
+			})
+		})
+		outside = Type("Outside", func() {
+			Attribute("b", String)
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		Ω(inside.Package).Should(Equal(billingPath))
+		Ω(outside.Package).Should(BeEmpty())
+	})
+
+	It("cannot be nested", func() {
+		DesignPackage(billingPath, func() {
+			DesignPackage(shippingPath, func() {})
+		})
+		Ω(dslengine.Errors).Should(HaveOccurred())
+		Ω(dslengine.Errors.Error()).Should(ContainSubstring("cannot appear in DesignPackage"))
+	})
+})
@@ -0,0 +1,3 @@
+// Package compose contains a DSL test composing the designs of the billing and shipping packages.
+// This file is needed for `go get ./...` and thus the build to succeed.
+package compose
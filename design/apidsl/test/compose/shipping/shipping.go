@@ -0,0 +1,31 @@
+// Package shipping is a design package used to test the composition of designs.
+package shipping
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+
+// Define registers the shipping design definitions.
+func Define() {
+	DesignPackage("github.com/goadesign/goa/design/apidsl/test/compose/shipping", func() {
+		Type("Address", func() {
+			Attribute("city", String)
+		})
+		Resource("shipment", func() {
+			BasePath("/shipments")
+			Action("create", func() {
+				Routing(POST(""))
+				Payload("Address")
+				Response(OK)
+			})
+		})
+		Resource("health", func() {
+			BasePath("/shipping_health")
+			Action("check", func() {
+				Routing(GET(""))
+				Response(OK)
+			})
+		})
+	})
+}
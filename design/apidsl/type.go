@@ -28,9 +28,8 @@ import (
 //
 // This function returns the newly defined type so the value can be used throughout the dsl.
 func Type(name string, dsl func()) *design.UserTypeDefinition {
-	if design.Design.Types == nil {
-		design.Design.Types = make(map[string]*design.UserTypeDefinition)
-	} else if _, ok := design.Design.Types[name]; ok {
+	pkg := currentPackage
+	if design.Design.Package(pkg).UserType(name) != nil {
 		dslengine.ReportError("type %#v defined twice", name)
 		return nil
 	}
@@ -42,15 +41,106 @@ func Type(name string, dsl func()) *design.UserTypeDefinition {
 
 	t := &design.UserTypeDefinition{
 		TypeName:            name,
-		AttributeDefinition: &design.AttributeDefinition{DSLFunc: dsl},
+		AttributeDefinition: &design.AttributeDefinition{DSLFunc: inPackage(pkg, dsl)},
+		Package:             pkg,
 	}
 	if dsl == nil {
 		t.Type = design.String
 	}
-	design.Design.Types[name] = t
+	design.Design.RegisterType(t)
 	return t
 }
 
+// Extend adds the attributes of the given type or media type to the type being defined. The
+// required attributes and validations of the base type are also inherited. Extend may appear in
+// Type, MediaType Attributes or any DSL that defines child attributes. Attributes defined in the
+// extending type override base attributes with the same name, their properties default to the
+// base attribute properties in the same way they do with Reference. Example:
+//
+//	var Audit = Type("Audit", func() {
+//		Attribute("created_at", DateTime)
+//		Attribute("created_by", String)
+//		Required("created_at")
+//	})
+//
+//	var Bottle = Type("Bottle", func() {
+//		Extend(Audit)
+//		Attribute("name", String)
+//		Required("name")
+//	})
+//
+// The generated Bottle struct includes the created_at and created_by fields and the Bottle JSON
+// schema references the Audit schema via "allOf".
+func Extend(t design.DataType) {
+	var parent *design.AttributeDefinition
+	switch def := dslengine.CurrentDefinition().(type) {
+	case *design.AttributeDefinition:
+		parent = def
+	case *design.MediaTypeDefinition:
+		parent = def.AttributeDefinition
+	case design.ContainerDefinition:
+		parent = def.Attribute()
+	default:
+		dslengine.IncompatibleDSL()
+		return
+	}
+	var base *design.AttributeDefinition
+	switch actual := t.(type) {
+	case *design.MediaTypeDefinition:
+		if actual.AttributeDefinition == parent {
+			dslengine.ReportError("media type %#v cannot extend itself", actual.Identifier)
+			return
+		}
+		executeBase(actual.AttributeDefinition, actual)
+		base = actual.AttributeDefinition
+	case *design.UserTypeDefinition:
+		if actual.AttributeDefinition == parent {
+			dslengine.ReportError("type %#v cannot extend itself", actual.TypeName)
+			return
+		}
+		executeBase(actual.AttributeDefinition, actual.AttributeDefinition)
+		base = actual.AttributeDefinition
+	default:
+		dslengine.ReportError("invalid Extend argument, must be a type or a media type")
+		return
+	}
+	if base.Type == nil || base.Type.Kind() != design.ObjectKind {
+		dslengine.ReportError("cannot extend %s, base type must be an object", t.Name())
+		return
+	}
+	if parent.Type == nil {
+		parent.Type = design.Object{}
+	}
+	if parent.Type.Kind() != design.ObjectKind {
+		incompatibleAttributeType("extend", parent.Type.Name(), "an object")
+		return
+	}
+	merged := &design.AttributeDefinition{Type: design.Object{}}
+	merged.Merge(base).Merge(parent)
+	parent.Type = merged.Type
+	parent.Inherit(base)
+	if base.Validation != nil {
+		if parent.Validation == nil {
+			parent.Validation = &dslengine.ValidationDefinition{}
+		}
+		parent.Validation.Merge(base.Validation)
+	}
+	parent.Bases = append(parent.Bases, t)
+}
+
+// executeBase runs the DSL of the given base type if it hasn't run yet so that its attributes
+// may be extended regardless of the order in which types are defined.
+func executeBase(base *design.AttributeDefinition, def dslengine.Definition) {
+	if base.Type != nil && len(base.Type.ToObject()) > 0 {
+		return
+	}
+	if dsl := base.DSLFunc; dsl != nil {
+		// Make sure the DSL does not run a second time when the runner gets to it.
+		base.DSLFunc = nil
+		dslengine.Execute(dsl, def)
+	}
+}
+
 // ArrayOf creates an array type from its element type. The result can be used anywhere a type can.
 // Examples:
 //
@@ -94,3 +184,69 @@ func HashOf(k, v design.DataType) *design.Hash {
 	vat := design.AttributeDefinition{Type: v}
 	return &design.Hash{KeyType: &kat, ElemType: &vat}
 }
+
+// OneOf creates a type whose values may be any one of the given variants. The variant a value
+// uses is identified by the value of the discriminator attribute. The result can be used anywhere
+// a type can. Variants are object user types, they must not define the discriminator attribute
+// and may not define attributes with identical names. Example:
+//
+//	var CardPayment = Type("CardPayment", func() {
+//		Attribute("number", String)
+//		Required("number")
+//	})
+//
+//	var BankPayment = Type("BankPayment", func() {
+//		Attribute("iban", String)
+//		Required("iban")
+//	})
+//
+//	var PaymentMethod = OneOf("type",
+//		Variant("card", CardPayment),
+//		Variant("bank", BankPayment),
+//	)
+//
+//	Action("pay", func() {
+//		Payload(func() {
+//			Member("method", PaymentMethod)   // e.g. {"method":{"type":"card","number":"4242..."}}
+//		})
+//	})
+func OneOf(discriminator string, variants ...*design.UnionVariant) *design.Union {
+	return &design.Union{Discriminator: discriminator, Variants: variants}
+}
+
+// Variant defines a OneOf variant. value is the value of the discriminator attribute that
+// identifies the variant, see OneOf.
+func Variant(value string, t *design.UserTypeDefinition) *design.UnionVariant {
+	return &design.UnionVariant{Value: value, Type: t}
+}
+
+// currentPackage is the import path of the design package whose definitions are being declared or
+// executed, the empty string for the package that defines the API.
+var currentPackage string
+
+// inPackage returns a DSL that runs dsl with currentPackage set to pkg so that the type names
+// used by the DSL of a definition made with DesignPackage resolve to the types of the same
+// package.
+func inPackage(pkg string, dsl func()) func() {
+	if pkg == "" || dsl == nil {
+		return dsl
+	}
+	return func() {
+		prev := currentPackage
+		currentPackage = pkg
+		defer func() { currentPackage = prev }()
+		dsl()
+	}
+}
+
+// lookupType returns the user type with the given name as seen by the design package that defines
+// the DSL being executed: the namespaced types of the package take precedence.
+func lookupType(name string) (*design.UserTypeDefinition, bool) {
+	if len(design.Design.Imports) > 0 {
+		if ut, ok := design.Design.Types[design.Design.PackageTypeName(currentPackage, name)]; ok {
+			return ut, true
+		}
+	}
+	ut, ok := design.Design.Types[name]
+	return ut, ok
+}
@@ -107,3 +107,133 @@ var _ = Describe("Type", func() {
 		})
 	})
 })
+
+var _ = Describe("OneOf", func() {
+	var union *Union
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		card := Type("card", func() {
+			Attribute("number")
+		})
+		bank := Type("bank", func() {
+			Attribute("iban")
+		})
+		union = OneOf("type", Variant("card", card), Variant("bank", bank))
+	})
+
+	JustBeforeEach(func() {
+		Type("payment", func() {
+			Attribute("method", union)
+		})
+		dslengine.Run()
+	})
+
+	It("produces a union type", func() {
+		Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+		Ω(union.Discriminator).Should(Equal("type"))
+		Ω(union.Variants).Should(HaveLen(2))
+		Ω(union.Variant("bank").Type.TypeName).Should(Equal("bank"))
+		method := Design.Types["payment"].Type.ToObject()["method"]
+		Ω(method.Type).Should(Equal(union))
+		Ω(method.Type.ToObject()).Should(HaveKey("type"))
+		Ω(method.Type.ToObject()).Should(HaveKey("number"))
+		Ω(method.Type.ToObject()).Should(HaveKey("iban"))
+		Ω(method.Type.IsCompatible(nil)).Should(BeFalse())
+		Ω(method.Type.IsCompatible(map[string]interface{}{"type": "card"})).Should(BeTrue())
+	})
+
+	Context("with variants defining the same attribute", func() {
+		BeforeEach(func() {
+			other := Type("other", func() {
+				Attribute("number")
+			})
+			union.Variants = append(union.Variants, Variant("other", other))
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+
+	Context("with a variant defining the discriminator", func() {
+		BeforeEach(func() {
+			other := Type("other", func() {
+				Attribute("type")
+			})
+			union.Variants = append(union.Variants, Variant("other", other))
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("Extend", func() {
+	var base, extended *UserTypeDefinition
+	var dsl func()
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		dsl = nil
+	})
+
+	JustBeforeEach(func() {
+		// Define the extending type first to make sure the base DSL may run in any order.
+		extended = Type("audited", dsl)
+		base = Type("zaudit", func() {
+			Attribute("created_at", DateTime)
+			Attribute("created_by", String, func() {
+				MinLength(2)
+			})
+			Required("created_at")
+		})
+		dslengine.Run()
+	})
+
+	Context("with a base type", func() {
+		BeforeEach(func() {
+			dsl = func() {
+				Extend(base)
+				Attribute("name")
+				Attribute("created_by", String, "creator")
+				Required("name")
+			}
+		})
+
+		It("merges the base attributes", func() {
+			Ω(dslengine.Errors).ShouldNot(HaveOccurred())
+			o := extended.Type.ToObject()
+			Ω(o).Should(HaveLen(3))
+			Ω(o).Should(HaveKey("name"))
+			Ω(o["created_at"]).Should(Equal(base.Type.ToObject()["created_at"]))
+			Ω(extended.Bases).Should(Equal([]DataType{base}))
+		})
+
+		It("inherits the base required attributes", func() {
+			Ω(extended.Validation.Required).Should(ConsistOf("name", "created_at"))
+		})
+
+		It("uses the base attribute properties for redefined attributes", func() {
+			att := extended.Type.ToObject()["created_by"]
+			Ω(att).ShouldNot(Equal(base.Type.ToObject()["created_by"]))
+			Ω(att.Description).Should(Equal("creator"))
+			Ω(att.Validation).ShouldNot(BeNil())
+			Ω(*att.Validation.MinLength).Should(Equal(2))
+		})
+	})
+
+	Context("with a base type that is not an object", func() {
+		BeforeEach(func() {
+			str := Type("str", nil)
+			dsl = func() {
+				Extend(str)
+			}
+		})
+
+		It("produces an error", func() {
+			Ω(dslengine.Errors).Should(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,313 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+type (
+	// ImportDefinition describes a design package imported by the API with Import. The
+	// definitions of an imported package may be namespaced, exposed under a common base path
+	// or restricted to a subset of the package resources.
+	ImportDefinition struct {
+		// Path is the import path of the design package.
+		Path string
+		// Namespace is the prefix added to the names of the package user types and media
+		// types.
+		Namespace string
+		// BasePath is the common base path of the package resources, it is appended to
+		// the API base path.
+		BasePath string
+		// Resources lists the names of the package resources exposed by the API, all the
+		// package resources are exposed if empty.
+		Resources []string
+		// Parent is the API definition.
+		Parent *APIDefinition
+		// DSLFunc contains the DSL used to create this definition if any.
+		DSLFunc func()
+	}
+
+	// PackageDefinition lists the top level definitions registered by a design package.
+	PackageDefinition struct {
+		// Path is the import path of the design package.
+		Path string
+		// Resources lists the package resources in order of definition.
+		Resources []*ResourceDefinition
+		// Types lists the package user types in order of definition.
+		Types []*UserTypeDefinition
+		// MediaTypes lists the package media types in order of definition.
+		MediaTypes []*MediaTypeDefinition
+	}
+)
+
+// Context returns the generic definition name used in error messages.
+func (i *ImportDefinition) Context() string {
+	return fmt.Sprintf("import of %#v", i.Path)
+}
+
+// DSL returns the initialization DSL.
+func (i *ImportDefinition) DSL() func() {
+	return i.DSLFunc
+}
+
+// Package returns the definitions registered by the design package with the given import path,
+// creating them if needed.
+func (a *APIDefinition) Package(path string) *PackageDefinition {
+	if a.Packages == nil {
+		a.Packages = make(map[string]*PackageDefinition)
+	}
+	pkg, ok := a.Packages[path]
+	if !ok {
+		pkg = &PackageDefinition{Path: path}
+		a.Packages[path] = pkg
+	}
+	return pkg
+}
+
+// PackageImport returns the import of the design package with the given import path, nil if the
+// API does not import the package.
+func (a *APIDefinition) PackageImport(path string) *ImportDefinition {
+	if path == "" {
+		return nil
+	}
+	for _, imp := range a.Imports {
+		if imp.Path == path {
+			return imp
+		}
+	}
+	return nil
+}
+
+// PackageTypeName returns the name of the user type or media type with the given name defined by
+// the design package with the given import path once the import namespace is applied.
+func (a *APIDefinition) PackageTypeName(path, name string) string {
+	if imp := a.PackageImport(path); imp != nil {
+		return imp.Namespace + name
+	}
+	return name
+}
+
+// RegisterType records the given user type defined at the top level of the design package
+// ut.Package. The type is added to the API types unless another design package already defines a
+// type with the same name in which case the conflict is resolved once the API imports are known.
+func (a *APIDefinition) RegisterType(ut *UserTypeDefinition) {
+	pkg := a.Package(ut.Package)
+	pkg.Types = append(pkg.Types, ut)
+	if a.Types == nil {
+		a.Types = make(map[string]*UserTypeDefinition)
+	}
+	if _, ok := a.Types[ut.TypeName]; !ok {
+		a.Types[ut.TypeName] = ut
+	}
+}
+
+// RegisterMediaType records the given media type defined at the top level of the design package
+// mt.Package, see RegisterType.
+func (a *APIDefinition) RegisterMediaType(mt *MediaTypeDefinition) {
+	pkg := a.Package(mt.Package)
+	pkg.MediaTypes = append(pkg.MediaTypes, mt)
+	if a.MediaTypes == nil {
+		a.MediaTypes = make(map[string]*MediaTypeDefinition)
+	}
+	id := CanonicalIdentifier(mt.Identifier)
+	if _, ok := a.MediaTypes[id]; !ok {
+		a.MediaTypes[id] = mt
+	}
+}
+
+// RegisterResource records the given resource defined at the top level of the design package
+// r.Package, see RegisterType.
+func (a *APIDefinition) RegisterResource(r *ResourceDefinition) {
+	pkg := a.Package(r.Package)
+	pkg.Resources = append(pkg.Resources, r)
+	if a.Resources == nil {
+		a.Resources = make(map[string]*ResourceDefinition)
+	}
+	if _, ok := a.Resources[r.Name]; !ok {
+		a.Resources[r.Name] = r
+	}
+}
+
+// compose applies the API imports to the definitions registered by the design packages: it
+// prefixes the names of the types and media types of namespaced packages, removes the resources
+// that are not exposed and reports the conflicts between definitions of different packages that
+// remain. compose runs once, after the API DSL has executed.
+func (a *APIDefinition) compose() {
+	if a.composed {
+		return
+	}
+	a.composed = true
+	excluded := make(map[*ResourceDefinition]bool)
+	for _, imp := range a.Imports {
+		pkg, ok := a.Packages[imp.Path]
+		if !ok {
+			reportComposeError("design package %#v does not define any resource, type or media type, make sure it is imported", imp.Path)
+			continue
+		}
+		if imp.Namespace != "" {
+			for _, ut := range pkg.Types {
+				if a.Types[ut.TypeName] == ut {
+					delete(a.Types, ut.TypeName)
+				}
+				ut.TypeName = imp.Namespace + ut.TypeName
+			}
+			for _, mt := range pkg.MediaTypes {
+				mt.TypeName = imp.Namespace + mt.TypeName
+			}
+		}
+		if len(imp.Resources) == 0 {
+			continue
+		}
+		exposed := make(map[string]bool, len(imp.Resources))
+		for _, n := range imp.Resources {
+			exposed[n] = true
+		}
+		for _, r := range pkg.Resources {
+			if exposed[r.Name] {
+				delete(exposed, r.Name)
+				continue
+			}
+			excluded[r] = true
+			if a.Resources[r.Name] == r {
+				delete(a.Resources, r.Name)
+			}
+		}
+		for _, n := range imp.Resources {
+			if exposed[n] {
+				reportComposeError("design package %#v does not define resource %#v", imp.Path, n)
+			}
+		}
+	}
+
+	paths := make([]string, 0, len(a.Packages))
+	for p := range a.Packages {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		pkg := a.Packages[p]
+		for _, ut := range pkg.Types {
+			if other, ok := a.Types[ut.TypeName]; !ok {
+				a.Types[ut.TypeName] = ut
+			} else if other != ut {
+				reportConflict("type", ut.TypeName, other.Package, ut.Package, "use Import with Namespace to disambiguate")
+			}
+		}
+		for _, mt := range pkg.MediaTypes {
+			id := CanonicalIdentifier(mt.Identifier)
+			if other, ok := a.MediaTypes[id]; !ok {
+				a.MediaTypes[id] = mt
+			} else if other != mt {
+				reportConflict("media type", mt.Identifier, other.Package, mt.Package, "media types must have distinct identifiers")
+			}
+		}
+		for _, r := range pkg.Resources {
+			if excluded[r] {
+				continue
+			}
+			if other, ok := a.Resources[r.Name]; !ok {
+				a.Resources[r.Name] = r
+			} else if other != r {
+				reportConflict("resource", r.Name, other.Package, r.Package, "use Import with Resources to expose only one of them")
+			}
+		}
+	}
+
+	// User types and media types share the same Go namespace.
+	for _, p := range paths {
+		for _, mt := range a.Packages[p].MediaTypes {
+			ut, ok := a.Types[mt.TypeName]
+			if ok && ut.Package != mt.Package && a.MediaTypes[CanonicalIdentifier(mt.Identifier)] == mt {
+				reportComposeError("media type %#v of design package %#v and type %#v of design package %#v have the same name, use Import with Namespace to disambiguate",
+					mt.Identifier, mt.Package, ut.TypeName, ut.Package)
+			}
+		}
+	}
+}
+
+// Restrict removes the resources that are not listed in names from the API and its versions so
+// that the code generators produce a sub-API made of the given resources. The types and media types
+// are kept. Restrict returns an error if a name is not the name of a resource or if a listed
+// resource has a parent resource that is not listed.
+func (a *APIDefinition) Restrict(names ...string) error {
+	kept := make(map[string]bool, len(names))
+	for _, n := range names {
+		kept[n] = true
+	}
+	all := []map[string]*ResourceDefinition{a.Resources}
+	for _, v := range a.Versions {
+		all = append(all, v.Resources)
+	}
+	for _, n := range names {
+		found := false
+		for _, resources := range all {
+			r, ok := resources[n]
+			if !ok {
+				continue
+			}
+			found = true
+			if r.ParentName != "" && !kept[r.ParentName] {
+				return fmt.Errorf("resource %#v has parent resource %#v which must also be listed", n, r.ParentName)
+			}
+		}
+		if !found {
+			return fmt.Errorf("API %#v does not define resource %#v", a.Name, n)
+		}
+	}
+	for _, resources := range all {
+		for n := range resources {
+			if !kept[n] {
+				delete(resources, n)
+			}
+		}
+	}
+	return nil
+}
+
+// UserType returns the user type with the given name defined by the package, nil if there isn't
+// one.
+func (p *PackageDefinition) UserType(name string) *UserTypeDefinition {
+	for _, ut := range p.Types {
+		if ut.TypeName == name {
+			return ut
+		}
+	}
+	return nil
+}
+
+// MediaType returns the media type with the given identifier defined by the package, nil if there
+// isn't one.
+func (p *PackageDefinition) MediaType(identifier string) *MediaTypeDefinition {
+	id := CanonicalIdentifier(identifier)
+	for _, mt := range p.MediaTypes {
+		if CanonicalIdentifier(mt.Identifier) == id {
+			return mt
+		}
+	}
+	return nil
+}
+
+// Resource returns the resource with the given name defined by the package, nil if there isn't
+// one.
+func (p *PackageDefinition) Resource(name string) *ResourceDefinition {
+	for _, r := range p.Resources {
+		if r.Name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+// reportConflict records an error describing two definitions of different design packages that
+// have the same name.
+func reportConflict(kind, name, pkg1, pkg2, hint string) {
+	reportComposeError("%s %#v is defined by both design packages %#v and %#v, %s",
+		kind, name, pkg1, pkg2, hint)
+}
+
+// reportComposeError records a design composition error.
+func reportComposeError(format string, vals ...interface{}) {
+	dslengine.Errors = append(dslengine.Errors, &dslengine.Error{GoError: fmt.Errorf(format, vals...)})
+}
@@ -6,6 +6,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/dimfeld/httppath"
 	"github.com/goadesign/goa/dslengine"
@@ -46,6 +47,18 @@ type (
 		Docs *DocsDefinition
 		// Resources is the set of exposed resources indexed by name
 		Resources map[string]*ResourceDefinition
+		// Versions lists the API versions defined with APIVersion in order of definition
+		Versions []*APIVersionDefinition
+		// VersionHeader is the name of the request header that selects the API version if any
+		VersionHeader string
+		// VersionParam is the name of the Accept header media type parameter that selects the
+		// API version if any
+		VersionParam string
+		// Imports lists the design packages imported with Import in order of definition
+		Imports []*ImportDefinition
+		// Packages lists the definitions registered by each design package indexed by
+		// import path
+		Packages map[string]*PackageDefinition
 		// Types indexes the user defined types by name
 		Types map[string]*UserTypeDefinition
 		// MediaTypes indexes the API media types by canonical identifier
@@ -71,9 +84,37 @@ type (
 		// resources and actions, unless overridden by Resource or
 		// Action-level Security() calls.
 		Security *SecurityDefinition
+		// RateLimit defines the rate limit shared by all the actions unless overridden by
+		// Resource or Action-level RateLimit() calls.
+		RateLimit *RateLimitDefinition
+		// Errors lists the typed errors that may be returned by all the API actions indexed
+		// by name.
+		Errors map[string]*ErrorDefinition
 
 		// rand is the random generator used to generate examples.
 		rand *RandomGenerator
+		// composed is true once the design packages definitions have been composed.
+		composed bool
+	}
+
+	// APIVersionDefinition describes a version of the API. A version exposes the resources of
+	// the previous version - or of the API for the first version - and may override them or
+	// define new ones.
+	APIVersionDefinition struct {
+		// Name of version, e.g. "v2"
+		Name string
+		// Description of version
+		Description string
+		// BasePath is the common base path to all the version endpoints if different from
+		// the API base path
+		BasePath string
+		// Previous is the version this version inherits resources from, nil if the version
+		// inherits the API resources
+		Previous *APIVersionDefinition
+		// Resources is the set of resources defined by the version indexed by name
+		Resources map[string]*ResourceDefinition
+		// DSLFunc contains the DSL used to create this definition if any
+		DSLFunc func()
 	}
 
 	// ContactDefinition contains the API contact information.
@@ -132,6 +173,11 @@ type (
 		Headers *AttributeDefinition
 		// Origins defines the CORS policies that apply to this resource.
 		Origins map[string]*CORSDefinition
+		// Version is the API version that defines the resource, nil if the resource is
+		// defined by the API.
+		Version *APIVersionDefinition
+		// Package is the import path of the design package that defines the resource.
+		Package string
 		// DSLFunc contains the DSL used to create this definition if any.
 		DSLFunc func()
 		// metadata is a list of key/value pairs
@@ -139,6 +185,15 @@ type (
 		// Security defines security requirements for the Resource,
 		// for actions that don't define one themselves.
 		Security *SecurityDefinition
+		// RateLimit defines the rate limit shared by the resource actions that don't define
+		// one themselves.
+		RateLimit *RateLimitDefinition
+		// Errors lists the typed errors that may be returned by the resource actions indexed
+		// by name.
+		Errors map[string]*ErrorDefinition
+		// api is the API that exposes the resource if not Design, see
+		// APIVersionDefinition.API.
+		api *APIDefinition
 	}
 
 	// CORSDefinition contains the definition for a specific origin CORS policy.
@@ -234,12 +289,50 @@ type (
 		QueryParams *AttributeDefinition
 		// Payload blueprint (request body) if any
 		Payload *UserTypeDefinition
+		// PayloadMultipart if true indicates that the request payload is encoded using a
+		// multipart form, see MultipartForm.
+		PayloadMultipart bool
 		// Request headers that need to be made available to action
 		Headers *AttributeDefinition
+		// Request cookies that need to be made available to action
+		Cookies *AttributeDefinition
 		// Metadata is a list of key/value pairs
 		Metadata dslengine.MetadataDefinition
 		// Security defines security requirements for the action
 		Security *SecurityDefinition
+		// Deprecation is set when the action is deprecated
+		Deprecation *DeprecationDefinition
+		// Pagination describes how the action responses are paginated if they are
+		Pagination *PaginationDefinition
+		// Fields describes the field selection supported by the action if any
+		Fields *FieldsDefinition
+		// Stream describes the server-sent events sent by the action if it streams them
+		Stream *StreamDefinition
+		// Messages describes the messages exchanged by the WebSocket action if it declares them
+		Messages *MessagesDefinition
+		// Cacheable is true if the action supports HTTP conditional requests
+		Cacheable bool
+		// RateLimit defines the rate limit of the action if any
+		RateLimit *RateLimitDefinition
+		// Errors lists the typed errors that may be returned by the action indexed by name.
+		Errors map[string]*ErrorDefinition
+	}
+
+	// StreamDefinition describes the server-sent events (text/event-stream) sent by a
+	// streaming action.
+	StreamDefinition struct {
+		// MediaType is the identifier of the media type of the events data.
+		MediaType string
+		// Event is the value of the event field of the events, empty to use the default
+		// "message" event type.
+		Event string
+	}
+
+	// DeprecationDefinition describes the deprecation of an action or an attribute.
+	DeprecationDefinition struct {
+		// Sunset is the date after which the action or attribute may be removed, zero if
+		// not specified.
+		Sunset time.Time
 	}
 
 	// LinkDefinition defines a media type link, it specifies a URL to a related resource.
@@ -285,6 +378,8 @@ type (
 		Type DataType
 		// Attribute reference type if any
 		Reference DataType
+		// Bases lists the types whose attributes the attribute type extends if any
+		Bases []DataType
 		// Optional description
 		Description string
 		// Optional validations
@@ -297,6 +392,8 @@ type (
 		Example interface{}
 		// Optional view used to render Attribute (only applies to media type attributes).
 		View string
+		// Deprecation is set when the attribute is deprecated
+		Deprecation *DeprecationDefinition
 		// NonZeroAttributes lists the names of the child attributes that cannot have a
 		// zero value (and thus whose presence does not need to be validated).
 		NonZeroAttributes map[string]bool
@@ -417,13 +514,16 @@ func (a *APIDefinition) DependsOn() []dslengine.Root {
 	return nil
 }
 
-// IterateSets calls the given iterator possing in the API definition, user types, media types and
-// finally resources.
+// IterateSets calls the given iterator possing in the API definition, user types, media types,
+// resources and finally API versions.
 func (a *APIDefinition) IterateSets(iterator dslengine.SetIterator) {
 	// First run the top level API DSL to initialize responses and
 	// response templates needed by resources.
 	iterator([]dslengine.Definition{a})
 
+	// Apply the imports now that the API DSL has run.
+	a.compose()
+
 	// Then run the user type DSLs
 	typeAttributes := make([]dslengine.Definition, len(a.Types))
 	i := 0
@@ -463,6 +563,25 @@ func (a *APIDefinition) IterateSets(iterator dslengine.SetIterator) {
 		return nil
 	})
 	iterator(resources)
+
+	// Finally the API versions followed by the resources they define.
+	versions := make([]dslengine.Definition, len(a.Versions))
+	for i, v := range a.Versions {
+		versions[i] = v
+	}
+	iterator(versions)
+	var versionResources []dslengine.Definition
+	for _, v := range a.Versions {
+		names := make([]string, 0, len(v.Resources))
+		for n := range v.Resources {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			versionResources = append(versionResources, v.Resources[n])
+		}
+	}
+	iterator(versionResources)
 }
 
 // Reset sets all the API definition fields to their zero value except the default responses and
@@ -621,6 +740,64 @@ func (a *APIDefinition) Finalize() {
 	})
 }
 
+// Context returns the generic definition name used in error messages.
+func (v *APIVersionDefinition) Context() string {
+	if v.Name != "" {
+		return fmt.Sprintf("API version %#v", v.Name)
+	}
+	return "unnamed API version"
+}
+
+// DSL returns the initialization DSL.
+func (v *APIVersionDefinition) DSL() func() {
+	return v.DSLFunc
+}
+
+// SelectedByPath returns true if the version defines a base path different from the API base
+// path so that requests select the version with their path. The other versions are selected with
+// the API VersionHeader or VersionParam.
+func (v *APIVersionDefinition) SelectedByPath() bool {
+	return v.BasePath != "" && v.BasePath != Design.BasePath
+}
+
+// AllResources returns the resources exposed by the version indexed by name: the resources
+// inherited from the previous version overridden by the resources defined by the version.
+func (v *APIVersionDefinition) AllResources() map[string]*ResourceDefinition {
+	inherited := Design.Resources
+	if v.Previous != nil {
+		inherited = v.Previous.AllResources()
+	}
+	all := make(map[string]*ResourceDefinition, len(inherited)+len(v.Resources))
+	for n, r := range inherited {
+		all[n] = r
+	}
+	for n, r := range v.Resources {
+		all[n] = r
+	}
+	return all
+}
+
+// API returns a copy of the API definition that describes the version: the copy exposes copies of
+// the version resources whose paths are computed using the version base path. Code generators use
+// it in place of Design to generate the code of a version.
+func (v *APIVersionDefinition) API() *APIDefinition {
+	api := *Design
+	api.Version = v.Name
+	if v.Description != "" {
+		api.Description = v.Description
+	}
+	if v.BasePath != "" {
+		api.BasePath = v.BasePath
+	}
+	all := v.AllResources()
+	api.Resources = make(map[string]*ResourceDefinition, len(all))
+	for n, r := range all {
+		api.Resources[n] = r.exposedBy(&api)
+	}
+	api.Versions = nil
+	return &api
+}
+
 // NewResourceDefinition creates a resource definition but does not
 // execute the DSL.
 func NewResourceDefinition(name string, dsl func()) *ResourceDefinition {
@@ -680,6 +857,26 @@ func (r *ResourceDefinition) URITemplate() string {
 	return ca.Routes[0].FullPath()
 }
 
+// exposedBy returns a copy of the resource, of its actions and of their routes whose paths are
+// computed using the given API.
+func (r *ResourceDefinition) exposedBy(api *APIDefinition) *ResourceDefinition {
+	res := *r
+	res.api = api
+	res.Actions = make(map[string]*ActionDefinition, len(r.Actions))
+	for n, a := range r.Actions {
+		action := *a
+		action.Parent = &res
+		action.Routes = make([]*RouteDefinition, len(a.Routes))
+		for i, route := range a.Routes {
+			rt := *route
+			rt.Parent = &action
+			action.Routes[i] = &rt
+		}
+		res.Actions[n] = &action
+	}
+	return &res
+}
+
 // FullPath computes the base path to the resource actions concatenating the API and parent resource
 // base paths as needed.
 func (r *ResourceDefinition) FullPath() string {
@@ -694,15 +891,29 @@ func (r *ResourceDefinition) FullPath() string {
 			}
 		}
 	} else {
-		basePath = Design.BasePath
+		api := Design
+		if r.api != nil {
+			api = r.api
+		}
+		basePath = api.BasePath
+		if imp := api.PackageImport(r.Package); imp != nil {
+			basePath = path.Join(basePath, imp.BasePath)
+		}
 	}
 	return httppath.Clean(path.Join(basePath, r.BasePath))
 }
 
-// Parent returns the parent resource if any, nil otherwise.
+// Parent returns the parent resource if any, nil otherwise. The parent of a resource defined by
+// an API version is looked up in the version resources.
 func (r *ResourceDefinition) Parent() *ResourceDefinition {
 	if r.ParentName != "" {
-		if parent, ok := Design.Resources[r.ParentName]; ok {
+		resources := Design.Resources
+		if r.api != nil {
+			resources = r.api.Resources
+		} else if r.Version != nil {
+			resources = r.Version.AllResources()
+		}
+		if parent, ok := resources[r.ParentName]; ok {
 			return parent
 		}
 	}
@@ -763,9 +974,9 @@ func (r *ResourceDefinition) DSL() func() {
 	return r.DSLFunc
 }
 
-// Finalize is run post DSL execution. It merges response definitions, creates implicit action
-// parameters, initializes querystring parameters, sets path parameters as non zero attributes
-// and sets the fallbacks for security schemes.
+// Finalize is run post DSL execution. It merges response definitions, adds the pagination
+// parameters and headers, creates implicit action parameters, initializes querystring parameters,
+// sets path parameters as non zero attributes and sets the fallbacks for security schemes.
 func (r *ResourceDefinition) Finalize() {
 	r.IterateActions(func(a *ActionDefinition) error {
 		a.Finalize()
@@ -783,6 +994,8 @@ func (r *ResourceDefinition) Finalize() {
 				resp.Merge(dr)
 			}
 		}
+		a.finalizePagination()
+		a.finalizeFields()
 		// 2. Create implicit action parameters for path wildcards that dont' have one
 		for _, r := range a.Routes {
 			wcs := ExtractWildcards(r.FullPath())
@@ -941,7 +1154,8 @@ func (a *AttributeDefinition) IsNonZero(attName string) bool {
 }
 
 // IsPrimitivePointer returns true if the field generated for the given attribute should be a
-// pointer to a primitive type. The target attribute must be an object.
+// pointer to a primitive type. The target attribute must be an object. Bytes fields are never
+// pointers as nil already denotes the absence of value.
 func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if !a.Type.IsObject() {
 		panic("checking pointer field on non-object") // bug
@@ -950,7 +1164,7 @@ func (a *AttributeDefinition) IsPrimitivePointer(attName string) bool {
 	if att == nil {
 		return false
 	}
-	if att.Type.IsPrimitive() {
+	if att.Type.IsPrimitive() && att.Type.Kind() != BytesKind {
 		return !a.IsRequired(attName) && !a.HasDefaultValue(attName) && !a.IsNonZero(attName)
 	}
 	return false
@@ -980,6 +1194,12 @@ func (a *AttributeDefinition) SetExample(example interface{}) bool {
 	return false
 }
 
+// HasCustomExample returns true if the attribute example is given by the design, including when
+// the design explicitly disables examples, and false if the example is auto-generated.
+func (a *AttributeDefinition) HasCustomExample() bool {
+	return a.isCustomExample
+}
+
 // finalizeExample goes through each Example and consolidates all of the information it knows i.e.
 // a custom example or auto-generate for the user. It also tracks whether we've randomized
 // the entire example; if so, we shall re-generate the random value for Array/Hash.
@@ -1110,6 +1330,9 @@ func (a *AttributeDefinition) inheritRecursive(parent *AttributeDefinition) {
 			if att.View == "" {
 				att.View = patt.View
 			}
+			if att.Deprecation == nil {
+				att.Deprecation = patt.Deprecation
+			}
 			if att.Type == nil {
 				att.Type = patt.Type
 			} else if att.shouldInherit(patt) {
@@ -1422,6 +1645,35 @@ func (a *ActionDefinition) IterateResponses(it ResponseIterator) error {
 	return nil
 }
 
+// SunsetHeader returns the value of the Sunset HTTP response header, the empty string if there is
+// no sunset date.
+func (d *DeprecationDefinition) SunsetHeader() string {
+	if d.Sunset.IsZero() {
+		return ""
+	}
+	return d.Sunset.UTC().Format(http.TimeFormat)
+}
+
+// Message returns a human readable notice that describes when the deprecated action or attribute
+// is removed, e.g. "it will be removed after 2017-01-01".
+func (d *DeprecationDefinition) Message() string {
+	if d.Sunset.IsZero() {
+		return "it may be removed in a future version"
+	}
+	return "it will be removed after " + d.Sunset.UTC().Format("2006-01-02")
+}
+
+// Context returns the generic definition name used in error messages.
+func (s *StreamDefinition) Context() string {
+	return fmt.Sprintf("stream of %#v events", s.MediaType)
+}
+
+// EventMediaType returns the media type of the events data, nil if there is no media type with
+// the stream media type identifier.
+func (s *StreamDefinition) EventMediaType() *MediaTypeDefinition {
+	return Design.MediaTypeWithIdentifier(s.MediaType)
+}
+
 // Context returns the generic definition name used in error messages.
 func (l *LinkDefinition) Context() string {
 	var prefix, suffix string
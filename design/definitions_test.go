@@ -1,6 +1,7 @@
 package design_test
 
 import (
+	"github.com/goadesign/goa"
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
 	. "github.com/onsi/ginkgo"
@@ -45,3 +46,36 @@ var _ = Describe("IsRequired", func() {
 		})
 	})
 })
+
+var _ = Describe("GenerateExample", func() {
+	var attribute *design.AttributeDefinition
+	var example interface{}
+
+	BeforeEach(func() {
+		goa.RegisterFormat("country-code", func(string) error { return nil })
+		attribute = &design.AttributeDefinition{
+			Type:       design.String,
+			Validation: &dslengine.ValidationDefinition{Format: "country-code"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		example = attribute.GenerateExample(design.NewRandomGenerator("seed"))
+	})
+
+	It("generates a string", func() {
+		Ω(example).Should(BeAssignableToTypeOf(""))
+	})
+
+	Context("with a registered example generator", func() {
+		BeforeEach(func() {
+			design.RegisterFormatExample("country-code", func(*design.RandomGenerator) interface{} {
+				return "fr"
+			})
+		})
+
+		It("uses it", func() {
+			Ω(example).Should(Equal("fr"))
+		})
+	})
+})
@@ -52,7 +52,9 @@ func (d *dupper) DupAttribute(att *AttributeDefinition) *AttributeDefinition {
 		DefaultValue:      att.DefaultValue,
 		NonZeroAttributes: att.NonZeroAttributes,
 		View:              att.View,
+		Deprecation:       att.Deprecation,
 		DSLFunc:           att.DSLFunc,
+		Bases:             att.Bases,
 	}
 	return &dup
 }
@@ -75,6 +77,15 @@ func (d *dupper) DupType(t DataType) DataType {
 			KeyType:  d.DupAttribute(actual.KeyType),
 			ElemType: d.DupAttribute(actual.ElemType),
 		}
+	case *Union:
+		res := &Union{Discriminator: actual.Discriminator}
+		for _, v := range actual.Variants {
+			res.Variants = append(res.Variants, &UnionVariant{
+				Value: v.Value,
+				Type:  d.DupType(v.Type).(*UserTypeDefinition),
+			})
+		}
+		return res
 	case *UserTypeDefinition:
 		if u, ok := d.dts[actual.TypeName]; ok {
 			return u
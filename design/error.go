@@ -0,0 +1,106 @@
+package design
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// ErrorDefinition describes a typed error. An error defined on the API may be returned by all the
+// API actions, one defined on a resource by the resource actions and one defined on an action by
+// that action only.
+type ErrorDefinition struct {
+	// Name is the error code, e.g. "not_found".
+	Name string
+	// Status is the HTTP status code of the responses that carry the error.
+	Status int
+	// Type describes the error fields: the code, status and detail fields common to all errors
+	// and the fields defined in the design.
+	Type *UserTypeDefinition
+	// Parent is the API, resource or action definition that defines the error.
+	Parent dslengine.Definition
+}
+
+// ErrorFields returns the fields common to all typed errors indexed by name.
+func ErrorFields() Object {
+	return Object{
+		"code": &AttributeDefinition{
+			Type:        String,
+			Description: "Code identifies the class of errors.",
+		},
+		"status": &AttributeDefinition{
+			Type:        Integer,
+			Description: "Status is the HTTP status code used by responses that carry the error.",
+		},
+		"detail": &AttributeDefinition{
+			Type:        String,
+			Description: "Detail describes the specific error occurrence.",
+		},
+	}
+}
+
+// Context returns the generic definition name used in error messages.
+func (e *ErrorDefinition) Context() string {
+	var suffix string
+	if e.Parent != nil {
+		suffix = fmt.Sprintf(" of %s", e.Parent.Context())
+	}
+	return fmt.Sprintf("error %#v%s", e.Name, suffix)
+}
+
+// Description returns the error description if any.
+func (e *ErrorDefinition) Description() string {
+	if e.Type == nil {
+		return ""
+	}
+	return e.Type.Description
+}
+
+// AllErrors returns the errors defined on the API, its resources and their actions sorted by name.
+func (a *APIDefinition) AllErrors() []*ErrorDefinition {
+	all := errorList(a.Errors)
+	for _, r := range a.Resources {
+		all = append(all, errorList(r.Errors)...)
+		for _, ac := range r.Actions {
+			all = append(all, errorList(ac.Errors)...)
+		}
+	}
+	sortErrors(all)
+	return all
+}
+
+// AllErrors returns the errors that may be returned by the action sorted by name: the errors
+// defined on the API, on the parent resource and on the action.
+func (a *ActionDefinition) AllErrors() []*ErrorDefinition {
+	all := errorList(a.Errors)
+	if a.Parent != nil {
+		all = append(all, errorList(a.Parent.Errors)...)
+	}
+	if Design != nil {
+		all = append(all, errorList(Design.Errors)...)
+	}
+	sortErrors(all)
+	return all
+}
+
+// errorList returns the errors of the given map.
+func errorList(errors map[string]*ErrorDefinition) []*ErrorDefinition {
+	list := make([]*ErrorDefinition, 0, len(errors))
+	for _, e := range errors {
+		list = append(list, e)
+	}
+	return list
+}
+
+// sortErrors sorts the given errors by name.
+func sortErrors(errors []*ErrorDefinition) {
+	sort.Sort(errorsByName(errors))
+}
+
+// errorsByName makes it possible to sort errors by name.
+type errorsByName []*ErrorDefinition
+
+func (e errorsByName) Len() int           { return len(e) }
+func (e errorsByName) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e errorsByName) Less(i, j int) bool { return e[i].Name < e[j].Name }
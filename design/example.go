@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/goadesign/goa/format"
 	regen "github.com/zach-klippenstein/goregen"
 )
 
@@ -23,6 +24,17 @@ func newExampleGenerator(a *AttributeDefinition, r *RandomGenerator) *exampleGen
 // Maximum number of tries for generating example.
 const maxAttempts = 500
 
+// formatExamples records the example generators registered with RegisterFormatExample.
+var formatExamples = make(map[string]func(*RandomGenerator) interface{})
+
+// RegisterFormatExample registers the function used to generate examples for attributes that use
+// the given custom format, see goa.RegisterFormat. The function must return values that conform to
+// the format. goagen generates examples for attributes that use custom formats without a registered
+// function from their type only.
+func RegisterFormatExample(name string, example func(*RandomGenerator) interface{}) {
+	formatExamples[name] = example
+}
+
 // generate generates a random value based on the given validations.
 func (eg *exampleGenerator) generate() interface{} {
 	// Randomize array length first, since that's from higher level
@@ -35,6 +47,7 @@ func (eg *exampleGenerator) generate() interface{} {
 	}
 	// loop until a satisified example is generated
 	hasFormat, hasPattern, hasMinMax := eg.hasFormatValidation(), eg.hasPatternValidation(), eg.hasMinMaxValidation()
+	hasMultipleOf := eg.hasMultipleOfValidation()
 	attempts := 0
 	for attempts < maxAttempts {
 		attempts++
@@ -54,7 +67,18 @@ func (eg *exampleGenerator) generate() interface{} {
 		if hasMinMax {
 			if example == nil {
 				example = eg.generateValidatedMinMaxValueExample()
-			} else if !eg.checkMinMaxValueValidation(example) {
+			}
+			// Check even generated examples as they may be equal to exclusive bounds
+			if !eg.checkMinMaxValueValidation(example) {
+				continue
+			}
+		}
+		if hasMultipleOf {
+			if example == nil {
+				example = eg.a.Type.GenerateExample(eg.r)
+			}
+			example = eg.roundToMultipleOf(example)
+			if !eg.checkMinMaxValueValidation(example) {
 				continue
 			}
 		}
@@ -136,7 +160,7 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 	if !eg.hasFormatValidation() {
 		return nil
 	}
-	format := eg.a.Validation.Format
+	name := eg.a.Validation.Format
 	if res, ok := map[string]interface{}{
 		"email":     eg.r.faker.Email(),
 		"hostname":  eg.r.faker.DomainName() + "." + eg.r.faker.DomainSuffix(),
@@ -153,10 +177,16 @@ func (eg *exampleGenerator) generateFormatExample() interface{} {
 		}(),
 		"cidr":   "192.168.100.14/24",
 		"regexp": eg.r.faker.Characters(3) + ".*",
-	}[format]; ok {
+	}[name]; ok {
 		return res
 	}
-	panic("Validation: unknown format '" + format + "'") // bug
+	if example, ok := formatExamples[name]; ok {
+		return example(eg.r)
+	}
+	if format.IsRegistered(name) {
+		return nil
+	}
+	panic("Validation: unknown format '" + name + "'") // bug
 }
 
 func (eg *exampleGenerator) hasPatternValidation() bool {
@@ -203,27 +233,46 @@ func (eg *exampleGenerator) checkMinMaxValueValidation(example interface{}) bool
 	if !eg.hasMinMaxValidation() {
 		return true
 	}
-	valid := true
+	var v float64
+	switch actual := example.(type) {
+	case int:
+		v = float64(actual)
+	case float64:
+		v = actual
+	default:
+		return true
+	}
 	if min := eg.a.Validation.Minimum; min != nil {
-		if v, ok := example.(int); ok && float64(v) < *min {
-			valid = false
-		} else if v, ok := example.(float64); ok && v < *min {
-			valid = false
+		if v < *min || (eg.a.Validation.ExclusiveMinimum && v == *min) {
+			return false
 		}
 	}
-	if !valid {
-		return false
-	}
 	if max := eg.a.Validation.Maximum; max != nil {
-		if v, ok := example.(int); ok && float64(v) > *max {
-			return false
-		} else if v, ok := example.(float64); ok && v > *max {
+		if v > *max || (eg.a.Validation.ExclusiveMaximum && v == *max) {
 			return false
 		}
 	}
 	return true
 }
 
+func (eg *exampleGenerator) hasMultipleOfValidation() bool {
+	return eg.a.Validation != nil && eg.a.Validation.MultipleOf != nil
+}
+
+// roundToMultipleOf returns a multiple of the "multipleOf" validation value close to example.
+func (eg *exampleGenerator) roundToMultipleOf(example interface{}) interface{} {
+	m := *eg.a.Validation.MultipleOf
+	switch v := example.(type) {
+	case int:
+		if mi := int(m); mi > 0 {
+			return v - v%mi
+		}
+	case float64:
+		return math.Floor(v/m+0.5) * m
+	}
+	return example
+}
+
 func (eg *exampleGenerator) generateValidatedMinMaxValueExample() interface{} {
 	if !eg.hasMinMaxValidation() {
 		return nil
@@ -0,0 +1,98 @@
+package design
+
+import (
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// FieldsParam is the name of the query string parameter that lists the fields rendered in the
+// responses of actions that support field selection.
+const FieldsParam = "fields"
+
+// FieldsDefinition describes the field selection (sparse fieldsets) supported by an action. Clients
+// may list the fields of the OK response media type to render using the FieldsParam query string
+// parameter, e.g. "?fields=id,account.name".
+type FieldsDefinition struct {
+	// MediaType is the identifier of the media type whose fields may be selected, it is the
+	// media type of the action OK response.
+	MediaType string
+}
+
+// Context returns the generic definition name used in error messages.
+func (f *FieldsDefinition) Context() string {
+	return "fields"
+}
+
+// Paths returns the sorted list of the field paths that may be selected. The path of a child
+// attribute is the path of its parent followed by a dot and by the name of the attribute. The
+// attributes of the elements of collections use the path of the collection.
+func (f *FieldsDefinition) Paths() []string {
+	mt := Design.MediaTypeWithIdentifier(f.MediaType)
+	if mt == nil {
+		return nil
+	}
+	paths := fieldPaths("", mt.Type, make(map[string]bool))
+	sort.Strings(paths)
+	return paths
+}
+
+// Param returns the definition of the FieldsParam query string parameter.
+func (f *FieldsDefinition) Param() *AttributeDefinition {
+	var values []interface{}
+	for _, p := range f.Paths() {
+		values = append(values, p)
+	}
+	elem := &AttributeDefinition{Type: String}
+	if len(values) > 0 {
+		elem.Validation = &dslengine.ValidationDefinition{Values: values}
+	}
+	return &AttributeDefinition{
+		Type:        &Array{ElemType: elem},
+		Description: "Comma separated list of the fields to render in the response, all fields are rendered if empty",
+	}
+}
+
+// fieldPaths returns the paths of the attributes of the given type. seen records the user types
+// being traversed so that recursive types are only expanded once.
+func fieldPaths(prefix string, dt DataType, seen map[string]bool) []string {
+	switch actual := dt.(type) {
+	case *Array:
+		return fieldPaths(prefix, actual.ElemType.Type, seen)
+	case *UserTypeDefinition:
+		if seen[actual.TypeName] {
+			return nil
+		}
+		seen[actual.TypeName] = true
+		defer delete(seen, actual.TypeName)
+		return fieldPaths(prefix, actual.Type, seen)
+	case *MediaTypeDefinition:
+		return fieldPaths(prefix, actual.UserTypeDefinition, seen)
+	case Object:
+		var paths []string
+		for n, att := range actual {
+			p := n
+			if prefix != "" {
+				p = prefix + "." + n
+			}
+			paths = append(paths, p)
+			paths = append(paths, fieldPaths(p, att.Type, seen)...)
+		}
+		return paths
+	}
+	return nil
+}
+
+// finalizeFields adds the FieldsParam parameter to the action parameters.
+func (a *ActionDefinition) finalizeFields() {
+	if a.Fields == nil {
+		return
+	}
+	if resp, ok := a.Responses["OK"]; ok {
+		a.Fields.MediaType = resp.MediaType
+	}
+	if a.Params == nil {
+		a.Params = &AttributeDefinition{Type: Object{}}
+	}
+	a.Params.Type.ToObject()[FieldsParam] = a.Fields.Param()
+}
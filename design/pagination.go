@@ -0,0 +1,106 @@
+package design
+
+import "github.com/goadesign/goa/dslengine"
+
+const (
+	// DefaultPageLimit is the default value of the "limit" parameter of paginated actions. It
+	// must match goa.DefaultPageLimit, the page size used by the generated code when the request
+	// does not specify one.
+	DefaultPageLimit = 20
+
+	// MaxPageLimit is the maximum value of the "limit" parameter of paginated actions.
+	MaxPageLimit = 100
+
+	// OffsetPagination is the pagination style where the pages are identified by their number
+	// using the "page" and "limit" query string parameters.
+	OffsetPagination = "offset"
+
+	// CursorPagination is the pagination style where the pages are identified by an opaque
+	// cursor returned in the Link header of the previous page using the "cursor" and "limit"
+	// query string parameters.
+	CursorPagination = "cursor"
+)
+
+// PaginationDefinition describes how the responses of an action are paginated.
+type PaginationDefinition struct {
+	// Style is the pagination style, one of OffsetPagination or CursorPagination.
+	Style string
+}
+
+// Context returns the generic definition name used in error messages.
+func (p *PaginationDefinition) Context() string {
+	return "pagination"
+}
+
+// Params returns the query string parameters used by the pagination style indexed by name.
+func (p *PaginationDefinition) Params() Object {
+	min, max := 1.0, float64(MaxPageLimit)
+	params := Object{
+		"limit": &AttributeDefinition{
+			Type:         Integer,
+			Description:  "Maximum number of items in page",
+			DefaultValue: DefaultPageLimit,
+			Validation:   &dslengine.ValidationDefinition{Minimum: &min, Maximum: &max},
+		},
+	}
+	if p.Style == CursorPagination {
+		params["cursor"] = &AttributeDefinition{
+			Type:        String,
+			Description: "Cursor of page as returned in the Link header of the previous page, first page if empty",
+		}
+	} else {
+		params["page"] = &AttributeDefinition{
+			Type:         Integer,
+			Description:  "Page number starting at 1",
+			DefaultValue: 1,
+		}
+	}
+	return params
+}
+
+// Headers returns the response headers set by paginated actions indexed by name.
+func (p *PaginationDefinition) Headers() Object {
+	headers := Object{
+		"Link": &AttributeDefinition{
+			Type:        String,
+			Description: "Links to the other pages as defined by RFC 5988",
+		},
+	}
+	if p.Style == OffsetPagination {
+		headers["X-Total-Count"] = &AttributeDefinition{
+			Type:        Integer,
+			Description: "Total number of items",
+		}
+	}
+	return headers
+}
+
+// finalizePagination adds the pagination parameters to the action parameters and the pagination
+// headers to the action successful response.
+func (a *ActionDefinition) finalizePagination() {
+	if a.Pagination == nil {
+		return
+	}
+	if a.Params == nil {
+		a.Params = &AttributeDefinition{Type: Object{}}
+	}
+	params := a.Params.Type.ToObject()
+	for n, att := range a.Pagination.Params() {
+		params[n] = att
+	}
+	if resp, ok := a.Responses["OK"]; ok {
+		var headers *AttributeDefinition
+		if resp.Headers != nil {
+			headers = DupAtt(resp.Headers)
+		} else {
+			headers = &AttributeDefinition{Type: Object{}}
+		}
+		obj := headers.Type.ToObject()
+		for n, att := range a.Pagination.Headers() {
+			if _, ok := obj[n]; !ok {
+				obj[n] = att
+			}
+		}
+		resp.Headers = headers
+	}
+}
@@ -4,8 +4,10 @@ import (
 	"crypto/md5"
 	"encoding/binary"
 	"math/rand"
+	"strconv"
 	"time"
 
+	"github.com/goadesign/goa/format"
 	"github.com/manveru/faker"
 	"github.com/satori/go.uuid"
 )
@@ -58,6 +60,27 @@ func (r *RandomGenerator) DateTime() time.Time {
 	return time.Unix(unix, 0)
 }
 
+// Date produces a random RFC3339 full-date value.
+func (r *RandomGenerator) Date() string {
+	return r.DateTime().UTC().Format(format.DateLayout)
+}
+
+// Duration produces a random ISO 8601 duration value.
+func (r *RandomGenerator) Duration() string {
+	secs := r.rand.Int63n(24 * 60 * 60)
+	return format.FormatDuration(time.Duration(secs) * time.Second)
+}
+
+// Bytes produces a random byte slice.
+func (r *RandomGenerator) Bytes() []byte {
+	return []byte(r.faker.Words(1, false)[0])
+}
+
+// Decimal produces a random decimal number with two decimal places.
+func (r *RandomGenerator) Decimal() string {
+	return strconv.FormatFloat(float64(r.rand.Int63n(100000))/100, 'f', 2, 64)
+}
+
 // UUID produces a random UUID.
 func (r *RandomGenerator) UUID() uuid.UUID {
 	return uuid.NewV4()
@@ -72,3 +95,8 @@ func (r *RandomGenerator) Bool() bool {
 func (r *RandomGenerator) Float64() float64 {
 	return r.rand.Float64()
 }
+
+// File produces a random file name.
+func (r *RandomGenerator) File() string {
+	return r.faker.Words(1, false)[0] + ".txt"
+}
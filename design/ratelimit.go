@@ -0,0 +1,68 @@
+package design
+
+import (
+	"fmt"
+	"time"
+)
+
+// RateLimitDefinition describes the maximum number of requests a principal may make in a given
+// period of time. Requests are counted per scope: a rate limit defined at the API level is shared
+// by all the API actions, one defined on a resource by the resource actions.
+type RateLimitDefinition struct {
+	// Limit is the maximum number of requests per period.
+	Limit int
+	// Period is the duration of the period.
+	Period time.Duration
+	// Scope identifies the requests counted together, e.g. "cellar" for a rate limit defined
+	// on the cellar API, "cellar/bottle" for one defined on the bottle resource and
+	// "cellar/bottle#create" for one defined on the create action.
+	Scope string
+}
+
+// Context returns the generic definition name used in error messages.
+func (r *RateLimitDefinition) Context() string {
+	return fmt.Sprintf("rate limit of %s", r.Scope)
+}
+
+// Description returns a human readable description of the rate limit, e.g. "100 requests per 1m0s".
+func (r *RateLimitDefinition) Description() string {
+	return fmt.Sprintf("%d requests per %s", r.Limit, r.Period)
+}
+
+// Headers returns the headers set on responses to rate limited requests indexed by name.
+func (r *RateLimitDefinition) Headers() Object {
+	return Object{
+		"RateLimit-Limit": &AttributeDefinition{
+			Type:        Integer,
+			Description: "Maximum number of requests per period",
+		},
+		"RateLimit-Remaining": &AttributeDefinition{
+			Type:        Integer,
+			Description: "Number of requests remaining in the current period",
+		},
+		"RateLimit-Reset": &AttributeDefinition{
+			Type:        Integer,
+			Description: "Number of seconds until the quota is fully restored",
+		},
+		"Retry-After": &AttributeDefinition{
+			Type:        Integer,
+			Description: "Number of seconds to wait before making a new request",
+		},
+	}
+}
+
+// EffectiveRateLimit returns the rate limit that applies to the action: the action rate limit if
+// any, the resource rate limit otherwise and finally the API rate limit. It returns nil if the
+// action is not rate limited.
+func (a *ActionDefinition) EffectiveRateLimit() *RateLimitDefinition {
+	if a.RateLimit != nil {
+		return a.RateLimit
+	}
+	if a.Parent != nil && a.Parent.RateLimit != nil {
+		return a.Parent.RateLimit
+	}
+	if Design == nil {
+		return nil
+	}
+	return Design.RateLimit
+}
@@ -53,10 +53,10 @@ type SecuritySchemeDefinition struct {
 	Type string `json:"type"`
 	// Description describes the security scheme. Ex: "Google OAuth2"
 	Description string `json:"description"`
-	// In determines whether it is in the "header" or in the "query"
-	// string that we will find an `apiKey`.
+	// In determines whether it is in the "header", in the "query"
+	// string or in a "cookie" that we will find an `apiKey`.
 	In string `json:"in,omitempty"`
-	// Name refers to a header or parameter name, based on In's value.
+	// Name refers to a header, parameter or cookie name, based on In's value.
 	Name string `json:"name,omitempty"`
 	// Scopes is a list of available scopes for this scheme, along
 	// with their textual description.
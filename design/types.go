@@ -10,6 +10,7 @@
 package design
 
 import (
+	"encoding/base64"
 	"fmt"
 	"reflect"
 	"sort"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/format"
 	"github.com/satori/go.uuid"
 )
 
@@ -92,6 +94,24 @@ type (
 	// HashVal is the value of a hash used to specify the default value.
 	HashVal map[interface{}]interface{}
 
+	// Union is the type for a value that may be any one of a set of object user types called
+	// variants. The variant a value uses is identified by the value of the discriminator
+	// attribute.
+	Union struct {
+		// Discriminator is the name of the attribute whose value identifies the variant.
+		Discriminator string
+		// Variants lists the possible types in order of definition.
+		Variants []*UnionVariant
+	}
+
+	// UnionVariant describes one of the types a union value may take.
+	UnionVariant struct {
+		// Value is the discriminator value that identifies the variant.
+		Value string
+		// Type is the variant type.
+		Type *UserTypeDefinition
+	}
+
 	// UserTypeDefinition is the type for user defined types that are not media types
 	// (e.g. payload types).
 	UserTypeDefinition struct {
@@ -99,6 +119,9 @@ type (
 		*AttributeDefinition
 		// Name of type
 		TypeName string
+		// Package is the import path of the design package that defines the type if
+		// defined at the top level of a design.
+		Package string
 	}
 
 	// MediaTypeDefinition describes the rendering of a resource using property and link
@@ -146,6 +169,18 @@ const (
 	UserTypeKind
 	// MediaTypeKind represents a media type.
 	MediaTypeKind
+	// FileKind represents a file uploaded via a multipart form.
+	FileKind
+	// UnionKind represents a JSON object that may be one of a set of types.
+	UnionKind
+	// DateKind represents a JSON string that is parsed as a Go goa.Date
+	DateKind
+	// DurationKind represents a JSON string that is parsed as a Go goa.Duration
+	DurationKind
+	// BytesKind represents a base64 encoded JSON string that is parsed as a Go []byte
+	BytesKind
+	// DecimalKind represents a JSON string that is parsed as a Go goa.Decimal
+	DecimalKind
 )
 
 const (
@@ -171,6 +206,30 @@ const (
 
 	// Any is the type for an arbitrary JSON value (interface{} in Go).
 	Any = Primitive(AnyKind)
+
+	// File is the type for a file uploaded as part of a multipart form, see MultipartForm.
+	// File is parsed as a Go *multipart.FileHeader.
+	File = Primitive(FileKind)
+
+	// Date is the type for a JSON string parsed as a Go goa.Date
+	// Date expects an RFC3339 full-date formatted value (e.g. "2016-07-11").
+	// goa.Date embeds a time.Time and marshals to the full-date format, time.Time marshals to
+	// full date-time values.
+	Date = Primitive(DateKind)
+
+	// Duration is the type for a JSON string parsed as a Go goa.Duration
+	// Duration expects an ISO 8601 duration formatted value (e.g. "PT1H30M").
+	// goa.Duration converts to and from time.Duration and marshals to the ISO 8601 format,
+	// time.Duration marshals to a number of nanoseconds.
+	Duration = Primitive(DurationKind)
+
+	// Bytes is the type for a JSON string parsed as a Go []byte
+	// Bytes expects a base64 encoded value.
+	Bytes = Primitive(BytesKind)
+
+	// Decimal is the type for a JSON string parsed as a Go goa.Decimal
+	// Decimal expects an exact decimal number (e.g. "12.50").
+	Decimal = Primitive(DecimalKind)
 )
 
 // DataType implementation
@@ -187,10 +246,12 @@ func (p Primitive) Name() string {
 		return "integer"
 	case Number:
 		return "number"
-	case String, DateTime, UUID:
+	case String, DateTime, UUID, Date, Duration, Bytes, Decimal:
 		return "string"
 	case Any:
 		return "any"
+	case File:
+		return "file"
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -231,7 +292,8 @@ func (p Primitive) CanHaveDefault() (ok bool) {
 
 // IsCompatible returns true if val is compatible with p.
 func (p Primitive) IsCompatible(val interface{}) bool {
-	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID && p != Any {
+	if p != Boolean && p != Integer && p != Number && p != String && p != DateTime && p != UUID &&
+		p != Any && p != File && p != Date && p != Duration && p != Bytes && p != Decimal {
 		panic("unknown primitive type") // bug
 	}
 	if p == Any {
@@ -241,9 +303,11 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 	case bool:
 		return p == Boolean
 	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
-		return p == Integer || p == Number
+		return p == Integer || p == Number || p == Decimal
 	case float32, float64:
-		return p == Number
+		return p == Number || p == Decimal
+	case []byte:
+		return p == Bytes
 	case string:
 		if p == String {
 			return true
@@ -256,6 +320,24 @@ func (p Primitive) IsCompatible(val interface{}) bool {
 			_, err := uuid.FromString(val.(string))
 			return err == nil
 		}
+		if p == File {
+			return true
+		}
+		if p == Date {
+			_, err := format.ParseDate(val.(string))
+			return err == nil
+		}
+		if p == Duration {
+			_, err := format.ParseDuration(val.(string))
+			return err == nil
+		}
+		if p == Bytes {
+			_, err := base64.StdEncoding.DecodeString(val.(string))
+			return err == nil
+		}
+		if p == Decimal {
+			return format.ValidateDecimal(val.(string)) == nil
+		}
 	}
 	return false
 }
@@ -280,6 +362,16 @@ func (p Primitive) GenerateExample(r *RandomGenerator) interface{} {
 	case Any:
 		// to not make it too complicated, pick one of the primitive types
 		return anyPrimitive[r.Int()%len(anyPrimitive)].GenerateExample(r)
+	case File:
+		return r.File()
+	case Date:
+		return r.Date()
+	case Duration:
+		return r.Duration()
+	case Bytes:
+		return r.Bytes()
+	case Decimal:
+		return r.Decimal()
 	default:
 		panic("unknown primitive type") // bug
 	}
@@ -499,6 +591,103 @@ func (h *Hash) MakeMap(m map[interface{}]interface{}) interface{} {
 	return hash.Interface()
 }
 
+// Kind implements DataKind.
+func (u *Union) Kind() Kind { return UnionKind }
+
+// Name returns the type name.
+func (u *Union) Name() string { return "union" }
+
+// IsPrimitive returns false.
+func (u *Union) IsPrimitive() bool { return false }
+
+// HasAttributes returns true.
+func (u *Union) HasAttributes() bool { return true }
+
+// IsObject returns true, a union value is always a JSON object.
+func (u *Union) IsObject() bool { return true }
+
+// IsArray returns false.
+func (u *Union) IsArray() bool { return false }
+
+// IsHash returns false.
+func (u *Union) IsHash() bool { return false }
+
+// ToObject returns an object made of the discriminator and of the attributes of all the
+// variants so that the object view matches the JSON representation of any union value. Use
+// Variants to access the attributes of each variant.
+func (u *Union) ToObject() Object {
+	o := Object{u.Discriminator: u.DiscriminatorAttribute()}
+	for _, v := range u.Variants {
+		if v.Type == nil || v.Type.Type == nil {
+			continue
+		}
+		for n, att := range v.Type.ToObject() {
+			if _, ok := o[n]; !ok {
+				o[n] = att
+			}
+		}
+	}
+	return o
+}
+
+// ToArray returns nil.
+func (u *Union) ToArray() *Array { return nil }
+
+// ToHash returns nil.
+func (u *Union) ToHash() *Hash { return nil }
+
+// CanHaveDefault returns false.
+func (u *Union) CanHaveDefault() bool { return false }
+
+// IsCompatible returns true if val is compatible with u.
+func (u *Union) IsCompatible(val interface{}) bool {
+	if val == nil {
+		return false
+	}
+	k := reflect.TypeOf(val).Kind()
+	return k == reflect.Map || k == reflect.Struct
+}
+
+// GenerateExample returns a random value of one of the variants.
+func (u *Union) GenerateExample(r *RandomGenerator) interface{} {
+	if len(u.Variants) == 0 {
+		return nil
+	}
+	v := u.Variants[r.Int()%len(u.Variants)]
+	res := make(map[string]interface{})
+	if ex, ok := v.Type.GenerateExample(r).(map[string]interface{}); ok {
+		for n, val := range ex {
+			res[n] = val
+		}
+	}
+	res[u.Discriminator] = v.Value
+	return res
+}
+
+// DiscriminatorAttribute returns the definition of the discriminator attribute: a string whose
+// values are the variant discriminator values.
+func (u *Union) DiscriminatorAttribute() *AttributeDefinition {
+	values := make([]interface{}, len(u.Variants))
+	for i, v := range u.Variants {
+		values[i] = v.Value
+	}
+	return &AttributeDefinition{
+		Type:       String,
+		Validation: &dslengine.ValidationDefinition{Values: values},
+	}
+}
+
+// Variant returns the variant identified by the given discriminator value, nil if there is
+// none.
+func (u *Union) Variant(value string) *UnionVariant {
+	for _, v := range u.Variants {
+		if v.Value == value {
+			return v
+		}
+	}
+	return nil
+}
+
 // AttributeIterator is the type of the function given to IterateAttributes.
 type AttributeIterator func(string, *AttributeDefinition) error
 
@@ -558,6 +747,17 @@ func UserTypes(dt DataType) map[string]*UserTypeDefinition {
 			types[n] = ut
 		}
 		return types
+	case *Union:
+		types := make(map[string]*UserTypeDefinition)
+		for _, v := range actual.Variants {
+			for n, ut := range UserTypes(v.Type) {
+				types[n] = ut
+			}
+		}
+		if len(types) == 0 {
+			return nil
+		}
+		return types
 	default:
 		panic("unknown type") // bug
 	}
@@ -903,7 +1103,11 @@ func toReflectType(dtype DataType) reflect.Type {
 		return reflect.TypeOf("")
 	case DateTimeKind:
 		return reflect.TypeOf(time.Time{})
-	case ObjectKind, UserTypeKind, MediaTypeKind:
+	case DateKind, DurationKind, DecimalKind:
+		return reflect.TypeOf("")
+	case BytesKind:
+		return reflect.TypeOf([]byte{})
+	case ObjectKind, UserTypeKind, MediaTypeKind, UnionKind:
 		return reflect.TypeOf(map[string]interface{}{})
 	case ArrayKind:
 		return reflect.SliceOf(toReflectType(dtype.ToArray().ElemType.Type))
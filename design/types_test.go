@@ -271,3 +271,39 @@ var _ = Describe("MediaTypeDefinition", func() {
 		})
 	})
 })
+
+var _ = Describe("Primitive", func() {
+	Describe("IsCompatible", func() {
+		It("accepts valid dates", func() {
+			Ω(Date.IsCompatible("2016-07-11")).Should(BeTrue())
+			Ω(Date.IsCompatible("2016-07-11T10:00:00Z")).Should(BeFalse())
+		})
+
+		It("accepts ISO 8601 durations", func() {
+			Ω(Duration.IsCompatible("PT1H30M")).Should(BeTrue())
+			Ω(Duration.IsCompatible("1h30m")).Should(BeFalse())
+		})
+
+		It("accepts base64 encoded bytes", func() {
+			Ω(Bytes.IsCompatible("Zm9v")).Should(BeTrue())
+			Ω(Bytes.IsCompatible([]byte("foo"))).Should(BeTrue())
+			Ω(Bytes.IsCompatible("not base64!")).Should(BeFalse())
+		})
+
+		It("accepts decimal strings and numbers", func() {
+			Ω(Decimal.IsCompatible("12.50")).Should(BeTrue())
+			Ω(Decimal.IsCompatible(12.5)).Should(BeTrue())
+			Ω(Decimal.IsCompatible(12)).Should(BeTrue())
+			Ω(Decimal.IsCompatible("twelve")).Should(BeFalse())
+		})
+	})
+
+	Describe("GenerateExample", func() {
+		It("generates compatible examples", func() {
+			r := NewRandomGenerator("seed")
+			for _, p := range []Primitive{Date, Duration, Bytes, Decimal} {
+				Ω(p.IsCompatible(p.GenerateExample(r))).Should(BeTrue(), p.Name())
+			}
+		})
+	})
+})
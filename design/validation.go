@@ -72,6 +72,10 @@ func (a *APIDefinition) Validate() error {
 	a.validateLicense(verr)
 	a.validateDocs(verr)
 	a.validateOrigins(verr)
+	a.validateErrors(verr)
+	for _, v := range a.Versions {
+		v.API().validateErrors(verr)
+	}
 
 	var allRoutes []*routeInfo
 	a.IterateResources(func(r *ResourceDefinition) error {
@@ -126,6 +130,9 @@ func (a *APIDefinition) Validate() error {
 			}
 		}
 	}
+	for _, v := range a.Versions {
+		verr.Merge(v.Validate())
+	}
 	a.IterateMediaTypes(func(mt *MediaTypeDefinition) error {
 		verr.Merge(mt.Validate())
 		return nil
@@ -183,6 +190,55 @@ func (a *APIDefinition) validateOrigins(verr *dslengine.ValidationErrors) {
 	}
 }
 
+// validateErrors makes sure the typed errors have unique names and that the names of the
+// generated error types do not conflict with the names of user or media types.
+func (a *APIDefinition) validateErrors(verr *dslengine.ValidationErrors) {
+	seen := make(map[string]*ErrorDefinition)
+	for _, e := range a.AllErrors() {
+		if other, ok := seen[e.Name]; ok {
+			if other != e {
+				verr.Add(e, "error name conflicts with %s", other.Context())
+			}
+			continue
+		}
+		seen[e.Name] = e
+		if e.Status < 400 || e.Status > 599 {
+			verr.Add(e, "invalid status %d, must be a 4xx or 5xx status", e.Status)
+		}
+		if _, ok := a.Types[e.Type.TypeName]; ok {
+			verr.Add(e, "type name %#v conflicts with the name of a user type", e.Type.TypeName)
+		}
+		for _, mt := range a.MediaTypes {
+			if mt.TypeName == e.Type.TypeName {
+				verr.Add(e, "type name %#v conflicts with the name of a media type", e.Type.TypeName)
+			}
+		}
+		verr.Merge(e.Type.Validate("", e))
+	}
+}
+
+// Validate tests whether the API version definition is consistent: the version must have a name,
+// its base path may only use wildcards defined in the API base parameters, requests must be able to
+// select it and the resources it defines must be valid.
+func (v *APIVersionDefinition) Validate() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if v.Name == "" {
+		verr.Add(v, "API version name cannot be empty")
+	}
+	for _, wc := range ExtractWildcards(v.BasePath) {
+		if Design.BaseParams == nil || Design.BaseParams.Type.ToObject()[wc] == nil {
+			verr.Add(v, "Variable %s from base path %s does not match any API base parameter", wc, v.BasePath)
+		}
+	}
+	if !v.SelectedByPath() && Design.VersionHeader == "" && Design.VersionParam == "" {
+		verr.Add(v, "the version shares the API base path, use VersionHeader or VersionParam in API so that requests may select it")
+	}
+	for _, r := range v.Resources {
+		verr.Merge(r.Validate())
+	}
+	return verr.AsError()
+}
+
 // Validate tests whether the resource definition is consistent: action names are valid and each action is
 // valid.
 func (r *ResourceDefinition) Validate() *dslengine.ValidationErrors {
@@ -253,8 +309,8 @@ func (r *ResourceDefinition) validateBaseParams(verr *dslengine.ValidationErrors
 }
 
 func (r *ResourceDefinition) validateParent(verr *dslengine.ValidationErrors) {
-	p, ok := Design.Resources[r.ParentName]
-	if !ok {
+	p := r.Parent()
+	if p == nil {
 		verr.Add(r, "Parent resource named %#v not found", r.ParentName)
 	} else {
 		if p.CanonicalAction() == nil {
@@ -341,13 +397,163 @@ func (a *ActionDefinition) Validate() *dslengine.ValidationErrors {
 	if a.Payload != nil {
 		verr.Merge(a.Payload.Validate("action payload", a))
 	}
+	if a.PayloadMultipart {
+		if a.Payload == nil {
+			verr.Add(a, "multipart form requires a payload")
+		} else if !a.Payload.IsObject() {
+			verr.Add(a, "multipart form payload must be an object")
+		} else {
+			for n, att := range a.Payload.ToObject() {
+				t := att.Type
+				if t.IsArray() {
+					t = t.ToArray().ElemType.Type
+				}
+				if !t.IsPrimitive() {
+					verr.Add(a, "multipart form payload attribute %s must be a primitive or an array of primitives", n)
+				}
+			}
+		}
+	} else if a.Payload != nil && a.Payload.IsObject() {
+		for n, att := range a.Payload.ToObject() {
+			if att.Type.Kind() == FileKind {
+				verr.Add(a, "payload attribute %s is a file, action must use MultipartForm", n)
+			}
+		}
+	}
 	if a.Parent == nil {
 		verr.Add(a, "missing parent resource")
 	}
+	if a.Pagination != nil {
+		verr.Merge(a.validatePagination())
+	}
+	if a.Fields != nil {
+		verr.Merge(a.validateFields())
+	}
+	if a.Cookies != nil {
+		verr.Merge(a.validateCookies())
+	}
+	if a.Stream != nil {
+		verr.Merge(a.validateStream())
+	}
+	if a.Messages != nil {
+		verr.Merge(a.validateMessages())
+	}
 
 	return verr.AsError()
 }
 
+// validateStream makes sure the stream events use a known media type and that the action can be
+// used to stream server-sent events.
+func (a *ActionDefinition) validateStream() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if a.Stream.EventMediaType() == nil {
+		verr.Add(a, "unknown stream event media type %#v", a.Stream.MediaType)
+	}
+	if a.WebSocket() {
+		verr.Add(a, "websocket action cannot stream server-sent events")
+	}
+	if a.Pagination != nil {
+		verr.Add(a, "paginated action cannot stream server-sent events")
+	}
+	if a.Cacheable {
+		verr.Add(a, "cacheable action cannot stream server-sent events")
+	}
+	for _, r := range a.Routes {
+		if r.Verb != "GET" {
+			verr.Add(a, "streaming action route %#v must use GET", r.FullPath())
+		}
+	}
+	return verr
+}
+
+// validateMessages makes sure the WebSocket messages use object user types or media types.
+func (a *ActionDefinition) validateMessages() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if !a.WebSocket() {
+		verr.Add(a, "action declaring messages must use the ws or wss scheme")
+	}
+	m := a.Messages
+	if m.Inbound == nil && m.Outbound == nil {
+		verr.Add(m, "must define the inbound or outbound messages")
+	}
+	if m.Inbound != nil {
+		if ut := m.InboundType(); ut == nil || !ut.IsObject() {
+			verr.Add(m, "inbound messages must use a type or a media type describing an object")
+		}
+	}
+	if m.Outbound != nil {
+		if ut := m.OutboundType(); ut == nil || !ut.IsObject() {
+			verr.Add(m, "outbound messages must use a type or a media type describing an object")
+		}
+	}
+	if m.KeepAlive < 0 {
+		verr.Add(m, "keep-alive period cannot be negative")
+	}
+	return verr
+}
+
+// validateCookies makes sure the action cookies use primitive types and do not use the names of
+// action parameters.
+func (a *ActionDefinition) validateCookies() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	cookies, ok := a.Cookies.Type.(Object)
+	if !ok {
+		verr.Add(a, `"Cookies" field of action is not an object`)
+		return verr
+	}
+	params := a.AllParams().Type.ToObject()
+	for n, att := range cookies {
+		if !att.Type.IsPrimitive() {
+			verr.Add(a, "cookie %#v must use a primitive type", n)
+		}
+		if _, ok := params[n]; ok {
+			verr.Add(a, "cookie %#v has the same name as a parameter", n)
+		}
+	}
+	return verr
+}
+
+// validateFields makes sure the action OK response uses a known media type and that the fields
+// parameter does not conflict with the action parameters.
+func (a *ActionDefinition) validateFields() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if resp, ok := a.Responses["OK"]; !ok {
+		verr.Add(a, "action supporting field selection must define an OK response")
+	} else if Design.MediaTypeWithIdentifier(resp.MediaType) == nil {
+		verr.Add(a, "OK response of action supporting field selection must use a media type defined in the design")
+	}
+	if a.Params != nil {
+		if _, ok := a.Params.Type.ToObject()[FieldsParam]; ok {
+			verr.Add(a, "parameter %#v is defined by Fields and cannot be redefined", FieldsParam)
+		}
+	}
+	if a.Stream != nil {
+		verr.Add(a, "streaming action cannot support field selection")
+	}
+	return verr
+}
+
+// validatePagination makes sure the pagination parameters and headers do not conflict with the
+// action parameters and headers.
+func (a *ActionDefinition) validatePagination() *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if a.Pagination.Style != OffsetPagination && a.Pagination.Style != CursorPagination {
+		verr.Add(a, "invalid pagination style %#v, must be %#v or %#v", a.Pagination.Style, OffsetPagination, CursorPagination)
+	}
+	if a.Params != nil {
+		params := a.Params.Type.ToObject()
+		for n := range a.Pagination.Params() {
+			if _, ok := params[n]; ok {
+				verr.Add(a, "parameter %#v is defined by the pagination and cannot be redefined", n)
+			}
+		}
+	}
+	if _, ok := a.Responses["OK"]; !ok {
+		verr.Add(a, "paginated action must define an OK response")
+	}
+	return verr
+}
+
 // ValidateParams checks the action parameters (make sure they have names, members and types).
 func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 	verr := new(dslengine.ValidationErrors)
@@ -386,6 +592,8 @@ func (a *ActionDefinition) ValidateParams() *dslengine.ValidationErrors {
 			verr.Add(a, `parameter %s cannot be an object, only action payloads may be of type object`, n)
 		} else if p.Type.Kind() == HashKind {
 			verr.Add(a, `parameter %s cannot be a hash, only action payloads may be of type hash`, n)
+		} else if p.Type.Kind() == FileKind {
+			verr.Add(a, `parameter %s cannot be a file, only multipart form payloads may contain files`, n)
 		}
 		ctx := fmt.Sprintf("parameter %s", n)
 		verr.Merge(p.Validate(ctx, a))
@@ -431,6 +639,9 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 			verr.Add(parent, "%sdefault value %#v is not one of the accepted values: %#v", ctx, a.DefaultValue, a.Validation.Values)
 		}
 	}
+	if u, ok := a.Type.(*Union); ok {
+		verr.Merge(u.Validate(ctx, parent))
+	}
 	o := a.Type.ToObject()
 	if o != nil {
 		for _, n := range a.AllRequired() {
@@ -459,6 +670,51 @@ func (a *AttributeDefinition) Validate(ctx string, parent dslengine.Definition)
 	return verr.AsError()
 }
 
+// Validate checks that the union definition is consistent: the discriminator and the variant
+// discriminator values must be set and the variants must be object user types that define
+// distinct attributes.
+func (u *Union) Validate(ctx string, parent dslengine.Definition) *dslengine.ValidationErrors {
+	verr := new(dslengine.ValidationErrors)
+	if u.Discriminator == "" {
+		verr.Add(parent, "%sunion discriminator cannot be empty", ctx)
+	}
+	if len(u.Variants) == 0 {
+		verr.Add(parent, "%sunion must define at least one variant", ctx)
+	}
+	values := make(map[string]bool)
+	owners := make(map[string]string)
+	for _, v := range u.Variants {
+		if v.Value == "" {
+			verr.Add(parent, "%sunion variant discriminator value cannot be empty", ctx)
+		} else if values[v.Value] {
+			verr.Add(parent, "%sduplicate union variant discriminator value %#v", ctx, v.Value)
+		}
+		values[v.Value] = true
+		if v.Type == nil {
+			verr.Add(parent, "%sunion variant %#v type cannot be nil", ctx, v.Value)
+			continue
+		}
+		if _, ok := v.Type.Type.(Object); !ok {
+			verr.Add(parent, "%sunion variant %#v type %s must be an object", ctx, v.Value, v.Type.TypeName)
+			continue
+		}
+		for n := range v.Type.ToObject() {
+			if n == u.Discriminator {
+				verr.Add(parent, "%sunion variant %#v type %s cannot define the discriminator attribute %#v",
+					ctx, v.Value, v.Type.TypeName, n)
+				continue
+			}
+			if other, ok := owners[n]; ok {
+				verr.Add(parent, "%sunion variants %s and %s both define attribute %#v",
+					ctx, other, v.Type.TypeName, n)
+				continue
+			}
+			owners[n] = v.Type.TypeName
+		}
+	}
+	return verr.AsError()
+}
+
 // Validate checks that the response definition is consistent: its status is set and the media
 // type definition if any is valid.
 func (r *ResponseDefinition) Validate() *dslengine.ValidationErrors {
@@ -0,0 +1,58 @@
+package design
+
+import (
+	"fmt"
+	"time"
+)
+
+// MessagesDefinition describes the messages exchanged over the connection of a WebSocket action.
+type MessagesDefinition struct {
+	// Inbound is the type of the messages sent by the client, nil if the client does not
+	// send messages. It is a user type or a media type.
+	Inbound DataType
+	// Outbound is the type of the messages sent by the server, nil if the server does not
+	// send messages. It is a user type or a media type.
+	Outbound DataType
+	// KeepAlive is the interval between the ping frames sent by the server to keep the
+	// connection alive, no ping frame is sent if zero.
+	KeepAlive time.Duration
+	// Parent is the WebSocket action.
+	Parent *ActionDefinition
+	// DSLFunc contains the DSL used to create this definition if any.
+	DSLFunc func()
+}
+
+// Context returns the generic definition name used in error messages.
+func (m *MessagesDefinition) Context() string {
+	if m.Parent != nil {
+		return fmt.Sprintf("messages of %s", m.Parent.Context())
+	}
+	return "messages"
+}
+
+// DSL returns the initialization DSL.
+func (m *MessagesDefinition) DSL() func() {
+	return m.DSLFunc
+}
+
+// InboundType returns the user type of the inbound messages, nil if there isn't one.
+func (m *MessagesDefinition) InboundType() *UserTypeDefinition {
+	return messageType(m.Inbound)
+}
+
+// OutboundType returns the user type of the outbound messages, nil if there isn't one.
+func (m *MessagesDefinition) OutboundType() *UserTypeDefinition {
+	return messageType(m.Outbound)
+}
+
+// messageType returns the user type of the given message data type, nil if the data type is not a
+// user type or a media type.
+func messageType(dt DataType) *UserTypeDefinition {
+	switch actual := dt.(type) {
+	case *UserTypeDefinition:
+		return actual
+	case *MediaTypeDefinition:
+		return actual.UserTypeDefinition
+	}
+	return nil
+}
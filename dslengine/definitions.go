@@ -94,12 +94,32 @@ type (
 		// Maximum represents a maximum value validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor17.
 		Maximum *float64
+		// ExclusiveMinimum indicates whether the Minimum value is excluded from the range of
+		// valid values as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor21.
+		ExclusiveMinimum bool
+		// ExclusiveMaximum indicates whether the Maximum value is excluded from the range of
+		// valid values as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor17.
+		ExclusiveMaximum bool
+		// MultipleOf represents a multiple of validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor14.
+		MultipleOf *float64
 		// MinLength represents an minimum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor29.
 		MinLength *int
 		// MaxLength represents an maximum length validation as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor26.
 		MaxLength *int
+		// UniqueItems represents a unique items validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor49.
+		UniqueItems bool
+		// MinProperties represents a minimum number of properties validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor57.
+		MinProperties *int
+		// MaxProperties represents a maximum number of properties validation as described at
+		// http://json-schema.org/latest/json-schema-validation.html#anchor54.
+		MaxProperties *int
 		// Required list the required fields of object attributes as described at
 		// http://json-schema.org/latest/json-schema-validation.html#anchor61.
 		Required []string
@@ -137,9 +157,14 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	}
 	if v.Minimum == nil || (other.Minimum != nil && *v.Minimum > *other.Minimum) {
 		v.Minimum = other.Minimum
+		v.ExclusiveMinimum = other.ExclusiveMinimum
 	}
 	if v.Maximum == nil || (other.Maximum != nil && *v.Maximum < *other.Maximum) {
 		v.Maximum = other.Maximum
+		v.ExclusiveMaximum = other.ExclusiveMaximum
+	}
+	if v.MultipleOf == nil {
+		v.MultipleOf = other.MultipleOf
 	}
 	if v.MinLength == nil || (other.MinLength != nil && *v.MinLength > *other.MinLength) {
 		v.MinLength = other.MinLength
@@ -147,6 +172,13 @@ func (v *ValidationDefinition) Merge(other *ValidationDefinition) {
 	if v.MaxLength == nil || (other.MaxLength != nil && *v.MaxLength < *other.MaxLength) {
 		v.MaxLength = other.MaxLength
 	}
+	v.UniqueItems = v.UniqueItems || other.UniqueItems
+	if v.MinProperties == nil || (other.MinProperties != nil && *v.MinProperties > *other.MinProperties) {
+		v.MinProperties = other.MinProperties
+	}
+	if v.MaxProperties == nil || (other.MaxProperties != nil && *v.MaxProperties < *other.MaxProperties) {
+		v.MaxProperties = other.MaxProperties
+	}
 	v.AddRequired(other.Required)
 }
 
@@ -169,13 +201,19 @@ func (v *ValidationDefinition) AddRequired(required []string) {
 // Dup makes a shallow dup of the validation.
 func (v *ValidationDefinition) Dup() *ValidationDefinition {
 	return &ValidationDefinition{
-		Values:    v.Values,
-		Format:    v.Format,
-		Pattern:   v.Pattern,
-		Minimum:   v.Minimum,
-		Maximum:   v.Maximum,
-		MinLength: v.MinLength,
-		MaxLength: v.MaxLength,
-		Required:  v.Required,
+		Values:           v.Values,
+		Format:           v.Format,
+		Pattern:          v.Pattern,
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		UniqueItems:      v.UniqueItems,
+		MinProperties:    v.MinProperties,
+		MaxProperties:    v.MaxProperties,
+		Required:         v.Required,
 	}
 }
@@ -78,6 +78,20 @@ func NewGobEncoder(w io.Writer) Encoder { return gob.NewEncoder(w) }
 // NewGobDecoder is an adapter for the encoding package gob decoder.
 func NewGobDecoder(r io.Reader) Decoder { return gob.NewDecoder(r) }
 
+// NewFormEncoder returns an encoder that writes values using the
+// application/x-www-form-urlencoded encoding. Nested objects and arrays are encoded using the
+// bracket notation, for example "address[city]=Paris&tags[0]=a&tags[1]=b". The encoder uses
+// the JSON field names of the value being encoded.
+func NewFormEncoder(w io.Writer) Encoder { return &formEncoder{w: w} }
+
+// NewFormDecoder returns a decoder that reads application/x-www-form-urlencoded bodies.
+// Form field names are matched against the JSON field names of the value being decoded and
+// the field values are coerced into the corresponding Go types. Nested objects and arrays may
+// be described using the bracket notation: "address[city]=Paris" sets the field "city" of the
+// object "address" while "tags[]=a&tags[]=b", "tags[0]=a&tags[1]=b" and "tags=a&tags=b" all
+// build the array "tags".
+func NewFormDecoder(r io.Reader) Decoder { return &formDecoder{r: r} }
+
 // DecodeRequest retrieves the request body and `Content-Type` header and uses Decode to unmarshal
 // into the provided value.
 func (service *Service) DecodeRequest(req *http.Request, v interface{}) error {
@@ -178,8 +192,15 @@ func (p *decoderPool) Put(d Decoder) {
 // EncodeResponse uses registered Encoders to marshal the response body based on the request Accept
 // header and writes it to the http.ResponseWriter
 func (service *Service) EncodeResponse(ctx context.Context, v interface{}) error {
-	now := time.Now()
 	accept := ContextRequest(ctx).Header.Get("Accept")
+	return service.Encode(v, ContextResponse(ctx), accept)
+}
+
+// Encode uses registered encoders to marshal the given value into w using the encoder that matches
+// the given Accept header value. It uses the default encoder if accept is empty or if no encoder
+// is registered for it.
+func (service *Service) Encode(v interface{}, w io.Writer, accept string) error {
+	now := time.Now()
 	if accept == "" {
 		accept = "*/*"
 	}
@@ -200,7 +221,7 @@ func (service *Service) EncodeResponse(ctx context.Context, v interface{}) error
 	}
 
 	// the encoderPool will handle whether or not a pool is actually in use
-	encoder := p.Get(ContextResponse(ctx))
+	encoder := p.Get(w)
 	if err := encoder.Encode(v); err != nil {
 		return err
 	}
@@ -55,6 +55,13 @@ var (
 	// ErrNotFound is the error returned to requests that don't match a registered handler.
 	ErrNotFound = NewErrorClass("not_found", 404)
 
+	// ErrPreconditionFailed is the error returned to requests whose If-Match or
+	// If-Unmodified-Since precondition fails.
+	ErrPreconditionFailed = NewErrorClass("precondition_failed", 412)
+
+	// ErrTooManyRequests is the error returned to requests that exceed a rate limit.
+	ErrTooManyRequests = NewErrorClass("too_many_requests", 429)
+
 	// ErrInternal is the class of error used for uncaught errors.
 	ErrInternal = NewErrorClass("internal", 500)
 )
@@ -72,6 +79,16 @@ type (
 		MetaValues map[string]interface{} `json:"meta,omitempty" xml:"meta,omitempty"`
 	}
 
+	// ServiceError is the interface implemented by the errors that the error handler middleware
+	// turns into responses that carry them: Error and the typed errors generated by goagen.
+	ServiceError interface {
+		error
+		// ResponseStatus returns the HTTP status code used by responses that carry the error.
+		ResponseStatus() int
+		// ErrorCode returns the code that identifies the class of errors.
+		ErrorCode() string
+	}
+
 	// ErrorClass is an error generating function.
 	// It accepts a format and values and produces errors with the resulting string.
 	// If the format is a string or a Stringer then the string value is used.
@@ -127,6 +144,11 @@ func MissingHeaderError(name string) *Error {
 	return ErrInvalidRequest("missing required HTTP header %#v", name)
 }
 
+// MissingCookieError is the error produced when a request is missing a required cookie.
+func MissingCookieError(name string) *Error {
+	return ErrInvalidRequest("missing required cookie %#v", name)
+}
+
 // InvalidEnumValueError is the error produced when the value of a parameter or payload field does
 // not match one the values defined in the design Enum validation.
 func InvalidEnumValueError(ctx string, val interface{}, allowed []interface{}) *Error {
@@ -169,6 +191,38 @@ func InvalidLengthError(ctx string, target interface{}, ln, value int, min bool)
 	return ErrInvalidRequest("length of %s must be %s than %d but got value %#v (len=%d)", ctx, comp, value, target, ln)
 }
 
+// InvalidExclusiveRangeError is the error produced when the value of a parameter or payload field
+// does not match the exclusive range validation defined in the design.
+func InvalidExclusiveRangeError(ctx string, target interface{}, value interface{}, min bool) *Error {
+	comp := "greater"
+	if !min {
+		comp = "lesser"
+	}
+	return ErrInvalidRequest("%s must be %s than %v but got value %#v", ctx, comp, value, target)
+}
+
+// InvalidMultipleOfError is the error produced when the value of a parameter or payload field
+// does not match the multiple of validation defined in the design.
+func InvalidMultipleOfError(ctx string, target interface{}, value interface{}) *Error {
+	return ErrInvalidRequest("%s must be a multiple of %v but got value %#v", ctx, value, target)
+}
+
+// InvalidUniqueItemsError is the error produced when the elements of a parameter or payload
+// field array are not unique while the design defines a unique items validation.
+func InvalidUniqueItemsError(ctx string, target interface{}) *Error {
+	return ErrInvalidRequest("elements of %s must be unique but got value %#v", ctx, target)
+}
+
+// InvalidPropertiesCountError is the error produced when the number of keys of a parameter or
+// payload field hash does not match the min or max properties validation defined in the design.
+func InvalidPropertiesCountError(ctx string, target interface{}, count, value int, min bool) *Error {
+	comp := "greater or equal"
+	if !min {
+		comp = "lesser or equal"
+	}
+	return ErrInvalidRequest("number of properties of %s must be %s than %d but got value %#v (count=%d)", ctx, comp, value, target, count)
+}
+
 // NoSecurityScheme is the error produced when goa is unable to lookup a security scheme defined in
 // the design.
 func NoSecurityScheme(schemeName string) *Error {
@@ -180,6 +234,16 @@ func (e *Error) Error() string {
 	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
 }
 
+// ResponseStatus returns the HTTP status code used by responses that carry the error.
+func (e *Error) ResponseStatus() int {
+	return e.Status
+}
+
+// ErrorCode returns the code that identifies the class of errors.
+func (e *Error) ErrorCode() string {
+	return e.Code
+}
+
 // Meta adds key/value pairs to the error metadata.
 func (e *Error) Meta(keyvals ...interface{}) *Error {
 	if e.MetaValues == nil {
@@ -135,6 +135,22 @@ var _ = Describe("MissingHeaderError", func() {
 	})
 })
 
+var _ = Describe("MissingCookieError", func() {
+	var valErr error
+	name := "session"
+
+	JustBeforeEach(func() {
+		valErr = goa.MissingCookieError(name)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&goa.Error{}))
+		err := valErr.(*goa.Error)
+		Ω(err.Detail).Should(ContainSubstring(name))
+	})
+})
+
 var _ = Describe("InvalidEnumValueError", func() {
 	var valErr error
 	ctx := "ctx"
@@ -219,6 +235,48 @@ var _ = Describe("InvalidRangeError", func() {
 	})
 })
 
+var _ = Describe("InvalidExclusiveRangeError", func() {
+	var valErr error
+	ctx := "ctx"
+	target := "target"
+	value := 42
+	min := true
+
+	JustBeforeEach(func() {
+		valErr = goa.InvalidExclusiveRangeError(ctx, target, value, min)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&goa.Error{}))
+		err := valErr.(*goa.Error)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("greater than"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", value)))
+		Ω(err.Detail).Should(ContainSubstring(target))
+	})
+})
+
+var _ = Describe("InvalidMultipleOfError", func() {
+	var valErr error
+	ctx := "ctx"
+	target := 42
+	value := 5
+
+	JustBeforeEach(func() {
+		valErr = goa.InvalidMultipleOfError(ctx, target, value)
+	})
+
+	It("creates a http error", func() {
+		Ω(valErr).ShouldNot(BeNil())
+		Ω(valErr).Should(BeAssignableToTypeOf(&goa.Error{}))
+		err := valErr.(*goa.Error)
+		Ω(err.Detail).Should(ContainSubstring(ctx))
+		Ω(err.Detail).Should(ContainSubstring("multiple of 5"))
+		Ω(err.Detail).Should(ContainSubstring(fmt.Sprintf("%#v", target)))
+	})
+})
+
 var _ = Describe("InvalidLengthError", func() {
 	const ctx = "ctx"
 	const value = 42
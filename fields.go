@@ -0,0 +1,82 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// SelectFields returns a copy of the given response body that only contains the given fields. The
+// fields are given by their dot separated path, e.g. "account.name", and are matched against the
+// JSON representation of v: the fields of collection elements are selected using the path of the
+// collection. Selecting a field selects all its children. SelectFields returns v if fields is
+// empty.
+func SelectFields(v interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return v, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	return selectFields(raw, newFieldSet(fields)), nil
+}
+
+// fieldSet is the tree of selected fields, a nil fieldSet selects all fields.
+type fieldSet map[string]fieldSet
+
+// newFieldSet builds the tree of the given field paths.
+func newFieldSet(fields []string) fieldSet {
+	set := make(fieldSet)
+	for _, f := range fields {
+		cur := set
+		parts := strings.Split(f, ".")
+		for i, p := range parts {
+			child, ok := cur[p]
+			if ok && child == nil {
+				// Parent field already selected entirely
+				break
+			}
+			if i == len(parts)-1 {
+				cur[p] = nil
+				break
+			}
+			if !ok {
+				child = make(fieldSet)
+				cur[p] = child
+			}
+			cur = child
+		}
+	}
+	return set
+}
+
+// selectFields returns the value that only contains the fields of raw selected by set.
+func selectFields(raw interface{}, set fieldSet) interface{} {
+	if set == nil {
+		return raw
+	}
+	switch actual := raw.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(set))
+		for n, child := range set {
+			if v, ok := actual[n]; ok {
+				res[n] = selectFields(v, child)
+			}
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(actual))
+		for i, elem := range actual {
+			res[i] = selectFields(elem, set)
+		}
+		return res
+	}
+	return raw
+}
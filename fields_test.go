@@ -0,0 +1,80 @@
+package goa_test
+
+import (
+	"encoding/json"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("SelectFields", func() {
+	type account struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+	type bottle struct {
+		ID      int64    `json:"id"`
+		Name    string   `json:"name,omitempty"`
+		Account *account `json:"account,omitempty"`
+	}
+	var v interface{}
+	var fields []string
+	var selected interface{}
+	var err error
+
+	BeforeEach(func() {
+		v = &bottle{ID: 9007199254740993, Name: "red", Account: &account{ID: 1, Name: "joe"}}
+		fields = nil
+	})
+
+	JustBeforeEach(func() {
+		selected, err = goa.SelectFields(v, fields)
+	})
+
+	selectedJSON := func() string {
+		b, err := json.Marshal(selected)
+		Ω(err).ShouldNot(HaveOccurred())
+		return string(b)
+	}
+
+	Context("with no field", func() {
+		It("returns the value", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(selected).Should(BeIdenticalTo(v))
+		})
+	})
+
+	Context("with top level and nested fields", func() {
+		BeforeEach(func() {
+			fields = []string{"id", "account.name", "unknown"}
+		})
+
+		It("only renders the selected fields", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(selectedJSON()).Should(MatchJSON(`{"id":9007199254740993,"account":{"name":"joe"}}`))
+			Ω(selectedJSON()).Should(ContainSubstring("9007199254740993"))
+		})
+	})
+
+	Context("with a field and one of its children", func() {
+		BeforeEach(func() {
+			fields = []string{"account.name", "account"}
+		})
+
+		It("renders the field entirely", func() {
+			Ω(selectedJSON()).Should(MatchJSON(`{"account":{"id":1,"name":"joe"}}`))
+		})
+	})
+
+	Context("with a collection", func() {
+		BeforeEach(func() {
+			v = []*bottle{{ID: 1, Name: "red"}, {ID: 2, Name: "white"}}
+			fields = []string{"name"}
+		})
+
+		It("selects the fields of each element", func() {
+			Ω(selectedJSON()).Should(MatchJSON(`[{"name":"red"},{"name":"white"}]`))
+		})
+	})
+})
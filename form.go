@@ -0,0 +1,401 @@
+package goa
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// formDecoder decodes application/x-www-form-urlencoded bodies.
+	formDecoder struct {
+		r io.Reader
+	}
+
+	// formEncoder encodes values into application/x-www-form-urlencoded bodies.
+	formEncoder struct {
+		w io.Writer
+	}
+
+	// formNode is a node of the tree built from the form field names. Leaf nodes hold the
+	// field values, inner nodes hold the nested fields indexed by name.
+	formNode struct {
+		values   []string
+		children map[string]*formNode
+		keys     []string
+		// appended is true if the children were added with the empty key, e.g. "tags[]".
+		appended bool
+	}
+)
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// Decode reads the form from the underlying reader and stores the result in v. The form field
+// names are matched with the "form" tags of the struct fields, with their JSON names if they have
+// none, so that the generated types whose JSON names are overridden with the "struct:tag:json"
+// metadata still decode the design attribute names.
+func (d *formDecoder) Decode(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("form decoder: invalid target type %T, must be a non-nil pointer", v)
+	}
+	b, err := ioutil.ReadAll(d.r)
+	if err != nil {
+		return err
+	}
+	// Parse the fields in order as appended elements depend on the fields that precede them.
+	root := &formNode{}
+	for _, field := range strings.Split(string(b), "&") {
+		if field == "" {
+			continue
+		}
+		var val string
+		if i := strings.IndexByte(field, '='); i >= 0 {
+			field, val = field[:i], field[i+1:]
+		}
+		name, err := url.QueryUnescape(field)
+		if err != nil {
+			return err
+		}
+		if val, err = url.QueryUnescape(val); err != nil {
+			return err
+		}
+		path, err := parseFormKey(name)
+		if err != nil {
+			return err
+		}
+		if err := root.insert(path, val, name); err != nil {
+			return err
+		}
+	}
+	if root.children == nil {
+		return nil
+	}
+	data, err := root.convert(rv.Type().Elem(), "")
+	if err != nil {
+		return err
+	}
+	js, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(js, v)
+}
+
+// Reset sets the reader used by the decoder.
+func (d *formDecoder) Reset(r io.Reader) { d.r = r }
+
+// Encode writes the form encoding of v to the underlying writer. The form field names are the
+// "form" tags of the struct fields, their JSON names if they have none.
+func (e *formEncoder) Encode(v interface{}) error {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var data interface{}
+	dec := json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	if err := dec.Decode(&data); err != nil {
+		return err
+	}
+	obj, ok := data.(map[string]interface{})
+	if !ok && data != nil {
+		return fmt.Errorf("form encoder: invalid value type %T, must be an object", v)
+	}
+	values := make(url.Values)
+	flattenForm("", obj, reflect.TypeOf(v), values)
+	_, err = io.WriteString(e.w, values.Encode())
+	return err
+}
+
+// Reset sets the writer used by the encoder.
+func (e *formEncoder) Reset(w io.Writer) { e.w = w }
+
+// parseFormKey splits a form field name using the bracket notation into its path elements, e.g.
+// "a[b][0][]" produces ["a", "b", "0", ""].
+func parseFormKey(key string) ([]string, error) {
+	idx := strings.IndexByte(key, '[')
+	if idx < 0 {
+		return []string{key}, nil
+	}
+	path := []string{key[:idx]}
+	rest := key[idx:]
+	for len(rest) > 0 {
+		if rest[0] != '[' {
+			return nil, fmt.Errorf("form decoder: invalid field name %#v", key)
+		}
+		end := strings.IndexByte(rest, ']')
+		if end < 0 {
+			return nil, fmt.Errorf("form decoder: invalid field name %#v", key)
+		}
+		path = append(path, rest[1:end])
+		rest = rest[end+1:]
+	}
+	return path, nil
+}
+
+// insert adds the value at the given path, name is the form field name used in error messages.
+// Empty path elements append a new child unless the path continues with a field that the last
+// appended child does not have yet, so that "items[][name]=x&items[][qty]=1" produces a single
+// element. A node cannot mix appended children with children added with an explicit key.
+func (n *formNode) insert(path []string, val, name string) error {
+	if len(path) == 0 {
+		n.values = append(n.values, val)
+		return nil
+	}
+	key := path[0]
+	if n.children == nil {
+		n.children = make(map[string]*formNode)
+		n.appended = key == ""
+	} else if n.appended != (key == "") {
+		return fmt.Errorf("form decoder: field %#v mixes appended elements and elements with an explicit key", name)
+	}
+	if key == "" {
+		key = strconv.Itoa(len(n.keys))
+		if len(path) > 1 && path[1] != "" && len(n.keys) > 0 {
+			last := n.children[n.keys[len(n.keys)-1]]
+			if _, ok := last.children[path[1]]; !ok && last.values == nil {
+				return last.insert(path[1:], val, name)
+			}
+		}
+	}
+	child, ok := n.children[key]
+	if !ok {
+		child = &formNode{}
+		n.children[key] = child
+		n.keys = append(n.keys, key)
+	}
+	return child.insert(path[1:], val, name)
+}
+
+// convert coerces the node into a value that serializes to the JSON representation of t.
+func (n *formNode) convert(t reflect.Type, ctx string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if n.children == nil && (reflect.PtrTo(t).Implements(jsonUnmarshalerType) ||
+		reflect.PtrTo(t).Implements(textUnmarshalerType)) {
+		return n.value(), nil
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.children == nil {
+			return nil, fmt.Errorf("form decoder: invalid value for field %#v, must be an object", ctx)
+		}
+		res := make(map[string]interface{}, len(n.children))
+		for _, k := range n.keys {
+			ft, jn := reflect.TypeOf((*interface{})(nil)).Elem(), k
+			if f, ok := formField(t, k); ok {
+				ft, jn = f.Type, jsonFieldName(f)
+			}
+			v, err := n.children[k].convert(ft, formContext(ctx, k))
+			if err != nil {
+				return nil, err
+			}
+			res[jn] = v
+		}
+		return res, nil
+	case reflect.Map:
+		if n.children == nil {
+			return nil, fmt.Errorf("form decoder: invalid value for field %#v, must be an object", ctx)
+		}
+		res := make(map[string]interface{}, len(n.children))
+		for _, k := range n.keys {
+			v, err := n.children[k].convert(t.Elem(), formContext(ctx, k))
+			if err != nil {
+				return nil, err
+			}
+			res[k] = v
+		}
+		return res, nil
+	case reflect.Slice, reflect.Array:
+		var elems []*formNode
+		if n.children != nil {
+			keys := make([]string, len(n.keys))
+			copy(keys, n.keys)
+			if indices, ok := formIndices(keys); ok {
+				sort.Sort(indices)
+				for i, idx := range indices {
+					keys[i] = strconv.Itoa(idx)
+				}
+			}
+			for _, k := range keys {
+				elems = append(elems, n.children[k])
+			}
+		} else {
+			for _, v := range n.values {
+				elems = append(elems, &formNode{values: []string{v}})
+			}
+		}
+		res := make([]interface{}, len(elems))
+		for i, e := range elems {
+			v, err := e.convert(t.Elem(), fmt.Sprintf("%s[%d]", ctx, i))
+			if err != nil {
+				return nil, err
+			}
+			res[i] = v
+		}
+		return res, nil
+	case reflect.Interface:
+		if n.children != nil {
+			return n.convert(reflect.TypeOf(map[string]interface{}{}), ctx)
+		}
+		if len(n.values) > 1 {
+			return n.values, nil
+		}
+		return n.value(), nil
+	}
+
+	if n.children != nil {
+		return nil, fmt.Errorf("form decoder: invalid value for field %#v, must be a %s", ctx, t.Kind())
+	}
+	val := n.value()
+	if t.Kind() == reflect.String {
+		return val, nil
+	}
+	if val == "" {
+		return nil, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return nil, fmt.Errorf("form decoder: invalid value %#v for field %#v, must be a boolean", val, ctx)
+		}
+		return b, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(val, 10, 64); err != nil {
+			return nil, fmt.Errorf("form decoder: invalid value %#v for field %#v, must be an integer", val, ctx)
+		}
+		return json.Number(val), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(val, 10, 64); err != nil {
+			return nil, fmt.Errorf("form decoder: invalid value %#v for field %#v, must be an integer", val, ctx)
+		}
+		return json.Number(val), nil
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(val, 64); err != nil {
+			return nil, fmt.Errorf("form decoder: invalid value %#v for field %#v, must be a number", val, ctx)
+		}
+		return json.Number(val), nil
+	}
+	return nil, fmt.Errorf("form decoder: unsupported type %s for field %#v", t, ctx)
+}
+
+// value returns the last value of the node, the empty string if there is none.
+func (n *formNode) value() string {
+	if len(n.values) == 0 {
+		return ""
+	}
+	return n.values[len(n.values)-1]
+}
+
+// formIndices returns the integer values of keys if they all are array indices.
+func formIndices(keys []string) (sort.IntSlice, bool) {
+	indices := make(sort.IntSlice, len(keys))
+	for i, k := range keys {
+		idx, err := strconv.Atoi(k)
+		if err != nil {
+			return nil, false
+		}
+		indices[i] = idx
+	}
+	return indices, true
+}
+
+// formField returns the struct field of t that holds the form field with the given name, see
+// formFieldName. Names are matched case insensitively if no field matches exactly.
+func formField(t reflect.Type, name string) (reflect.StructField, bool) {
+	var fold *reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+			continue
+		}
+		fn := formFieldName(f)
+		if fn == name {
+			return f, true
+		}
+		if fold == nil && strings.EqualFold(fn, name) {
+			fold = &f
+		}
+	}
+	if fold != nil {
+		return *fold, true
+	}
+	return reflect.StructField{}, false
+}
+
+// formFieldName returns the name of the form field held by the given struct field: the name of
+// its "form" tag if any, its JSON name otherwise.
+func formFieldName(f reflect.StructField) string {
+	if tn := strings.Split(f.Tag.Get("form"), ",")[0]; tn != "" {
+		return tn
+	}
+	return jsonFieldName(f)
+}
+
+// jsonFieldName returns the name of the JSON object key that encodes the given struct field.
+func jsonFieldName(f reflect.StructField) string {
+	if tn := strings.Split(f.Tag.Get("json"), ",")[0]; tn != "" {
+		return tn
+	}
+	return f.Name
+}
+
+// formContext computes the name of a nested field used in error messages.
+func formContext(ctx, name string) string {
+	if ctx == "" {
+		return name
+	}
+	return ctx + "[" + name + "]"
+}
+
+// flattenForm writes the value v into values using the bracket notation for nested objects and
+// arrays. v is the JSON representation of a value of type t, t is used to compute the form field
+// names of the struct fields.
+func flattenForm(prefix string, v interface{}, t reflect.Type, values url.Values) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		for k, e := range actual {
+			var et reflect.Type
+			name := k
+			if t != nil && t.Kind() == reflect.Struct {
+				for i := 0; i < t.NumField(); i++ {
+					if f := t.Field(i); f.PkgPath == "" && jsonFieldName(f) == k {
+						et, name = f.Type, formFieldName(f)
+						break
+					}
+				}
+			} else if t != nil && t.Kind() == reflect.Map {
+				et = t.Elem()
+			}
+			flattenForm(formContext(prefix, name), e, et, values)
+		}
+	case []interface{}:
+		var et reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			et = t.Elem()
+		}
+		for i, e := range actual {
+			flattenForm(fmt.Sprintf("%s[%d]", prefix, i), e, et, values)
+		}
+	case nil:
+	default:
+		values.Add(prefix, fmt.Sprintf("%v", actual))
+	}
+}
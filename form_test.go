@@ -0,0 +1,180 @@
+package goa_test
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type formAddress struct {
+	City *string `json:"city,omitempty"`
+	Zip  *int    `json:"zip,omitempty"`
+}
+
+type formItem struct {
+	Name *string `json:"name,omitempty"`
+	Qty  *int    `json:"qty,omitempty"`
+}
+
+type formPayload struct {
+	Name      *string        `json:"name,omitempty"`
+	Label     *string        `json:"custom_label,omitempty" form:"label"`
+	Items     []*formItem    `json:"items,omitempty"`
+	Count     *int           `json:"count,omitempty"`
+	Ratio     *float64       `json:"ratio,omitempty"`
+	Enabled   *bool          `json:"enabled,omitempty"`
+	Tags      []string       `json:"tags,omitempty"`
+	Ids       []int          `json:"ids,omitempty"`
+	Address   *formAddress   `json:"address,omitempty"`
+	Addresses []*formAddress `json:"addresses,omitempty"`
+}
+
+var _ = Describe("Form encoding", func() {
+	Describe("NewFormDecoder", func() {
+		var body string
+		var payload *formPayload
+		var decodeErr error
+
+		JustBeforeEach(func() {
+			payload = &formPayload{}
+			decodeErr = goa.NewFormDecoder(strings.NewReader(body)).Decode(payload)
+		})
+
+		Context("with primitive fields", func() {
+			BeforeEach(func() {
+				body = "name=foo&count=42&ratio=1.5&enabled=true"
+			})
+
+			It("coerces the values", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(*payload.Name).Should(Equal("foo"))
+				Ω(*payload.Count).Should(Equal(42))
+				Ω(*payload.Ratio).Should(Equal(1.5))
+				Ω(*payload.Enabled).Should(BeTrue())
+			})
+		})
+
+		Context("with arrays", func() {
+			BeforeEach(func() {
+				body = "tags=a&tags=b&ids[]=1&ids[]=2"
+			})
+
+			It("decodes the arrays", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(payload.Tags).Should(Equal([]string{"a", "b"}))
+				Ω(payload.Ids).Should(Equal([]int{1, 2}))
+			})
+		})
+
+		Context("with nested objects", func() {
+			BeforeEach(func() {
+				body = "address[city]=Paris&address[zip]=75001&" +
+					"addresses[1][city]=Lyon&addresses[0][city]=Nice"
+			})
+
+			It("decodes the objects", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(payload.Address).ShouldNot(BeNil())
+				Ω(*payload.Address.City).Should(Equal("Paris"))
+				Ω(*payload.Address.Zip).Should(Equal(75001))
+				Ω(payload.Addresses).Should(HaveLen(2))
+				Ω(*payload.Addresses[0].City).Should(Equal("Nice"))
+				Ω(*payload.Addresses[1].City).Should(Equal("Lyon"))
+			})
+		})
+
+		Context("with appended and indexed array elements", func() {
+			BeforeEach(func() {
+				body = "tags[1]=a&tags[]=b"
+			})
+
+			It("returns an error", func() {
+				Ω(decodeErr).Should(MatchError(`form decoder: field "tags[]" mixes appended elements and elements with an explicit key`))
+			})
+		})
+
+		Context("with appended objects", func() {
+			BeforeEach(func() {
+				body = "items[][name]=x&items[][qty]=1&items[][name]=y&items[][qty]=2"
+			})
+
+			It("starts a new element when a field repeats", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(payload.Items).Should(HaveLen(2))
+				Ω(*payload.Items[0].Name).Should(Equal("x"))
+				Ω(*payload.Items[0].Qty).Should(Equal(1))
+				Ω(*payload.Items[1].Name).Should(Equal("y"))
+				Ω(*payload.Items[1].Qty).Should(Equal(2))
+			})
+		})
+
+		Context("with a field whose JSON name differs from its form name", func() {
+			BeforeEach(func() {
+				body = "label=foo"
+			})
+
+			It("uses the form tag", func() {
+				Ω(decodeErr).ShouldNot(HaveOccurred())
+				Ω(payload.Label).ShouldNot(BeNil())
+				Ω(*payload.Label).Should(Equal("foo"))
+			})
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				body = "address[zip]=foo"
+			})
+
+			It("returns an error", func() {
+				Ω(decodeErr).Should(HaveOccurred())
+				Ω(decodeErr.Error()).Should(ContainSubstring("address[zip]"))
+			})
+		})
+	})
+
+	Describe("NewFormEncoder", func() {
+		var payload *formPayload
+		var values url.Values
+
+		BeforeEach(func() {
+			name, label, city, zip := "foo", "bar", "Paris", 75001
+			payload = &formPayload{
+				Name:      &name,
+				Label:     &label,
+				Tags:      []string{"a", "b"},
+				Address:   &formAddress{City: &city},
+				Addresses: []*formAddress{{Zip: &zip}},
+			}
+		})
+
+		JustBeforeEach(func() {
+			var b bytes.Buffer
+			err := goa.NewFormEncoder(&b).Encode(payload)
+			Ω(err).ShouldNot(HaveOccurred())
+			values, err = url.ParseQuery(b.String())
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("encodes using the bracket notation", func() {
+			Ω(values).Should(Equal(url.Values{
+				"name":              {"foo"},
+				"label":             {"bar"},
+				"tags[0]":           {"a"},
+				"tags[1]":           {"b"},
+				"address[city]":     {"Paris"},
+				"addresses[0][zip]": {"75001"},
+			}))
+		})
+
+		It("produces a body that decodes back into the value", func() {
+			decoded := &formPayload{}
+			err := goa.NewFormDecoder(strings.NewReader(values.Encode())).Decode(decoded)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(payload))
+		})
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Package format implements the textual formats of the Date, Duration and Decimal primitive types
+and the registry of custom validation formats. It is shared by the design package, which validates
+and generates examples, and by the goa runtime package, which parses and validates the values
+received by the generated code, so that the design package does not depend on the runtime.
+*/
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateLayout is the time layout of RFC3339 full-date values (e.g. "2016-07-11").
+const DateLayout = "2006-01-02"
+
+// decimalRegex matches valid decimal numbers.
+var decimalRegex = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// ParseDate parses a RFC3339 full-date value (e.g. "2016-07-11").
+func ParseDate(s string) (time.Time, error) {
+	return time.Parse(DateLayout, s)
+}
+
+// ParseDuration parses an ISO 8601 duration value (e.g. "P1DT12H" or "PT0.5S"). Years and months
+// are not supported as their length varies.
+func ParseDuration(s string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid ISO 8601 duration %#v", s)
+	str := s
+	neg := false
+	if strings.HasPrefix(str, "-") {
+		neg = true
+		str = str[1:]
+	}
+	if len(str) < 3 || str[0] != 'P' {
+		return 0, invalid
+	}
+	str = str[1:]
+	var total float64
+	inTime := false
+	for str != "" {
+		if str[0] == 'T' {
+			if inTime || len(str) == 1 {
+				return 0, invalid
+			}
+			inTime = true
+			str = str[1:]
+			continue
+		}
+		i := 0
+		for i < len(str) && (str[i] >= '0' && str[i] <= '9' || str[i] == '.' || str[i] == ',') {
+			i++
+		}
+		if i == 0 || i == len(str) {
+			return 0, invalid
+		}
+		n, err := strconv.ParseFloat(strings.Replace(str[:i], ",", ".", 1), 64)
+		if err != nil {
+			return 0, invalid
+		}
+		var unit time.Duration
+		switch {
+		case !inTime && str[i] == 'W':
+			unit = 7 * 24 * time.Hour
+		case !inTime && str[i] == 'D':
+			unit = 24 * time.Hour
+		case inTime && str[i] == 'H':
+			unit = time.Hour
+		case inTime && str[i] == 'M':
+			unit = time.Minute
+		case inTime && str[i] == 'S':
+			unit = time.Second
+		default:
+			return 0, invalid
+		}
+		total += n * float64(unit)
+		str = str[i+1:]
+	}
+	d := time.Duration(total + 0.5)
+	if neg {
+		d = -d
+	}
+	return d, nil
+}
+
+// FormatDuration returns the ISO 8601 representation of the duration using hours, minutes and
+// seconds (e.g. "PT36H0.5S").
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "PT0S"
+	}
+	var b bytes.Buffer
+	if d < 0 {
+		b.WriteByte('-')
+		d = -d
+	}
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		b.WriteString(strconv.FormatInt(int64(h), 10) + "H")
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		b.WriteString(strconv.FormatInt(int64(m), 10) + "M")
+		d -= m * time.Minute
+	}
+	if d > 0 {
+		b.WriteString(strconv.FormatFloat(float64(d)/float64(time.Second), 'f', -1, 64) + "S")
+	}
+	return b.String()
+}
+
+// ValidateDecimal returns an error if s is not a decimal number (e.g. "-12.345").
+func ValidateDecimal(s string) error {
+	if !decimalRegex.MatchString(s) {
+		return fmt.Errorf("invalid decimal %#v", s)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package format_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/format"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ParseDate", func() {
+	It("parses full dates", func() {
+		t, err := format.ParseDate("2016-07-11")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(t).Should(Equal(time.Date(2016, time.July, 11, 0, 0, 0, 0, time.UTC)))
+	})
+
+	It("rejects date times", func() {
+		_, err := format.ParseDate("2016-07-11T10:00:00Z")
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("ParseDuration", func() {
+	It("parses the values formatted by FormatDuration", func() {
+		d := 36*time.Hour + 90*time.Minute + 500*time.Millisecond
+		Ω(format.FormatDuration(d)).Should(Equal("PT37H30M0.5S"))
+		parsed, err := format.ParseDuration(format.FormatDuration(d))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(parsed).Should(Equal(d))
+	})
+
+	It("rejects years and months", func() {
+		_, err := format.ParseDuration("P1Y2M")
+		Ω(err).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("ValidateDecimal", func() {
+	It("accepts decimal numbers", func() {
+		Ω(format.ValidateDecimal("-12.345")).Should(Succeed())
+		Ω(format.ValidateDecimal("1e10")).Should(Succeed())
+	})
+
+	It("rejects other values", func() {
+		Ω(format.ValidateDecimal("12,5")).Should(HaveOccurred())
+	})
+})
+
+var _ = Describe("Register", func() {
+	It("records the validator of the format", func() {
+		Ω(format.IsRegistered("format-test")).Should(BeFalse())
+		format.Register("format-test", func(string) error { return nil })
+		Ω(format.IsRegistered("format-test")).Should(BeTrue())
+		validator, ok := format.Validator("format-test")
+		Ω(ok).Should(BeTrue())
+		Ω(validator("anything")).Should(Succeed())
+	})
+})
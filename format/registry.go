@@ -0,0 +1,35 @@
+package format
+
+import "sync"
+
+var (
+	// validators records the custom formats registered with Register.
+	validators = make(map[string]func(string) error)
+
+	// lock protects validators.
+	lock sync.RWMutex
+)
+
+// Register registers the validator function of a custom validation format. The function must
+// return an error if the value does not conform to the format. Use goa.RegisterFormat rather than
+// Register, it also prevents overriding the standard formats.
+func Register(name string, validator func(string) error) {
+	lock.Lock()
+	defer lock.Unlock()
+	validators[name] = validator
+}
+
+// IsRegistered returns true if a custom format with the given name was registered.
+func IsRegistered(name string) bool {
+	_, ok := Validator(name)
+	return ok
+}
+
+// Validator returns the validator function of the custom format with the given name and true if
+// the format was registered, nil and false otherwise.
+func Validator(name string) (func(string) error, bool) {
+	lock.RLock()
+	defer lock.RUnlock()
+	v, ok := validators[name]
+	return v, ok
+}
@@ -25,6 +25,10 @@ var (
 	// NoFormat causes "goimports" to be skipped when true.
 	NoFormat bool
 
+	// Resources lists the names of the resources the generated code is
+	// restricted to, the code covers all the resources of the design if empty.
+	Resources []string
+
 	// CommandName is the name of the command being run.
 	CommandName string
 
@@ -73,6 +77,7 @@ func RegisterFlags(r FlagRegistry) {
 	r.Flags().BoolVar(&Debug, "debug", false, "enable debug mode, does not cleanup temporary files.")
 	r.Flags().BoolVar(&NoFormat, "noformat", false, "disable goimports, useful to goa developers for debugging.")
 	r.Flags().MarkHidden("noformat")
+	r.Flags().StringSliceVar(&Resources, "resources", nil, "generate a sub-API made of the given comma separated resources")
 }
 
 // BaseCommand provides the basic logic for all commands. It implements
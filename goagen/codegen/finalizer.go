@@ -36,7 +36,19 @@ func init() {
 // given attribute.
 func RecursiveFinalizer(att *design.AttributeDefinition, target string, depth int) string {
 	var assignments []string
-	if o := att.Type.ToObject(); o != nil {
+	if u, ok := att.Type.(*design.Union); ok {
+		// Private structs embed the variant values, see GoTypeDef.
+		for _, v := range u.Variants {
+			assignment := RecursiveFinalizer(
+				&design.AttributeDefinition{Type: v.Type},
+				fmt.Sprintf("%s.%s", target, Goify(v.Type.TypeName, false)),
+				depth,
+			)
+			if assignment != "" {
+				assignments = append(assignments, assignment)
+			}
+		}
+	} else if o := att.Type.ToObject(); o != nil {
 		if mt, ok := att.Type.(*design.MediaTypeDefinition); ok {
 			att = mt.AttributeDefinition
 		} else if ut, ok := att.Type.(*design.UserTypeDefinition); ok {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/goadesign/goa/design"
@@ -87,6 +88,30 @@ func Tabs(depth int) string {
 // Add adds two integers and returns the sum of the two.
 func Add(a, b int) int { return a + b }
 
+// DurationLiteral returns the Go expression of the given duration using the largest unit of the
+// time package that divides it, e.g. "time.Minute" or "90 * time.Second".
+func DurationLiteral(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+		{time.Microsecond, "time.Microsecond"},
+	}
+	for _, u := range units {
+		if d%u.d == 0 {
+			if d == u.d {
+				return u.name
+			}
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", int64(d))
+}
+
 // CanonicalTemplate returns the resource URI template as a format string suitable for use in the
 // fmt.Printf function family.
 func CanonicalTemplate(r *design.ResourceDefinition) string {
@@ -14,6 +14,7 @@ var (
 	objectPublicizeT    *template.Template
 	arrayPublicizeT     *template.Template
 	hashPublicizeT      *template.Template
+	unionPublicizeT     *template.Template
 )
 
 func init() {
@@ -42,6 +43,9 @@ func init() {
 	if hashPublicizeT, err = template.New("hashPublicize").Funcs(fm).Parse(hashPublicizeTmpl); err != nil {
 		panic(err)
 	}
+	if unionPublicizeT, err = template.New("unionPublicize").Funcs(fm).Parse(unionPublicizeTmpl); err != nil {
+		panic(err)
+	}
 }
 
 // RecursivePublicizer produces code that copies fields from the private struct to the
@@ -60,7 +64,7 @@ func RecursivePublicizer(att *design.AttributeDefinition, source, target string,
 				catt,
 				fmt.Sprintf("%s.%s", source, Goify(n, true)),
 				fmt.Sprintf("%s.%s", target, Goify(n, true)),
-				catt.Type.IsPrimitive() && !att.IsPrimitivePointer(n),
+				catt.Type.IsPrimitive() && catt.Type.Kind() != design.FileKind && catt.Type.Kind() != design.BytesKind && !att.IsPrimitivePointer(n),
 				depth+1,
 				false,
 			)
@@ -87,6 +91,9 @@ func Publicizer(att *design.AttributeDefinition, sourceField, targetField string
 	switch {
 	case att.Type.IsPrimitive():
 		publication = RunTemplate(simplePublicizeT, data)
+	case att.Type.Kind() == design.UnionKind:
+		data["union"] = att.Type
+		publication = RunTemplate(unionPublicizeT, data)
 	case att.Type.IsObject():
 		if _, ok := att.Type.(*design.MediaTypeDefinition); ok {
 			publication = RunTemplate(recursivePublicizeT, data)
@@ -124,6 +131,15 @@ const (
 	objectPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} = &{{ gotypedef .att .depth true false }}{}
 {{ recursivePublicizer .att .sourceField .targetField .depth }}`
 
+	unionPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= &{{ gotypedef .att .depth true false }}{}
+{{ $disc := goify .union.Discriminator true }}{{ tabs .depth }}if {{ .sourceField }}.{{ $disc }} != nil {
+{{ tabs .depth }}	{{ .targetField }}.{{ $disc }} = *{{ .sourceField }}.{{ $disc }}
+{{ tabs .depth }}}
+{{ tabs .depth }}switch {{ .targetField }}.{{ $disc }} {
+{{ range .union.Variants }}{{ tabs $.depth }}case "{{ .Value }}":
+{{ tabs $.depth }}	{{ $.targetField }}.{{ goify .Type.TypeName true }} = {{ $.sourceField }}.{{ goify .Type.TypeName false }}.Publicize()
+{{ end }}{{ tabs .depth }}}`
+
 	arrayPublicizeTmpl = `{{ tabs .depth }}{{ .targetField }} {{ if .init }}:{{ end }}= make({{ gotyperef .att.Type .att.AllRequired .depth false }}, len({{ .sourceField }})){{/*
 */}}{{ $i := printf "%s%d" "i" .depth }}{{ $elem := printf "%s%d" "elem" .depth }}
 {{ tabs .depth }}for {{ $i }}, {{ $elem }} := range {{ .sourceField }} {
@@ -87,6 +87,8 @@ func GoTypeDef(ds design.DataStructure, tabs int, jsonTags, private bool) string
 		return fmt.Sprintf("map[%s]%s", keyDef, elemDef)
 	case design.Object:
 		return goTypeDefObject(actual, def, tabs, jsonTags, private)
+	case *design.Union:
+		return goTypeDefUnion(actual, tabs, jsonTags, private)
 	case *design.UserTypeDefinition:
 		return GoTypeName(actual, actual.AllRequired(), tabs, private)
 	case *design.MediaTypeDefinition:
@@ -111,7 +113,8 @@ func goTypeDefObject(actual design.Object, def *design.AttributeDefinition, tabs
 		WriteTabs(&buffer, tabs+1)
 		field := actual[name]
 		typedef := GoTypeDef(field, tabs+1, jsonTags, private)
-		if (field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name) {
+		kind := field.Type.Kind()
+		if kind != design.FileKind && kind != design.BytesKind && ((field.Type.IsPrimitive() && private) || field.Type.IsObject() || def.IsPrimitivePointer(name)) {
 			typedef = "*" + typedef
 		}
 		fname := name
@@ -138,6 +141,39 @@ func goTypeDefObject(actual design.Object, def *design.AttributeDefinition, tabs
 	return buffer.String()
 }
 
+// goTypeDefUnion returns the Go code that defines a Go struct for the given union type. The struct
+// holds the discriminator field and embeds one field per variant. Public structs embed pointers so
+// that only the variant in use is non-nil. Private structs embed values as encoding/json cannot
+// allocate embedded pointers to unexported types, the discriminator identifies the variant in use.
+func goTypeDefUnion(actual *design.Union, tabs int, jsonTags, private bool) string {
+	var buffer bytes.Buffer
+	buffer.WriteString("struct {\n")
+	parent := &design.AttributeDefinition{
+		Type:       actual.ToObject(),
+		Validation: &dslengine.ValidationDefinition{Required: []string{actual.Discriminator}},
+	}
+	WriteTabs(&buffer, tabs+1)
+	typedef := "string"
+	if private {
+		typedef = "*string"
+	}
+	var tags string
+	if jsonTags {
+		tags = attributeTags(parent, parent.Type.ToObject()[actual.Discriminator], actual.Discriminator, private)
+	}
+	buffer.WriteString(fmt.Sprintf("%s %s%s\n", Goify(actual.Discriminator, true), typedef, tags))
+	for _, v := range actual.Variants {
+		WriteTabs(&buffer, tabs+1)
+		if !private {
+			buffer.WriteString("*")
+		}
+		buffer.WriteString(GoTypeName(v.Type, nil, tabs+1, private) + "\n")
+	}
+	WriteTabs(&buffer, tabs)
+	buffer.WriteString("}")
+	return buffer.String()
+}
+
 // attributeTags computes the struct field tags.
 func attributeTags(parent, att *design.AttributeDefinition, name string, private bool) string {
 	var elems []string
@@ -157,6 +193,11 @@ func attributeTags(parent, att *design.AttributeDefinition, name string, private
 		}
 	}
 	if len(elems) > 0 {
+		_, hasJSON := att.Metadata["struct:tag:json"]
+		if _, hasForm := att.Metadata["struct:tag:form"]; hasJSON && !hasForm {
+			// Form bodies use the attribute names.
+			elems = append(elems, fmt.Sprintf("form:\"%s\"", name))
+		}
 		return " `" + strings.Join(elems, " ") + "`"
 	}
 	// Default algorithm
@@ -207,6 +248,8 @@ func GoTypeName(t design.DataType, required []string, tabs int, private bool) st
 			GoTypeRef(actual.KeyType.Type, actual.KeyType.AllRequired(), tabs+1, private),
 			GoTypeRef(actual.ElemType.Type, actual.ElemType.AllRequired(), tabs+1, private),
 		)
+	case *design.Union:
+		return goTypeDefUnion(actual, tabs, false, private)
 	case *design.UserTypeDefinition:
 		return Goify(actual.TypeName, !private)
 	case *design.MediaTypeDefinition:
@@ -238,12 +281,22 @@ func GoNativeType(t design.DataType) string {
 			return "uuid.UUID"
 		case design.AnyKind:
 			return "interface{}"
+		case design.FileKind:
+			return "*multipart.FileHeader"
+		case design.DateKind:
+			return "goa.Date"
+		case design.DurationKind:
+			return "goa.Duration"
+		case design.BytesKind:
+			return "[]byte"
+		case design.DecimalKind:
+			return "goa.Decimal"
 		default:
 			panic(fmt.Sprintf("goa bug: unknown primitive type %#v", actual))
 		}
 	case *design.Array:
 		return "[]" + GoNativeType(actual.ElemType.Type)
-	case design.Object:
+	case design.Object, *design.Union:
 		return "map[string]interface{}"
 	case *design.Hash:
 		return fmt.Sprintf("map[%s]%s", GoNativeType(actual.KeyType.Type), GoNativeType(actual.ElemType.Type))
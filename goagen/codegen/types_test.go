@@ -203,6 +203,24 @@ var _ = Describe("code generation", func() {
 					})
 				})
 
+				Context("using the json struct tag metadata", func() {
+					BeforeEach(func() {
+						object["foo"].Metadata = dslengine.MetadataDefinition{
+							"struct:tag:json": []string{"my_foo", "omitempty"},
+						}
+					})
+
+					It("keeps the attribute name in the form tag", func() {
+						expected := "struct {\n" +
+							"	Bar *string `json:\"bar,omitempty\" xml:\"bar,omitempty\"`\n" +
+							"	Baz *time.Time `json:\"baz,omitempty\" xml:\"baz,omitempty\"`\n" +
+							"	Foo *int `json:\"my_foo,omitempty\" form:\"foo\"`\n" +
+							"	Qux *uuid.UUID `json:\"qux,omitempty\" xml:\"qux,omitempty\"`\n" +
+							"}"
+						Ω(st).Should(Equal(expected))
+					})
+				})
+
 				Context("using struct field name metadata", func() {
 					BeforeEach(func() {
 						object["foo"].Metadata = dslengine.MetadataDefinition{
@@ -222,6 +240,76 @@ var _ = Describe("code generation", func() {
 				})
 			})
 
+			Context("of file types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"foo": &AttributeDefinition{Type: File},
+					}
+					required = nil
+				})
+
+				It("produces the struct go code", func() {
+					Ω(st).Should(Equal("struct {\n\tFoo *multipart.FileHeader `json:\"foo,omitempty\" xml:\"foo,omitempty\"`\n}"))
+				})
+			})
+
+			Context("of date, duration, bytes and decimal types", func() {
+				BeforeEach(func() {
+					object = Object{
+						"date":     &AttributeDefinition{Type: Date},
+						"duration": &AttributeDefinition{Type: Duration},
+						"bytes":    &AttributeDefinition{Type: Bytes},
+						"decimal":  &AttributeDefinition{Type: Decimal},
+					}
+					required = nil
+				})
+
+				It("produces the struct go code", func() {
+					expected := "struct {\n" +
+						"	Bytes []byte `json:\"bytes,omitempty\" xml:\"bytes,omitempty\"`\n" +
+						"	Date *goa.Date `json:\"date,omitempty\" xml:\"date,omitempty\"`\n" +
+						"	Decimal *goa.Decimal `json:\"decimal,omitempty\" xml:\"decimal,omitempty\"`\n" +
+						"	Duration *goa.Duration `json:\"duration,omitempty\" xml:\"duration,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
+			Context("of union types", func() {
+				BeforeEach(func() {
+					card := &UserTypeDefinition{
+						TypeName:            "card",
+						AttributeDefinition: &AttributeDefinition{Type: Object{}},
+					}
+					bank := &UserTypeDefinition{
+						TypeName:            "bank",
+						AttributeDefinition: &AttributeDefinition{Type: Object{}},
+					}
+					union := &Union{
+						Discriminator: "type",
+						Variants: []*UnionVariant{
+							{Value: "card", Type: card},
+							{Value: "bank", Type: bank},
+						},
+					}
+					object = Object{
+						"foo": &AttributeDefinition{Type: union},
+					}
+					required = nil
+				})
+
+				It("produces the struct go code", func() {
+					expected := "struct {\n" +
+						"	Foo *struct {\n" +
+						"		Type string `json:\"type\" xml:\"type\"`\n" +
+						"		*Card\n" +
+						"		*Bank\n" +
+						"	} `json:\"foo,omitempty\" xml:\"foo,omitempty\"`\n" +
+						"}"
+					Ω(st).Should(Equal(expected))
+				})
+			})
+
 			Context("of hash of primitive types", func() {
 				BeforeEach(func() {
 					elemType := &AttributeDefinition{Type: Integer}
@@ -10,13 +10,17 @@ import (
 )
 
 var (
-	arrayValT    *template.Template
-	enumValT     *template.Template
-	formatValT   *template.Template
-	patternValT  *template.Template
-	minMaxValT   *template.Template
-	lengthValT   *template.Template
-	requiredValT *template.Template
+	arrayValT       *template.Template
+	enumValT        *template.Template
+	formatValT      *template.Template
+	patternValT     *template.Template
+	minMaxValT      *template.Template
+	multipleOfValT  *template.Template
+	lengthValT      *template.Template
+	uniqueItemsValT *template.Template
+	propertiesValT  *template.Template
+	requiredValT    *template.Template
+	unionValT       *template.Template
 )
 
 //  init instantiates the templates.
@@ -46,19 +50,39 @@ func init() {
 	if minMaxValT, err = template.New("minMax").Funcs(fm).Parse(minMaxValTmpl); err != nil {
 		panic(err)
 	}
+	if multipleOfValT, err = template.New("multipleOf").Funcs(fm).Parse(multipleOfValTmpl); err != nil {
+		panic(err)
+	}
 	if lengthValT, err = template.New("length").Funcs(fm).Parse(lengthValTmpl); err != nil {
 		panic(err)
 	}
+	if uniqueItemsValT, err = template.New("uniqueItems").Funcs(fm).Parse(uniqueItemsValTmpl); err != nil {
+		panic(err)
+	}
+	if propertiesValT, err = template.New("properties").Funcs(fm).Parse(propertiesValTmpl); err != nil {
+		panic(err)
+	}
 	if requiredValT, err = template.New("required").Funcs(fm).Parse(requiredValTmpl); err != nil {
 		panic(err)
 	}
+	if unionValT, err = template.New("union").Funcs(fm).Parse(unionValTmpl); err != nil {
+		panic(err)
+	}
 }
 
 // RecursiveChecker produces Go code that runs the validation checks recursively over the given
 // attribute.
 func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDefault bool, target, context string, depth int, private bool) string {
 	var checks []string
-	if o := att.Type.ToObject(); o != nil {
+	if u, ok := att.Type.(*design.Union); ok {
+		validation := ValidationChecker(att, nonzero, required, hasDefault, target, context, depth, private)
+		if validation != "" {
+			checks = append(checks, validation)
+		}
+		if validation = unionChecker(u, target, context, depth, private); validation != "" {
+			checks = append(checks, validation)
+		}
+	} else if o := att.Type.ToObject(); o != nil {
 		if mt, ok := att.Type.(*design.MediaTypeDefinition); ok {
 			att = mt.AttributeDefinition
 		} else if ut, ok := att.Type.(*design.UserTypeDefinition); ok {
@@ -118,6 +142,62 @@ func RecursiveChecker(att *design.AttributeDefinition, nonzero, required, hasDef
 	return strings.Join(checks, "\n")
 }
 
+// unionChecker produces Go code that validates the discriminator of a union value and runs the
+// validation checks of the variant it identifies.
+func unionChecker(u *design.Union, target, context string, depth int, private bool) string {
+	caseDepth := depth + 1
+	if private {
+		caseDepth++
+	}
+	variants := make([]map[string]interface{}, len(u.Variants))
+	values := make([]interface{}, len(u.Variants))
+	for i, v := range u.Variants {
+		values[i] = v.Value
+		field := fmt.Sprintf("%s.%s", target, Goify(v.Type.TypeName, !private))
+		checkDepth := caseDepth
+		if !private {
+			checkDepth++
+		}
+		checks := strings.TrimRight(RecursiveChecker(&design.AttributeDefinition{Type: v.Type}, false, true, false,
+			field, context, checkDepth, private), "\n")
+		if !private {
+			// encoding/json leaves the embedded pointer nil if the value has no attribute of
+			// the variant, the required attributes are then missing.
+			var missing []string
+			if v.Type.Validation != nil {
+				for _, r := range v.Type.Validation.Required {
+					missing = append(missing, fmt.Sprintf("%s\terr = goa.MergeErrors(err, goa.MissingAttributeError(`%s`, %q))",
+						Tabs(caseDepth), context, r))
+				}
+			}
+			switch {
+			case len(missing) > 0 && checks != "":
+				checks = fmt.Sprintf("%sif %s == nil {\n%s\n%s} else {\n%s\n%s}", Tabs(caseDepth), field,
+					strings.Join(missing, "\n"), Tabs(caseDepth), checks, Tabs(caseDepth))
+			case len(missing) > 0:
+				checks = fmt.Sprintf("%sif %s == nil {\n%s\n%s}", Tabs(caseDepth), field,
+					strings.Join(missing, "\n"), Tabs(caseDepth))
+			case checks != "":
+				checks = fmt.Sprintf("%sif %s != nil {\n%s\n%s}", Tabs(caseDepth), field, checks, Tabs(caseDepth))
+			}
+		}
+		variants[i] = map[string]interface{}{
+			"value":  v.Value,
+			"checks": checks,
+		}
+	}
+	data := map[string]interface{}{
+		"discriminator": u.Discriminator,
+		"variants":      variants,
+		"values":        values,
+		"context":       context,
+		"target":        target,
+		"depth":         depth,
+		"private":       private,
+	}
+	return RunTemplate(unionValT, data)
+}
+
 // ValidationChecker produces Go code that runs the validation defined in the given attribute
 // definition against the content of the variable named target recursively.
 // context is used to keep track of recursion to produce helpful error messages in case of type
@@ -172,6 +252,7 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	if min := validation.Minimum; min != nil {
 		data["min"] = *min
 		data["isMin"] = true
+		data["exclusive"] = validation.ExclusiveMinimum
 		delete(data, "max")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
@@ -180,11 +261,19 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 	if max := validation.Maximum; max != nil {
 		data["max"] = *max
 		data["isMin"] = false
+		data["exclusive"] = validation.ExclusiveMaximum
 		delete(data, "min")
 		if val := RunTemplate(minMaxValT, data); val != "" {
 			res = append(res, val)
 		}
 	}
+	if multipleOf := validation.MultipleOf; multipleOf != nil {
+		data["multipleOf"] = *multipleOf
+		data["integer"] = data["attribute"].(*design.AttributeDefinition).Type.Kind() == design.IntegerKind
+		if val := RunTemplate(multipleOfValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if minLength := validation.MinLength; minLength != nil {
 		data["minLength"] = minLength
 		data["isMinLength"] = true
@@ -201,6 +290,25 @@ func validationsCode(validation *dslengine.ValidationDefinition, data map[string
 			res = append(res, val)
 		}
 	}
+	if validation.UniqueItems {
+		if val := RunTemplate(uniqueItemsValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if minProperties := validation.MinProperties; minProperties != nil {
+		data["count"] = *minProperties
+		data["isMinProperties"] = true
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
+	if maxProperties := validation.MaxProperties; maxProperties != nil {
+		data["count"] = *maxProperties
+		data["isMinProperties"] = false
+		if val := RunTemplate(propertiesValT, data); val != "" {
+			res = append(res, val)
+		}
+	}
 	if required := validation.Required; len(required) > 0 {
 		data["required"] = required
 		if val := RunTemplate(requiredValT, data); val != "" {
@@ -220,7 +328,8 @@ func oneof(target string, vals []interface{}) string {
 	return strings.Join(elems, " || ")
 }
 
-// constant returns the Go constant name of the format with the given value.
+// constant returns the Go constant name of the format with the given value or a conversion of
+// the format name for custom formats.
 func constant(formatName string) string {
 	switch formatName {
 	case "date-time":
@@ -242,7 +351,7 @@ func constant(formatName string) string {
 	case "regexp":
 		return "goa.FormatRegexp"
 	}
-	panic("unknown format") // bug
+	return fmt.Sprintf("goa.Format(%q)", formatName)
 }
 
 const (
@@ -274,8 +383,15 @@ const (
 
 	minMaxValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
 */}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
-{{end}}{{tabs .depth}}	if {{.targetVal}} {{if .isMin}}<{{else}}>{{end}} {{if .isMin}}{{.min}}{{else}}{{.max}}{{end}} {
-{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, {{if .isMin}}{{.min}}, true{{else}}{{.max}}, false{{end}}))
+{{end}}{{tabs .depth}}	if {{.targetVal}} {{if .isMin}}<{{else}}>{{end}}{{if .exclusive}}={{end}} {{if .isMin}}{{.min}}{{else}}{{.max}}{{end}} {
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.Invalid{{if .exclusive}}Exclusive{{end}}RangeError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, {{if .isMin}}{{.min}}, true{{else}}{{.max}}, false{{end}}))
+{{if .isPointer}}{{tabs $depth}}}
+{{end}}{{tabs .depth}}}`
+
+	multipleOfValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
+*/}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
+{{end}}{{tabs $depth}}if {{if .integer}}{{.targetVal}}%{{.multipleOf}} != 0{{else}}!goa.ValidateMultipleOf({{.targetVal}}, {{.multipleOf}}){{end}} {
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`" + `{{.context}}` + "`" + `, {{.targetVal}}, {{.multipleOf}}))
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
@@ -287,11 +403,35 @@ const (
 {{if .isPointer}}{{tabs $depth}}}
 {{end}}{{tabs .depth}}}`
 
+	uniqueItemsValTmpl = `{{tabs .depth}}if !goa.ValidateUniqueItems({{.target}}) {
+{{tabs .depth}}	err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`" + `{{.context}}` + "`" + `, {{.target}}))
+{{tabs .depth}}}`
+
+	propertiesValTmpl = `{{$depth := or (and .isPointer (add .depth 1)) .depth}}{{/*
+*/}}{{if .isPointer}}{{tabs .depth}}if {{.target}} != nil {
+{{end}}{{tabs $depth}}if len({{.target}}) {{if .isMinProperties}}<{{else}}>{{end}} {{.count}} {
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`" + `{{.context}}` + "`" + `, {{.target}}, len({{.target}}), {{.count}}, {{.isMinProperties}}))
+{{if .isPointer}}{{tabs $depth}}}
+{{end}}{{tabs .depth}}}`
+
+	unionValTmpl = `{{$disc := printf "%s.%s" .target (goify .discriminator true)}}{{/*
+*/}}{{$depth := or (and .private (add .depth 1)) .depth}}{{/*
+*/}}{{if .private}}{{tabs .depth}}if {{$disc}} == nil {
+{{tabs .depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{.context}}` + "`" + `, "{{.discriminator}}"))
+{{tabs .depth}}} else {
+{{end}}{{tabs $depth}}switch {{if .private}}*{{end}}{{$disc}} {
+{{range .variants}}{{tabs $depth}}case "{{.value}}":
+{{if .checks}}{{.checks}}
+{{end}}{{end}}{{tabs $depth}}default:
+{{tabs $depth}}	err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `{{.context}}.{{.discriminator}}` + "`" + `, {{if .private}}*{{end}}{{$disc}}, {{slice .values}}))
+{{tabs $depth}}}{{if .private}}
+{{tabs .depth}}}{{end}}`
+
 	requiredValTmpl = `{{range $r := .required}}{{$catt := index $.attribute.Type.ToObject $r}}{{/*
-*/}}{{if and (not $.private) (eq $catt.Type.Kind 4)}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == "" {
+*/}}{{if and (not $.private) (or (eq $catt.Type.Kind 4) (eq $catt.Type.Kind 18))}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == "" {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
-{{else if or $.private (not $catt.Type.IsPrimitive)}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == nil {
+{{else if or (or $.private (not $catt.Type.IsPrimitive)) (or (eq $catt.Type.Kind 13) (eq $catt.Type.Kind 17))}}{{tabs $.depth}}if {{$.target}}.{{goify $r true}} == nil {
 {{tabs $.depth}}	err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`" + `{{$.context}}` + "`" + `, "{{$r}}"))
 {{tabs $.depth}}}
 {{end}}{{end}}`
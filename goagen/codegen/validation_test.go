@@ -55,6 +55,19 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of custom format", func() {
+				BeforeEach(func() {
+					attType = design.String
+					validation = &dslengine.ValidationDefinition{
+						Format: "semver",
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(customFormatValCode))
+				})
+			})
+
 			Context("of min value 0", func() {
 				BeforeEach(func() {
 					attType = design.Integer
@@ -87,6 +100,87 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of exclusive min value 0", func() {
+				BeforeEach(func() {
+					attType = design.Integer
+					min := 0.0
+					validation = &dslengine.ValidationDefinition{
+						Minimum:          &min,
+						ExclusiveMinimum: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(exclusiveMinValCode))
+				})
+			})
+
+			Context("of multiple of", func() {
+				var multiple float64
+
+				BeforeEach(func() {
+					validation = &dslengine.ValidationDefinition{
+						MultipleOf: &multiple,
+					}
+				})
+
+				Context("on integers", func() {
+					BeforeEach(func() {
+						attType = design.Integer
+						multiple = 5
+					})
+
+					It("produces the validation go code", func() {
+						Ω(code).Should(Equal(multipleOfIntValCode))
+					})
+				})
+
+				Context("on numbers", func() {
+					BeforeEach(func() {
+						attType = design.Number
+						multiple = 0.01
+					})
+
+					It("produces the validation go code", func() {
+						Ω(code).Should(Equal(multipleOfNumberValCode))
+					})
+				})
+			})
+
+			Context("of unique items", func() {
+				BeforeEach(func() {
+					attType = &design.Array{
+						ElemType: &design.AttributeDefinition{
+							Type: design.String,
+						},
+					}
+					validation = &dslengine.ValidationDefinition{
+						UniqueItems: true,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(uniqueItemsValCode))
+				})
+			})
+
+			Context("of min properties 1", func() {
+				BeforeEach(func() {
+					attType = &design.Hash{
+						KeyType:  &design.AttributeDefinition{Type: design.String},
+						ElemType: &design.AttributeDefinition{Type: design.String},
+					}
+					min := 1
+					validation = &dslengine.ValidationDefinition{
+						MinProperties: &min,
+					}
+				})
+
+				It("produces the validation go code", func() {
+					Ω(code).Should(Equal(minPropertiesValCode))
+				})
+			})
+
 			Context("of embedded object", func() {
 				BeforeEach(func() {
 					enumVal := &dslengine.ValidationDefinition{
@@ -122,6 +216,31 @@ var _ = Describe("validation code generation", func() {
 				})
 			})
 
+			Context("of union", func() {
+				BeforeEach(func() {
+					card := &design.UserTypeDefinition{
+						TypeName: "Card",
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{
+								"number": &design.AttributeDefinition{Type: design.String},
+							},
+							Validation: &dslengine.ValidationDefinition{
+								Required: []string{"number"},
+							},
+						},
+					}
+					union := &design.Union{
+						Discriminator: "type",
+						Variants:      []*design.UnionVariant{{Value: "card", Type: card}},
+					}
+					attType = design.Object{"foo": &design.AttributeDefinition{Type: union}}
+					validation = nil
+				})
+
+				It("checks the discriminator and validates the variant", func() {
+					Ω(code).Should(Equal(unionValCode))
+				})
+			})
 		})
 	})
 })
@@ -139,6 +258,12 @@ const (
 		}
 	}`
 
+	customFormatValCode = `	if val != nil {
+		if err2 := goa.ValidateFormat(goa.Format("semver"), *val); err2 != nil {
+				err = goa.MergeErrors(err, goa.InvalidFormatError(` + "`context`" + `, *val, goa.Format("semver"), err2))
+		}
+	}`
+
 	minValCode = `	if val != nil {
 		if *val < 0 {
 			err = goa.MergeErrors(err, goa.InvalidRangeError(` + "`" + `context` + "`" + `, *val, 0, true))
@@ -151,6 +276,34 @@ const (
 		}
 	}`
 
+	exclusiveMinValCode = `	if val != nil {
+		if *val <= 0 {
+			err = goa.MergeErrors(err, goa.InvalidExclusiveRangeError(` + "`context`" + `, *val, 0, true))
+		}
+	}`
+
+	multipleOfIntValCode = `	if val != nil {
+		if *val%5 != 0 {
+			err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`context`" + `, *val, 5))
+		}
+	}`
+
+	multipleOfNumberValCode = `	if val != nil {
+		if !goa.ValidateMultipleOf(*val, 0.01) {
+			err = goa.MergeErrors(err, goa.InvalidMultipleOfError(` + "`context`" + `, *val, 0.01))
+		}
+	}`
+
+	uniqueItemsValCode = `	if !goa.ValidateUniqueItems(val) {
+		err = goa.MergeErrors(err, goa.InvalidUniqueItemsError(` + "`context`" + `, val))
+	}`
+
+	minPropertiesValCode = `	if val != nil {
+		if len(val) < 1 {
+			err = goa.MergeErrors(err, goa.InvalidPropertiesCountError(` + "`context`" + `, val, len(val), 1, true))
+		}
+	}`
+
 	embeddedValCode = `	if val.Foo != nil {
 		if val.Foo.Bar != nil {
 			if !(*val.Foo.Bar == 1 || *val.Foo.Bar == 2 || *val.Foo.Bar == 3) {
@@ -170,4 +323,19 @@ const (
 			}
 		}
 	}`
+
+	unionValCode = `	if val.Foo != nil {
+		switch val.Foo.Type {
+		case "card":
+			if val.Foo.Card == nil {
+				err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context.foo`" + `, "number"))
+			} else {
+				if val.Foo.Card.Number == "" {
+					err = goa.MergeErrors(err, goa.MissingAttributeError(` + "`context.foo`" + `, "number"))
+				}
+			}
+		default:
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`context.foo.type`" + `, val.Foo.Type, []interface{}{"card"}))
+		}
+	}`
 )
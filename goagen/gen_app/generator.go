@@ -16,6 +16,9 @@ import (
 // Generator is the application code generator.
 type Generator struct {
 	genfiles []string
+	outDir   string                       // Path to the directory of the generated package
+	target   string                       // Name of the generated package
+	version  *design.APIVersionDefinition // API version being generated if any
 }
 
 // Generate is the generator entry point called by the meta generator.
@@ -51,7 +54,11 @@ func AppOutputDir() string {
 
 // AppPackagePath returns the Go package path to the generated package.
 func AppPackagePath() (string, error) {
-	outputDir := AppOutputDir()
+	return packagePath(AppOutputDir())
+}
+
+// packagePath returns the Go package path to the package generated in the given directory.
+func packagePath(outputDir string) (string, error) {
 	gopaths := filepath.SplitList(os.Getenv("GOPATH"))
 	for _, gopath := range gopaths {
 		if strings.HasPrefix(outputDir, gopath) {
@@ -71,6 +78,13 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		return nil, fmt.Errorf("missing API definition, make sure design is properly initialized")
 	}
 
+	if g.outDir == "" {
+		g.outDir = AppOutputDir()
+	}
+	if g.target == "" {
+		g.target = TargetPackage
+	}
+
 	go utils.Catch(nil, func() { g.Cleanup() })
 
 	defer func() {
@@ -79,36 +93,68 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		}
 	}()
 
-	os.RemoveAll(AppOutputDir())
-
-	if err := os.MkdirAll(AppOutputDir(), 0755); err != nil {
+	if err := g.generate(api); err != nil {
 		return nil, err
 	}
+
+	return g.genfiles, nil
+}
+
+// generate generates the code of the given API in the generator output directory.
+func (g *Generator) generate(api *design.APIDefinition) error {
+	os.RemoveAll(g.outDir)
+
+	if err := os.MkdirAll(g.outDir, 0755); err != nil {
+		return err
+	}
 	if err := g.generateContexts(api); err != nil {
-		return nil, err
+		return err
 	}
 	if err := g.generateControllers(api); err != nil {
-		return nil, err
+		return err
 	}
 	if err := g.generateSecurity(api); err != nil {
-		return nil, err
+		return err
 	}
 	if err := g.generateHrefs(api); err != nil {
-		return nil, err
+		return err
 	}
 	if err := g.generateMediaTypes(api); err != nil {
-		return nil, err
+		return err
 	}
 	if err := g.generateUserTypes(api); err != nil {
-		return nil, err
+		return err
+	}
+	if err := g.generateErrors(api); err != nil {
+		return err
 	}
 	if !NoGenTest {
 		if err := g.generateResourceTest(api); err != nil {
-			return nil, err
+			return err
+		}
+	}
+	for _, v := range api.Versions {
+		if err := g.generateVersion(v); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return g.genfiles, nil
+// VersionPackage returns the name of the Go package generated for the given API version.
+func VersionPackage(v *design.APIVersionDefinition) string {
+	return strings.ToLower(codegen.Goify(v.Name, false))
+}
+
+// generateVersion generates the code of the given API version in a sub-package of the application
+// package. The code is generated from the version API definition so that the resource paths use
+// the version base path.
+func (g *Generator) generateVersion(v *design.APIVersionDefinition) error {
+	target := VersionPackage(v)
+	vg := &Generator{outDir: filepath.Join(g.outDir, target), target: target, version: v}
+	err := vg.generate(v.API())
+	g.genfiles = append(g.genfiles, vg.genfiles...)
+	return err
 }
 
 // Cleanup removes the entire "app" directory if it was created by this generator.
@@ -116,7 +162,7 @@ func (g *Generator) Cleanup() {
 	if len(g.genfiles) == 0 {
 		return
 	}
-	os.RemoveAll(AppOutputDir())
+	os.RemoveAll(g.outDir)
 	g.genfiles = nil
 }
 
@@ -149,22 +195,25 @@ func BuildResponses(l, r map[string]*design.ResponseDefinition) map[string]*desi
 // generateContexts iterates through the API resources and actions and generates the action
 // contexts.
 func (g *Generator) generateContexts(api *design.APIDefinition) error {
-	ctxFile := filepath.Join(AppOutputDir(), "contexts.go")
+	ctxFile := filepath.Join(g.outDir, "contexts.go")
 	ctxWr, err := NewContextsWriter(ctxFile)
 	if err != nil {
 		panic(err) // bug
 	}
 	title := fmt.Sprintf("%s: Application Contexts", api.Context())
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("mime/multipart"),
+		codegen.SimpleImport("net/url"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
-	ctxWr.WriteHeader(title, TargetPackage, imports)
+	ctxWr.WriteHeader(title, g.target, imports)
 	err = api.IterateResources(func(r *design.ResourceDefinition) error {
 		return r.IterateActions(func(a *design.ActionDefinition) error {
 			ctxName := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Context"
@@ -176,6 +225,10 @@ func (g *Generator) generateContexts(api *design.APIDefinition) error {
 			if params != nil && len(params.Type.ToObject()) == 0 {
 				params = nil // So that {{if .Params}} returns false in templates
 			}
+			cookies := a.Cookies
+			if cookies != nil && len(cookies.Type.ToObject()) == 0 {
+				cookies = nil // So that {{if .Cookies}} returns false in templates
+			}
 
 			ctxData := ContextTemplateData{
 				Name:         ctxName,
@@ -184,11 +237,19 @@ func (g *Generator) generateContexts(api *design.APIDefinition) error {
 				Payload:      a.Payload,
 				Params:       params,
 				Headers:      headers,
+				Cookies:      cookies,
 				Routes:       a.Routes,
 				Responses:    BuildResponses(r.Responses, a.Responses),
 				API:          api,
-				DefaultPkg:   TargetPackage,
+				DefaultPkg:   g.target,
 				Security:     a.Security,
+				Pagination:   a.Pagination,
+				PageHref:     pageHref(a),
+				Fields:       a.Fields,
+				Stream:       a.Stream,
+				Messages:     a.Messages,
+				Cacheable:    a.Cacheable,
+				Errors:       a.AllErrors(),
 			}
 			return ctxWr.Execute(&ctxData)
 		})
@@ -362,7 +423,7 @@ func serialize(byfn map[string][]*design.EncodingDefinition, first string) []*de
 // generateControllers iterates through the API resources and generates the low level
 // controllers.
 func (g *Generator) generateControllers(api *design.APIDefinition) error {
-	ctlFile := filepath.Join(AppOutputDir(), "controllers.go")
+	ctlFile := filepath.Join(g.outDir, "controllers.go")
 	ctlWr, err := NewControllersWriter(ctlFile)
 	if err != nil {
 		panic(err) // bug
@@ -371,9 +432,15 @@ func (g *Generator) generateControllers(api *design.APIDefinition) error {
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("time"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("github.com/goadesign/goa/cors"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware"),
+		codegen.SimpleImport("github.com/goadesign/goa/middleware/ratelimit"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
 	encoders, err := BuildEncoders(api.Produces, true)
 	if err != nil {
@@ -395,7 +462,7 @@ func (g *Generator) generateControllers(api *design.APIDefinition) error {
 			imports = append(imports, codegen.SimpleImport(packagePath))
 		}
 	}
-	ctlWr.WriteHeader(title, TargetPackage, imports)
+	ctlWr.WriteHeader(title, g.target, imports)
 	ctlWr.WriteInitService(encoders, decoders)
 
 	var controllersData []*ControllerTemplateData
@@ -405,16 +472,24 @@ func (g *Generator) generateControllers(api *design.APIDefinition) error {
 			Resource:       codegen.Goify(r.Name, true),
 			PreflightPaths: r.PreflightPaths(),
 		}
+		if g.version != nil {
+			data.Version = g.version.Name
+		}
 		ierr := r.IterateActions(func(a *design.ActionDefinition) error {
 			context := fmt.Sprintf("%s%sContext", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			unmarshal := fmt.Sprintf("unmarshal%s%sPayload", codegen.Goify(a.Name, true), codegen.Goify(r.Name, true))
 			action := map[string]interface{}{
-				"Name":      codegen.Goify(a.Name, true),
-				"Routes":    a.Routes,
-				"Context":   context,
-				"Unmarshal": unmarshal,
-				"Payload":   a.Payload,
-				"Security":  a.Security,
+				"Name":        codegen.Goify(a.Name, true),
+				"Routes":      a.Routes,
+				"Context":     context,
+				"Unmarshal":   unmarshal,
+				"Payload":     a.Payload,
+				"Multipart":   a.PayloadMultipart,
+				"Security":    a.Security,
+				"Deprecation": a.Deprecation,
+				"RateLimit":   a.EffectiveRateLimit(),
+				"Cacheable":   a.Cacheable,
+				"Stream":      a.Stream != nil,
 			}
 			data.Actions = append(data.Actions, action)
 			return nil
@@ -447,7 +522,7 @@ func (g *Generator) generateSecurity(api *design.APIDefinition) error {
 		return nil
 	}
 
-	secFile := filepath.Join(AppOutputDir(), "security.go")
+	secFile := filepath.Join(g.outDir, "security.go")
 	secWr, err := NewSecurityWriter(secFile)
 	if err != nil {
 		panic(err) // bug
@@ -460,11 +535,11 @@ func (g *Generator) generateSecurity(api *design.APIDefinition) error {
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 	}
-	secWr.WriteHeader(title, TargetPackage, imports)
+	secWr.WriteHeader(title, g.target, imports)
 
 	g.genfiles = append(g.genfiles, secFile)
 
-	if err = secWr.Execute(design.Design.SecuritySchemes); err != nil {
+	if err = secWr.Execute(api.SecuritySchemes); err != nil {
 		return err
 	}
 
@@ -473,7 +548,7 @@ func (g *Generator) generateSecurity(api *design.APIDefinition) error {
 
 // generateHrefs iterates through the API resources and generates the href factory methods.
 func (g *Generator) generateHrefs(api *design.APIDefinition) error {
-	hrefFile := filepath.Join(AppOutputDir(), "hrefs.go")
+	hrefFile := filepath.Join(g.outDir, "hrefs.go")
 	resWr, err := NewResourcesWriter(hrefFile)
 	if err != nil {
 		panic(err) // bug
@@ -482,7 +557,7 @@ func (g *Generator) generateHrefs(api *design.APIDefinition) error {
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("fmt"),
 	}
-	resWr.WriteHeader(title, TargetPackage, imports)
+	resWr.WriteHeader(title, g.target, imports)
 	err = api.IterateResources(func(r *design.ResourceDefinition) error {
 		m := api.MediaTypeWithIdentifier(r.MediaType)
 		var identifier string
@@ -499,6 +574,12 @@ func (g *Generator) generateHrefs(api *design.APIDefinition) error {
 			CanonicalTemplate: codegen.CanonicalTemplate(r),
 			CanonicalParams:   codegen.CanonicalParams(r),
 		}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Pagination != nil {
+				data.ActionHrefs = append(data.ActionHrefs, actionHref(a))
+			}
+			return nil
+		})
 		return resWr.Execute(&data)
 	})
 	g.genfiles = append(g.genfiles, hrefFile)
@@ -508,10 +589,41 @@ func (g *Generator) generateHrefs(api *design.APIDefinition) error {
 	return resWr.FormatCode()
 }
 
+// actionHref returns the data needed to generate the href factory of the given action, the path
+// is the one of the first action route.
+func actionHref(a *design.ActionDefinition) *ActionHrefData {
+	data := &ActionHrefData{
+		Name:   codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Href",
+		Action: a.Name,
+	}
+	if len(a.Routes) > 0 {
+		data.Template = design.WildcardRegex.ReplaceAllLiteralString(a.Routes[0].FullPath(), "/%v")
+		for _, p := range a.Routes[0].Params() {
+			data.Params = append(data.Params, codegen.Goify(p, false))
+		}
+	}
+	return data
+}
+
+// pageHref returns the Go expression that computes the path of the given paginated action in its
+// context methods, an empty string if the action is not paginated.
+func pageHref(a *design.ActionDefinition) string {
+	if a.Pagination == nil {
+		return ""
+	}
+	var args []string
+	if len(a.Routes) > 0 {
+		for _, p := range a.Routes[0].Params() {
+			args = append(args, "ctx."+codegen.Goify(p, true))
+		}
+	}
+	return fmt.Sprintf("%s(%s)", actionHref(a).Name, strings.Join(args, ", "))
+}
+
 // generateMediaTypes iterates through the media types and generate the data structures and
 // marshaling code.
 func (g *Generator) generateMediaTypes(api *design.APIDefinition) error {
-	mtFile := filepath.Join(AppOutputDir(), "media_types.go")
+	mtFile := filepath.Join(g.outDir, "media_types.go")
 	mtWr, err := NewMediaTypesWriter(mtFile)
 	if err != nil {
 		panic(err) // bug
@@ -523,7 +635,7 @@ func (g *Generator) generateMediaTypes(api *design.APIDefinition) error {
 		codegen.SimpleImport("time"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
-	mtWr.WriteHeader(title, TargetPackage, imports)
+	mtWr.WriteHeader(title, g.target, imports)
 	err = api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
 		if mt.IsBuiltIn() {
 			return nil
@@ -543,7 +655,7 @@ func (g *Generator) generateMediaTypes(api *design.APIDefinition) error {
 // generateUserTypes iterates through the user types and generates the data structures and
 // marshaling code.
 func (g *Generator) generateUserTypes(api *design.APIDefinition) error {
-	utFile := filepath.Join(AppOutputDir(), "user_types.go")
+	utFile := filepath.Join(g.outDir, "user_types.go")
 	utWr, err := NewUserTypesWriter(utFile)
 	if err != nil {
 		panic(err) // bug
@@ -552,9 +664,10 @@ func (g *Generator) generateUserTypes(api *design.APIDefinition) error {
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("time"),
 	}
-	utWr.WriteHeader(title, TargetPackage, imports)
+	utWr.WriteHeader(title, g.target, imports)
 	err = api.IterateUserTypes(func(t *design.UserTypeDefinition) error {
 		return utWr.Execute(t)
 	})
@@ -564,3 +677,30 @@ func (g *Generator) generateUserTypes(api *design.APIDefinition) error {
 	}
 	return utWr.FormatCode()
 }
+
+// generateErrors generates the typed errors defined in the API, its resources and actions.
+func (g *Generator) generateErrors(api *design.APIDefinition) error {
+	errors := api.AllErrors()
+	if len(errors) == 0 {
+		return nil
+	}
+	errFile := filepath.Join(g.outDir, "errors.go")
+	errWr, err := NewErrorsWriter(errFile)
+	if err != nil {
+		panic(err) // bug
+	}
+	title := fmt.Sprintf("%s: Application Errors", api.Context())
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("time"),
+		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
+	}
+	errWr.WriteHeader(title, g.target, imports)
+	for _, e := range errors {
+		if err := errWr.Execute(e); err != nil {
+			return err
+		}
+	}
+	g.genfiles = append(g.genfiles, errFile)
+	return errWr.FormatCode()
+}
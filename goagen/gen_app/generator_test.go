@@ -279,6 +279,28 @@ var _ = Describe("BuildEncoders", func() {
 		})
 	})
 
+	Context("with a single definition using the form MIME type for decoding", func() {
+		BeforeEach(func() {
+			simple := &design.EncodingDefinition{
+				MIMETypes: []string{"application/x-www-form-urlencoded"},
+			}
+			info = append(info, simple)
+			encoder = false
+		})
+
+		It("generates a map with a single entry", func() {
+			Ω(resErr).ShouldNot(HaveOccurred())
+			Ω(data).Should(HaveLen(1))
+			jd := data[0]
+			Ω(jd).ShouldNot(BeNil())
+			Ω(jd.PackagePath).Should(Equal("github.com/goadesign/goa"))
+			Ω(jd.PackageName).Should(Equal("goa"))
+			Ω(jd.Function).Should(Equal("NewFormDecoder"))
+			Ω(jd.MIMETypes).Should(HaveLen(1))
+			Ω(jd.MIMETypes[0]).Should(Equal("application/x-www-form-urlencoded"))
+		})
+	})
+
 	Context("with a definition using a custom decoding package", func() {
 		const packagePath = "github.com/goadesign/goa/design" // Just to pick something always available
 		var mimeTypes = []string{"application/vnd.custom", "application/vnd.custom2"}
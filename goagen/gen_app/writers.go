@@ -63,6 +63,11 @@ type (
 		UserTypeTmpl *template.Template
 	}
 
+	// ErrorsWriter generate code for the typed errors defined in the DSL with "Error".
+	ErrorsWriter struct {
+		*codegen.SourceFile
+	}
+
 	// ContextTemplateData contains all the information used by the template to render the context
 	// code for an action.
 	ContextTemplateData struct {
@@ -72,22 +77,31 @@ type (
 		Params       *design.AttributeDefinition
 		Payload      *design.UserTypeDefinition
 		Headers      *design.AttributeDefinition
+		Cookies      *design.AttributeDefinition
 		Routes       []*design.RouteDefinition
 		Responses    map[string]*design.ResponseDefinition
 		API          *design.APIDefinition
 		DefaultPkg   string
 		Security     *design.SecurityDefinition
+		Pagination   *design.PaginationDefinition
+		PageHref     string // Go expression that computes the path of a paginated action, e.g. "ListBottleHref(ctx.ID)"
+		Fields       *design.FieldsDefinition
+		Stream       *design.StreamDefinition
+		Messages     *design.MessagesDefinition
+		Cacheable    bool
+		Errors       []*design.ErrorDefinition
 	}
 
 	// ControllerTemplateData contains the information required to generate an action handler.
 	ControllerTemplateData struct {
 		API            *design.APIDefinition    // API definition
 		Resource       string                   // Lower case plural resource name, e.g. "bottles"
-		Actions        []map[string]interface{} // Array of actions, each action has keys "Name", "Routes", "Context" and "Unmarshal"
+		Actions        []map[string]interface{} // Array of actions, each action has keys "Name", "Routes", "Context", "Unmarshal", "Deprecation", "RateLimit", "Cacheable" and "Stream"
 		Encoders       []*EncoderTemplateData   // Encoder data
 		Decoders       []*EncoderTemplateData   // Decoder data
 		Origins        []*design.CORSDefinition // CORS policies
 		PreflightPaths []string
+		Version        string // API version of the controller, empty if the controller belongs to the API
 	}
 
 	// ResourceData contains the information required to generate the resource GoGenerator
@@ -98,6 +112,15 @@ type (
 		Type              *design.MediaTypeDefinition // Type of resource media type
 		CanonicalTemplate string                      // CanonicalFormat represents the resource canonical path in the form of a fmt.Sprintf format.
 		CanonicalParams   []string                    // CanonicalParams is the list of parameter names that appear in the resource canonical path in order.
+		ActionHrefs       []*ActionHrefData           // ActionHrefs lists the href factories of the resource paginated actions.
+	}
+
+	// ActionHrefData contains the information required to generate the href factory of an action.
+	ActionHrefData struct {
+		Name     string   // Name of the factory function, e.g. "ListBottleHref"
+		Action   string   // Name of the action
+		Template string   // Template is the action path in the form of a fmt.Sprintf format.
+		Params   []string // Params is the list of parameter names that appear in the action path in order.
 	}
 
 	// EncoderTemplateData contains the data needed to render the registration code for a single
@@ -190,6 +213,35 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			return err
 		}
 	}
+	if data.Pagination != nil {
+		if err := w.ExecuteTemplate("pagination", ctxPageT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Stream != nil {
+		if err := w.ExecuteTemplate("stream", ctxStreamT, nil, data); err != nil {
+			return err
+		}
+	}
+	if data.Messages != nil {
+		fn := template.FuncMap{
+			"durationLit": codegen.DurationLiteral,
+			"isUserType":  isUserType,
+		}
+		if err := w.ExecuteTemplate("messages", ctxMessagesT, fn, data); err != nil {
+			return err
+		}
+	}
+	if data.Cacheable {
+		if err := w.ExecuteTemplate("cache", ctxCacheT, nil, data); err != nil {
+			return err
+		}
+	}
+	if len(data.Errors) > 0 {
+		if err := w.ExecuteTemplate("errors", ctxErrorsT, nil, data); err != nil {
+			return err
+		}
+	}
 	fn = template.FuncMap{
 		"project": func(mt *design.MediaTypeDefinition, v string) *design.MediaTypeDefinition {
 			p, _, _ := mt.Project(v)
@@ -206,7 +258,7 @@ func (w *ContextsWriter) Execute(data *ContextTemplateData) error {
 			if err := w.ExecuteTemplate("response", ctxTRespT, fn, respData); err != nil {
 				return err
 			}
-		} else if mt := design.Design.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
+		} else if mt := data.API.MediaTypeWithIdentifier(resp.MediaType); mt != nil {
 			respData["MediaType"] = mt
 			fn["respName"] = func(resp *design.ResponseDefinition, view string) string {
 				if view == "default" {
@@ -241,7 +293,6 @@ func NewControllersWriter(filename string) (*ControllersWriter, error) {
 // WriteInitService writes the initService function
 func (w *ControllersWriter) WriteInitService(encoders, decoders []*EncoderTemplateData) error {
 	ctx := map[string]interface{}{
-		"API":      design.Design,
 		"Encoders": encoders,
 		"Decoders": decoders,
 	}
@@ -260,7 +311,8 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 		if err := w.ExecuteTemplate("controller", ctrlT, nil, d); err != nil {
 			return err
 		}
-		if err := w.ExecuteTemplate("mount", mountT, nil, d); err != nil {
+		fn := template.FuncMap{"durationLit": codegen.DurationLiteral}
+		if err := w.ExecuteTemplate("mount", mountT, fn, d); err != nil {
 			return err
 		}
 		if len(d.Origins) > 0 {
@@ -268,7 +320,11 @@ func (w *ControllersWriter) Execute(data []*ControllerTemplateData) error {
 				return err
 			}
 		}
-		if err := w.ExecuteTemplate("unmarshal", unmarshalT, nil, d); err != nil {
+		fn = template.FuncMap{
+			"newCoerceData":  newCoerceData,
+			"arrayAttribute": arrayAttribute,
+		}
+		if err := w.ExecuteTemplate("unmarshal", unmarshalT, fn, d); err != nil {
 			return err
 		}
 	}
@@ -359,6 +415,21 @@ func (w *UserTypesWriter) Execute(t *design.UserTypeDefinition) error {
 	return w.ExecuteTemplate("types", userTypeT, nil, t)
 }
 
+// NewErrorsWriter returns a typed errors code writer.
+// Typed errors are defined in the DSL with "Error".
+func NewErrorsWriter(filename string) (*ErrorsWriter, error) {
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return nil, err
+	}
+	return &ErrorsWriter{SourceFile: file}, nil
+}
+
+// Execute writes the code for the typed error to the writer.
+func (w *ErrorsWriter) Execute(e *design.ErrorDefinition) error {
+	return w.ExecuteTemplate("error", errorT, nil, e)
+}
+
 // newCoerceData is a helper function that creates a map that can be given to the "Coerce" template.
 func newCoerceData(name string, att *design.AttributeDefinition, pointer bool, pkg string, depth int) map[string]interface{} {
 	return map[string]interface{}{
@@ -376,6 +447,13 @@ func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
 	return a.Type.(*design.Array).ElemType
 }
 
+// isUserType returns true if the given data type is a user type, false if it is a media type.
+// User types are decoded into their private counterpart.
+func isUserType(dt design.DataType) bool {
+	_, ok := dt.(*design.UserTypeDefinition)
+	return ok
+}
+
 const (
 	// ctxT generates the code for the context data type.
 	// template input: *ContextTemplateData
@@ -387,7 +465,10 @@ type {{ .Name }} struct {
 	Service *goa.Service
 {{ if .Params }}{{ range $name, $att := .Params.Type.ToObject }}{{/*
 */}}	{{ goify $name true }} {{ if and $att.Type.IsPrimitive ($.Params.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
+{{ end }}{{ end }}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{/*
+*/}}	{{ goify $name true }} {{ if ($.Cookies.IsPrimitivePointer $name) }}*{{ end }}{{ gotyperef .Type nil 0 false }}
 {{ end }}{{ end }}{{ if .Payload }}	Payload {{ gotyperef .Payload nil 0 false }}
+{{ end }}{{ if .Stream }}	stream *goa.EventStream
 {{ end }}}
 `
 	// coerceT generates the code that coerces the generic deserialized
@@ -433,7 +514,7 @@ type {{ .Name }} struct {
 
 */}}{{/* DateTimeType */}}{{/*
 */}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
-*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := time.Parse("RFC3339", raw{{ goify .Name true }}); err2 == nil {
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := time.Parse(time.RFC3339, raw{{ goify .Name true }}); err2 == nil {
 {{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
 {{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
 {{ tabs .Depth }}} else {
@@ -449,6 +530,44 @@ type {{ .Name }} struct {
 {{ tabs .Depth }}} else {
 {{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "uuid"))
 {{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 15 }}{{/*
+
+*/}}{{/* DateType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDate(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "date"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 16 }}{{/*
+
+*/}}{{/* DurationType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDuration(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "duration"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 17 }}{{/*
+
+*/}}{{/* BytesType */}}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := base64.StdEncoding.DecodeString(raw{{ goify .Name true }}); err2 == nil {
+{{ tabs .Depth }}	{{ .Pkg }} = {{ .VarName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "bytes"))
+{{ tabs .Depth }}}
+{{ end }}{{ if eq .Attribute.Type.Kind 18 }}{{/*
+
+*/}}{{/* DecimalType */}}{{/*
+*/}}{{ $varName := or (and (not .Pointer) .VarName) tempvar }}{{/*
+*/}}{{ tabs .Depth }}if {{ .VarName }}, err2 := goa.ParseDecimal(raw{{ goify .Name true }}); err2 == nil {
+{{ if .Pointer }}{{ tabs .Depth }}	{{ $varName }} := &{{ .VarName }}
+{{ end }}{{ tabs .Depth }}	{{ .Pkg }} = {{ $varName }}
+{{ tabs .Depth }}} else {
+{{ tabs .Depth }}	err = goa.MergeErrors(err, goa.InvalidParamTypeError("{{ .Name }}", raw{{ goify .Name true }}, "decimal"))
+{{ tabs .Depth }}}
 {{ end }}{{ if eq .Attribute.Type.Kind 7 }}{{/*
 
 */}}{{/* AnyType */}}{{/*
@@ -475,7 +594,9 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 	var err error
 	req := goa.ContextRequest(ctx)
 	rctx := {{ .Name }}{Context: ctx, ResponseData: goa.ContextResponse(ctx), RequestData: req, Service: service}
-{{ if .Headers }}{{ $headers := .Headers }}{{ range $name, $att := $headers.Type.ToObject }}	raw{{ goify $name true }} := req.Header.Get("{{ $name }}")
+{{ if .Stream }}	rctx.stream = goa.NewEventStream(ctx)
+	rctx.Context = rctx.stream.Context()
+{{ end }}{{ if .Headers }}{{ $headers := .Headers }}{{ range $name, $att := $headers.Type.ToObject }}	raw{{ goify $name true }} := req.Header.Get("{{ $name }}")
 {{ if $headers.IsRequired $name }}	if raw{{ goify $name true }} == "" {
 		err = goa.MergeErrors(err, goa.MissingHeaderError("{{ $name }}"))
 	} else {
@@ -499,9 +620,149 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 */}}{{ $validation := validationChecker $att ($.Params.IsNonZero $name) ($.Params.IsRequired $name) ($.Params.HasDefaultValue $name) (printf "rctx.%s" (goify $name true)) $name 2 false }}{{/*
 */}}{{ if $validation }}{{ $validation }}
 {{ end }}	}
-{{ end }}{{ end }}{{/* if .Params */}}	return &rctx, err
+{{ end }}{{ end }}{{/* if .Params */}}{{ if .Fields }}{{/*
+*/}}{{ $elem := arrayAttribute (index .Params.Type.ToObject "fields") }}{{/*
+*/}}{{ $validation := validationChecker $elem false true false "field" "fields" 2 false }}{{ if $validation }}	for _, field := range rctx.Fields {
+{{ $validation }}
+	}
+{{ end }}{{ end }}{{/* if .Fields */}}{{ if .Cookies }}{{ $cookies := .Cookies }}{{ range $name, $att := $cookies.Type.ToObject }}{{/*
+*/}}{{ if $cookies.IsRequired $name }}	if cookie, cerr := req.Cookie("{{ $name }}"); cerr != nil {
+		err = goa.MergeErrors(err, goa.MissingCookieError("{{ $name }}"))
+	} else {
+{{ else }}	if cookie, cerr := req.Cookie("{{ $name }}"); cerr == nil {
+{{ end }}		raw{{ goify $name true }} := cookie.Value
+{{ template "Coerce" (newCoerceData $name $att ($cookies.IsPrimitivePointer $name) (printf "rctx.%s" (goify $name true)) 2) }}{{/*
+*/}}{{ $validation := validationChecker $att ($cookies.IsNonZero $name) ($cookies.IsRequired $name) ($cookies.HasDefaultValue $name) (printf "rctx.%s" (goify $name true)) $name 2 false }}{{/*
+*/}}{{ if $validation }}{{ $validation }}
+{{ end }}	}
+{{ end }}{{ end }}{{/* if .Cookies */}}	return &rctx, err
 }
 `
+	// ctxPageT generates the pagination helpers of paginated action contexts.
+	// template input: *ContextTemplateData
+	ctxPageT = `{{ if eq .Pagination.Style "cursor" }}
+// PageRequest returns the cursor of the requested page, empty for the first page, and the page size.
+func (ctx *{{ .Name }}) PageRequest() (cursor string, limit int) {
+	if ctx.Cursor != nil {
+		cursor = *ctx.Cursor
+	}
+	return cursor, goa.PageLimit(ctx.Limit)
+}
+
+// SetPageLinks sets the Link response header with the link to the page identified by the given
+// cursor. An empty cursor indicates that the current page is the last one. The link path is the
+// action path as defined in the design.
+func (ctx *{{ .Name }}) SetPageLinks(next string) {
+	if next != "" {
+		_, limit := ctx.PageRequest()
+		u := &url.URL{Path: {{ .PageHref }}, RawQuery: ctx.RequestData.URL.RawQuery}
+		ctx.ResponseData.Header().Set("Link", goa.CursorPageLinks(u, next, limit))
+	}
+}
+{{ else }}
+// PageRequest returns the requested page number starting at 1 and the page size.
+func (ctx *{{ .Name }}) PageRequest() (page, limit int) {
+	return goa.OffsetPageRequest(ctx.Page, ctx.Limit)
+}
+
+// SetPageLinks sets the X-Total-Count response header and the Link response header with the links
+// to the first, previous, next and last pages given the total number of items. The links path is
+// the action path as defined in the design.
+func (ctx *{{ .Name }}) SetPageLinks(total int) {
+	page, limit := ctx.PageRequest()
+	u := &url.URL{Path: {{ .PageHref }}, RawQuery: ctx.RequestData.URL.RawQuery}
+	ctx.ResponseData.Header().Set("X-Total-Count", strconv.Itoa(total))
+	ctx.ResponseData.Header().Set("Link", goa.OffsetPageLinks(u, page, limit, total))
+}
+{{ end }}`
+
+	// ctxStreamT generates the server-sent events helpers of streaming action contexts.
+	// template input: *ContextTemplateData
+	ctxStreamT = `{{ $mt := .Stream.EventMediaType }}
+// Send sends the event to the client and flushes it. id is the event ID sent back by the client in
+// the Last-Event-ID header when reconnecting, it may be empty. Send returns an error if the client
+// disconnected or if the stream is closed.
+func (ctx *{{ .Name }}) Send(id string, event {{ gotyperef $mt $mt.AllRequired 0 false }}) error {
+	return ctx.stream.Send(id, {{ printf "%q" .Stream.Event }}, event)
+}
+
+// Close ends the stream, the context is canceled once the stream is closed.
+func (ctx *{{ .Name }}) Close() error {
+	return ctx.stream.Close()
+}
+
+// LastEventID returns the ID of the last event received by a reconnecting client as sent in the
+// Last-Event-ID header, the empty string if there is none.
+func (ctx *{{ .Name }}) LastEventID() string {
+	return ctx.stream.LastEventID()
+}
+`
+
+	// ctxMessagesT generates the typed WebSocket connection of actions that declare messages.
+	// template input: *ContextTemplateData
+	ctxMessagesT = `{{ $conn := printf "%s%sConn" (goify .ActionName true) (goify .ResourceName true) }}
+// {{ $conn }} is the WebSocket connection of the {{ .ResourceName }} {{ .ActionName }} action.
+type {{ $conn }} struct {
+	*goa.WebSocketConn
+}
+
+// Serve upgrades the request to a WebSocket connection and calls handler with the connection. The
+// connection is closed when handler returns, see goa.ServeWebSocket.
+func (ctx *{{ .Name }}) Serve(handler func(*{{ $conn }}) error) error {
+	return goa.ServeWebSocket(ctx, ctx.Service, {{ if .Messages.KeepAlive }}{{ durationLit .Messages.KeepAlive }}{{ else }}0{{ end }}, func(conn *goa.WebSocketConn) error {
+		return handler(&{{ $conn }}{WebSocketConn: conn})
+	})
+}
+{{ with .Messages.Inbound }}
+// Recv reads, decodes and validates the next message sent by the client. It returns io.EOF when
+// the client closes the connection.
+func (c *{{ $conn }}) Recv() ({{ gotyperef . .AllRequired 0 false }}, error) {
+{{ if isUserType . }}	msg := new({{ gotypename . .AllRequired 0 true }})
+	if err := c.WebSocketConn.Recv(msg); err != nil {
+		return nil, err
+	}
+	return msg.Publicize(), nil
+{{ else }}	msg := new({{ gotypename . .AllRequired 0 false }})
+	if err := c.WebSocketConn.Recv(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+{{ end }}}
+{{ end }}{{ with .Messages.Outbound }}
+// Send encodes and sends the message to the client.
+func (c *{{ $conn }}) Send(msg {{ gotyperef . .AllRequired 0 false }}) error {
+	return c.WebSocketConn.Send(msg)
+}
+{{ end }}`
+
+	// ctxCacheT generates the conditional requests helpers of cacheable action contexts.
+	// template input: *ContextTemplateData
+	ctxCacheT = `
+// SetETag sets the ETag response header. For requests made with a method other than GET or HEAD
+// it returns an error if the If-Match request header does not match the entity tag.
+func (ctx *{{ .Name }}) SetETag(etag string) error {
+	return goa.SetETag(ctx, etag)
+}
+
+// SetLastModified sets the Last-Modified response header. For requests made with a method other
+// than GET or HEAD it returns an error if the resource was modified since the date given in the
+// If-Unmodified-Since request header.
+func (ctx *{{ .Name }}) SetLastModified(t time.Time) error {
+	return goa.SetLastModified(ctx, t)
+}
+`
+
+	// ctxErrorsT generates the typed error response helpers of action contexts.
+	// template input: *ContextTemplateData
+	ctxErrorsT = `{{ $ctx := . }}{{ range .Errors }}{{ $typeName := .Type.TypeName }}
+// {{ $typeName }} sends a HTTP response with status code {{ .Status }} carrying the {{ printf "%q" .Name }} error.
+func (ctx *{{ $ctx.Name }}) {{ $typeName }}(r *{{ $typeName }}) error {
+	ctx.ResponseData.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+	ctx.ResponseData.ErrorCode = r.Code
+	return ctx.Service.Send(ctx.Context, {{ .Status }}, r)
+}
+{{ end }}`
+
 	// ctxMTRespT generates the response helpers for responses with media types.
 	// template input: map[string]interface{}
 	ctxMTRespT = `{{ $ctx := .Context }}{{ $resp := .Response }}{{ $mt := .MediaType }}{{/*
@@ -509,7 +770,7 @@ func New{{ .Name }}(ctx context.Context, service *goa.Service) (*{{ .Name }}, er
 // {{ respName $resp $name }} sends a HTTP response with status code {{ $resp.Status }}.
 func (ctx *{{ $ctx.Name }}) {{ respName $resp $name }}(r {{ gotyperef $projected $projected.AllRequired 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ $resp.MediaType }}")
-	return ctx.Service.Send(ctx.Context, {{ $resp.Status }}, r)
+{{ if and $ctx.Fields (eq $resp.Name "OK") }}` + fieldsT + `{{ end }}	return ctx.Service.Send(ctx.Context, {{ $resp.Status }}, r)
 }
 {{ end }}{{ end }}
 `
@@ -519,10 +780,22 @@ func (ctx *{{ $ctx.Name }}) {{ respName $resp $name }}(r {{ gotyperef $projected
 	ctxTRespT = `// {{ goify .Response.Name true }} sends a HTTP response with status code {{ .Response.Status }}.
 func (ctx *{{ .Context.Name }}) {{ goify .Response.Name true }}(r {{ gotyperef .Type nil 0 false }}) error {
 	ctx.ResponseData.Header().Set("Content-Type", "{{ .Response.MediaType }}")
-	return ctx.Service.Send(ctx.Context, {{ .Response.Status }}, r)
+{{ if and .Context.Fields (eq .Response.Name "OK") }}` + fieldsT + `{{ end }}	return ctx.Service.Send(ctx.Context, {{ .Response.Status }}, r)
 }
 `
 
+	// fieldsT generates the code that renders the fields selected by the request in responses
+	// of actions that support field selection.
+	// template input: none
+	fieldsT = `	if len(ctx.Fields) > 0 {
+		selected, err := goa.SelectFields(r, ctx.Fields)
+		if err != nil {
+			return err
+		}
+		return ctx.Service.Send(ctx.Context, 200, selected)
+	}
+`
+
 	// ctxNoMTRespT generates the response helpers for responses with no known media type.
 	// template input: *ContextTemplateData
 	ctxNoMTRespT = `
@@ -598,18 +871,26 @@ func initService(service *goa.Service) {
 {{ end }}{{ end }}}
 `
 
+	// muxT generates the code that refers to the mux a controller is mounted on.
+	// template input: *ControllerTemplateData
+	muxT = `{{ if .Version }}service.VersionMux({{ printf "%q" .Version }}){{ else }}service.Mux{{ end }}`
+
 	// mountT generates the code for a resource "Mount" function.
 	// template input: *ControllerTemplateData
-	mountT = `
+	mountT = `{{ define "mux" }}` + muxT + `{{ end }}` + `
 // Mount{{ .Resource }}Controller "mounts" a {{ .Resource }} resource controller on the given service.
 func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Controller) {
 	initService(service)
 	var h goa.Handler
-{{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}	service.Mux.Handle("OPTIONS", "{{ . }}", cors.HandlePreflight(service.Context, handle{{ $res }}Origin))
+{{ $res := .Resource }}{{ if .Origins }}{{ range .PreflightPaths }}	{{ template "mux" $ }}.Handle("OPTIONS", "{{ . }}", cors.HandlePreflight(service.Context, handle{{ $res }}Origin))
 {{ end }}{{ end }}{{ range .Actions }}{{ $action := . }}
 	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
-		rctx, err := New{{ .Context }}(ctx, service)
-		if err != nil {
+{{ with .Deprecation }}		rw.Header().Set("Deprecation", "true")
+{{ if .SunsetHeader }}		rw.Header().Set("Sunset", {{ printf "%q" .SunsetHeader }})
+{{ end }}		goa.LogInfo(ctx, "deprecated action", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }})
+{{ end }}		rctx, err := New{{ .Context }}(ctx, service)
+{{ if .Stream }}		defer rctx.stream.Release()
+{{ end }}		if err != nil {
 			return err
 		}
 {{ if .Payload }}if rawPayload := goa.ContextRequest(ctx).Payload; rawPayload != nil {
@@ -617,9 +898,11 @@ func Mount{{ .Resource }}Controller(service *goa.Service, ctrl {{ .Resource }}Co
 		}
 		{{ end }}		return ctrl.{{ .Name }}(rctx)
 	}
-{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
+{{ if .Cacheable }}	h = middleware.ConditionalRequest()(h)
+{{ end }}{{ with .RateLimit }}	h = ratelimit.Default({{ printf "%q" .Scope }}, {{ .Limit }}, {{ durationLit .Period }})(h)
+{{ end }}{{ if $.Origins }}	h = handle{{ $res }}Origin(h)
 {{ end }}{{ if .Security }}	h = handleSecurity({{ printf "%q" .Security.Scheme.SchemeName }}, h{{ range .Security.Scopes }}, {{ printf "%q" . }}{{ end }})
-{{ end }}{{ range .Routes }}	service.Mux.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
+{{ end }}{{ range .Routes }}	{{ template "mux" $ }}.Handle("{{ .Verb }}", {{ printf "%q" .FullPath }}, ctrl.MuxHandler({{ printf "%q" $action.Name }}, h, {{ if $action.Payload }}{{ $action.Unmarshal }}{{ else }}nil{{ end }}))
 	service.LogInfo("mount", "ctrl", {{ printf "%q" $res }}, "action", {{ printf "%q" $action.Name }}, "route", {{ printf "%q" (printf "%s %s" .Verb .FullPath) }}{{ with $action.Security }}, "security", {{ printf "%q" .Scheme.SchemeName }}{{ end }})
 {{ end }}{{ end }}}
 `
@@ -656,10 +939,44 @@ func handle{{ .Resource }}Origin(h goa.Handler) goa.Handler {
 
 	// unmarshalT generates the code for an action payload unmarshal function.
 	// template input: *ControllerTemplateData
-	unmarshalT = `{{ range .Actions }}{{ if .Payload }}
+	unmarshalT = `{{ define "Coerce" }}` + coerceT + `{{ end }}` + `{{ range .Actions }}{{ if .Payload }}
 // {{ .Unmarshal }} unmarshals the request body into the context request data Payload field.
 func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Request) error {
-	{{ if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
+	{{ if .Multipart }}var err error
+	if err = req.ParseMultipartForm(goa.MaxMultipartMemory); err != nil {
+		return err
+	}
+	payload := &{{ gotypename .Payload nil 1 true }}{}
+{{ range $name, $att := .Payload.ToObject }}{{ if eq $att.Type.Kind 13 }}{{/*
+
+*/}}{{/* File */}}{{/*
+*/}}	if files := req.MultipartForm.File["{{ $name }}"]; len(files) > 0 {
+		payload.{{ goify $name true }} = files[0]
+	}
+{{ else if $att.Type.IsArray }}{{ if eq (arrayAttribute $att).Type.Kind 13 }}{{/*
+
+*/}}{{/* Array of files */}}{{/*
+*/}}	if files := req.MultipartForm.File["{{ $name }}"]; len(files) > 0 {
+		payload.{{ goify $name true }} = files
+	}
+{{ else }}{{/*
+
+*/}}{{/* Array of values */}}{{/*
+*/}}	for _, raw{{ goify $name true }} := range req.MultipartForm.Value["{{ $name }}"] {
+		var params {{ gotypedef $att 2 true true }}
+{{ template "Coerce" (newCoerceData $name $att false "params" 2) }}{{/*
+*/}}		payload.{{ goify $name true }} = append(payload.{{ goify $name true }}, params...)
+	}
+{{ end }}{{ else }}{{/*
+
+*/}}{{/* Form value */}}{{/*
+*/}}	if raw{{ goify $name true }} := req.FormValue("{{ $name }}"); raw{{ goify $name true }} != "" {
+{{ template "Coerce" (newCoerceData $name $att true (printf "payload.%s" (goify $name true)) 2) }}{{/*
+*/}}	}
+{{ end }}{{ end }}	if err != nil {
+		return err
+	}{{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
+	payload.Finalize(){{ end }}{{ else if .Payload.IsObject }}payload := &{{ gotypename .Payload nil 1 true }}{}
 	if err := service.DecodeRequest(req, payload); err != nil {
 		return err
 	}{{ $assignment := recursiveFinalizer .Payload.AttributeDefinition "payload" 1 }}{{ if $assignment }}
@@ -682,6 +999,11 @@ func {{ .Unmarshal }}(ctx context.Context, service *goa.Service, req *http.Reque
 func {{ .Name }}Href({{ if .CanonicalParams }}{{ join .CanonicalParams ", " }} interface{}{{ end }}) string {
 	return fmt.Sprintf("{{ .CanonicalTemplate }}", {{ join .CanonicalParams ", " }})
 }
+{{ end }}{{ range .ActionHrefs }}
+// {{ .Name }} returns the href of the {{ .Action }} action.
+func {{ .Name }}({{ if .Params }}{{ join .Params ", " }} interface{}{{ end }}) string {
+	return fmt.Sprintf("{{ .Template }}", {{ join .Params ", " }})
+}
 {{ end }}`
 
 	// mediaTypeT generates the code for a media type.
@@ -738,6 +1060,33 @@ func (ut {{ gotyperef . .AllRequired 0 false }}) Validate() (err error) {
 {{ $validation }}
 	return err
 }{{ end }}
+`
+
+	// errorT generates the code for a typed error.
+	// template input: *design.ErrorDefinition
+	errorT = `{{ $typeName := .Type.TypeName }}// {{ $typeName }} is the {{ printf "%q" .Name }} error, responses that carry it use the status code {{ .Status }}.{{ with .Description }}
+{{ comment . }}{{ end }}
+type {{ $typeName }} {{ gotypedef .Type 0 true false }}
+
+// New{{ $typeName }} creates a {{ printf "%q" .Name }} error whose detail is produced by formatting v with format.
+func New{{ $typeName }}(format string, v ...interface{}) *{{ $typeName }} {
+	return &{{ $typeName }}{Code: {{ printf "%q" .Name }}, Status: {{ .Status }}, Detail: fmt.Sprintf(format, v...)}
+}
+
+// Error returns the error occurrence details.
+func (e *{{ $typeName }}) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+// ResponseStatus returns the HTTP status code used by responses that carry the error.
+func (e *{{ $typeName }}) ResponseStatus() int {
+	return e.Status
+}
+
+// ErrorCode returns the code that identifies the class of errors.
+func (e *{{ $typeName }}) ErrorCode() string {
+	return e.Code
+}
 `
 
 	// securitySchemesT generates the code for the security module.
@@ -751,7 +1100,7 @@ const securityScopesKey key = 1
 func Configure{{ goify .SchemeName true }}Security(service *goa.Service, f goa.{{ .Context }}ConfigFunc) {
 	def := &goa.{{ .Context }}{
 {{ if eq .Context "APIKeySecurity" }}
-		In:   {{ if eq .In "header" }}goa.LocHeader{{ else }}goa.LocQuery{{ end }},
+		In:   {{ if eq .In "header" }}goa.LocHeader{{ else if eq .In "cookie" }}goa.LocCookie{{ else }}goa.LocQuery{{ end }},
 		Name: {{ printf "%q" .Name }},
 {{ else if eq .Context "OAuth2Security" }}
 		Flow:             {{ printf "%q" .Flow }},
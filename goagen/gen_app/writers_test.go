@@ -3,6 +3,7 @@ package genapp_test
 import (
 	"io/ioutil"
 	"os"
+	"time"
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
@@ -46,17 +47,30 @@ var _ = Describe("ContextsWriter", func() {
 		})
 
 		Context("with data", func() {
-			var params, headers *design.AttributeDefinition
+			var params, headers, cookies *design.AttributeDefinition
 			var payload *design.UserTypeDefinition
 			var responses map[string]*design.ResponseDefinition
+			var pagination *design.PaginationDefinition
+			var fields *design.FieldsDefinition
+			var stream *design.StreamDefinition
+			var messages *design.MessagesDefinition
+			var cacheable bool
+			var errors []*design.ErrorDefinition
 
 			var data *genapp.ContextTemplateData
 
 			BeforeEach(func() {
 				params = nil
 				headers = nil
+				cookies = nil
 				payload = nil
 				responses = nil
+				pagination = nil
+				fields = nil
+				stream = nil
+				messages = nil
+				cacheable = false
+				errors = nil
 				data = nil
 			})
 
@@ -68,9 +82,17 @@ var _ = Describe("ContextsWriter", func() {
 					Params:       params,
 					Payload:      payload,
 					Headers:      headers,
+					Cookies:      cookies,
 					Responses:    responses,
 					API:          design.Design,
 					DefaultPkg:   "",
+					Pagination:   pagination,
+					PageHref:     "ListBottleHref(ctx.AccountID)",
+					Fields:       fields,
+					Stream:       stream,
+					Messages:     messages,
+					Cacheable:    cacheable,
+					Errors:       errors,
 				}
 			})
 
@@ -87,6 +109,196 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with offset pagination", func() {
+				BeforeEach(func() {
+					pagination = &design.PaginationDefinition{Style: design.OffsetPagination}
+					params = &design.AttributeDefinition{Type: pagination.Params()}
+				})
+
+				It("writes the pagination helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(offsetPageContext))
+				})
+			})
+
+			Context("with cursor pagination", func() {
+				BeforeEach(func() {
+					pagination = &design.PaginationDefinition{Style: design.CursorPagination}
+					params = &design.AttributeDefinition{Type: pagination.Params()}
+				})
+
+				It("writes the pagination helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tCursor *string\n"))
+					Ω(written).Should(ContainSubstring(cursorPageContext))
+				})
+			})
+
+			Context("with field selection", func() {
+				BeforeEach(func() {
+					mt := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"id": {Type: design.Integer}},
+							},
+							TypeName: "Bottle",
+						},
+						Identifier: "application/vnd.bottle+json",
+					}
+					fields = &design.FieldsDefinition{MediaType: mt.Identifier}
+					params = &design.AttributeDefinition{Type: design.Object{
+						design.FieldsParam: {Type: &design.Array{ElemType: &design.AttributeDefinition{
+							Type:       design.String,
+							Validation: &dslengine.ValidationDefinition{Values: []interface{}{"id"}},
+						}}},
+					}}
+					responses = map[string]*design.ResponseDefinition{
+						"OK": {Name: "OK", Status: 200, MediaType: mt.Identifier, Type: mt},
+					}
+				})
+
+				It("renders the selected fields in the OK response", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tFields []string\n"))
+					Ω(written).Should(ContainSubstring(fieldsContextFactory))
+					Ω(written).Should(ContainSubstring(fieldsContext))
+				})
+			})
+
+			Context("with cookies", func() {
+				BeforeEach(func() {
+					cookies = &design.AttributeDefinition{
+						Type: design.Object{
+							"session": &design.AttributeDefinition{Type: design.String},
+							"visits":  &design.AttributeDefinition{Type: design.Integer},
+						},
+						Validation: &dslengine.ValidationDefinition{Required: []string{"session"}},
+					}
+				})
+
+				It("writes the cookies parsing code", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(cookiesContext))
+					Ω(written).Should(ContainSubstring(cookiesContextFactory))
+				})
+			})
+
+			Context("with a stream", func() {
+				BeforeEach(func() {
+					tick := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"seq": &design.AttributeDefinition{Type: design.Integer}},
+							},
+							TypeName: "Tick",
+						},
+						Identifier: "application/vnd.tick+json",
+					}
+					design.Design = &design.APIDefinition{
+						MediaTypes: map[string]*design.MediaTypeDefinition{tick.Identifier: tick},
+					}
+					stream = &design.StreamDefinition{MediaType: tick.Identifier, Event: "tick"}
+				})
+
+				AfterEach(func() {
+					design.Design = nil
+				})
+
+				It("writes the stream helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring("\tstream *goa.EventStream\n"))
+					Ω(written).Should(ContainSubstring(streamContextFactory))
+					Ω(written).Should(ContainSubstring(streamContext))
+				})
+			})
+
+			Context("with messages", func() {
+				BeforeEach(func() {
+					post := &design.UserTypeDefinition{
+						AttributeDefinition: &design.AttributeDefinition{
+							Type: design.Object{"body": &design.AttributeDefinition{Type: design.String}},
+						},
+						TypeName: "ChatPost",
+					}
+					message := &design.MediaTypeDefinition{
+						UserTypeDefinition: &design.UserTypeDefinition{
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{"body": &design.AttributeDefinition{Type: design.String}},
+							},
+							TypeName: "ChatMessage",
+						},
+						Identifier: "application/vnd.chat.message+json",
+					}
+					messages = &design.MessagesDefinition{Inbound: post, Outbound: message, KeepAlive: 30 * time.Second}
+				})
+
+				It("writes the typed connection", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(messagesConn))
+					Ω(written).Should(ContainSubstring(messagesServe))
+					Ω(written).Should(ContainSubstring(messagesRecv))
+					Ω(written).Should(ContainSubstring(messagesSend))
+				})
+			})
+
+			Context("with a cacheable action", func() {
+				BeforeEach(func() {
+					cacheable = true
+				})
+
+				It("writes the conditional requests helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(cacheableContext))
+				})
+			})
+
+			Context("with typed errors", func() {
+				BeforeEach(func() {
+					errors = []*design.ErrorDefinition{{
+						Name:   "not_found",
+						Status: 404,
+						Type:   &design.UserTypeDefinition{TypeName: "NotFoundError"},
+					}}
+				})
+
+				It("writes the error response helpers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(errorContext))
+				})
+			})
+
 			Context("with an integer param", func() {
 				BeforeEach(func() {
 					intParam := &design.AttributeDefinition{Type: design.Integer}
@@ -156,6 +368,29 @@ var _ = Describe("ContextsWriter", func() {
 				})
 			})
 
+			Context("with a date time param", func() {
+				BeforeEach(func() {
+					dateTimeParam := &design.AttributeDefinition{Type: design.DateTime}
+					dataType := design.Object{
+						"param": dateTimeParam,
+					}
+					params = &design.AttributeDefinition{
+						Type: dataType,
+					}
+				})
+
+				It("writes the contexts code parsing RFC3339 values", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).ShouldNot(BeEmpty())
+					Ω(written).Should(ContainSubstring(dateTimeContext))
+					Ω(written).Should(ContainSubstring(dateTimeContextFactory))
+				})
+			})
+
 			Context("with a boolean param", func() {
 				BeforeEach(func() {
 					boolParam := &design.AttributeDefinition{Type: design.Boolean}
@@ -393,10 +628,22 @@ var _ = Describe("ControllersWriter", func() {
 			var payloads []*design.UserTypeDefinition
 			var encoders, decoders []*genapp.EncoderTemplateData
 			var origins []*design.CORSDefinition
+			var version string
+			var deprecation *design.DeprecationDefinition
+			var rateLimit *design.RateLimitDefinition
+			var cacheable bool
+			var multipart bool
+			var stream bool
 
 			var data []*genapp.ControllerTemplateData
 
 			BeforeEach(func() {
+				version = ""
+				deprecation = nil
+				rateLimit = nil
+				cacheable = false
+				multipart = false
+				stream = false
 				actions = nil
 				verbs = nil
 				paths = nil
@@ -414,6 +661,7 @@ var _ = Describe("ControllersWriter", func() {
 				d := &genapp.ControllerTemplateData{
 					Resource: "Bottles",
 					Origins:  origins,
+					Version:  version,
 				}
 				as := make([]map[string]interface{}, len(actions))
 				for i, a := range actions {
@@ -432,9 +680,14 @@ var _ = Describe("ControllersWriter", func() {
 								Verb: verbs[i],
 								Path: paths[i],
 							}},
-						"Context":   contexts[i],
-						"Unmarshal": unmarshal,
-						"Payload":   payload,
+						"Context":     contexts[i],
+						"Unmarshal":   unmarshal,
+						"Payload":     payload,
+						"Multipart":   multipart,
+						"Deprecation": deprecation,
+						"RateLimit":   rateLimit,
+						"Cacheable":   cacheable,
+						"Stream":      stream,
 					}
 				}
 				if len(as) > 0 {
@@ -479,6 +732,113 @@ var _ = Describe("ControllersWriter", func() {
 				})
 			})
 
+			Context("with a controller of an API version", func() {
+				BeforeEach(func() {
+					version = "v2"
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("mounts the controller on the version mux", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`service.VersionMux("v2").Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", h, nil))`))
+				})
+			})
+
+			Context("with a deprecated action", func() {
+				BeforeEach(func() {
+					deprecation = &design.DeprecationDefinition{
+						Sunset: time.Date(2017, time.January, 1, 0, 0, 0, 0, time.UTC),
+					}
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("sets the deprecation response headers", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(deprecatedMount))
+				})
+			})
+
+			Context("with a streaming action", func() {
+				BeforeEach(func() {
+					stream = true
+					actions = []string{"Watch"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles/watch"}
+					contexts = []string{"WatchBottleContext"}
+				})
+
+				It("releases the event stream once the action returns", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`		rctx, err := NewWatchBottleContext(ctx, service)
+		defer rctx.stream.Release()
+		if err != nil {
+			return err
+		}
+		return ctrl.Watch(rctx)
+`))
+				})
+			})
+
+			Context("with a rate limited action", func() {
+				BeforeEach(func() {
+					rateLimit = &design.RateLimitDefinition{Limit: 100, Period: time.Minute, Scope: "cellar/bottles"}
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("enforces the rate limit", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`	h = ratelimit.Default("cellar/bottles", 100, time.Minute)(h)
+	service.Mux.Handle("GET", "/accounts/:accountID/bottles", ctrl.MuxHandler("List", h, nil))`))
+				})
+			})
+
+			Context("with a cacheable action", func() {
+				BeforeEach(func() {
+					cacheable = true
+					rateLimit = &design.RateLimitDefinition{Limit: 100, Period: time.Minute, Scope: "cellar/bottles"}
+					actions = []string{"List"}
+					verbs = []string{"GET"}
+					paths = []string{"/accounts/:accountID/bottles"}
+					contexts = []string{"ListBottleContext"}
+				})
+
+				It("evaluates the conditional requests before applying the rate limit", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(`	h = middleware.ConditionalRequest()(h)
+	h = ratelimit.Default("cellar/bottles", 100, time.Minute)(h)
+`))
+				})
+			})
+
 			Context("with actions that take a payload", func() {
 				BeforeEach(func() {
 					actions = []string{"List"}
@@ -544,6 +904,43 @@ var _ = Describe("ControllersWriter", func() {
 				})
 			})
 
+			Context("with actions that take a multipart payload", func() {
+				BeforeEach(func() {
+					actions = []string{"Upload"}
+					verbs = []string{"POST"}
+					paths = []string{"/bottles/upload"}
+					contexts = []string{"UploadBottleContext"}
+					unmarshals = []string{"unmarshalUploadBottlePayload"}
+					multipart = true
+					payloads = []*design.UserTypeDefinition{
+						{
+							TypeName: "UploadBottlePayload",
+							AttributeDefinition: &design.AttributeDefinition{
+								Type: design.Object{
+									"label": &design.AttributeDefinition{Type: design.File},
+									"photos": &design.AttributeDefinition{Type: &design.Array{
+										ElemType: &design.AttributeDefinition{Type: design.File},
+									}},
+									"bottled_at": &design.AttributeDefinition{Type: design.DateTime},
+									"ratings": &design.AttributeDefinition{Type: &design.Array{
+										ElemType: &design.AttributeDefinition{Type: design.Integer},
+									}},
+								},
+							},
+						},
+					}
+				})
+
+				It("writes the multipart form unmarshal function", func() {
+					err := writer.Execute(data)
+					Ω(err).ShouldNot(HaveOccurred())
+					b, err := ioutil.ReadFile(filename)
+					Ω(err).ShouldNot(HaveOccurred())
+					written := string(b)
+					Ω(written).Should(ContainSubstring(payloadMultipartUnmarshal))
+				})
+			})
+
 			Context("with multiple controllers", func() {
 				BeforeEach(func() {
 					actions = []string{"List", "Show"}
@@ -666,6 +1063,7 @@ var _ = Describe("HrefWriter", func() {
 			var canoTemplate string
 			var canoParams []string
 			var mediaType *design.MediaTypeDefinition
+			var actionHrefs []*genapp.ActionHrefData
 
 			var data *genapp.ResourceData
 
@@ -673,6 +1071,7 @@ var _ = Describe("HrefWriter", func() {
 				mediaType = nil
 				canoTemplate = ""
 				canoParams = nil
+				actionHrefs = nil
 				data = nil
 			})
 
@@ -684,6 +1083,7 @@ var _ = Describe("HrefWriter", func() {
 					Type:              mediaType,
 					CanonicalTemplate: canoTemplate,
 					CanonicalParams:   canoParams,
+					ActionHrefs:       actionHrefs,
 				}
 			})
 
@@ -747,11 +1147,88 @@ var _ = Describe("HrefWriter", func() {
 						Ω(written).Should(ContainSubstring(simpleResourceHref))
 					})
 				})
+
+				Context("and a paginated action", func() {
+					BeforeEach(func() {
+						actionHrefs = []*genapp.ActionHrefData{{
+							Name:     "ListBottleHref",
+							Action:   "list",
+							Template: "/accounts/%v/bottles",
+							Params:   []string{"accountID"},
+						}}
+					})
+
+					It("writes the action href method", func() {
+						err := writer.Execute(data)
+						Ω(err).ShouldNot(HaveOccurred())
+						b, err := ioutil.ReadFile(filename)
+						Ω(err).ShouldNot(HaveOccurred())
+						written := string(b)
+						Ω(written).Should(ContainSubstring(actionHref))
+					})
+				})
 			})
 		})
 	})
 })
 
+var _ = Describe("ErrorsWriter", func() {
+	var writer *genapp.ErrorsWriter
+	var workspace *codegen.Workspace
+	var filename string
+
+	BeforeEach(func() {
+		var err error
+		workspace, err = codegen.NewWorkspace("test")
+		Ω(err).ShouldNot(HaveOccurred())
+		pkg, err := workspace.NewPackage("errors")
+		Ω(err).ShouldNot(HaveOccurred())
+		src := pkg.CreateSourceFile("test.go")
+		filename = src.Abs()
+	})
+
+	JustBeforeEach(func() {
+		var err error
+		writer, err = genapp.NewErrorsWriter(filename)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		workspace.Delete()
+	})
+
+	Context("with a typed error", func() {
+		var e *design.ErrorDefinition
+
+		BeforeEach(func() {
+			fields := design.ErrorFields()
+			fields["id"] = &design.AttributeDefinition{Type: design.Integer}
+			e = &design.ErrorDefinition{
+				Name:   "not_found",
+				Status: 404,
+				Type: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type:        fields,
+						Description: "The bottle does not exist",
+						Validation:  &dslengine.ValidationDefinition{Required: []string{"code", "status", "detail", "id"}},
+					},
+					TypeName: "NotFoundError",
+				},
+			}
+		})
+
+		It("writes the error type and constructor", func() {
+			err := writer.Execute(e)
+			Ω(err).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(filename)
+			Ω(err).ShouldNot(HaveOccurred())
+			written := string(b)
+			Ω(written).Should(ContainSubstring(errorType))
+			Ω(written).Should(ContainSubstring(errorConstructor))
+		})
+	})
+})
+
 const (
 	emptyContext = `
 type ListBottleContext struct {
@@ -852,6 +1329,34 @@ func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottl
 	}
 	return &rctx, err
 }
+`
+	dateTimeContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Service *goa.Service
+	Param *time.Time
+}
+`
+
+	dateTimeContextFactory = `
+func NewListBottleContext(ctx context.Context, service *goa.Service) (*ListBottleContext, error) {
+	var err error
+	req := goa.ContextRequest(ctx)
+	rctx := ListBottleContext{Context: ctx, ResponseData: goa.ContextResponse(ctx), RequestData: req, Service: service}
+	paramParam := req.Params["param"]
+	if len(paramParam) > 0 {
+		rawParam := paramParam[0]
+		if param, err2 := time.Parse(time.RFC3339, rawParam); err2 == nil {
+			tmp1 := &param
+			rctx.Param = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("param", rawParam, "datetime"))
+		}
+	}
+	return &rctx, err
+}
 `
 	boolContext = `
 type ListBottleContext struct {
@@ -1056,6 +1561,49 @@ func unmarshalListBottlePayload(ctx context.Context, service *goa.Service, req *
 	goa.ContextRequest(ctx).Payload = payload.Publicize()
 	return nil
 }
+`
+
+	payloadMultipartUnmarshal = `
+func unmarshalUploadBottlePayload(ctx context.Context, service *goa.Service, req *http.Request) error {
+	var err error
+	if err = req.ParseMultipartForm(goa.MaxMultipartMemory); err != nil {
+		return err
+	}
+	payload := &uploadBottlePayload{}
+	if rawBottledAt := req.FormValue("bottled_at"); rawBottledAt != "" {
+		if bottledAt, err2 := time.Parse(time.RFC3339, rawBottledAt); err2 == nil {
+			tmp1 := &bottledAt
+			payload.BottledAt = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("bottled_at", rawBottledAt, "datetime"))
+		}
+	}
+	if files := req.MultipartForm.File["label"]; len(files) > 0 {
+		payload.Label = files[0]
+	}
+	if files := req.MultipartForm.File["photos"]; len(files) > 0 {
+		payload.Photos = files
+	}
+	for _, rawRatings := range req.MultipartForm.Value["ratings"] {
+		var params []int
+		elemsRatings := strings.Split(rawRatings, ",")
+		elemsRatings2 := make([]int, len(elemsRatings))
+		for i, rawElem := range elemsRatings {
+			if elem, err2 := strconv.Atoi(rawElem); err2 == nil {
+				elemsRatings2[i] = elem
+			} else {
+				err = goa.MergeErrors(err, goa.InvalidParamTypeError("elem", rawElem, "integer"))
+			}
+		}
+		params = elemsRatings2
+		payload.Ratings = append(payload.Ratings, params...)
+	}
+	if err != nil {
+		return err
+	}
+	goa.ContextRequest(ctx).Payload = payload.Publicize()
+	return nil
+}
 `
 
 	simpleController = `// BottlesController is the controller interface for the Bottles actions.
@@ -1126,6 +1674,155 @@ func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 }
 `
 
+	cookiesContext = `
+type ListBottleContext struct {
+	context.Context
+	*goa.ResponseData
+	*goa.RequestData
+	Service *goa.Service
+	Session string
+	Visits *int
+}
+`
+
+	cookiesContextFactory = `
+	if cookie, cerr := req.Cookie("session"); cerr != nil {
+		err = goa.MergeErrors(err, goa.MissingCookieError("session"))
+	} else {
+		rawSession := cookie.Value
+		rctx.Session = rawSession
+	}
+	if cookie, cerr := req.Cookie("visits"); cerr == nil {
+		rawVisits := cookie.Value
+		if visits, err2 := strconv.Atoi(rawVisits); err2 == nil {
+			tmp2 := visits
+			tmp1 := &tmp2
+			rctx.Visits = tmp1
+		} else {
+			err = goa.MergeErrors(err, goa.InvalidParamTypeError("visits", rawVisits, "integer"))
+		}
+	}
+	return &rctx, err
+}
+`
+
+	streamContextFactory = `
+	rctx := ListBottleContext{Context: ctx, ResponseData: goa.ContextResponse(ctx), RequestData: req, Service: service}
+	rctx.stream = goa.NewEventStream(ctx)
+	rctx.Context = rctx.stream.Context()
+`
+
+	streamContext = `
+func (ctx *ListBottleContext) Send(id string, event *Tick) error {
+	return ctx.stream.Send(id, "tick", event)
+}
+`
+
+	messagesConn = `
+type ListBottlesConn struct {
+	*goa.WebSocketConn
+}
+`
+
+	messagesServe = `
+func (ctx *ListBottleContext) Serve(handler func(*ListBottlesConn) error) error {
+	return goa.ServeWebSocket(ctx, ctx.Service, 30 * time.Second, func(conn *goa.WebSocketConn) error {
+		return handler(&ListBottlesConn{WebSocketConn: conn})
+	})
+}
+`
+
+	messagesRecv = `
+func (c *ListBottlesConn) Recv() (*ChatPost, error) {
+	msg := new(chatPost)
+	if err := c.WebSocketConn.Recv(msg); err != nil {
+		return nil, err
+	}
+	return msg.Publicize(), nil
+}
+`
+
+	messagesSend = `
+func (c *ListBottlesConn) Send(msg *ChatMessage) error {
+	return c.WebSocketConn.Send(msg)
+}
+`
+
+	errorContext = `
+func (ctx *ListBottleContext) NotFoundError(r *NotFoundError) error {
+	ctx.ResponseData.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
+	ctx.ResponseData.ErrorCode = r.Code
+	return ctx.Service.Send(ctx.Context, 404, r)
+}
+`
+
+	errorType = `// NotFoundError is the "not_found" error, responses that carry it use the status code 404.
+// The bottle does not exist
+type NotFoundError struct {
+	// Code identifies the class of errors.
+	Code string ` + "`" + `json:"code" xml:"code"` + "`" + `
+	// Detail describes the specific error occurrence.
+	Detail string ` + "`" + `json:"detail" xml:"detail"` + "`" + `
+	ID int ` + "`" + `json:"id" xml:"id"` + "`" + `
+`
+
+	errorConstructor = `
+func NewNotFoundError(format string, v ...interface{}) *NotFoundError {
+	return &NotFoundError{Code: "not_found", Status: 404, Detail: fmt.Sprintf(format, v...)}
+}
+`
+
+	cacheableContext = `
+func (ctx *ListBottleContext) SetETag(etag string) error {
+	return goa.SetETag(ctx, etag)
+}
+`
+
+	offsetPageContext = `
+// PageRequest returns the requested page number starting at 1 and the page size.
+func (ctx *ListBottleContext) PageRequest() (page, limit int) {
+	return goa.OffsetPageRequest(ctx.Page, ctx.Limit)
+}
+
+// SetPageLinks sets the X-Total-Count response header and the Link response header with the links
+// to the first, previous, next and last pages given the total number of items. The links path is
+// the action path as defined in the design.
+func (ctx *ListBottleContext) SetPageLinks(total int) {
+	page, limit := ctx.PageRequest()
+	u := &url.URL{Path: ListBottleHref(ctx.AccountID), RawQuery: ctx.RequestData.URL.RawQuery}
+	ctx.ResponseData.Header().Set("X-Total-Count", strconv.Itoa(total))
+	ctx.ResponseData.Header().Set("Link", goa.OffsetPageLinks(u, page, limit, total))
+}
+`
+
+	cursorPageContext = `
+// PageRequest returns the cursor of the requested page, empty for the first page, and the page size.
+func (ctx *ListBottleContext) PageRequest() (cursor string, limit int) {
+	if ctx.Cursor != nil {
+		cursor = *ctx.Cursor
+	}
+	return cursor, goa.PageLimit(ctx.Limit)
+}
+
+// SetPageLinks sets the Link response header with the link to the page identified by the given
+// cursor. An empty cursor indicates that the current page is the last one. The link path is the
+// action path as defined in the design.
+func (ctx *ListBottleContext) SetPageLinks(next string) {
+	if next != "" {
+		_, limit := ctx.PageRequest()
+		u := &url.URL{Path: ListBottleHref(ctx.AccountID), RawQuery: ctx.RequestData.URL.RawQuery}
+		ctx.ResponseData.Header().Set("Link", goa.CursorPageLinks(u, next, limit))
+	}
+}
+`
+
+	deprecatedMount = `	h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+		rw.Header().Set("Deprecation", "true")
+		rw.Header().Set("Sunset", "Sun, 01 Jan 2017 00:00:00 GMT")
+		goa.LogInfo(ctx, "deprecated action", "ctrl", "Bottles", "action", "List")
+		rctx, err := NewListBottleContext(ctx, service)
+`
+
 	simpleMount = `func MountBottlesController(service *goa.Service, ctrl BottlesController) {
 	initService(service)
 	var h goa.Handler
@@ -1179,5 +1876,35 @@ type BottlesController interface {
 	simpleResourceHref = `func BottleHref(id interface{}) string {
 	return fmt.Sprintf("/bottles/%v", id)
 }
+`
+
+	actionHref = `// ListBottleHref returns the href of the list action.
+func ListBottleHref(accountID interface{}) string {
+	return fmt.Sprintf("/accounts/%v/bottles", accountID)
+}
+`
+
+	fieldsContextFactory = `
+	for _, field := range rctx.Fields {
+		if !(field == "id") {
+			err = goa.MergeErrors(err, goa.InvalidEnumValueError(` + "`" + `fields` + "`" + `, field, []interface{}{"id"}))
+		}
+	}
+	return &rctx, err
+`
+
+	fieldsContext = `
+// OK sends a HTTP response with status code 200.
+func (ctx *ListBottleContext) OK(r *Bottle) error {
+	ctx.ResponseData.Header().Set("Content-Type", "application/vnd.bottle+json")
+	if len(ctx.Fields) > 0 {
+		selected, err := goa.SelectFields(r, ctx.Fields)
+		if err != nil {
+			return err
+		}
+		return ctx.Service.Send(ctx.Context, 200, selected)
+	}
+	return ctx.Service.Send(ctx.Context, 200, r)
+}
 `
 )
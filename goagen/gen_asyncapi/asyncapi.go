@@ -0,0 +1,331 @@
+package genasyncapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// Version is the version of the AsyncAPI specification produced by the generator.
+const Version = "2.0.0"
+
+type (
+	// AsyncAPI represents an instance of an AsyncAPI specification.
+	// See https://www.asyncapi.com/docs/specifications/2.0.0
+	AsyncAPI struct {
+		AsyncAPI           string              `json:"asyncapi"`
+		Info               *Info               `json:"info"`
+		Servers            map[string]*Server  `json:"servers,omitempty"`
+		DefaultContentType string              `json:"defaultContentType,omitempty"`
+		Channels           map[string]*Channel `json:"channels"`
+		Components         *Components         `json:"components,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title          string                    `json:"title"`
+		Version        string                    `json:"version"`
+		Description    string                    `json:"description,omitempty"`
+		TermsOfService string                    `json:"termsOfService,omitempty"`
+		Contact        *design.ContactDefinition `json:"contact,omitempty"`
+		License        *design.LicenseDefinition `json:"license,omitempty"`
+	}
+
+	// Server describes a server the clients may connect to.
+	Server struct {
+		// URL is the server URL, it does not include the scheme.
+		URL string `json:"url"`
+		// Protocol is the protocol used to connect to the server, "ws" or "wss".
+		Protocol string `json:"protocol"`
+		// Description is the server description.
+		Description string `json:"description,omitempty"`
+	}
+
+	// Channel describes the messages exchanged over the connections made to a WebSocket action
+	// route.
+	Channel struct {
+		// Description is the description of the action.
+		Description string `json:"description,omitempty"`
+		// Parameters describes the route wildcards.
+		Parameters map[string]*Parameter `json:"parameters,omitempty"`
+		// Publish describes the messages sent by the clients.
+		Publish *Operation `json:"publish,omitempty"`
+		// Subscribe describes the messages sent by the server.
+		Subscribe *Operation `json:"subscribe,omitempty"`
+		// Bindings describes the WebSocket handshake request.
+		Bindings *ChannelBindings `json:"bindings,omitempty"`
+	}
+
+	// Parameter describes a channel parameter.
+	Parameter struct {
+		Description string                `json:"description,omitempty"`
+		Schema      *genschema.JSONSchema `json:"schema,omitempty"`
+	}
+
+	// Operation describes the messages sent in one direction over a channel.
+	Operation struct {
+		OperationID string   `json:"operationId,omitempty"`
+		Summary     string   `json:"summary,omitempty"`
+		Tags        []*Tag   `json:"tags,omitempty"`
+		Message     *Message `json:"message"`
+	}
+
+	// Message describes the messages sent by an operation.
+	Message struct {
+		Name    string                `json:"name,omitempty"`
+		Title   string                `json:"title,omitempty"`
+		Summary string                `json:"summary,omitempty"`
+		Payload *genschema.JSONSchema `json:"payload,omitempty"`
+	}
+
+	// Tag allows adding metadata to an operation.
+	Tag struct {
+		Name string `json:"name"`
+	}
+
+	// ChannelBindings contains the protocol specific information of a channel.
+	ChannelBindings struct {
+		WS *WebSocketBinding `json:"ws,omitempty"`
+	}
+
+	// WebSocketBinding describes the WebSocket handshake request.
+	WebSocketBinding struct {
+		// Method is the HTTP method of the handshake request.
+		Method string `json:"method,omitempty"`
+		// Query describes the handshake request query string parameters.
+		Query *genschema.JSONSchema `json:"query,omitempty"`
+		// Headers describes the handshake request headers.
+		Headers *genschema.JSONSchema `json:"headers,omitempty"`
+		// BindingVersion is the version of the binding specification.
+		BindingVersion string `json:"bindingVersion,omitempty"`
+	}
+
+	// Components holds the schemas referenced by the message payloads.
+	Components struct {
+		Schemas map[string]*genschema.JSONSchema `json:"schemas,omitempty"`
+	}
+)
+
+// New creates the AsyncAPI specification of the WebSocket actions of the given API.
+func New(api *design.APIDefinition) (*AsyncAPI, error) {
+	if api == nil {
+		return nil, nil
+	}
+	s := &AsyncAPI{
+		AsyncAPI: Version,
+		Info: &Info{
+			Title:          api.Title,
+			Version:        api.Version,
+			Description:    api.Description,
+			TermsOfService: api.TermsOfService,
+			Contact:        api.Contact,
+			License:        api.License,
+		},
+		DefaultContentType: "application/json",
+		Channels:           make(map[string]*Channel),
+	}
+	err := api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if !a.WebSocket() {
+				return nil
+			}
+			for _, scheme := range a.EffectiveSchemes() {
+				addServer(s, api, scheme)
+			}
+			for i, route := range a.Routes {
+				if err := buildChannelFromDefinition(s, api, route, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(genschema.Definitions) > 0 {
+		s.Components = &Components{Schemas: make(map[string]*genschema.JSONSchema)}
+		for n, d := range genschema.Definitions {
+			d.Media = nil
+			d.Links = nil
+			componentRefs(d)
+			s.Components.Schemas[n] = d
+		}
+	}
+	return s, nil
+}
+
+// addServer records the server of the API that uses the given scheme.
+func addServer(s *AsyncAPI, api *design.APIDefinition, scheme string) {
+	if api.Host == "" {
+		return
+	}
+	if _, ok := s.Servers[scheme]; ok {
+		return
+	}
+	if s.Servers == nil {
+		s.Servers = make(map[string]*Server)
+	}
+	s.Servers[scheme] = &Server{URL: api.Host, Protocol: scheme}
+}
+
+// buildChannelFromDefinition records the channel describing the given WebSocket action route.
+// index is the index of the route in the action routes.
+func buildChannelFromDefinition(s *AsyncAPI, api *design.APIDefinition, route *design.RouteDefinition, index int) error {
+	action := route.Parent
+	key := design.WildcardRegex.ReplaceAllStringFunc(
+		route.FullPath(),
+		func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		},
+	)
+	if key == "" {
+		key = "/"
+	}
+	if _, ok := s.Channels[key]; ok {
+		return fmt.Errorf("channel %s is defined by multiple WebSocket actions", key)
+	}
+	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
+	if index > 0 {
+		operationID = fmt.Sprintf("%s#%d", operationID, index)
+	}
+	channel := &Channel{Description: action.Description}
+
+	params := action.AllParams()
+	wildcards := design.ExtractWildcards(route.FullPath())
+	query := genschema.NewJSONSchema()
+	query.Type = genschema.JSONObject
+	if params != nil {
+		obj := params.Type.ToObject()
+		if obj == nil {
+			return fmt.Errorf("invalid parameters definition, not an object")
+		}
+		for _, n := range sortedNames(obj) {
+			schema := attributeSchema(api, obj[n])
+			if isWildcard(n, wildcards) {
+				if channel.Parameters == nil {
+					channel.Parameters = make(map[string]*Parameter)
+				}
+				channel.Parameters[n] = &Parameter{Description: obj[n].Description, Schema: schema}
+				continue
+			}
+			query.Properties[n] = schema
+			if params.IsRequired(n) {
+				query.Required = append(query.Required, n)
+			}
+		}
+	}
+	binding := &WebSocketBinding{Method: "GET", BindingVersion: "0.1.0"}
+	if len(query.Properties) > 0 {
+		binding.Query = query
+	}
+	if action.Headers != nil {
+		if obj := action.Headers.Type.ToObject(); len(obj) > 0 {
+			headers := genschema.NewJSONSchema()
+			headers.Type = genschema.JSONObject
+			for _, n := range sortedNames(obj) {
+				headers.Properties[n] = attributeSchema(api, obj[n])
+				if action.Headers.IsRequired(n) {
+					headers.Required = append(headers.Required, n)
+				}
+			}
+			binding.Headers = headers
+		}
+	}
+	channel.Bindings = &ChannelBindings{WS: binding}
+
+	if m := action.Messages; m != nil {
+		tags := []*Tag{{Name: action.Parent.Name}}
+		if m.Inbound != nil {
+			channel.Publish = &Operation{
+				OperationID: operationID + "#publish",
+				Summary:     fmt.Sprintf("Messages sent by the clients of the %s action of the %s resource", action.Name, action.Parent.Name),
+				Tags:        tags,
+				Message:     messageFromDefinition(api, m.Inbound),
+			}
+		}
+		if m.Outbound != nil {
+			channel.Subscribe = &Operation{
+				OperationID: operationID + "#subscribe",
+				Summary:     fmt.Sprintf("Messages sent by the %s action of the %s resource", action.Name, action.Parent.Name),
+				Tags:        tags,
+				Message:     messageFromDefinition(api, m.Outbound),
+			}
+		}
+	}
+	s.Channels[key] = channel
+	return nil
+}
+
+// messageFromDefinition returns the description of the messages of the given type.
+func messageFromDefinition(api *design.APIDefinition, dt design.DataType) *Message {
+	msg := &Message{Payload: genschema.TypeSchema(api, dt)}
+	componentRefs(msg.Payload)
+	switch actual := dt.(type) {
+	case *design.MediaTypeDefinition:
+		msg.Name = actual.TypeName
+		msg.Title = actual.Identifier
+		msg.Summary = actual.Description
+	case *design.UserTypeDefinition:
+		msg.Name = actual.TypeName
+		msg.Summary = actual.Description
+	}
+	return msg
+}
+
+// attributeSchema returns the JSON schema of the given parameter or header.
+func attributeSchema(api *design.APIDefinition, att *design.AttributeDefinition) *genschema.JSONSchema {
+	schema := genschema.TypeSchema(api, att.Type)
+	componentRefs(schema)
+	schema.Description = att.Description
+	schema.DefaultValue = att.DefaultValue
+	if att.Validation != nil {
+		schema.Enum = att.Validation.Values
+		schema.Pattern = att.Validation.Pattern
+	}
+	return schema
+}
+
+// componentRefs rewrites the references to the JSON schema definitions made by the given schema
+// so that they point to the specification components.
+func componentRefs(s *genschema.JSONSchema) {
+	if s == nil {
+		return
+	}
+	s.Ref = strings.Replace(s.Ref, "#/definitions/", "#/components/schemas/", 1)
+	componentRefs(s.Items)
+	for _, p := range s.Properties {
+		componentRefs(p)
+	}
+	for _, d := range s.Definitions {
+		componentRefs(d)
+	}
+	for _, l := range [][]*genschema.JSONSchema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, c := range l {
+			componentRefs(c)
+		}
+	}
+}
+
+// isWildcard returns true if name is one of the given route wildcards.
+func isWildcard(name string, wildcards []string) bool {
+	for _, w := range wildcards {
+		if w == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedNames returns the names of the attributes of the given object in alphabetical order.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,126 @@
+package genasyncapi_test
+
+import (
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_asyncapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var spec *genasyncapi.AsyncAPI
+	var newErr error
+
+	BeforeEach(func() {
+		spec = nil
+		newErr = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		spec, newErr = genasyncapi.New(Design)
+	})
+
+	Context("with a WebSocket action declaring messages", func() {
+		BeforeEach(func() {
+			API("chat", func() {
+				Title("Chat API")
+				Version("1.0")
+				Host("chat.goa.design")
+				BasePath("/chat")
+			})
+			Post := Type("Post", func() {
+				Attribute("body", String)
+				Required("body")
+			})
+			Message := MediaType("application/vnd.chat.message+json", func() {
+				Description("A chat message")
+				Attributes(func() {
+					Attribute("author", String)
+					Attribute("body", String)
+				})
+				View("default", func() {
+					Attribute("author")
+					Attribute("body")
+				})
+			})
+			Resource("room", func() {
+				Action("join", func() {
+					Description("Join a chat room")
+					Routing(GET("/rooms/:id"))
+					Scheme("wss")
+					Params(func() {
+						Param("id", Integer, "Room ID")
+						Param("nick", String)
+						Required("nick")
+					})
+					Messages(func() {
+						Inbound(Post)
+						Outbound(Message)
+						KeepAlive(30 * time.Second)
+					})
+				})
+				Action("list", func() {
+					Routing(GET("/rooms"))
+					Response(OK)
+				})
+			})
+		})
+
+		It("describes the channel", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec.AsyncAPI).Should(Equal(genasyncapi.Version))
+			Ω(spec.Info.Title).Should(Equal("Chat API"))
+			Ω(spec.Servers).Should(HaveKeyWithValue("wss", &genasyncapi.Server{URL: "chat.goa.design", Protocol: "wss"}))
+			Ω(spec.Channels).Should(HaveLen(1))
+			Ω(spec.Channels).Should(HaveKey("/chat/rooms/{id}"))
+
+			channel := spec.Channels["/chat/rooms/{id}"]
+			Ω(channel.Description).Should(Equal("Join a chat room"))
+			Ω(channel.Parameters).Should(HaveLen(1))
+			Ω(channel.Parameters["id"].Description).Should(Equal("Room ID"))
+			Ω(channel.Parameters["id"].Schema.Type).Should(BeEquivalentTo(genschema.JSONInteger))
+			query := channel.Bindings.WS.Query
+			Ω(query.Properties).Should(HaveKey("nick"))
+			Ω(query.Required).Should(Equal([]string{"nick"}))
+
+			Ω(channel.Publish.OperationID).Should(Equal("room#join#publish"))
+			Ω(channel.Publish.Message.Name).Should(Equal("Post"))
+			Ω(channel.Subscribe.OperationID).Should(Equal("room#join#subscribe"))
+			Ω(channel.Subscribe.Message.Title).Should(Equal("application/vnd.chat.message+json"))
+			Ω(channel.Subscribe.Message.Summary).Should(Equal("A chat message"))
+			Ω(channel.Subscribe.Message.Payload.Ref).Should(Equal("#/components/schemas/ChatMessage"))
+			Ω(spec.Components.Schemas).Should(HaveKey("ChatMessage"))
+		})
+	})
+
+	Context("with a WebSocket action without messages", func() {
+		BeforeEach(func() {
+			API("chat", func() {})
+			Resource("room", func() {
+				Action("watch", func() {
+					Routing(GET("/rooms/watch"))
+					Scheme("ws")
+				})
+			})
+		})
+
+		It("describes the channel without operations", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec.Servers).Should(BeEmpty())
+			Ω(spec.Channels).Should(HaveKey("/rooms/watch"))
+			channel := spec.Channels["/rooms/watch"]
+			Ω(channel.Publish).Should(BeNil())
+			Ω(channel.Subscribe).Should(BeNil())
+			Ω(channel.Bindings.WS.Method).Should(Equal("GET"))
+		})
+	})
+})
@@ -0,0 +1,28 @@
+package genasyncapi
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Command is the goa AsyncAPI specification generator command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("asyncapi", "Generate AsyncAPI specification of the WebSocket actions, see https://www.asyncapi.com")
+	return &Command{BaseCommand: base}
+}
+
+// Run simply calls the meta generator.
+func (c *Command) Run() ([]string, error) {
+	gen := meta.NewGenerator(
+		"genasyncapi.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_asyncapi")},
+		nil,
+	)
+	return gen.Generate()
+}
@@ -0,0 +1,8 @@
+/*
+Package genasyncapi provides a generator for the AsyncAPI specification of the API WebSocket
+actions, see https://www.asyncapi.com. Each route of a WebSocket action is described by a channel
+whose publish operation describes the messages sent by the clients (Inbound) and whose subscribe
+operation describes the messages sent by the server (Outbound). The message payloads are described
+by JSON schemas listed in the components of the specification.
+*/
+package genasyncapi
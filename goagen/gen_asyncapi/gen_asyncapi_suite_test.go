@@ -0,0 +1,13 @@
+package genasyncapi_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenAsyncAPI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenAsyncAPI Suite")
+}
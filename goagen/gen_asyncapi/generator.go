@@ -0,0 +1,93 @@
+package genasyncapi
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+	"github.com/spf13/cobra"
+)
+
+// Generator is the AsyncAPI specification generator.
+type Generator struct{}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	api := design.Design
+	g := new(Generator)
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "AsyncAPI generator",
+		Long:  "AsyncAPI generator",
+		Run:   func(*cobra.Command, []string) { files, err = g.Generate(api) },
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
+
+// Generate writes the AsyncAPI specification of the API to the files "asyncapi.json" and
+// "asyncapi.yaml" of the "asyncapi" directory of the output directory.
+func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error) {
+	var genfiles []string
+
+	cleanup := func() {
+		for _, f := range genfiles {
+			os.Remove(f)
+		}
+	}
+
+	go utils.Catch(nil, cleanup)
+
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	asyncAPIDir := filepath.Join(codegen.OutputDir, "asyncapi")
+	os.RemoveAll(asyncAPIDir)
+	if err = os.MkdirAll(asyncAPIDir, 0755); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, asyncAPIDir)
+	s, err := New(api)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON
+	rawJSON, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	specFile := filepath.Join(asyncAPIDir, "asyncapi.json")
+	if err := ioutil.WriteFile(specFile, rawJSON, 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, specFile)
+
+	// YAML
+	var yamlSource interface{}
+	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
+		return nil, err
+	}
+	rawYAML, err := yaml.Marshal(yamlSource)
+	if err != nil {
+		return nil, err
+	}
+	specFile = filepath.Join(asyncAPIDir, "asyncapi.yaml")
+	if err := ioutil.WriteFile(specFile, rawYAML, 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, specFile)
+
+	return genfiles, nil
+}
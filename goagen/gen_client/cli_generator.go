@@ -86,11 +86,13 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 	commandTypesTmpl := template.Must(template.New("commandTypes").Funcs(funcs).Parse(commandTypesTmpl))
 	commandsTmpl := template.Must(template.New("commands").Funcs(funcs).Parse(commandsTmpl))
 	commandsTmplWS := template.Must(template.New("commandsWS").Funcs(funcs).Parse(commandsTmplWS))
+	commandsTmplStream := template.Must(template.New("commandsStream").Funcs(funcs).Parse(commandsTmplStream))
 	registerTmpl := template.Must(template.New("register").Funcs(funcs).Parse(registerTmpl))
 
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
 		codegen.SimpleImport("log"),
 		codegen.SimpleImport("os"),
 		codegen.SimpleImport("time"),
@@ -127,6 +129,8 @@ func (g *Generator) generateCommands(commandsFile string, clientPkg string, func
 			var err error
 			if action.WebSocket() {
 				err = commandsTmplWS.Execute(file, data)
+			} else if action.Stream != nil {
+				err = commandsTmplStream.Execute(file, data)
 			} else {
 				err = commandsTmpl.Execute(file, data)
 
@@ -233,7 +237,11 @@ const commandTypesTmpl = `{{ $cmdName := goify (printf "%s%s%s" .Name (title .Pa
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type }}
 {{ end }}{{ end }}{{ $headers := .Headers }}{{ if $headers }}{{ range $name, $att := $headers.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
 {{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type }}
-{{ end }}{{ end }}	}
+{{ end }}{{ end }}{{ $cookies := .Cookies }}{{ if $cookies }}{{ range $name, $att := $cookies.Type.ToObject }}{{ if $att.Description }}		{{ multiComment $att.Description }}
+{{ end }}		{{ goify $name true }} {{ cmdFieldType $att.Type }}
+{{ end }}{{ end }}{{ if .Stream }}		// LastEventID is the ID of the last event received used to resume the stream
+		LastEventID string
+{{ end }}	}
 `
 
 const commandsTmplWS = `
@@ -255,13 +263,50 @@ func (cmd *{{ $cmdName }}) Run(c *client.Client, args []string) error {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
 	}
-	go goaclient.WSWrite(ws)
+{{ if .Action.Messages }}	go goaclient.WSWrite(ws.Conn())
+	goaclient.WSRead(ws.Conn())
+{{ else }}	go goaclient.WSWrite(ws)
 	goaclient.WSRead(ws)
-
+{{ end }}
 	return nil
 }
 `
 
+const commandsTmplStream = `
+{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title .Resource.Name)) true }}// Run opens the event stream of the {{ $cmdName }} command and prints the events data.
+func (cmd *{{ $cmdName }}) Run(c *client.Client, args []string) error {
+	var path string
+	if len(args) > 0 {
+		path = args[0]
+	} else {
+{{ $default := defaultPath .Action }}{{ if $default }}	path = "{{ $default }}"
+{{ else }}{{ $pparams := defaultRouteParams .Action }}	path = fmt.Sprintf("{{ defaultRouteTemplate .Action }}", {{ joinNames $pparams }})
+{{ end }}	}
+	logger := goa.NewLogger(log.New(os.Stderr, "", log.LstdFlags))
+	ctx := goa.WithLogger(context.Background(), logger)
+	stream, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{/*
+	*/}}{{ $params := joinNames .Action.QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
+	*/}}{{ $headers := joinNames .Action.Headers }}{{ if $headers }}, {{ $headers }}{{ end }}{{/*
+	*/}}{{ $cookies := joinNames .Action.Cookies }}{{ if $cookies }}, {{ $cookies }}{{ end }}, cmd.LastEventID)
+	if err != nil {
+		goa.LogError(ctx, "failed", "err", err)
+		return err
+	}
+	defer stream.Close()
+	for {
+		e, err := stream.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			goa.LogError(ctx, "failed", "err", err)
+			return err
+		}
+		fmt.Println(string(e.Data))
+	}
+}
+`
+
 const registerTmpl = `{{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title .Resource.Name)) true }}// RegisterFlags registers the command flags with the command line.
 func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *client.Client) {
 {{ if .Action.Payload }}	cc.Flags().StringVar(&cmd.Payload, "payload", "", "Request JSON body")
@@ -269,14 +314,21 @@ func (cmd *{{ $cmdName }}) RegisterFlags(cc *cobra.Command, c *client.Client) {
 */}}{{ if not $pparam.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $pparam.Type }}
 {{ end }}	cc.Flags().{{ flagType $pparam }}Var(&cmd.{{ goify $pname true }}, "{{ $pname }}", {{/*
 */}}{{ if $pparam.DefaultValue }}{{ printf "%#v" $pparam.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $pparam.Description }}` + "`" + `)
-{{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
+{{ with $pparam.Deprecation }}	cc.Flags().MarkDeprecated("{{ $pname }}", {{ printf "%q" .Message }})
+{{ end }}{{ end }}{{ end }}{{ $params := .Action.QueryParams }}{{ if $params }}{{ range $name, $param := $params.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
 */}}{{ if not $param.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $param.Type }}
 {{ end }}	cc.Flags().{{ flagType $param }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $param.DefaultValue }}{{ printf "%#v" $param.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $param.Description }}` + "`" + `)
-{{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
+{{ with $param.Deprecation }}	cc.Flags().MarkDeprecated("{{ $name }}", {{ printf "%q" .Message }})
+{{ end }}{{ end }}{{ end }}{{ $headers := .Action.Headers }}{{ if $headers }}{{ range $name, $header := $headers.Type.ToObject }}{{/*
 */}} cc.Flags().StringVar(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
 */}}{{ if $header.DefaultValue }}{{ printf "%q" $header.DefaultValue }}{{ else }}""{{ end }}, ` + "`" + `{{ escapeBackticks $header.Description }}` + "`" + `)
-{{ end }}{{ end }}{{ if .Action.Security }}   c.Signer{{ goify .Action.Security.Scheme.SchemeName true }}.RegisterFlags(cc){{ end }}}`
+{{ end }}{{ end }}{{ $cookies := .Action.Cookies }}{{ if $cookies }}{{ range $name, $cookie := $cookies.Type.ToObject }}{{ $tmp := goify $name false }}{{/*
+*/}}{{ if not $cookie.DefaultValue }}	var {{ $tmp }} {{ cmdFieldType $cookie.Type }}
+{{ end }}	cc.Flags().{{ flagType $cookie }}Var(&cmd.{{ goify $name true }}, "{{ $name }}", {{/*
+*/}}{{ if $cookie.DefaultValue }}{{ printf "%#v" $cookie.DefaultValue }}{{ else }}{{ $tmp }}{{ end }}, ` + "`" + `{{ escapeBackticks $cookie.Description }}` + "`" + `)
+{{ end }}{{ end }}{{ if .Action.Stream }}	cc.Flags().StringVar(&cmd.LastEventID, "last-event-id", "", ` + "`" + `ID of the last event received used to resume the stream` + "`" + `)
+{{ end }}{{ if .Action.Security }}   c.Signer{{ goify .Action.Security.Scheme.SchemeName true }}.RegisterFlags(cc){{ end }}}`
 
 const commandsTmpl = `
 {{ $cmdName := goify (printf "%s%sCommand" .Action.Name (title .Resource.Name)) true }}// Run makes the HTTP request corresponding to the {{ $cmdName }} command.
@@ -300,7 +352,8 @@ func (cmd *{{ $cmdName }}) Run(c *client.Client, args []string) error {
 	ctx := goa.WithLogger(context.Background(), logger)
 	resp, err := c.{{ goify (printf "%s%s" .Action.Name (title .Resource.Name)) true }}(ctx, path{{ if .Action.Payload }}, {{ if or .Action.Payload.Type.IsObject .Action.Payload.IsPrimitive }}&{{ end }}payload{{ else }}{{ end }}{{/*
 	*/}}{{ $params := joinNames .Action.QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
-	*/}}{{ $headers := joinNames .Action.Headers }}{{ if $headers }}, {{ $headers }}{{ end }})
+	*/}}{{ $headers := joinNames .Action.Headers }}{{ if $headers }}, {{ $headers }}{{ end }}{{/*
+	*/}}{{ $cookies := joinNames .Action.Cookies }}{{ if $cookies }}, {{ $cookies }}{{ end }})
 	if err != nil {
 		goa.LogError(ctx, "failed", "err", err)
 		return err
@@ -325,7 +378,8 @@ func RegisterCommands(app *cobra.Command, c *client.Client) {
 		Use:   ` + "`" + `{{ $action.Parent.Name }} {{ routes $action }}` + "`" + `,
 		Short: ` + "`" + `{{ escapeBackticks $action.Parent.Description }}` + "`" + `,
 		RunE:  func(cmd *cobra.Command, args []string) error { return {{ $tmp }}.Run(c, args) },
-	}
+{{ with $action.Deprecation }}		Deprecated: {{ printf "%q" .Message }},
+{{ end }}	}
 	{{ $tmp }}.RegisterFlags(sub, c)
 	command.AddCommand(sub)
 {{ end }}app.AddCommand(command)
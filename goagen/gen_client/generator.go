@@ -10,14 +10,19 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/spf13/cobra"
 )
 
 // Generator is the application code generator.
 type Generator struct {
+	outDir         string                       // Path to the directory of the generated client package.
+	pkg            string                       // Name of the generated client package.
+	version        *design.APIVersionDefinition // API version of the generated client package if any.
 	genfiles       []string
-	generatedTypes map[string]bool // Keeps track of names of user types that correspond to action payloads.
+	generatedTypes map[string]bool                      // Keeps track of names of user types that correspond to action payloads.
+	files          map[*design.AttributeDefinition]bool // Keeps track of multipart form attributes that hold file paths.
 }
 
 // Generate is the generator entry point called by the meta generator.
@@ -50,12 +55,24 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 		codegen.SimpleImport("net/http"),
 		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 	}
-	if err := file.WriteHeader("", "client", imports); err != nil {
+	if err := file.WriteHeader("", g.pkg, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, clientFile)
 
-	if err := clientTmpl.Execute(file, api); err != nil {
+	data := map[string]interface{}{"API": api}
+	if v := g.version; v != nil && !v.SelectedByPath() {
+		// Versions that share the API base path are selected with the version header or
+		// with the version parameter of the Accept header.
+		if header := design.Design.VersionHeader; header != "" {
+			data["VersionHeader"] = header
+			data["VersionValue"] = v.Name
+		} else if param := design.Design.VersionParam; param != "" {
+			data["VersionHeader"] = "Accept"
+			data["VersionValue"] = fmt.Sprintf("%s; %s=%s", acceptType(api), param, v.Name)
+		}
+	}
+	if err := clientTmpl.Execute(file, data); err != nil {
 		return err
 	}
 
@@ -65,9 +82,11 @@ func (g *Generator) generateClient(clientFile string, clientPkg string, funcs te
 func (g *Generator) generateClientResources(clientPkg string, funcs template.FuncMap, api *design.APIDefinition) error {
 	userTypeTmpl := template.Must(template.New("userType").Funcs(funcs).Parse(userTypeTmpl))
 	typeDecodeTmpl := template.Must(template.New("typeDecode").Funcs(funcs).Parse(typeDecodeTmpl))
+	errorTmpl := template.Must(template.New("error").Funcs(funcs).Parse(errorTmpl))
+	errorDecodeTmpl := template.Must(template.New("errorDecode").Funcs(funcs).Parse(errorDecodeTmpl))
 
 	err := api.IterateResources(func(res *design.ResourceDefinition) error {
-		return g.generateResourceClient(res, funcs)
+		return g.generateResourceClient(api, res, funcs)
 	})
 	if err != nil {
 		return err
@@ -77,20 +96,45 @@ func (g *Generator) generateClientResources(clientPkg string, funcs template.Fun
 		for n, ut := range res.UserTypes() {
 			types[n] = ut
 		}
+		for _, a := range res.Actions {
+			if a.Stream != nil {
+				if mt := a.Stream.EventMediaType(); mt != nil {
+					types[mt.TypeName] = mt.UserTypeDefinition
+				}
+			}
+			if a.Messages != nil {
+				for _, dt := range []design.DataType{a.Messages.Inbound, a.Messages.Outbound} {
+					if dt == nil {
+						continue
+					}
+					for n, ut := range design.UserTypes(dt) {
+						types[n] = ut
+					}
+				}
+			}
+		}
+	}
+	errors := api.AllErrors()
+	for _, e := range errors {
+		for n, ut := range design.UserTypes(e.Type.Type) {
+			types[n] = ut
+		}
 	}
-	filename := filepath.Join(codegen.OutputDir, "datatypes.go")
+	filename := filepath.Join(g.outDir, "datatypes.go")
 	file, err := codegen.SourceFileFor(filename)
 	if err != nil {
 		return err
 	}
 	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
 		codegen.SimpleImport("github.com/goadesign/goa"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
+		codegen.SimpleImport("io/ioutil"),
 		codegen.SimpleImport("time"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
-	if err := file.WriteHeader("User Types", "client", imports); err != nil {
+	if err := file.WriteHeader("User Types", g.pkg, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, filename)
@@ -158,40 +202,89 @@ func (g *Generator) generateClientResources(clientPkg string, funcs template.Fun
 		return err
 	}
 
+	// Generate typed errors and the error response decoder
+	if len(errors) > 0 {
+		for _, e := range errors {
+			if err := errorTmpl.Execute(file, e); err != nil {
+				return err
+			}
+		}
+		if err := errorDecodeTmpl.Execute(file, errors); err != nil {
+			return err
+		}
+	}
+
 	return file.FormatCode()
 }
 
-func (g *Generator) generateResourceClient(res *design.ResourceDefinition, funcs template.FuncMap) error {
+func (g *Generator) generateResourceClient(api *design.APIDefinition, res *design.ResourceDefinition, funcs template.FuncMap) error {
 	payloadTmpl := template.Must(template.New("payload").Funcs(funcs).Parse(payloadTmpl))
-	clientsTmpl := template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl))
-	clientsWSTmpl := template.Must(template.New("clients").Funcs(funcs).Parse(clientsWSTmpl))
+	funcs["isFile"] = g.isFile
+	var ws *wsEncoding
+	if hasMessages(res) {
+		var err error
+		if ws, err = buildWSEncoding(api); err != nil {
+			return err
+		}
+	}
+	funcs["wsEncoding"] = func() *wsEncoding { return ws }
+	clientsTmpl := template.Must(template.New("clients").Funcs(funcs).Parse(clientsTmpl + formFileTmpl + formValueTmpl + deprecationTmpl))
+	clientsWSTmpl := template.Must(template.New("clients").Funcs(funcs).Parse(clientsWSTmpl + deprecationTmpl))
+	clientsStreamTmpl := template.Must(template.New("clients").Funcs(funcs).Parse(clientsStreamTmpl + deprecationTmpl))
 	pathTmpl := template.Must(template.New("pathTemplate").Funcs(funcs).Parse(pathTmpl))
 
-	filename := filepath.Join(codegen.OutputDir, codegen.SnakeCase(res.Name)+"_client.go")
+	filename := filepath.Join(g.outDir, codegen.SnakeCase(res.Name)+"_client.go")
 	file, err := codegen.SourceFileFor(filename)
 	if err != nil {
 		return err
 	}
 	imports := []*codegen.ImportSpec{
 		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/base64"),
 		codegen.SimpleImport("encoding/json"),
 		codegen.SimpleImport("fmt"),
 		codegen.SimpleImport("io"),
+		codegen.SimpleImport("mime/multipart"),
 		codegen.SimpleImport("net/http"),
 		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("os"),
+		codegen.SimpleImport("path/filepath"),
 		codegen.SimpleImport("strconv"),
 		codegen.SimpleImport("strings"),
 		codegen.SimpleImport("time"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.NewImport("goaclient", "github.com/goadesign/goa/client"),
 		codegen.SimpleImport("golang.org/x/net/context"),
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 		codegen.NewImport("uuid", "github.com/satori/go.uuid"),
 	}
-	if err := file.WriteHeader("", "client", imports); err != nil {
+	if ws != nil {
+		for _, path := range []string{ws.Encoder.PackagePath, ws.Decoder.PackagePath} {
+			found := false
+			for _, imp := range imports {
+				if imp.Path == path {
+					found = true
+					break
+				}
+			}
+			if !found {
+				imports = append(imports, codegen.SimpleImport(path))
+			}
+		}
+	}
+	if err := file.WriteHeader("", g.pkg, imports); err != nil {
 		return err
 	}
 	g.genfiles = append(g.genfiles, filename)
 	g.generatedTypes = make(map[string]bool)
 	err = res.IterateActions(func(action *design.ActionDefinition) error {
+		if action.PayloadMultipart {
+			// Render a copy of the action so that the design used by the other generators
+			// keeps the original payload.
+			dup := *action
+			dup.Payload = g.multipartPayload(action.Payload)
+			action = &dup
+		}
 		if action.Payload != nil {
 			if err := payloadTmpl.Execute(file, action); err != nil {
 				return err
@@ -222,6 +315,9 @@ func (g *Generator) generateResourceClient(res *design.ResourceDefinition, funcs
 				return err
 			}
 		}
+		if action.Stream != nil {
+			return clientsStreamTmpl.Execute(file, action)
+		}
 		return clientsTmpl.Execute(file, action)
 	})
 	if err != nil {
@@ -247,10 +343,13 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 	if err != nil {
 		return
 	}
+	g.outDir = codegen.OutputDir
+	g.pkg = "client"
 
 	funcs := template.FuncMap{
 		"cmdFieldType":    cmdFieldType,
 		"defaultPath":     defaultPath,
+		"durationLit":     codegen.DurationLiteral,
 		"escapeBackticks": escapeBackticks,
 		"flagType":        flagType,
 		"goify":           codegen.Goify,
@@ -267,8 +366,11 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		"tempvar":         codegen.Tempvar,
 		"title":           strings.Title,
 		"toString":        toString,
+		"formValueString": formValueString,
 		"typeName":        typeName,
 		"signerType":      signerType,
+		"arrayAttribute":  arrayAttribute,
+		"formData":        formData,
 	}
 	clientPkg, err := codegen.PackagePath(codegen.OutputDir)
 	if err != nil {
@@ -287,7 +389,7 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 	}
 
 	// Generate client/client.go
-	if err = g.generateClient(filepath.Join(codegen.OutputDir, "client.go"), clientPkg, funcs, api); err != nil {
+	if err = g.generateClient(filepath.Join(g.outDir, "client.go"), clientPkg, funcs, api); err != nil {
 		return
 	}
 
@@ -296,9 +398,37 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		return
 	}
 
+	// Generate client/$version/client.go, client/$version/$res.go and client/$version/types.go
+	for _, v := range api.Versions {
+		if err = g.generateVersion(v, funcs); err != nil {
+			return
+		}
+	}
+
 	return g.genfiles, nil
 }
 
+// generateVersion generates the client package of the given API version in a sub-package of the
+// client package. The command line tool uses the client package of the API.
+func (g *Generator) generateVersion(v *design.APIVersionDefinition, funcs template.FuncMap) error {
+	pkg := genapp.VersionPackage(v)
+	vg := &Generator{outDir: filepath.Join(g.outDir, pkg), pkg: pkg, version: v}
+	defer func() { g.genfiles = append(g.genfiles, vg.genfiles...) }()
+	if err := os.MkdirAll(vg.outDir, 0755); err != nil {
+		return err
+	}
+	vg.genfiles = append(vg.genfiles, vg.outDir)
+	clientPkg, err := codegen.PackagePath(vg.outDir)
+	if err != nil {
+		return err
+	}
+	api := v.API()
+	if err := vg.generateClient(filepath.Join(vg.outDir, "client.go"), clientPkg, funcs, api); err != nil {
+		return err
+	}
+	return vg.generateClientResources(clientPkg, funcs, api)
+}
+
 // Cleanup removes all the files generated by this generator during the last invokation of Generate.
 func (g *Generator) Cleanup() {
 	for _, f := range g.genfiles {
@@ -307,6 +437,52 @@ func (g *Generator) Cleanup() {
 	g.genfiles = nil
 }
 
+// multipartPayload returns a copy of the given multipart form payload where the attributes of
+// type File are replaced with string attributes that hold the path to the file to upload.
+func (g *Generator) multipartPayload(p *design.UserTypeDefinition) *design.UserTypeDefinition {
+	if g.files == nil {
+		g.files = make(map[*design.AttributeDefinition]bool)
+	}
+	dup := design.DupAtt(p.AttributeDefinition)
+	toPath := func(att *design.AttributeDefinition) *design.AttributeDefinition {
+		if att.Type.Kind() != design.FileKind {
+			return att
+		}
+		path := &design.AttributeDefinition{Type: design.String, Description: att.Description}
+		g.files[path] = true
+		return path
+	}
+	for n, att := range dup.Type.ToObject() {
+		if arr := att.Type.ToArray(); arr != nil {
+			att.Type = &design.Array{ElemType: toPath(arr.ElemType)}
+		} else {
+			dup.Type.ToObject()[n] = toPath(att)
+		}
+	}
+	return &design.UserTypeDefinition{AttributeDefinition: dup, TypeName: p.TypeName}
+}
+
+// isFile returns true if the given attribute holds the path to a file uploaded via a multipart
+// form.
+func (g *Generator) isFile(att *design.AttributeDefinition) bool {
+	return g.files[att]
+}
+
+// formData is a code generation helper function that creates the data given to the templates that
+// write multipart form fields.
+func formData(name, value string, att ...*design.AttributeDefinition) map[string]interface{} {
+	data := map[string]interface{}{"Name": name, "Value": value}
+	if len(att) > 0 {
+		data["Attribute"] = att[0]
+	}
+	return data
+}
+
+// arrayAttribute returns the array element attribute definition.
+func arrayAttribute(a *design.AttributeDefinition) *design.AttributeDefinition {
+	return a.Type.(*design.Array).ElemType
+}
+
 // join is a code generation helper function that generates a function signature built from
 // concatenating the properties (name type) of the given attribute type (assuming it's an object).
 // join accepts an optional slice of strings which indicates the order in which the parameters
@@ -366,8 +542,12 @@ func goTypeRefExt(t design.DataType, tabs int, pkg string) string {
 
 // cmdFieldType computes the Go type name used to store command flags of the given design type.
 func cmdFieldType(t design.DataType) string {
-	if t.Kind() == design.DateTimeKind || t.Kind() == design.UUIDKind {
+	switch t.Kind() {
+	case design.DateTimeKind, design.UUIDKind, design.DateKind, design.DurationKind,
+		design.BytesKind, design.DecimalKind:
 		return "string"
+	case design.ArrayKind:
+		return "[]" + cmdFieldType(t.ToArray().ElemType.Type)
 	}
 	return codegen.GoNativeType(t)
 }
@@ -387,7 +567,8 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 			return fmt.Sprintf("%s := strconv.FormatBool(%s)", target, name)
 		case design.NumberKind:
 			return fmt.Sprintf("%s := strconv.FormatFloat(%s, 'f', -1, 64)", target, name)
-		case design.StringKind, design.DateTimeKind, design.UUIDKind:
+		case design.StringKind, design.DateTimeKind, design.UUIDKind, design.DateKind,
+			design.DurationKind, design.BytesKind, design.DecimalKind:
 			return fmt.Sprintf("%s := %s", target, name)
 		case design.AnyKind:
 			return fmt.Sprintf("%s := fmt.Sprintf(\"%%v\", %s)", target, name)
@@ -406,6 +587,23 @@ func toString(name, target string, att *design.AttributeDefinition) string {
 	}
 }
 
+// formValueString generates Go code that converts the given multipart form field value into a
+// string using the format the generated server decodes.
+func formValueString(name, target string, att *design.AttributeDefinition) string {
+	if strings.HasPrefix(name, "*") {
+		name = "(" + name + ")"
+	}
+	switch att.Type.Kind() {
+	case design.DateTimeKind:
+		return fmt.Sprintf("%s := %s.Format(time.RFC3339)", target, name)
+	case design.UUIDKind, design.DateKind, design.DurationKind, design.DecimalKind:
+		return fmt.Sprintf("%s := %s.String()", target, name)
+	case design.BytesKind:
+		return fmt.Sprintf("%s := base64.StdEncoding.EncodeToString(%s)", target, name)
+	}
+	return toString(name, target, att)
+}
+
 // flagType returns the flag type for the given (basic type) attribute definition.
 func flagType(att *design.AttributeDefinition) string {
 	switch att.Type.Kind() {
@@ -421,6 +619,8 @@ func flagType(att *design.AttributeDefinition) string {
 		return "String"
 	case design.UUIDKind:
 		return "String"
+	case design.DateKind, design.DurationKind, design.BytesKind, design.DecimalKind:
+		return "String"
 	case design.AnyKind:
 		return "String"
 	case design.ArrayKind:
@@ -486,6 +686,69 @@ func pathParamNames(r *design.RouteDefinition) string {
 	return strings.Join(goified, ", ")
 }
 
+// acceptType returns the first media type produced by the API, "application/json" if the API does
+// not declare any.
+func acceptType(api *design.APIDefinition) string {
+	for _, p := range api.Produces {
+		if len(p.MIMETypes) > 0 {
+			return p.MIMETypes[0]
+		}
+	}
+	return "application/json"
+}
+
+// wsEncoding describes the encodings used by the WebSocket connections exchanging messages.
+type wsEncoding struct {
+	ContentType string                      // Content-Type header of the handshake request.
+	Accept      string                      // Accept header of the handshake request.
+	Encoder     *genapp.EncoderTemplateData // Encoder of the messages sent to the server.
+	Decoder     *genapp.EncoderTemplateData // Decoder of the messages sent by the server.
+}
+
+// buildWSEncoding builds the WebSocket encodings of the given API: messages are encoded using the
+// first media type consumed by the API and decoded using the first media type it produces, JSON if
+// the API does not declare any.
+func buildWSEncoding(api *design.APIDefinition) (*wsEncoding, error) {
+	encoder := &design.EncodingDefinition{MIMETypes: []string{"application/json"}, Encoder: true}
+	for _, c := range api.Consumes {
+		if len(c.MIMETypes) > 0 {
+			encoder = &design.EncodingDefinition{MIMETypes: c.MIMETypes[:1], PackagePath: c.PackagePath, Encoder: true}
+			break
+		}
+	}
+	decoder := &design.EncodingDefinition{MIMETypes: []string{acceptType(api)}}
+	for _, p := range api.Produces {
+		if len(p.MIMETypes) > 0 {
+			decoder.PackagePath = p.PackagePath
+			break
+		}
+	}
+	encoders, err := genapp.BuildEncoders([]*design.EncodingDefinition{encoder}, true)
+	if err != nil {
+		return nil, err
+	}
+	decoders, err := genapp.BuildEncoders([]*design.EncodingDefinition{decoder}, false)
+	if err != nil {
+		return nil, err
+	}
+	return &wsEncoding{
+		ContentType: encoder.MIMETypes[0],
+		Accept:      decoder.MIMETypes[0],
+		Encoder:     encoders[0],
+		Decoder:     decoders[0],
+	}, nil
+}
+
+// hasMessages returns true if the given resource has WebSocket actions exchanging messages.
+func hasMessages(res *design.ResourceDefinition) bool {
+	for _, a := range res.Actions {
+		if a.WebSocket() && a.Messages != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func typeName(mt *design.MediaTypeDefinition) string {
 	name := codegen.GoTypeName(mt, mt.AllRequired(), 1, false)
 	if mt.IsBuiltIn() {
@@ -517,6 +780,49 @@ func {{ $funcName }}(r io.Reader, decoderFn goa.DecoderFunc) ({{ gotyperef . .Al
 }
 `
 
+const errorTmpl = `{{ $typeName := .Type.TypeName }}// {{ $typeName }} is the {{ printf "%q" .Name }} error, responses that carry it use the status code {{ .Status }}.
+type {{ $typeName }} {{ gotypedef .Type 0 true false }}
+
+// Error returns the error occurrence details.
+func (e *{{ $typeName }}) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, e.Code, e.Detail)
+}
+
+// ResponseStatus returns the HTTP status code used by responses that carry the error.
+func (e *{{ $typeName }}) ResponseStatus() int {
+	return e.Status
+}
+
+// ErrorCode returns the code that identifies the class of errors.
+func (e *{{ $typeName }}) ErrorCode() string {
+	return e.Code
+}
+
+`
+
+const errorDecodeTmpl = `// DecodeErrorResponse decodes the error encoded in r into the typed error identified by its code.
+// Errors whose codes are not defined in the design are decoded into goa.Error values.
+func DecodeErrorResponse(r io.Reader, decoderFn goa.DecoderFunc) error {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	var base goa.Error
+	if err := decoderFn(bytes.NewReader(body)).Decode(&base); err != nil {
+		return err
+	}
+	switch base.Code {
+{{ range . }}	case {{ printf "%q" .Name }}:
+		var decoded {{ .Type.TypeName }}
+		if err := decoderFn(bytes.NewReader(body)).Decode(&decoded); err != nil {
+			return err
+		}
+		return &decoded
+{{ end }}	}
+	return &base
+}
+`
+
 const pathTmpl = `{{ $funcName := printf "%sPath" (goify (printf "%s%s" .Parent.Name (title .Parent.Parent.Name)) true) }}{{/*
 */}}// {{ $funcName }} computes a request path to the {{ .Parent.Name }} action of {{ .Parent.Parent.Name }}.
 func {{ $funcName }}({{ pathParams . }}) string {
@@ -524,12 +830,26 @@ func {{ $funcName }}({{ pathParams . }}) string {
 }
 `
 
-const clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .Parent.Name)) true }}{{ $desc := .Description }}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .Parent.Name }} resource{{ end }}
+const clientsTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .Parent.Name)) true }}{{ $desc := .Description }}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} makes a request to the {{ .Name }} action endpoint of the {{ .Parent.Name }} resource{{ end }}{{ template "deprecationDoc" . }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Payload }}, payload {{ gotyperef .Payload .Payload.AllRequired 1 false }}{{ end }}{{/*
 	*/}}{{ $params := join .QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
-	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}) (*http.Response, error) {
-	var body io.Reader
-{{ if .Payload }}	b, err := json.Marshal(payload)
+	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}{{/*
+	*/}}{{ $cookies := join .Cookies }}{{ if $cookies }}, {{ $cookies }}{{ end }}) (*http.Response, error) {
+{{ template "deprecationLog" . }}	var body io.Reader
+{{ if .PayloadMultipart }}	var b bytes.Buffer
+	w := multipart.NewWriter(&b)
+{{ range $name, $att := .Payload.ToObject }}{{ $field := printf "payload.%s" (goify $name true) }}{{/*
+*/}}{{ $pointer := $.Payload.IsPrimitivePointer $name }}{{ if or $pointer $att.Type.IsArray }}	if {{ $field }} != nil {
+{{ end }}{{ $val := or (and $pointer (printf "*%s" $field)) $field }}{{/*
+*/}}{{ if $att.Type.IsArray }}	for _, {{ $elem := tempvar }}{{ $elem }} := range {{ $field }} {
+{{ if isFile (arrayAttribute $att) }}{{ template "formFile" (formData $name $elem) }}{{ else }}{{ template "formValue" (formData $name $elem (arrayAttribute $att)) }}{{ end }}	}
+{{ else if isFile $att }}{{ template "formFile" (formData $name $val) }}{{ else }}{{ template "formValue" (formData $name $val $att) }}{{ end }}{{/*
+*/}}{{ if or $pointer $att.Type.IsArray }}	}
+{{ end }}{{ end }}	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	body = &b
+{{ else if .Payload }}	b, err := json.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to serialize body: %s", err)
 	}
@@ -554,18 +874,85 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{ if .Payload
 {{ if $headers }}{{ range $name, $att := $params.Type.ToObject }}{{ if (eq $att.Type.Kind 4) }}	header.Set("{{ $name }}", {{ goify $name false }})
 {{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
 	header.Set("{{ $name }}", {{ $tmp }})
-{{ end }}{{ end }}{{ end }}	header.Set("Content-Type", "application/json"){{ if .Security }}
+{{ end }}{{ end }}{{ end }}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{ if eq (cmdFieldType $att.Type) "string" }}	if {{ goify $name false }} != "" {
+		req.AddCookie(&http.Cookie{Name: "{{ $name }}", Value: {{ goify $name false }}})
+	}
+{{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
+	req.AddCookie(&http.Cookie{Name: "{{ $name }}", Value: {{ $tmp }}})
+{{ end }}{{ end }}{{ end }}{{ if .PayloadMultipart }}	header.Set("Content-Type", w.FormDataContentType()){{ else }}	header.Set("Content-Type", "application/json"){{ end }}{{ if .Security }}
 	c.Signer{{ goify .Security.Scheme.SchemeName true }}.Sign(ctx, req){{ end }}
 	return c.Client.Do(ctx, req)
 }
-`
+{{ if .Pagination }}
+// {{ $funcName }}Pages returns an iterator over the pages of the {{ .Name }} action responses of the
+// {{ .Parent.Name }} resource. The first page is retrieved using the given arguments and the following
+// pages by following the links returned in the responses.
+func (c *Client) {{ $funcName }}Pages(ctx context.Context, path string{{ if .Payload }}, payload {{ gotyperef .Payload .Payload.AllRequired 1 false }}{{ end }}{{/*
+	*/}}{{ $params := join .QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
+	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}{{/*
+	*/}}{{ $cookies := join .Cookies }}{{ if $cookies }}, {{ $cookies }}{{ end }}) *goaclient.PageIterator {
+	return goaclient.NewPageIterator(ctx, c.Client, func() (*http.Response, error) {
+		return c.{{ $funcName }}(ctx, path{{ if .Payload }}, payload{{ end }}{{/*
+		*/}}{{ if .QueryParams }}{{ range $name, $att := .QueryParams.Type.ToObject }}, {{ goify $name false }}{{ end }}{{ end }}{{/*
+		*/}}{{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}, {{ goify $name false }}{{ end }}{{ end }}{{/*
+		*/}}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}, {{ goify $name false }}{{ end }}{{ end }})
+	})
+}
+{{ end }}`
+
+const formFileTmpl = `{{ define "formFile" }}	{
+		fw, err := w.CreateFormFile("{{ .Name }}", filepath.Base({{ .Value }}))
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open({{ .Value }})
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if _, err := io.Copy(fw, f); err != nil {
+			return nil, err
+		}
+	}
+{{ end }}`
+
+const formValueTmpl = `{{ define "formValue" }}{{ if eq .Attribute.Type.Kind 4 }}	if err := w.WriteField("{{ .Name }}", {{ .Value }}); err != nil {
+		return nil, err
+	}
+{{ else }}	{
+		{{ formValueString .Value "s" .Attribute }}
+		if err := w.WriteField("{{ .Name }}", s); err != nil {
+			return nil, err
+		}
+	}
+{{ end }}{{ end }}`
 
 const clientsWSTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .Parent.Name)) true }}{{/*
-*/}}{{ $desc := .Description }}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .Parent.Name }} resource{{ end }}
+*/}}{{ if .Messages }}{{ $connName := printf "%sConn" $funcName }}// {{ $connName }} is the WebSocket connection of the {{ .Name }} action of the {{ .Parent.Name }} resource.
+type {{ $connName }} struct {
+	*goaclient.WebSocketConn
+}
+{{ with .Messages.Outbound }}
+// Recv reads, decodes and validates the next message sent by the server. It returns io.EOF when
+// the server closes the connection.
+func (c *{{ $connName }}) Recv() ({{ gotyperef . .AllRequired 0 false }}, error) {
+	msg := new({{ gotypename . .AllRequired 0 false }})
+	if err := c.WebSocketConn.Recv(msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+{{ end }}{{ with .Messages.Inbound }}
+// Send encodes and sends the message to the server.
+func (c *{{ $connName }}) Send(msg {{ gotyperef . .AllRequired 0 false }}) error {
+	return c.WebSocketConn.Send(msg)
+}
+{{ end }}
+{{ end }}{{ $desc := .Description }}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} establishes a websocket connection to the {{ .Name }} action endpoint of the {{ .Parent.Name }} resource{{ end }}{{ template "deprecationDoc" . }}
 func (c *Client) {{ $funcName }}(ctx context.Context, path string{{/*
 	*/}}{{ $params := join .QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
-	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}) (*websocket.Conn, error) {
-	scheme := c.Scheme
+	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}) ({{ if .Messages }}*{{ $funcName }}Conn{{ else }}*websocket.Conn{{ end }}, error) {
+{{ template "deprecationLog" . }}	scheme := c.Scheme
 	if scheme == "" {
 		scheme = "{{ .CanonicalScheme }}"
 	}
@@ -575,21 +962,119 @@ func (c *Client) {{ $funcName }}(ctx context.Context, path string{{/*
 {{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
 	values.Set("{{ $name }}", {{ $tmp }})
 {{ end }}{{ end }}	u.RawQuery = values.Encode()
-{{ end }}{{ end }}	return websocket.Dial(u.String(), "", u.String())
+{{ end }}{{ end }}{{ if .Messages }}{{ $enc := wsEncoding }}	cfg, err := websocket.NewConfig(u.String(), u.String())
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range c.Header {
+		for _, v := range vals {
+			cfg.Header.Add(k, v)
+		}
+	}
+	cfg.Header.Set("Content-Type", "{{ $enc.ContentType }}")
+	cfg.Header.Set("Accept", "{{ $enc.Accept }}")
+	conn, err := goaclient.DialWebSocket(cfg, {{ if .Messages.KeepAlive }}{{ durationLit .Messages.KeepAlive }}{{ else }}0{{ end }}, {{ $enc.Encoder.PackageName }}.{{ $enc.Encoder.Function }}, {{ $enc.Decoder.PackageName }}.{{ $enc.Decoder.Function }})
+	if err != nil {
+		return nil, err
+	}
+	return &{{ $funcName }}Conn{WebSocketConn: conn}, nil
+{{ else }}	return websocket.Dial(u.String(), "", u.String())
+{{ end }}}
+`
+
+const clientsStreamTmpl = `{{ $funcName := goify (printf "%s%s" .Name (title .Parent.Name)) true }}{{/*
+*/}}{{ $streamName := printf "%sStream" $funcName }}{{ $mt := .Stream.EventMediaType }}{{/*
+*/}}// {{ $streamName }} reads the events streamed by the {{ .Name }} action of the {{ .Parent.Name }} resource.
+type {{ $streamName }} struct {
+	*goaclient.EventReader
+}
+
+// Recv returns the next event and its ID. It returns io.EOF when the server ends the stream.
+func (s *{{ $streamName }}) Recv() ({{ gotyperef $mt $mt.AllRequired 0 false }}, string, error) {
+	for {
+		e, err := s.Next()
+		if err != nil {
+			return nil, "", err
+		}
+{{ if .Stream.Event }}		if e.Event != {{ printf "%q" .Stream.Event }} {
+			continue
+		}
+{{ end }}		var event {{ gotyperef $mt $mt.AllRequired 0 false }}
+		if err := json.Unmarshal(e.Data, &event); err != nil {
+			return nil, "", fmt.Errorf("failed to decode event: %s", err)
+		}
+		return event, e.ID, nil
+	}
+}
+
+{{ $desc := .Description }}{{ if $desc }}{{ multiComment $desc }}{{ else }}// {{ $funcName }} opens the stream of events of the {{ .Name }} action endpoint of the {{ .Parent.Name }} resource.
+// lastEventID is sent in the Last-Event-ID header to resume the stream if not empty{{ end }}{{ template "deprecationDoc" . }}
+func (c *Client) {{ $funcName }}(ctx context.Context, path string{{/*
+	*/}}{{ $params := join .QueryParams }}{{ if $params }}, {{ $params }}{{ end }}{{/*
+	*/}}{{ $headers := join .Headers }}{{ if $headers }}, {{ $headers }}{{ end }}{{/*
+	*/}}{{ $cookies := join .Cookies }}{{ if $cookies }}, {{ $cookies }}{{ end }}, lastEventID string) (*{{ $streamName }}, error) {
+{{ template "deprecationLog" . }}	scheme := c.Scheme
+	if scheme == "" {
+		scheme = "{{ .CanonicalScheme }}"
+	}
+	u := url.URL{Host: c.Host, Scheme: scheme, Path: path}
+{{ $params := .QueryParams }}{{ if $params }}{{ if gt (len $params.Type.ToObject) 0 }}	values := u.Query()
+{{ range $name, $att := $params.Type.ToObject }}{{ if (eq $att.Type.Kind 4) }}	values.Set("{{ $name }}", {{ goify $name false }})
+{{ else }} {{ if $att.Type.IsArray }}	if {{ goify $name false }} != nil {
+	{{ end }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
+	values.Set("{{ $name }}", {{ $tmp }})
+{{ if $att.Type.IsArray }}	}
+{{ end }}{{ end }}{{ end }}	u.RawQuery = values.Encode()
+{{ end }}{{ end }}	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	header := req.Header
+{{ if .Headers }}{{ range $name, $att := .Headers.Type.ToObject }}{{ if (eq $att.Type.Kind 4) }}	header.Set("{{ $name }}", {{ goify $name false }})
+{{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
+	header.Set("{{ $name }}", {{ $tmp }})
+{{ end }}{{ end }}{{ end }}{{ if .Cookies }}{{ range $name, $att := .Cookies.Type.ToObject }}{{ if eq (cmdFieldType $att.Type) "string" }}	if {{ goify $name false }} != "" {
+		req.AddCookie(&http.Cookie{Name: "{{ $name }}", Value: {{ goify $name false }}})
+	}
+{{ else }}{{ $tmp := tempvar }}{{ toString (goify $name false) $tmp $att }}
+	req.AddCookie(&http.Cookie{Name: "{{ $name }}", Value: {{ $tmp }}})
+{{ end }}{{ end }}{{ end }}	header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		header.Set("Last-Event-ID", lastEventID)
+	}{{ if .Security }}
+	c.Signer{{ goify .Security.Scheme.SchemeName true }}.Sign(ctx, req){{ end }}
+	resp, err := c.Client.Do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+	return &{{ $streamName }}{EventReader: goaclient.NewEventReader(resp.Body)}, nil
 }
 `
 
-const clientTmpl = `// Client is the {{ .Name }} service client.
+const deprecationTmpl = `{{ define "deprecationDoc" }}{{ with .Deprecation }}
+//
+// Deprecated: the {{ $.Name }} action of the {{ $.Parent.Name }} resource is deprecated, {{ .Message }}.{{ end }}{{ end }}{{/*
+*/}}{{ define "deprecationLog" }}{{ with .Deprecation }}	c.WarnDeprecated(ctx, {{ printf "%q" $.Parent.Name }}, {{ printf "%q" $.Name }}, {{ printf "%q" .SunsetHeader }})
+{{ end }}{{ end }}`
+
+const clientTmpl = `{{ $api := .API }}// Client is the {{ $api.Name }} service client.
 type Client struct {
-	*goaclient.Client{{range $security := .SecuritySchemes }}
+	*goaclient.Client{{range $security := $api.SecuritySchemes }}
 	Signer{{ goify $security.SchemeName true }} goaclient.Signer{{ end }}
 }
 
 // New instantiates the client.
 func New(c *http.Client) *Client {
-	return &Client{
-		Client: goaclient.New(c),{{range $security := .SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
-		Signer{{ goify $security.SchemeName true }}: &{{ $signer }}{},{{ end }}{{ end }}
-	}
+{{ if .VersionHeader }}	client := &Client{
+{{ else }}	return &Client{
+{{ end }}		Client: goaclient.New(c),{{range $security := $api.SecuritySchemes }}{{ $signer := signerType $security }}{{ if $signer }}
+		Signer{{ goify $security.SchemeName true }}: &{{ $signer }}{ {{- if eq $security.In "cookie" }}Cookie: {{ printf "%q" $security.Name }}{{ end -}} },{{ end }}{{ end }}
+	}{{ if .VersionHeader }}
+	client.Header = http.Header{ {{ printf "%q" .VersionHeader }}: []string{ {{ printf "%q" .VersionValue }} } }
+	return client{{ end }}
 }
 `
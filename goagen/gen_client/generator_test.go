@@ -4,14 +4,22 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_client"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gexec"
 )
 
+// registeredDesign is the API definition registered with the DSL engine, the tests that build
+// their own definition replace design.Design.
+var registeredDesign = design.Design
+
 var _ = Describe("Generate", func() {
 	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_client/test_"
 
@@ -104,4 +112,229 @@ var _ = Describe("Generate", func() {
 			Ω(content).Should(ContainSubstring("c.SignerJWT1.Sign(ctx, req)"))
 		})
 	})
+
+	Context("with typed errors", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			fields := design.ErrorFields()
+			fields["id"] = &design.AttributeDefinition{Type: design.Integer}
+			notFound := &design.ErrorDefinition{
+				Name:   "not_found",
+				Status: 404,
+				Type: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{
+						Type:       fields,
+						Validation: &dslengine.ValidationDefinition{Required: []string{"code", "status", "detail"}},
+					},
+					TypeName: "NotFoundError",
+				},
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name:   "foo",
+						Errors: map[string]*design.ErrorDefinition{"not_found": notFound},
+						Actions: map[string]*design.ActionDefinition{
+							"show": {
+								Name: "show",
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "GET",
+										Path: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			showAct := fooRes.Actions["show"]
+			showAct.Parent = fooRes
+			showAct.Routes[0].Parent = showAct
+			notFound.Parent = fooRes
+		})
+
+		It("generates the typed errors and the error response decoder", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "client", "datatypes.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(content).Should(ContainSubstring("type NotFoundError struct {"))
+			Ω(content).Should(ContainSubstring("`json:\"id,omitempty\" xml:\"id,omitempty\"`"))
+			Ω(content).Should(ContainSubstring("func (e *NotFoundError) ResponseStatus() int {"))
+			Ω(content).Should(ContainSubstring(`	case "not_found":
+		var decoded NotFoundError`))
+		})
+	})
+
+	Context("with a multipart payload", func() {
+		var payload *design.UserTypeDefinition
+		var oldDesign *design.APIDefinition
+
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			oldDesign = design.Design
+			fields := design.Object{}
+			kinds := map[string]design.Primitive{
+				"boolean":   design.Boolean,
+				"integer":   design.Integer,
+				"number":    design.Number,
+				"string":    design.String,
+				"date_time": design.DateTime,
+				"uuid":      design.UUID,
+				"any":       design.Any,
+				"file":      design.File,
+				"date":      design.Date,
+				"duration":  design.Duration,
+				"bytes":     design.Bytes,
+				"decimal":   design.Decimal,
+			}
+			for n, k := range kinds {
+				fields[n] = &design.AttributeDefinition{Type: k}
+				fields[n+"_required"] = &design.AttributeDefinition{Type: k}
+				fields[n+"_array"] = &design.AttributeDefinition{Type: &design.Array{
+					ElemType: &design.AttributeDefinition{Type: k},
+				}}
+			}
+			var required []string
+			for n := range fields {
+				if strings.HasSuffix(n, "_required") {
+					required = append(required, n)
+				}
+			}
+			payload = &design.UserTypeDefinition{
+				AttributeDefinition: &design.AttributeDefinition{
+					Type:       fields,
+					Validation: &dslengine.ValidationDefinition{Required: required},
+				},
+				TypeName: "UploadPayload",
+			}
+			design.Design = &design.APIDefinition{
+				Name: "testapi",
+				Resources: map[string]*design.ResourceDefinition{
+					"foo": {
+						Name: "foo",
+						Actions: map[string]*design.ActionDefinition{
+							"upload": {
+								Name:             "upload",
+								Payload:          payload,
+								PayloadMultipart: true,
+								Routes: []*design.RouteDefinition{
+									{
+										Verb: "POST",
+										Path: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			fooRes := design.Design.Resources["foo"]
+			uploadAct := fooRes.Actions["upload"]
+			uploadAct.Parent = fooRes
+			uploadAct.Routes[0].Parent = uploadAct
+		})
+
+		AfterEach(func() {
+			design.Design = oldDesign
+		})
+
+		It("generates a client that formats the values of all the primitive types", func() {
+			Ω(genErr).Should(BeNil())
+			b, err := ioutil.ReadFile(filepath.Join(outDir, "client", "foo_client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(b)
+			Ω(content).Should(ContainSubstring("(*payload.DateTime).Format(time.RFC3339)"))
+			Ω(content).Should(ContainSubstring("payload.UUIDRequired.String()"))
+			Ω(content).Should(ContainSubstring("base64.StdEncoding.EncodeToString(payload.Bytes)"))
+			_, err = gexec.Build(filepath.Join(testgenPackagePath, "client", "testapi-cli"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("does not modify the design", func() {
+			Ω(genErr).Should(BeNil())
+			upload := design.Design.Resources["foo"].Actions["upload"]
+			Ω(upload.Payload).Should(BeIdenticalTo(payload))
+			Ω(payload.Type.ToObject()["file"].Type).Should(Equal(design.File))
+		})
+	})
+
+	Context("with a WebSocket action exchanging messages", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = registeredDesign
+			dslengine.Reset()
+			API("testapi", func() {
+				Consumes("application/xml")
+				Produces("application/gob")
+			})
+			Post := Type("Post", func() {
+				Attribute("body", design.String)
+			})
+			Resource("room", func() {
+				Action("join", func() {
+					Routing(GET("/rooms/:id"))
+					Scheme("ws")
+					Messages(func() {
+						Inbound(Post)
+						Outbound(Post)
+					})
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("sends the content types and uses the API encoders", func() {
+			Ω(genErr).Should(BeNil())
+			b, err := ioutil.ReadFile(filepath.Join(outDir, "client", "room_client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(b)
+			Ω(content).Should(ContainSubstring(`cfg.Header.Set("Content-Type", "application/xml")`))
+			Ω(content).Should(ContainSubstring(`cfg.Header.Set("Accept", "application/gob")`))
+			Ω(content).Should(ContainSubstring("goaclient.DialWebSocket(cfg, 0, goa.NewXMLEncoder, goa.NewGobDecoder)"))
+			_, err = gexec.Build(filepath.Join(testgenPackagePath, "client"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
+
+	Context("with API versions", func() {
+		BeforeEach(func() {
+			codegen.TempCount = 0
+			design.Design = registeredDesign
+			dslengine.Reset()
+			API("testapi", func() {
+				VersionHeader("X-API-Version")
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					Response(design.NoContent)
+				})
+			})
+			APIVersion("v1", nil)
+			APIVersion("v2", func() {
+				BasePath("/v2")
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		})
+
+		It("generates a client package per version", func() {
+			Ω(genErr).Should(BeNil())
+			b, err := ioutil.ReadFile(filepath.Join(outDir, "client", "v1", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content := string(b)
+			Ω(content).Should(ContainSubstring("package v1"))
+			Ω(content).Should(ContainSubstring(`client.Header = http.Header{"X-API-Version": []string{"v1"}}`))
+			b, err = ioutil.ReadFile(filepath.Join(outDir, "client", "v2", "bottle_client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(ContainSubstring(`fmt.Sprintf("/v2/bottles/%v", id)`))
+			b, err = ioutil.ReadFile(filepath.Join(outDir, "client", "v2", "client.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).ShouldNot(ContainSubstring("client.Header"))
+			_, err = gexec.Build(filepath.Join(testgenPackagePath, "client", "v1"))
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
 })
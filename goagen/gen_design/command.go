@@ -0,0 +1,41 @@
+package gendesign
+
+import (
+	"fmt"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Format is the document format, "json" or "yaml".
+var Format string
+
+// Command is the goa design document generator command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("design", "Serialize the evaluated design to a JSON or YAML document")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().StringVar(&Format, "format", "json", `document format, "json" or "yaml"`)
+}
+
+// Run simply calls the meta generator.
+func (c *Command) Run() ([]string, error) {
+	if Format != "json" && Format != "yaml" {
+		return nil, fmt.Errorf("invalid document format %#v, must be json or yaml", Format)
+	}
+	gen := meta.NewGenerator(
+		"gendesign.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_design")},
+		map[string]string{"format": Format},
+	)
+	return gen.Generate()
+}
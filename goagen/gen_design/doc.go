@@ -0,0 +1,13 @@
+/*
+Package gendesign provides a generator that serializes the evaluated design to a canonical JSON or
+YAML document. Unlike the Swagger specification the document describes the design data structures
+exactly: it includes the media type views and links, the attribute metadata, defaults and examples,
+the security schemes, the errors and the rate limits of the API, resources and actions. Traits and
+response templates are not part of the document since their effects are already applied to the
+definitions that use them.
+
+The document is versioned, see DocumentVersion. The package also provides a loader that
+reconstructs the design data structures from a document so that tools written in Go may process
+designs without compiling and running the design package.
+*/
+package gendesign
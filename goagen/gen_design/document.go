@@ -0,0 +1,416 @@
+package gendesign
+
+import "github.com/goadesign/goa/design"
+
+// DocumentVersion is the version of the document format, it changes whenever the format changes in
+// an incompatible way.
+const DocumentVersion = 1
+
+type (
+	// Document is the serializable representation of an evaluated design.
+	Document struct {
+		// Version is the document format version.
+		Version int `json:"version"`
+		// API describes the API.
+		API *APIDocument `json:"api"`
+	}
+
+	// APIDocument describes an API.
+	APIDocument struct {
+		// Name is the API name.
+		Name string `json:"name"`
+		// Title is the API title.
+		Title string `json:"title,omitempty"`
+		// Description is the API description.
+		Description string `json:"description,omitempty"`
+		// Version is the API version.
+		Version string `json:"version,omitempty"`
+		// Host is the API hostname.
+		Host string `json:"host,omitempty"`
+		// Schemes lists the API URL schemes.
+		Schemes []string `json:"schemes,omitempty"`
+		// BasePath is the common base path to all API endpoints.
+		BasePath string `json:"base_path,omitempty"`
+		// BaseParams describes the parameters defined in BasePath.
+		BaseParams *AttributeDocument `json:"base_params,omitempty"`
+		// Consumes lists the request decoders.
+		Consumes []*EncodingDocument `json:"consumes,omitempty"`
+		// Produces lists the response encoders.
+		Produces []*EncodingDocument `json:"produces,omitempty"`
+		// Origins lists the API CORS policies indexed by origin.
+		Origins map[string]*CORSDocument `json:"origins,omitempty"`
+		// TermsOfService describes or links to the API terms of service.
+		TermsOfService string `json:"terms_of_service,omitempty"`
+		// Contact provides the API users with contact information.
+		Contact *design.ContactDefinition `json:"contact,omitempty"`
+		// License describes the API license.
+		License *design.LicenseDefinition `json:"license,omitempty"`
+		// Docs points to the API external documentation.
+		Docs *design.DocsDefinition `json:"docs,omitempty"`
+		// Resources lists the API resources that do not belong to a version indexed by name.
+		Resources map[string]*ResourceDocument `json:"resources,omitempty"`
+		// Versions lists the API versions in order of definition.
+		Versions []*VersionDocument `json:"versions,omitempty"`
+		// VersionHeader is the name of the request header that selects the API version.
+		VersionHeader string `json:"version_header,omitempty"`
+		// VersionParam is the name of the Accept header parameter that selects the API version.
+		VersionParam string `json:"version_param,omitempty"`
+		// Types lists the user types indexed by name.
+		Types map[string]*UserTypeDocument `json:"types,omitempty"`
+		// MediaTypes lists the media types indexed by identifier.
+		MediaTypes map[string]*MediaTypeDocument `json:"media_types,omitempty"`
+		// Responses lists the responses defined at the API level indexed by name.
+		Responses map[string]*ResponseDocument `json:"responses,omitempty"`
+		// Metadata is the API metadata.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// SecuritySchemes lists the security schemes in order of definition.
+		SecuritySchemes []*SecuritySchemeDocument `json:"security_schemes,omitempty"`
+		// Security is the default security requirement of the API actions.
+		Security *SecurityDocument `json:"security,omitempty"`
+		// RateLimit is the default rate limit of the API actions.
+		RateLimit *RateLimitDocument `json:"rate_limit,omitempty"`
+		// Errors lists the errors returned by all the API actions indexed by name.
+		Errors map[string]*ErrorDocument `json:"errors,omitempty"`
+	}
+
+	// VersionDocument describes an API version.
+	VersionDocument struct {
+		// Name is the version name.
+		Name string `json:"name"`
+		// Description is the version description.
+		Description string `json:"description,omitempty"`
+		// BasePath is the version base path.
+		BasePath string `json:"base_path,omitempty"`
+		// Previous is the name of the version this version derives from if any.
+		Previous string `json:"previous,omitempty"`
+		// Resources lists the version resources indexed by name.
+		Resources map[string]*ResourceDocument `json:"resources,omitempty"`
+	}
+
+	// ResourceDocument describes a resource.
+	ResourceDocument struct {
+		// Description is the resource description.
+		Description string `json:"description,omitempty"`
+		// Schemes lists the resource URL schemes.
+		Schemes []string `json:"schemes,omitempty"`
+		// BasePath is the common base path to all the resource actions.
+		BasePath string `json:"base_path,omitempty"`
+		// BaseParams describes the parameters defined in BasePath.
+		BaseParams *AttributeDocument `json:"base_params,omitempty"`
+		// Parent is the name of the parent resource if any.
+		Parent string `json:"parent,omitempty"`
+		// MediaType is the identifier of the resource default media type.
+		MediaType string `json:"media_type,omitempty"`
+		// CanonicalAction is the name of the resource canonical action.
+		CanonicalAction string `json:"canonical_action,omitempty"`
+		// Actions lists the resource actions indexed by name.
+		Actions map[string]*ActionDocument `json:"actions,omitempty"`
+		// Responses lists the default responses of the resource actions indexed by name.
+		Responses map[string]*ResponseDocument `json:"responses,omitempty"`
+		// Params describes the parameters common to all the resource actions.
+		Params *AttributeDocument `json:"params,omitempty"`
+		// Headers describes the headers common to all the resource actions.
+		Headers *AttributeDocument `json:"headers,omitempty"`
+		// Origins lists the resource CORS policies indexed by origin.
+		Origins map[string]*CORSDocument `json:"origins,omitempty"`
+		// Metadata is the resource metadata.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Security is the default security requirement of the resource actions.
+		Security *SecurityDocument `json:"security,omitempty"`
+		// RateLimit is the default rate limit of the resource actions.
+		RateLimit *RateLimitDocument `json:"rate_limit,omitempty"`
+		// Errors lists the errors returned by all the resource actions indexed by name.
+		Errors map[string]*ErrorDocument `json:"errors,omitempty"`
+	}
+
+	// ActionDocument describes an action.
+	ActionDocument struct {
+		// Description is the action description.
+		Description string `json:"description,omitempty"`
+		// Docs points to the action external documentation.
+		Docs *design.DocsDefinition `json:"docs,omitempty"`
+		// Schemes lists the action URL schemes.
+		Schemes []string `json:"schemes,omitempty"`
+		// Routes lists the action routes in order of definition.
+		Routes []*RouteDocument `json:"routes,omitempty"`
+		// Params describes the action path and query string parameters.
+		Params *AttributeDocument `json:"params,omitempty"`
+		// QueryParams describes the action query string parameters.
+		QueryParams *AttributeDocument `json:"query_params,omitempty"`
+		// Payload describes the action request payload.
+		Payload *UserTypeDocument `json:"payload,omitempty"`
+		// Multipart is true if the payload is sent as a multipart form.
+		Multipart bool `json:"multipart,omitempty"`
+		// Headers describes the action request headers.
+		Headers *AttributeDocument `json:"headers,omitempty"`
+		// Cookies describes the action request cookies.
+		Cookies *AttributeDocument `json:"cookies,omitempty"`
+		// Responses lists the action responses indexed by name.
+		Responses map[string]*ResponseDocument `json:"responses,omitempty"`
+		// Metadata is the action metadata.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Security is the action security requirement.
+		Security *SecurityDocument `json:"security,omitempty"`
+		// Deprecation describes the action deprecation if any.
+		Deprecation *DeprecationDocument `json:"deprecation,omitempty"`
+		// Pagination is the action pagination style if any.
+		Pagination string `json:"pagination,omitempty"`
+		// Fields is the identifier of the media type whose fields may be selected if the
+		// action supports field selection.
+		Fields string `json:"fields,omitempty"`
+		// Stream describes the action event stream if any.
+		Stream *StreamDocument `json:"stream,omitempty"`
+		// Messages describes the messages exchanged by the WebSocket action if any.
+		Messages *MessagesDocument `json:"messages,omitempty"`
+		// Cacheable is true if the action responses support conditional requests.
+		Cacheable bool `json:"cacheable,omitempty"`
+		// RateLimit is the action rate limit.
+		RateLimit *RateLimitDocument `json:"rate_limit,omitempty"`
+		// Errors lists the errors returned by the action indexed by name.
+		Errors map[string]*ErrorDocument `json:"errors,omitempty"`
+	}
+
+	// RouteDocument describes an action route.
+	RouteDocument struct {
+		// Verb is the HTTP method.
+		Verb string `json:"verb"`
+		// Path is the route path relative to the resource base path.
+		Path string `json:"path"`
+	}
+
+	// ResponseDocument describes a response.
+	ResponseDocument struct {
+		// Status is the response HTTP status code.
+		Status int `json:"status"`
+		// Description is the response description.
+		Description string `json:"description,omitempty"`
+		// Type describes the response body type if not a media type.
+		Type *TypeDocument `json:"type,omitempty"`
+		// MediaType is the identifier of the response media type if any.
+		MediaType string `json:"media_type,omitempty"`
+		// Headers describes the response headers.
+		Headers *AttributeDocument `json:"headers,omitempty"`
+		// Metadata is the response metadata.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Standard is true if the response is the standard response for its status.
+		Standard bool `json:"standard,omitempty"`
+	}
+
+	// StreamDocument describes an action event stream.
+	StreamDocument struct {
+		// MediaType is the identifier of the media type of the events.
+		MediaType string `json:"media_type"`
+		// Event is the name of the events.
+		Event string `json:"event,omitempty"`
+	}
+
+	// MessagesDocument describes the messages exchanged by a WebSocket action.
+	MessagesDocument struct {
+		// Inbound describes the type of the messages sent by the client if any.
+		Inbound *TypeDocument `json:"inbound,omitempty"`
+		// Outbound describes the type of the messages sent by the server if any.
+		Outbound *TypeDocument `json:"outbound,omitempty"`
+		// KeepAlive is the interval between the ping frames if any, e.g. "30s".
+		KeepAlive string `json:"keep_alive,omitempty"`
+	}
+
+	// DeprecationDocument describes a deprecation.
+	DeprecationDocument struct {
+		// Sunset is the RFC 3339 date after which the definition may be removed if any.
+		Sunset string `json:"sunset,omitempty"`
+	}
+
+	// CORSDocument describes a CORS policy.
+	CORSDocument struct {
+		// Headers lists the allowed request headers.
+		Headers []string `json:"headers,omitempty"`
+		// Methods lists the allowed HTTP methods.
+		Methods []string `json:"methods,omitempty"`
+		// Exposed lists the headers exposed to the client.
+		Exposed []string `json:"exposed,omitempty"`
+		// MaxAge is the number of seconds the preflight response may be cached.
+		MaxAge uint `json:"max_age,omitempty"`
+		// Credentials is true if the policy allows credentials.
+		Credentials bool `json:"credentials,omitempty"`
+	}
+
+	// EncodingDocument describes an encoder or decoder.
+	EncodingDocument struct {
+		// MIMETypes lists the MIME types handled by the encoder or decoder.
+		MIMETypes []string `json:"mime_types"`
+		// PackagePath is the import path of the package implementing the encoder or decoder.
+		PackagePath string `json:"package_path,omitempty"`
+		// Function is the name of the encoder or decoder factory function.
+		Function string `json:"function,omitempty"`
+	}
+
+	// SecuritySchemeDocument describes a security scheme.
+	SecuritySchemeDocument struct {
+		// Kind is the scheme kind: "oauth2", "basic", "api_key", "jwt" or "none".
+		Kind string `json:"kind"`
+		// Name is the name of the scheme used in security requirements.
+		Name string `json:"name"`
+		// Type is the Swagger type of the scheme.
+		Type string `json:"type"`
+		// Description is the scheme description.
+		Description string `json:"description,omitempty"`
+		// In is the location of the API key: "header", "query" or "cookie".
+		In string `json:"in,omitempty"`
+		// Param is the name of the header, query string parameter or cookie.
+		Param string `json:"param,omitempty"`
+		// Scopes lists the scheme scopes and their descriptions.
+		Scopes map[string]string `json:"scopes,omitempty"`
+		// Flow is the OAuth2 flow.
+		Flow string `json:"flow,omitempty"`
+		// TokenURL is the URL used to retrieve or refresh tokens.
+		TokenURL string `json:"token_url,omitempty"`
+		// AuthorizationURL is the URL used to retrieve authorization codes.
+		AuthorizationURL string `json:"authorization_url,omitempty"`
+	}
+
+	// SecurityDocument describes a security requirement.
+	SecurityDocument struct {
+		// Scheme is the name of the security scheme.
+		Scheme string `json:"scheme,omitempty"`
+		// Scopes lists the required scopes.
+		Scopes []string `json:"scopes,omitempty"`
+		// NoSecurity is true if the requirement disables security, see NoSecurity.
+		NoSecurity bool `json:"no_security,omitempty"`
+	}
+
+	// RateLimitDocument describes a rate limit.
+	RateLimitDocument struct {
+		// Limit is the number of requests allowed per period.
+		Limit int `json:"limit"`
+		// Period is the duration of the period, e.g. "1m0s".
+		Period string `json:"period"`
+		// Scope is the scope of the limit.
+		Scope string `json:"scope,omitempty"`
+	}
+
+	// ErrorDocument describes an error.
+	ErrorDocument struct {
+		// Status is the HTTP status code of the error responses.
+		Status int `json:"status"`
+		// Type describes the error type.
+		Type *UserTypeDocument `json:"type"`
+	}
+
+	// UserTypeDocument describes a user type.
+	UserTypeDocument struct {
+		// Name is the type name.
+		Name string `json:"name"`
+		*AttributeDocument
+	}
+
+	// MediaTypeDocument describes a media type.
+	MediaTypeDocument struct {
+		UserTypeDocument
+		// Identifier is the media type identifier.
+		Identifier string `json:"identifier"`
+		// Links lists the media type links indexed by name.
+		Links map[string]*LinkDocument `json:"links,omitempty"`
+		// Views lists the media type views indexed by name.
+		Views map[string]*AttributeDocument `json:"views,omitempty"`
+		// Resource is the name of the resource the media type is the default media type of.
+		Resource string `json:"resource,omitempty"`
+	}
+
+	// LinkDocument describes a media type link.
+	LinkDocument struct {
+		// View is the view used to render the link.
+		View string `json:"view,omitempty"`
+		// URITemplate is the link URI template.
+		URITemplate string `json:"uri_template,omitempty"`
+	}
+
+	// AttributeDocument describes an attribute.
+	AttributeDocument struct {
+		*TypeDocument
+		// Description is the attribute description.
+		Description string `json:"description,omitempty"`
+		// Validation describes the attribute validations.
+		Validation *ValidationDocument `json:"validation,omitempty"`
+		// Metadata is the attribute metadata.
+		Metadata map[string][]string `json:"metadata,omitempty"`
+		// Default is the attribute default value.
+		Default interface{} `json:"default,omitempty"`
+		// Example is the attribute example.
+		Example interface{} `json:"example,omitempty"`
+		// GeneratedExample is true if the example was generated rather than given by the
+		// design.
+		GeneratedExample bool `json:"generated_example,omitempty"`
+		// NoExample is true if the design disables the attribute example.
+		NoExample bool `json:"no_example,omitempty"`
+		// View is the view used to render the attribute if it is a media type.
+		View string `json:"view,omitempty"`
+		// Deprecation describes the attribute deprecation if any.
+		Deprecation *DeprecationDocument `json:"deprecation,omitempty"`
+		// NonZero lists the names of the child attributes that may not be zero.
+		NonZero []string `json:"non_zero,omitempty"`
+		// Reference is the type used to define default properties of the child attributes.
+		Reference *TypeDocument `json:"reference,omitempty"`
+		// Bases lists the types whose attributes are copied into the attribute.
+		Bases []*TypeDocument `json:"bases,omitempty"`
+	}
+
+	// TypeDocument describes a data type.
+	TypeDocument struct {
+		// Type is the kind of type: one of "boolean", "integer", "number", "string",
+		// "datetime", "uuid", "date", "duration", "bytes", "decimal", "any", "file",
+		// "array", "hash", "object", "union", "user_type" or "media_type".
+		Type string `json:"type"`
+		// Ref is the name of the user type or the identifier of the media type.
+		Ref string `json:"ref,omitempty"`
+		// Elem describes the elements of arrays and hashes.
+		Elem *AttributeDocument `json:"elem,omitempty"`
+		// Key describes the keys of hashes.
+		Key *AttributeDocument `json:"key,omitempty"`
+		// Attributes lists the child attributes of objects indexed by name.
+		Attributes map[string]*AttributeDocument `json:"attributes,omitempty"`
+		// Discriminator is the name of the union discriminator attribute.
+		Discriminator string `json:"discriminator,omitempty"`
+		// Variants lists the union variants in order of definition.
+		Variants []*VariantDocument `json:"variants,omitempty"`
+	}
+
+	// VariantDocument describes a union variant.
+	VariantDocument struct {
+		// Value is the discriminator value of the variant.
+		Value string `json:"value"`
+		// Type is the name of the variant user type.
+		Type string `json:"type"`
+	}
+
+	// ValidationDocument describes the validations of an attribute.
+	ValidationDocument struct {
+		// Values lists the enum values.
+		Values []interface{} `json:"values,omitempty"`
+		// Format is the format validation.
+		Format string `json:"format,omitempty"`
+		// Pattern is the regular expression validation.
+		Pattern string `json:"pattern,omitempty"`
+		// Minimum is the minimum value validation.
+		Minimum *float64 `json:"minimum,omitempty"`
+		// Maximum is the maximum value validation.
+		Maximum *float64 `json:"maximum,omitempty"`
+		// ExclusiveMinimum is true if Minimum is excluded from the valid values.
+		ExclusiveMinimum bool `json:"exclusive_minimum,omitempty"`
+		// ExclusiveMaximum is true if Maximum is excluded from the valid values.
+		ExclusiveMaximum bool `json:"exclusive_maximum,omitempty"`
+		// MultipleOf is the multiple of validation.
+		MultipleOf *float64 `json:"multiple_of,omitempty"`
+		// MinLength is the minimum length validation.
+		MinLength *int `json:"min_length,omitempty"`
+		// MaxLength is the maximum length validation.
+		MaxLength *int `json:"max_length,omitempty"`
+		// UniqueItems is the unique items validation.
+		UniqueItems bool `json:"unique_items,omitempty"`
+		// MinProperties is the minimum number of properties validation.
+		MinProperties *int `json:"min_properties,omitempty"`
+		// MaxProperties is the maximum number of properties validation.
+		MaxProperties *int `json:"max_properties,omitempty"`
+		// Required lists the required child attributes.
+		Required []string `json:"required,omitempty"`
+	}
+)
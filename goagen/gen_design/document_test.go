@@ -0,0 +1,271 @@
+package gendesign_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_design"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Document", func() {
+	var doc *gendesign.Document
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		API("cellar", func() {
+			Description("The wine cellar API")
+			BasePath("/cellar")
+			Metadata("team", "sommeliers")
+			JWTSecurity("jwt", func() {
+				Header("Authorization")
+				Scope("api:read", "Read access")
+			})
+		})
+		Country := Type("Country", func() {
+			Attribute("code", String, func() {
+				Pattern("^[A-Z]{2}$")
+			})
+		})
+		AccountMedia := MediaType("application/vnd.account+json", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+			})
+			View("default", func() {
+				Attribute("id")
+			})
+			View("tiny", func() {
+				Attribute("id")
+			})
+		})
+		BottleMedia := MediaType("application/vnd.bottle+json", func() {
+			Description("A wine bottle")
+			Attributes(func() {
+				Attribute("id", Integer, "ID", func() {
+					Example(1)
+				})
+				Attribute("name", String, func() {
+					Default("red")
+				})
+				Attribute("vintage", Integer, func() {
+					Minimum(1900)
+				})
+				Attribute("bottled_at", DateTime)
+				Attribute("country", Country)
+				Attribute("ratings", HashOf(String, Integer), func() {
+					Default(map[string]int{"parker": 95})
+				})
+				Attribute("account", AccountMedia)
+				Required("id", "name")
+			})
+			Links(func() {
+				Link("account", "tiny")
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+				Attribute("links")
+			})
+			View("tiny", func() {
+				Attribute("id")
+			})
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			DefaultMedia(BottleMedia)
+			Security("jwt", func() {
+				Scope("api:read")
+			})
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Fields()
+				Response(OK)
+				Response(NotFound)
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				NoSecurity()
+				Payload(func() {
+					Attribute("name", String, func() {
+						MinLength(1)
+					})
+					Attribute("color", String, func() {
+						Enum("red", "white")
+					})
+					Required("name")
+				})
+				Response(Created)
+				Response(BadRequest, ErrorMedia)
+			})
+			Action("watch", func() {
+				Routing(GET("/:id/watch"))
+				Scheme("ws")
+				Messages(func() {
+					Inbound(Country)
+					Outbound(BottleMedia)
+					KeepAlive(30 * time.Second)
+				})
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		doc = gendesign.NewDocument(Design)
+	})
+
+	It("describes the design", func() {
+		api := doc.API
+		Ω(doc.Version).Should(Equal(gendesign.DocumentVersion))
+		Ω(api.Name).Should(Equal("cellar"))
+		Ω(api.Metadata).Should(HaveKeyWithValue("team", []string{"sommeliers"}))
+		Ω(api.SecuritySchemes).Should(HaveLen(1))
+		Ω(api.SecuritySchemes[0].Kind).Should(Equal("jwt"))
+
+		Ω(api.MediaTypes).Should(HaveKey("application/vnd.bottle+json"))
+		mt := api.MediaTypes["application/vnd.bottle+json"]
+		Ω(mt.Name).Should(Equal("Bottle"))
+		Ω(mt.Resource).Should(Equal("bottle"))
+		Ω(mt.Links).Should(HaveKeyWithValue("account", &gendesign.LinkDocument{View: "tiny"}))
+		Ω(mt.Views).Should(HaveKey("tiny"))
+		Ω(mt.Validation.Required).Should(Equal([]string{"id", "name"}))
+		atts := mt.Attributes
+		Ω(atts["id"].Example).Should(Equal(1))
+		Ω(atts["id"].GeneratedExample).Should(BeFalse())
+		Ω(atts["name"].Default).Should(Equal("red"))
+		Ω(atts["bottled_at"].Type).Should(Equal("datetime"))
+		Ω(atts["bottled_at"].GeneratedExample).Should(BeTrue())
+		Ω(atts["country"].TypeDocument).Should(Equal(&gendesign.TypeDocument{Type: "user_type", Ref: "Country"}))
+		Ω(atts["ratings"].Default).Should(Equal(map[string]int{"parker": 95}))
+		Ω(api.Types).Should(HaveKey("Country"))
+
+		res := api.Resources["bottle"]
+		Ω(res.Security).Should(Equal(&gendesign.SecurityDocument{Scheme: "jwt", Scopes: []string{"api:read"}}))
+		create := res.Actions["create"]
+		Ω(create.Routes).Should(Equal([]*gendesign.RouteDocument{{Verb: "POST", Path: ""}}))
+		Ω(create.Security).Should(BeNil())
+		Ω(res.Actions["show"].Security).Should(Equal(res.Security))
+		Ω(create.Payload.Name).Should(Equal("CreateBottlePayload"))
+		Ω(create.Payload.Attributes["color"].Validation.Values).Should(Equal([]interface{}{"red", "white"}))
+		Ω(create.Responses["BadRequest"].MediaType).Should(Equal(ErrorMediaIdentifier))
+		Ω(res.Actions["watch"].Messages).Should(Equal(&gendesign.MessagesDocument{
+			Inbound:   &gendesign.TypeDocument{Type: "user_type", Ref: "Country"},
+			Outbound:  &gendesign.TypeDocument{Type: "media_type", Ref: "application/vnd.bottle+json"},
+			KeepAlive: "30s",
+		}))
+	})
+
+	Context("loaded back", func() {
+		var format string
+		var api *APIDefinition
+
+		BeforeEach(func() {
+			format = "json"
+		})
+
+		JustBeforeEach(func() {
+			b, err := doc.Marshal(format)
+			Ω(err).ShouldNot(HaveOccurred())
+			loaded, err := gendesign.UnmarshalDocument(b)
+			Ω(err).ShouldNot(HaveOccurred())
+			api, err = loaded.APIDefinition()
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("reconstructs the design", func() {
+			Ω(api.Name).Should(Equal("cellar"))
+			mt := api.MediaTypes[CanonicalIdentifier("application/vnd.bottle+json")]
+			Ω(mt).ShouldNot(BeNil())
+			Ω(mt.Resource).Should(Equal(api.Resources["bottle"]))
+			Ω(mt.Views["tiny"].Parent).Should(Equal(mt))
+			obj := mt.Type.ToObject()
+			Ω(obj["id"].HasCustomExample()).Should(BeTrue())
+			Ω(obj["bottled_at"].HasCustomExample()).Should(BeFalse())
+			Ω(obj["bottled_at"].Type).Should(Equal(DateTime))
+			Ω(obj["country"].Type).Should(Equal(api.Types["Country"]))
+			Ω(obj["ratings"].DefaultValue).Should(Equal(map[interface{}]interface{}{"parker": 95}))
+			Ω(api.MediaTypes).Should(HaveKeyWithValue(CanonicalIdentifier(ErrorMediaIdentifier), ErrorMedia))
+
+			show := api.Resources["bottle"].Actions["show"]
+			Ω(show.Parent).Should(Equal(api.Resources["bottle"]))
+			Ω(show.Routes[0].Parent).Should(Equal(show))
+			Ω(show.Routes[0].FullPath()).Should(Equal("/cellar/bottles/:id"))
+			Ω(api.Resources["bottle"].Security.Scheme).Should(Equal(api.SecuritySchemes[0]))
+
+			watch := api.Resources["bottle"].Actions["watch"]
+			Ω(watch.Messages.Parent).Should(Equal(watch))
+			Ω(watch.Messages.Inbound).Should(Equal(api.Types["Country"]))
+			Ω(watch.Messages.Outbound).Should(Equal(mt))
+			Ω(watch.Messages.KeepAlive).Should(Equal(30 * time.Second))
+		})
+
+		It("produces the same document", func() {
+			Ω(documentJSON(gendesign.NewDocument(api))).Should(MatchJSON(documentJSON(doc)))
+		})
+
+		Context("from YAML", func() {
+			BeforeEach(func() {
+				format = "yaml"
+			})
+
+			It("produces the same document", func() {
+				Ω(documentJSON(gendesign.NewDocument(api))).Should(MatchJSON(documentJSON(doc)))
+			})
+		})
+	})
+
+	Context("written to a file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "gendesign")
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("uses the format given by the file extension", func() {
+			path := filepath.Join(dir, "design.yaml")
+			Ω(doc.WriteDocument(path)).ShouldNot(HaveOccurred())
+			b, err := ioutil.ReadFile(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(b)).Should(HavePrefix("api:\n"))
+			loaded, err := gendesign.LoadDocument(path)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(loaded.API.Name).Should(Equal("cellar"))
+		})
+	})
+
+	Context("with an unknown type reference", func() {
+		It("returns an error", func() {
+			doc.API.Types["Country"].Attributes["code"].Type = "user_type"
+			doc.API.Types["Country"].Attributes["code"].Ref = "Region"
+			_, err := doc.APIDefinition()
+			Ω(err).Should(MatchError(`type "Country": attribute "code": unknown type "Region"`))
+		})
+	})
+
+	Context("with an unsupported version", func() {
+		It("returns an error", func() {
+			_, err := gendesign.UnmarshalDocument([]byte(`{"version": 2, "api": {"name": "cellar"}}`))
+			Ω(err).Should(MatchError("unsupported design document version 2, must be 1"))
+		})
+	})
+})
+
+// documentJSON returns the JSON representation of the given document.
+func documentJSON(doc *gendesign.Document) string {
+	b, err := json.Marshal(doc)
+	Ω(err).ShouldNot(HaveOccurred())
+	return string(b)
+}
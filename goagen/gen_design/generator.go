@@ -0,0 +1,34 @@
+package gendesign
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/spf13/cobra"
+)
+
+// Generate is the generator entry point called by the meta generator. It writes the document
+// describing the design to the file "design.json" or "design.yaml" of the output directory.
+func Generate() (files []string, err error) {
+	api := design.Design
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "Design document generator",
+		Long:  "Design document generator",
+		Run: func(*cobra.Command, []string) {
+			if err = os.MkdirAll(codegen.OutputDir, 0755); err != nil {
+				return
+			}
+			path := filepath.Join(codegen.OutputDir, "design."+Format)
+			if err = NewDocument(api).WriteDocument(path); err == nil {
+				files = []string{path}
+			}
+		},
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
@@ -0,0 +1,789 @@
+package gendesign
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"gopkg.in/yaml.v2"
+)
+
+// loader reconstructs designs from documents.
+type loader struct {
+	// doc is the document being loaded.
+	doc *APIDocument
+	// api is the design being built.
+	api *design.APIDefinition
+	// mediaTypes lists the media types indexed by document identifier.
+	mediaTypes map[string]*design.MediaTypeDefinition
+	// schemes lists the security schemes indexed by name.
+	schemes map[string]*design.SecuritySchemeDefinition
+	// values lists the functions that load the attribute values once all the types are loaded.
+	values []func() error
+}
+
+// UnmarshalDocument parses the given document, either in JSON or YAML format.
+func UnmarshalDocument(b []byte) (*Document, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var d Document
+	if err := dec.Decode(&d); err != nil {
+		var v interface{}
+		if err := yaml.Unmarshal(b, &v); err != nil {
+			return nil, fmt.Errorf("failed to parse design document: %s", err)
+		}
+		js, err := json.Marshal(stringKeys(v))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse design document: %s", err)
+		}
+		dec = json.NewDecoder(bytes.NewReader(js))
+		dec.UseNumber()
+		d = Document{}
+		if err := dec.Decode(&d); err != nil {
+			return nil, fmt.Errorf("invalid design document: %s", err)
+		}
+	}
+	if d.Version != DocumentVersion {
+		return nil, fmt.Errorf("unsupported design document version %d, must be %d", d.Version, DocumentVersion)
+	}
+	if d.API == nil {
+		return nil, fmt.Errorf("invalid design document: missing API")
+	}
+	return &d, nil
+}
+
+// LoadDocument reads the document written to the given file, either in JSON or YAML format.
+func LoadDocument(path string) (*Document, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalDocument(b)
+}
+
+// APIDefinition reconstructs the API design described by the document. The built-in error media
+// type is represented by design.ErrorMedia.
+func (d *Document) APIDefinition() (*design.APIDefinition, error) {
+	doc := d.API
+	api := design.NewAPIDefinition()
+	api.Name = doc.Name
+	api.Title = doc.Title
+	api.Description = doc.Description
+	api.Version = doc.Version
+	api.Host = doc.Host
+	api.Schemes = doc.Schemes
+	api.BasePath = doc.BasePath
+	api.VersionHeader = doc.VersionHeader
+	api.VersionParam = doc.VersionParam
+	api.TermsOfService = doc.TermsOfService
+	api.Contact = doc.Contact
+	api.License = doc.License
+	api.Docs = doc.Docs
+	api.Metadata = doc.Metadata
+	api.Types = make(map[string]*design.UserTypeDefinition, len(doc.Types))
+	api.MediaTypes = make(map[string]*design.MediaTypeDefinition, len(doc.MediaTypes))
+	l := &loader{
+		doc:        doc,
+		api:        api,
+		mediaTypes: make(map[string]*design.MediaTypeDefinition, len(doc.MediaTypes)),
+		schemes:    make(map[string]*design.SecuritySchemeDefinition, len(doc.SecuritySchemes)),
+	}
+	if err := l.loadTypes(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if api.BaseParams, err = l.attribute(doc.BaseParams); err != nil {
+		return nil, fmt.Errorf("API base params: %s", err)
+	}
+	api.Consumes = encodingDefinitions(doc.Consumes, false)
+	api.Produces = encodingDefinitions(doc.Produces, true)
+	api.Origins = corsDefinitions(api, doc.Origins)
+	for _, sd := range doc.SecuritySchemes {
+		scheme := &design.SecuritySchemeDefinition{
+			SchemeName:       sd.Name,
+			Type:             sd.Type,
+			Description:      sd.Description,
+			In:               sd.In,
+			Name:             sd.Param,
+			Scopes:           sd.Scopes,
+			Flow:             sd.Flow,
+			TokenURL:         sd.TokenURL,
+			AuthorizationURL: sd.AuthorizationURL,
+		}
+		for k, n := range securityKinds {
+			if n == sd.Kind {
+				scheme.Kind = k
+			}
+		}
+		if scheme.Kind == 0 {
+			return nil, fmt.Errorf("security scheme %#v: unknown kind %#v", sd.Name, sd.Kind)
+		}
+		api.SecuritySchemes = append(api.SecuritySchemes, scheme)
+		l.schemes[sd.Name] = scheme
+	}
+	if api.Security, err = l.security(doc.Security); err != nil {
+		return nil, fmt.Errorf("API security: %s", err)
+	}
+	if api.RateLimit, err = rateLimitDefinition(doc.RateLimit); err != nil {
+		return nil, fmt.Errorf("API rate limit: %s", err)
+	}
+	if api.Responses, err = l.responses(api, doc.Responses); err != nil {
+		return nil, fmt.Errorf("API responses: %s", err)
+	}
+	if api.Errors, err = l.errors(api, doc.Errors); err != nil {
+		return nil, fmt.Errorf("API errors: %s", err)
+	}
+	if api.Resources, err = l.resources(doc.Resources, nil); err != nil {
+		return nil, err
+	}
+	versions := make(map[string]*design.APIVersionDefinition, len(doc.Versions))
+	for _, vd := range doc.Versions {
+		v := &design.APIVersionDefinition{
+			Name:        vd.Name,
+			Description: vd.Description,
+			BasePath:    vd.BasePath,
+		}
+		if vd.Previous != "" {
+			if v.Previous = versions[vd.Previous]; v.Previous == nil {
+				return nil, fmt.Errorf("API version %#v: unknown previous version %#v", vd.Name, vd.Previous)
+			}
+		}
+		if v.Resources, err = l.resources(vd.Resources, v); err != nil {
+			return nil, err
+		}
+		versions[vd.Name] = v
+		api.Versions = append(api.Versions, v)
+	}
+
+	// Media types may refer to resources defined in any version.
+	for id, mtd := range doc.MediaTypes {
+		if mtd.Resource == "" {
+			continue
+		}
+		mt := l.mediaTypes[id]
+		if mt.Resource = api.Resources[mtd.Resource]; mt.Resource == nil {
+			for _, v := range api.Versions {
+				if mt.Resource = v.Resources[mtd.Resource]; mt.Resource != nil {
+					break
+				}
+			}
+		}
+		if mt.Resource == nil {
+			return nil, fmt.Errorf("media type %#v: unknown resource %#v", id, mtd.Resource)
+		}
+	}
+	for _, load := range l.values {
+		if err := load(); err != nil {
+			return nil, err
+		}
+	}
+	return api, nil
+}
+
+// loadTypes creates the user types and media types first so that attributes may refer to any of
+// them and then loads their definitions.
+func (l *loader) loadTypes() error {
+	for n := range l.doc.Types {
+		l.api.Types[n] = &design.UserTypeDefinition{
+			AttributeDefinition: &design.AttributeDefinition{},
+			TypeName:            n,
+		}
+	}
+	errorMediaID := design.CanonicalIdentifier(design.ErrorMediaIdentifier)
+	for id, mtd := range l.doc.MediaTypes {
+		canonical := design.CanonicalIdentifier(id)
+		mt := design.ErrorMedia
+		if canonical != errorMediaID {
+			mt = &design.MediaTypeDefinition{
+				UserTypeDefinition: &design.UserTypeDefinition{
+					AttributeDefinition: &design.AttributeDefinition{},
+					TypeName:            mtd.Name,
+				},
+				Identifier: id,
+			}
+		}
+		l.api.MediaTypes[canonical] = mt
+		l.mediaTypes[id] = mt
+	}
+
+	for n, utd := range l.doc.Types {
+		if err := l.fillAttribute(l.api.Types[n].AttributeDefinition, utd.AttributeDocument); err != nil {
+			return fmt.Errorf("type %#v: %s", n, err)
+		}
+	}
+	for id, mtd := range l.doc.MediaTypes {
+		mt := l.mediaTypes[id]
+		if mt == design.ErrorMedia {
+			continue
+		}
+		if err := l.fillAttribute(mt.AttributeDefinition, mtd.AttributeDocument); err != nil {
+			return fmt.Errorf("media type %#v: %s", id, err)
+		}
+		if len(mtd.Links) > 0 {
+			mt.Links = make(map[string]*design.LinkDefinition, len(mtd.Links))
+			for n, ld := range mtd.Links {
+				mt.Links[n] = &design.LinkDefinition{
+					Name:        n,
+					View:        ld.View,
+					URITemplate: ld.URITemplate,
+					Parent:      mt,
+				}
+			}
+		}
+		if len(mtd.Views) > 0 {
+			mt.Views = make(map[string]*design.ViewDefinition, len(mtd.Views))
+			for n, vd := range mtd.Views {
+				att, err := l.attribute(vd)
+				if err != nil {
+					return fmt.Errorf("media type %#v view %#v: %s", id, n, err)
+				}
+				mt.Views[n] = &design.ViewDefinition{AttributeDefinition: att, Name: n, Parent: mt}
+			}
+		}
+	}
+	return nil
+}
+
+// resources reconstructs the given resources. version is the API version the resources belong to
+// if any.
+func (l *loader) resources(docs map[string]*ResourceDocument, version *design.APIVersionDefinition) (map[string]*design.ResourceDefinition, error) {
+	res := make(map[string]*design.ResourceDefinition, len(docs))
+	for n, rd := range docs {
+		r := &design.ResourceDefinition{
+			Name:                n,
+			Schemes:             rd.Schemes,
+			BasePath:            rd.BasePath,
+			ParentName:          rd.Parent,
+			Description:         rd.Description,
+			MediaType:           rd.MediaType,
+			CanonicalActionName: rd.CanonicalAction,
+			Actions:             make(map[string]*design.ActionDefinition, len(rd.Actions)),
+			Version:             version,
+			Metadata:            rd.Metadata,
+		}
+		r.Origins = corsDefinitions(r, rd.Origins)
+		var err error
+		if r.BaseParams, err = l.attribute(rd.BaseParams); err != nil {
+			return nil, fmt.Errorf("resource %#v base params: %s", n, err)
+		}
+		if r.Params, err = l.attribute(rd.Params); err != nil {
+			return nil, fmt.Errorf("resource %#v params: %s", n, err)
+		}
+		if r.Headers, err = l.attribute(rd.Headers); err != nil {
+			return nil, fmt.Errorf("resource %#v headers: %s", n, err)
+		}
+		if r.Responses, err = l.responses(r, rd.Responses); err != nil {
+			return nil, fmt.Errorf("resource %#v: %s", n, err)
+		}
+		if r.Security, err = l.security(rd.Security); err != nil {
+			return nil, fmt.Errorf("resource %#v: %s", n, err)
+		}
+		if r.RateLimit, err = rateLimitDefinition(rd.RateLimit); err != nil {
+			return nil, fmt.Errorf("resource %#v: %s", n, err)
+		}
+		if r.Errors, err = l.errors(r, rd.Errors); err != nil {
+			return nil, fmt.Errorf("resource %#v: %s", n, err)
+		}
+		for an, ad := range rd.Actions {
+			a, err := l.action(r, an, ad)
+			if err != nil {
+				return nil, fmt.Errorf("action %s#%s: %s", n, an, err)
+			}
+			r.Actions[an] = a
+		}
+		res[n] = r
+	}
+	return res, nil
+}
+
+// action reconstructs the given action.
+func (l *loader) action(r *design.ResourceDefinition, name string, ad *ActionDocument) (*design.ActionDefinition, error) {
+	a := &design.ActionDefinition{
+		Name:             name,
+		Description:      ad.Description,
+		Docs:             ad.Docs,
+		Parent:           r,
+		Schemes:          ad.Schemes,
+		PayloadMultipart: ad.Multipart,
+		Metadata:         ad.Metadata,
+		Cacheable:        ad.Cacheable,
+	}
+	for _, rd := range ad.Routes {
+		a.Routes = append(a.Routes, &design.RouteDefinition{Verb: rd.Verb, Path: rd.Path, Parent: a})
+	}
+	var err error
+	if a.Params, err = l.attribute(ad.Params); err != nil {
+		return nil, fmt.Errorf("params: %s", err)
+	}
+	if a.QueryParams, err = l.attribute(ad.QueryParams); err != nil {
+		return nil, fmt.Errorf("query params: %s", err)
+	}
+	if a.Payload, err = l.userType(ad.Payload); err != nil {
+		return nil, fmt.Errorf("payload: %s", err)
+	}
+	if a.Headers, err = l.attribute(ad.Headers); err != nil {
+		return nil, fmt.Errorf("headers: %s", err)
+	}
+	if a.Cookies, err = l.attribute(ad.Cookies); err != nil {
+		return nil, fmt.Errorf("cookies: %s", err)
+	}
+	if a.Responses, err = l.responses(a, ad.Responses); err != nil {
+		return nil, err
+	}
+	if a.Security, err = l.security(ad.Security); err != nil {
+		return nil, err
+	}
+	if a.Deprecation, err = deprecationDefinition(ad.Deprecation); err != nil {
+		return nil, err
+	}
+	if ad.Pagination != "" {
+		a.Pagination = &design.PaginationDefinition{Style: ad.Pagination}
+	}
+	if ad.Fields != "" {
+		a.Fields = &design.FieldsDefinition{MediaType: ad.Fields}
+	}
+	if ad.Stream != nil {
+		a.Stream = &design.StreamDefinition{MediaType: ad.Stream.MediaType, Event: ad.Stream.Event}
+	}
+	if a.Messages, err = l.messages(a, ad.Messages); err != nil {
+		return nil, err
+	}
+	if a.RateLimit, err = rateLimitDefinition(ad.RateLimit); err != nil {
+		return nil, err
+	}
+	if a.Errors, err = l.errors(a, ad.Errors); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// messages reconstructs the given WebSocket messages.
+func (l *loader) messages(a *design.ActionDefinition, md *MessagesDocument) (*design.MessagesDefinition, error) {
+	if md == nil {
+		return nil, nil
+	}
+	m := &design.MessagesDefinition{Parent: a}
+	var err error
+	if md.Inbound != nil {
+		if m.Inbound, err = l.dataType(md.Inbound); err != nil {
+			return nil, fmt.Errorf("inbound messages: %s", err)
+		}
+	}
+	if md.Outbound != nil {
+		if m.Outbound, err = l.dataType(md.Outbound); err != nil {
+			return nil, fmt.Errorf("outbound messages: %s", err)
+		}
+	}
+	if md.KeepAlive != "" {
+		if m.KeepAlive, err = time.ParseDuration(md.KeepAlive); err != nil {
+			return nil, fmt.Errorf("invalid keep-alive period %#v: %s", md.KeepAlive, err)
+		}
+	}
+	return m, nil
+}
+
+// responses reconstructs the given responses.
+func (l *loader) responses(parent dslengine.Definition, docs map[string]*ResponseDocument) (map[string]*design.ResponseDefinition, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]*design.ResponseDefinition, len(docs))
+	for n, rd := range docs {
+		r := &design.ResponseDefinition{
+			Name:        n,
+			Status:      rd.Status,
+			Description: rd.Description,
+			MediaType:   rd.MediaType,
+			Parent:      parent,
+			Metadata:    rd.Metadata,
+			Standard:    rd.Standard,
+		}
+		var err error
+		if rd.Type != nil {
+			if r.Type, err = l.dataType(rd.Type); err != nil {
+				return nil, fmt.Errorf("response %#v: %s", n, err)
+			}
+		}
+		if r.Headers, err = l.attribute(rd.Headers); err != nil {
+			return nil, fmt.Errorf("response %#v headers: %s", n, err)
+		}
+		res[n] = r
+	}
+	return res, nil
+}
+
+// errors reconstructs the given errors.
+func (l *loader) errors(parent dslengine.Definition, docs map[string]*ErrorDocument) (map[string]*design.ErrorDefinition, error) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	res := make(map[string]*design.ErrorDefinition, len(docs))
+	for n, ed := range docs {
+		t, err := l.userType(ed.Type)
+		if err != nil {
+			return nil, fmt.Errorf("error %#v: %s", n, err)
+		}
+		res[n] = &design.ErrorDefinition{Name: n, Status: ed.Status, Type: t, Parent: parent}
+	}
+	return res, nil
+}
+
+// security reconstructs the given security requirement.
+func (l *loader) security(sd *SecurityDocument) (*design.SecurityDefinition, error) {
+	if sd == nil {
+		return nil, nil
+	}
+	if sd.NoSecurity {
+		return &design.SecurityDefinition{
+			Scheme: &design.SecuritySchemeDefinition{Kind: design.NoSecurityKind},
+		}, nil
+	}
+	scheme, ok := l.schemes[sd.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unknown security scheme %#v", sd.Scheme)
+	}
+	return &design.SecurityDefinition{Scheme: scheme, Scopes: sd.Scopes}, nil
+}
+
+// userType reconstructs a user type that is not part of the design types such as a payload or
+// error type.
+func (l *loader) userType(utd *UserTypeDocument) (*design.UserTypeDefinition, error) {
+	if utd == nil {
+		return nil, nil
+	}
+	att := &design.AttributeDefinition{}
+	if err := l.fillAttribute(att, utd.AttributeDocument); err != nil {
+		return nil, err
+	}
+	return &design.UserTypeDefinition{AttributeDefinition: att, TypeName: utd.Name}, nil
+}
+
+// attribute reconstructs the given attribute.
+func (l *loader) attribute(ad *AttributeDocument) (*design.AttributeDefinition, error) {
+	if ad == nil {
+		return nil, nil
+	}
+	att := &design.AttributeDefinition{}
+	if err := l.fillAttribute(att, ad); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// fillAttribute initializes att with the definition described by the given document.
+func (l *loader) fillAttribute(att *design.AttributeDefinition, ad *AttributeDocument) error {
+	if ad == nil {
+		return nil
+	}
+	att.Description = ad.Description
+	att.Metadata = ad.Metadata
+	att.View = ad.View
+	var err error
+	if ad.TypeDocument != nil {
+		if att.Type, err = l.dataType(ad.TypeDocument); err != nil {
+			return err
+		}
+	}
+	if ad.Reference != nil {
+		if att.Reference, err = l.dataType(ad.Reference); err != nil {
+			return err
+		}
+	}
+	for _, b := range ad.Bases {
+		base, err := l.dataType(b)
+		if err != nil {
+			return err
+		}
+		att.Bases = append(att.Bases, base)
+	}
+	if ad.Validation != nil {
+		att.Validation = validationDefinition(ad.Validation)
+	}
+	// Values are converted once all the types are loaded since the conversion depends on the
+	// attribute type.
+	l.values = append(l.values, func() error { return loadValues(att, ad) })
+	if att.Deprecation, err = deprecationDefinition(ad.Deprecation); err != nil {
+		return err
+	}
+	if len(ad.NonZero) > 0 {
+		att.NonZeroAttributes = make(map[string]bool, len(ad.NonZero))
+		for _, n := range ad.NonZero {
+			att.NonZeroAttributes[n] = true
+		}
+	}
+	return nil
+}
+
+// dataType reconstructs the given data type.
+func (l *loader) dataType(td *TypeDocument) (design.DataType, error) {
+	switch td.Type {
+	case "array":
+		elem, err := l.attribute(td.Elem)
+		if err != nil {
+			return nil, err
+		}
+		if elem == nil {
+			return nil, fmt.Errorf("missing array element type")
+		}
+		return &design.Array{ElemType: elem}, nil
+	case "hash":
+		key, err := l.attribute(td.Key)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := l.attribute(td.Elem)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil || elem == nil {
+			return nil, fmt.Errorf("missing hash key or element type")
+		}
+		return &design.Hash{KeyType: key, ElemType: elem}, nil
+	case "object":
+		obj := make(design.Object, len(td.Attributes))
+		for n, ad := range td.Attributes {
+			att, err := l.attribute(ad)
+			if err != nil {
+				return nil, fmt.Errorf("attribute %#v: %s", n, err)
+			}
+			obj[n] = att
+		}
+		return obj, nil
+	case "union":
+		u := &design.Union{Discriminator: td.Discriminator}
+		for _, vd := range td.Variants {
+			ut, ok := l.api.Types[vd.Type]
+			if !ok {
+				return nil, fmt.Errorf("unknown union variant type %#v", vd.Type)
+			}
+			u.Variants = append(u.Variants, &design.UnionVariant{Value: vd.Value, Type: ut})
+		}
+		return u, nil
+	case "user_type":
+		ut, ok := l.api.Types[td.Ref]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %#v", td.Ref)
+		}
+		return ut, nil
+	case "media_type":
+		mt, ok := l.mediaTypes[td.Ref]
+		if !ok {
+			return nil, fmt.Errorf("unknown media type %#v", td.Ref)
+		}
+		return mt, nil
+	}
+	for k, n := range primitiveNames {
+		if n == td.Type {
+			return design.Primitive(k), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown type %#v", td.Type)
+}
+
+// validationDefinition reconstructs the given validation, the enum values are loaded by loadValues.
+func validationDefinition(vd *ValidationDocument) *dslengine.ValidationDefinition {
+	return &dslengine.ValidationDefinition{
+		Format:           vd.Format,
+		Pattern:          vd.Pattern,
+		Minimum:          vd.Minimum,
+		Maximum:          vd.Maximum,
+		ExclusiveMinimum: vd.ExclusiveMinimum,
+		ExclusiveMaximum: vd.ExclusiveMaximum,
+		MultipleOf:       vd.MultipleOf,
+		MinLength:        vd.MinLength,
+		MaxLength:        vd.MaxLength,
+		UniqueItems:      vd.UniqueItems,
+		MinProperties:    vd.MinProperties,
+		MaxProperties:    vd.MaxProperties,
+		Required:         vd.Required,
+	}
+}
+
+// loadValues initializes the enum values, default value and example of att with the values
+// described by the given document.
+func loadValues(att *design.AttributeDefinition, ad *AttributeDocument) error {
+	if ad.Validation != nil {
+		for _, val := range ad.Validation.Values {
+			att.Validation.Values = append(att.Validation.Values, value(att.Type, val))
+		}
+	}
+	if ad.Default != nil {
+		att.DefaultValue = value(att.Type, ad.Default)
+	}
+	switch {
+	case ad.NoExample:
+		att.SetExample(nil)
+	case ad.GeneratedExample:
+		att.Example = value(att.Type, ad.Example)
+	case ad.Example != nil:
+		if !att.SetExample(value(att.Type, ad.Example)) {
+			return fmt.Errorf("example %#v is incompatible with type %s", ad.Example, att.Type.Name())
+		}
+	}
+	return nil
+}
+
+// encodingDefinitions reconstructs the given encoders or decoders.
+func encodingDefinitions(docs []*EncodingDocument, encoder bool) []*design.EncodingDefinition {
+	var res []*design.EncodingDefinition
+	for _, ed := range docs {
+		res = append(res, &design.EncodingDefinition{
+			MIMETypes:   ed.MIMETypes,
+			PackagePath: ed.PackagePath,
+			Function:    ed.Function,
+			Encoder:     encoder,
+		})
+	}
+	return res
+}
+
+// corsDefinitions reconstructs the given CORS policies.
+func corsDefinitions(parent dslengine.Definition, docs map[string]*CORSDocument) map[string]*design.CORSDefinition {
+	if len(docs) == 0 {
+		return nil
+	}
+	res := make(map[string]*design.CORSDefinition, len(docs))
+	for o, cd := range docs {
+		res[o] = &design.CORSDefinition{
+			Parent:      parent,
+			Origin:      o,
+			Headers:     cd.Headers,
+			Methods:     cd.Methods,
+			Exposed:     cd.Exposed,
+			MaxAge:      cd.MaxAge,
+			Credentials: cd.Credentials,
+		}
+	}
+	return res
+}
+
+// rateLimitDefinition reconstructs the given rate limit.
+func rateLimitDefinition(rd *RateLimitDocument) (*design.RateLimitDefinition, error) {
+	if rd == nil {
+		return nil, nil
+	}
+	period, err := time.ParseDuration(rd.Period)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rate limit period %#v: %s", rd.Period, err)
+	}
+	return &design.RateLimitDefinition{Limit: rd.Limit, Period: period, Scope: rd.Scope}, nil
+}
+
+// deprecationDefinition reconstructs the given deprecation.
+func deprecationDefinition(dd *DeprecationDocument) (*design.DeprecationDefinition, error) {
+	if dd == nil {
+		return nil, nil
+	}
+	d := &design.DeprecationDefinition{}
+	if dd.Sunset != "" {
+		sunset, err := time.Parse(time.RFC3339, dd.Sunset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sunset date %#v: %s", dd.Sunset, err)
+		}
+		d.Sunset = sunset
+	}
+	return d, nil
+}
+
+// value converts the given decoded default, example or enum value into the representation used by
+// the design for values of type t: integers are represented with int, numbers with float64 and hash
+// values with maps indexed by interface{}. t may be nil in which case numbers are converted to int if
+// they have no fractional part and to float64 otherwise.
+func value(t design.DataType, v interface{}) interface{} {
+	switch actual := v.(type) {
+	case json.Number:
+		f, err := actual.Float64()
+		if err != nil {
+			return actual.String()
+		}
+		if t != nil && t.Kind() == design.NumberKind {
+			return f
+		}
+		if (t != nil && t.Kind() == design.IntegerKind) || f == math.Trunc(f) {
+			if i, err := actual.Int64(); err == nil {
+				return int(i)
+			}
+			return int(f)
+		}
+		return f
+	case []interface{}:
+		var elem design.DataType
+		if t != nil && t.IsArray() {
+			elem = t.ToArray().ElemType.Type
+		}
+		res := make([]interface{}, len(actual))
+		for i, val := range actual {
+			res[i] = value(elem, val)
+		}
+		return res
+	case map[string]interface{}:
+		if t != nil && t.IsHash() {
+			h := t.ToHash()
+			res := make(map[interface{}]interface{}, len(actual))
+			for k, val := range actual {
+				res[hashKey(h.KeyType.Type, k)] = value(h.ElemType.Type, val)
+			}
+			return res
+		}
+		var obj design.Object
+		if t != nil && t.IsObject() {
+			obj = t.ToObject()
+		}
+		res := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			var ct design.DataType
+			if att, ok := obj[k]; ok {
+				ct = att.Type
+			}
+			res[k] = value(ct, val)
+		}
+		return res
+	}
+	return v
+}
+
+// hashKey converts the given serialized hash key into a value of type t.
+func hashKey(t design.DataType, k string) interface{} {
+	if t == nil {
+		return k
+	}
+	switch t.Kind() {
+	case design.IntegerKind:
+		if i, err := strconv.Atoi(k); err == nil {
+			return i
+		}
+	case design.NumberKind:
+		if f, err := strconv.ParseFloat(k, 64); err == nil {
+			return f
+		}
+	case design.BooleanKind:
+		if b, err := strconv.ParseBool(k); err == nil {
+			return b
+		}
+	}
+	return k
+}
+
+// stringKeys converts the maps produced by the YAML decoder into maps indexed by strings.
+func stringKeys(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			m[fmt.Sprintf("%v", k)] = stringKeys(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range actual {
+			actual[i] = stringKeys(val)
+		}
+	}
+	return v
+}
@@ -0,0 +1,505 @@
+package gendesign
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"gopkg.in/yaml.v2"
+)
+
+// primitiveNames maps the primitive types to their names in documents. Primitive.Name returns
+// the JSON type name which is the same for all the string based primitive types.
+var primitiveNames = map[design.Kind]string{
+	design.BooleanKind:  "boolean",
+	design.IntegerKind:  "integer",
+	design.NumberKind:   "number",
+	design.StringKind:   "string",
+	design.DateTimeKind: "datetime",
+	design.UUIDKind:     "uuid",
+	design.DateKind:     "date",
+	design.DurationKind: "duration",
+	design.BytesKind:    "bytes",
+	design.DecimalKind:  "decimal",
+	design.AnyKind:      "any",
+	design.FileKind:     "file",
+}
+
+// securityKinds maps the security scheme kinds to their names in documents.
+var securityKinds = map[design.SecuritySchemeKind]string{
+	design.OAuth2SecurityKind:    "oauth2",
+	design.BasicAuthSecurityKind: "basic",
+	design.APIKeySecurityKind:    "api_key",
+	design.JWTSecurityKind:       "jwt",
+	design.NoSecurityKind:        "none",
+}
+
+// serializer produces documents from designs.
+type serializer struct {
+	// doc is the document being built.
+	doc *APIDocument
+}
+
+// NewDocument produces the document that describes the given API design. The document includes
+// all the user types and media types of the design as well as any type reachable from the
+// resources that is not part of the design types.
+func NewDocument(api *design.APIDefinition) *Document {
+	s := &serializer{doc: &APIDocument{
+		Name:           api.Name,
+		Title:          api.Title,
+		Description:    api.Description,
+		Version:        api.Version,
+		Host:           api.Host,
+		Schemes:        api.Schemes,
+		BasePath:       api.BasePath,
+		VersionHeader:  api.VersionHeader,
+		VersionParam:   api.VersionParam,
+		TermsOfService: api.TermsOfService,
+		Contact:        api.Contact,
+		License:        api.License,
+		Docs:           api.Docs,
+		Metadata:       api.Metadata,
+		Types:          make(map[string]*UserTypeDocument),
+		MediaTypes:     make(map[string]*MediaTypeDocument),
+	}}
+	doc := s.doc
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		s.addUserType(ut)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		s.addMediaType(mt)
+		return nil
+	})
+	doc.BaseParams = s.attribute(api.BaseParams)
+	doc.Consumes = encodings(api.Consumes)
+	doc.Produces = encodings(api.Produces)
+	doc.Origins = origins(api.Origins)
+	doc.Resources = s.resources(api.Resources)
+	for _, v := range api.Versions {
+		vd := &VersionDocument{
+			Name:        v.Name,
+			Description: v.Description,
+			BasePath:    v.BasePath,
+			Resources:   s.resources(v.Resources),
+		}
+		if v.Previous != nil {
+			vd.Previous = v.Previous.Name
+		}
+		doc.Versions = append(doc.Versions, vd)
+	}
+	doc.Responses = s.responses(api.Responses)
+	for _, scheme := range api.SecuritySchemes {
+		doc.SecuritySchemes = append(doc.SecuritySchemes, &SecuritySchemeDocument{
+			Kind:             securityKinds[scheme.Kind],
+			Name:             scheme.SchemeName,
+			Type:             scheme.Type,
+			Description:      scheme.Description,
+			In:               scheme.In,
+			Param:            scheme.Name,
+			Scopes:           scheme.Scopes,
+			Flow:             scheme.Flow,
+			TokenURL:         scheme.TokenURL,
+			AuthorizationURL: scheme.AuthorizationURL,
+		})
+	}
+	doc.Security = security(api.Security)
+	doc.RateLimit = rateLimit(api.RateLimit)
+	doc.Errors = s.errors(api.Errors)
+	return &Document{Version: DocumentVersion, API: doc}
+}
+
+// Marshal returns the representation of the document in the given format, "json" or "yaml".
+func (d *Document) Marshal(format string) ([]byte, error) {
+	b, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "json":
+		return append(b, '\n'), nil
+	case "yaml":
+		var v interface{}
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+		return yaml.Marshal(v)
+	default:
+		return nil, fmt.Errorf("invalid document format %#v, must be json or yaml", format)
+	}
+}
+
+// WriteDocument writes the document to the given file. The document is written in YAML if the file
+// extension is ".yaml" or ".yml", in JSON otherwise.
+func (d *Document) WriteDocument(path string) error {
+	format := "json"
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		format = "yaml"
+	}
+	b, err := d.Marshal(format)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// addUserType records the document of the given user type if not already recorded.
+func (s *serializer) addUserType(ut *design.UserTypeDefinition) {
+	if _, ok := s.doc.Types[ut.TypeName]; ok {
+		return
+	}
+	// Record the type before producing its document to handle recursive types.
+	utd := &UserTypeDocument{Name: ut.TypeName}
+	s.doc.Types[ut.TypeName] = utd
+	utd.AttributeDocument = s.attribute(ut.AttributeDefinition)
+}
+
+// addMediaType records the document of the given media type if not already recorded.
+func (s *serializer) addMediaType(mt *design.MediaTypeDefinition) {
+	if _, ok := s.doc.MediaTypes[mt.Identifier]; ok {
+		return
+	}
+	mtd := &MediaTypeDocument{
+		UserTypeDocument: UserTypeDocument{Name: mt.TypeName},
+		Identifier:       mt.Identifier,
+	}
+	s.doc.MediaTypes[mt.Identifier] = mtd
+	mtd.AttributeDocument = s.attribute(mt.AttributeDefinition)
+	if len(mt.Links) > 0 {
+		mtd.Links = make(map[string]*LinkDocument, len(mt.Links))
+		for n, l := range mt.Links {
+			mtd.Links[n] = &LinkDocument{View: l.View, URITemplate: l.URITemplate}
+		}
+	}
+	if len(mt.Views) > 0 {
+		mtd.Views = make(map[string]*AttributeDocument, len(mt.Views))
+		for n, v := range mt.Views {
+			mtd.Views[n] = s.attribute(v.AttributeDefinition)
+		}
+	}
+	if mt.Resource != nil {
+		mtd.Resource = mt.Resource.Name
+	}
+}
+
+// resources produces the documents of the given resources.
+func (s *serializer) resources(resources map[string]*design.ResourceDefinition) map[string]*ResourceDocument {
+	if len(resources) == 0 {
+		return nil
+	}
+	res := make(map[string]*ResourceDocument, len(resources))
+	for n, r := range resources {
+		rd := &ResourceDocument{
+			Description:     r.Description,
+			Schemes:         r.Schemes,
+			BasePath:        r.BasePath,
+			BaseParams:      s.attribute(r.BaseParams),
+			Parent:          r.ParentName,
+			MediaType:       r.MediaType,
+			CanonicalAction: r.CanonicalActionName,
+			Responses:       s.responses(r.Responses),
+			Params:          s.attribute(r.Params),
+			Headers:         s.attribute(r.Headers),
+			Origins:         origins(r.Origins),
+			Metadata:        r.Metadata,
+			Security:        security(r.Security),
+			RateLimit:       rateLimit(r.RateLimit),
+			Errors:          s.errors(r.Errors),
+		}
+		if len(r.Actions) > 0 {
+			rd.Actions = make(map[string]*ActionDocument, len(r.Actions))
+			for an, a := range r.Actions {
+				rd.Actions[an] = s.action(a)
+			}
+		}
+		res[n] = rd
+	}
+	return res
+}
+
+// action produces the document of the given action.
+func (s *serializer) action(a *design.ActionDefinition) *ActionDocument {
+	ad := &ActionDocument{
+		Description: a.Description,
+		Docs:        a.Docs,
+		Schemes:     a.Schemes,
+		Params:      s.attribute(a.Params),
+		QueryParams: s.attribute(a.QueryParams),
+		Payload:     s.inlineUserType(a.Payload),
+		Multipart:   a.PayloadMultipart,
+		Headers:     s.attribute(a.Headers),
+		Cookies:     s.attribute(a.Cookies),
+		Responses:   s.responses(a.Responses),
+		Metadata:    a.Metadata,
+		Security:    security(a.Security),
+		Deprecation: deprecation(a.Deprecation),
+		Cacheable:   a.Cacheable,
+		RateLimit:   rateLimit(a.RateLimit),
+		Errors:      s.errors(a.Errors),
+	}
+	for _, r := range a.Routes {
+		ad.Routes = append(ad.Routes, &RouteDocument{Verb: r.Verb, Path: r.Path})
+	}
+	if a.Pagination != nil {
+		ad.Pagination = a.Pagination.Style
+	}
+	if a.Fields != nil {
+		ad.Fields = a.Fields.MediaType
+	}
+	if a.Stream != nil {
+		ad.Stream = &StreamDocument{MediaType: a.Stream.MediaType, Event: a.Stream.Event}
+	}
+	if m := a.Messages; m != nil {
+		md := &MessagesDocument{}
+		if m.Inbound != nil {
+			md.Inbound = s.dataType(m.Inbound)
+		}
+		if m.Outbound != nil {
+			md.Outbound = s.dataType(m.Outbound)
+		}
+		if m.KeepAlive > 0 {
+			md.KeepAlive = m.KeepAlive.String()
+		}
+		ad.Messages = md
+	}
+	return ad
+}
+
+// responses produces the documents of the given responses.
+func (s *serializer) responses(responses map[string]*design.ResponseDefinition) map[string]*ResponseDocument {
+	if len(responses) == 0 {
+		return nil
+	}
+	res := make(map[string]*ResponseDocument, len(responses))
+	for n, r := range responses {
+		rd := &ResponseDocument{
+			Status:      r.Status,
+			Description: r.Description,
+			MediaType:   r.MediaType,
+			Headers:     s.attribute(r.Headers),
+			Metadata:    r.Metadata,
+			Standard:    r.Standard,
+		}
+		if r.Type != nil {
+			rd.Type = s.dataType(r.Type)
+		}
+		res[n] = rd
+	}
+	return res
+}
+
+// errors produces the documents of the given errors.
+func (s *serializer) errors(errors map[string]*design.ErrorDefinition) map[string]*ErrorDocument {
+	if len(errors) == 0 {
+		return nil
+	}
+	res := make(map[string]*ErrorDocument, len(errors))
+	for n, e := range errors {
+		res[n] = &ErrorDocument{Status: e.Status, Type: s.inlineUserType(e.Type)}
+	}
+	return res
+}
+
+// inlineUserType produces the document of a user type that is not part of the design types such
+// as a payload or error type.
+func (s *serializer) inlineUserType(ut *design.UserTypeDefinition) *UserTypeDocument {
+	if ut == nil {
+		return nil
+	}
+	return &UserTypeDocument{Name: ut.TypeName, AttributeDocument: s.attribute(ut.AttributeDefinition)}
+}
+
+// attribute produces the document of the given attribute.
+func (s *serializer) attribute(att *design.AttributeDefinition) *AttributeDocument {
+	if att == nil {
+		return nil
+	}
+	ad := &AttributeDocument{
+		Description: att.Description,
+		Validation:  validation(att.Validation),
+		Metadata:    att.Metadata,
+		Default:     jsonValue(att.DefaultValue),
+		View:        att.View,
+		Deprecation: deprecation(att.Deprecation),
+	}
+	if att.Type != nil {
+		ad.TypeDocument = s.dataType(att.Type)
+	}
+	if att.HasCustomExample() {
+		ad.Example = jsonValue(att.Example)
+		ad.NoExample = att.Example == nil
+	} else if att.Example != nil {
+		ad.Example = jsonValue(att.Example)
+		ad.GeneratedExample = true
+	}
+	for n, nz := range att.NonZeroAttributes {
+		if nz {
+			ad.NonZero = append(ad.NonZero, n)
+		}
+	}
+	sort.Strings(ad.NonZero)
+	if att.Reference != nil {
+		ad.Reference = s.dataType(att.Reference)
+	}
+	for _, b := range att.Bases {
+		ad.Bases = append(ad.Bases, s.dataType(b))
+	}
+	return ad
+}
+
+// dataType produces the document of the given data type and records the documents of the user
+// types and media types it uses.
+func (s *serializer) dataType(dt design.DataType) *TypeDocument {
+	switch t := dt.(type) {
+	case design.Primitive:
+		return &TypeDocument{Type: primitiveNames[t.Kind()]}
+	case *design.Array:
+		return &TypeDocument{Type: "array", Elem: s.attribute(t.ElemType)}
+	case *design.Hash:
+		return &TypeDocument{Type: "hash", Key: s.attribute(t.KeyType), Elem: s.attribute(t.ElemType)}
+	case design.Object:
+		td := &TypeDocument{Type: "object", Attributes: make(map[string]*AttributeDocument, len(t))}
+		for n, child := range t {
+			td.Attributes[n] = s.attribute(child)
+		}
+		return td
+	case *design.Union:
+		td := &TypeDocument{Type: "union", Discriminator: t.Discriminator}
+		for _, v := range t.Variants {
+			s.addUserType(v.Type)
+			td.Variants = append(td.Variants, &VariantDocument{Value: v.Value, Type: v.Type.TypeName})
+		}
+		return td
+	case *design.UserTypeDefinition:
+		s.addUserType(t)
+		return &TypeDocument{Type: "user_type", Ref: t.TypeName}
+	case *design.MediaTypeDefinition:
+		s.addMediaType(t)
+		return &TypeDocument{Type: "media_type", Ref: t.Identifier}
+	default:
+		panic(fmt.Sprintf("unknown data type %T", dt)) // bug
+	}
+}
+
+// validation produces the document of the given validation or nil if there is none.
+func validation(v *dslengine.ValidationDefinition) *ValidationDocument {
+	if v == nil {
+		return nil
+	}
+	vd := &ValidationDocument{
+		Format:           v.Format,
+		Pattern:          v.Pattern,
+		Minimum:          v.Minimum,
+		Maximum:          v.Maximum,
+		ExclusiveMinimum: v.ExclusiveMinimum,
+		ExclusiveMaximum: v.ExclusiveMaximum,
+		MultipleOf:       v.MultipleOf,
+		MinLength:        v.MinLength,
+		MaxLength:        v.MaxLength,
+		UniqueItems:      v.UniqueItems,
+		MinProperties:    v.MinProperties,
+		MaxProperties:    v.MaxProperties,
+		Required:         v.Required,
+	}
+	for _, val := range v.Values {
+		vd.Values = append(vd.Values, jsonValue(val))
+	}
+	return vd
+}
+
+// encodings produces the documents of the given encoders or decoders.
+func encodings(encs []*design.EncodingDefinition) []*EncodingDocument {
+	var res []*EncodingDocument
+	for _, e := range encs {
+		res = append(res, &EncodingDocument{
+			MIMETypes:   e.MIMETypes,
+			PackagePath: e.PackagePath,
+			Function:    e.Function,
+		})
+	}
+	return res
+}
+
+// origins produces the documents of the given CORS policies.
+func origins(policies map[string]*design.CORSDefinition) map[string]*CORSDocument {
+	if len(policies) == 0 {
+		return nil
+	}
+	res := make(map[string]*CORSDocument, len(policies))
+	for o, p := range policies {
+		res[o] = &CORSDocument{
+			Headers:     p.Headers,
+			Methods:     p.Methods,
+			Exposed:     p.Exposed,
+			MaxAge:      p.MaxAge,
+			Credentials: p.Credentials,
+		}
+	}
+	return res
+}
+
+// security produces the document of the given security requirement.
+func security(sec *design.SecurityDefinition) *SecurityDocument {
+	if sec == nil || sec.Scheme == nil {
+		return nil
+	}
+	if sec.Scheme.Kind == design.NoSecurityKind {
+		return &SecurityDocument{NoSecurity: true}
+	}
+	return &SecurityDocument{Scheme: sec.Scheme.SchemeName, Scopes: sec.Scopes}
+}
+
+// rateLimit produces the document of the given rate limit.
+func rateLimit(rl *design.RateLimitDefinition) *RateLimitDocument {
+	if rl == nil {
+		return nil
+	}
+	return &RateLimitDocument{Limit: rl.Limit, Period: rl.Period.String(), Scope: rl.Scope}
+}
+
+// deprecation produces the document of the given deprecation.
+func deprecation(d *design.DeprecationDefinition) *DeprecationDocument {
+	if d == nil {
+		return nil
+	}
+	dd := &DeprecationDocument{}
+	if !d.Sunset.IsZero() {
+		dd.Sunset = d.Sunset.Format(time.RFC3339)
+	}
+	return dd
+}
+
+// jsonValue converts the given default, example or enum value so that it can be serialized to
+// JSON: hash values are converted into maps indexed by strings.
+func jsonValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			m[fmt.Sprintf("%v", k)] = jsonValue(val)
+		}
+		return m
+	case design.HashVal:
+		return jsonValue(map[interface{}]interface{}(actual))
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			m[k] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(actual))
+		for i, val := range actual {
+			s[i] = jsonValue(val)
+		}
+		return s
+	case design.ArrayVal:
+		return jsonValue([]interface{}(actual))
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package gendiff
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+var (
+	// BaseDesign is the import path of the design package or the path to the snapshot file
+	// that describes the previous version of the design.
+	BaseDesign string
+
+	// SnapshotFile is the path to the file the snapshot of the design is written to.
+	SnapshotFile string
+)
+
+// Command is the goa design diff command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("diff", "Report the changes made to the design and detect breaking changes")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().StringVar(&BaseDesign, "base", "", "import path of the design package or path to the snapshot file of the previous design version")
+	r.Flags().StringVar(&SnapshotFile, "snapshot", "", "path to the file the snapshot of the design is written to")
+}
+
+// Run compiles and runs the generator once per design to produce their snapshots, compares them
+// and reports the changes. It returns an error if any breaking change is found.
+func (c *Command) Run() ([]string, error) {
+	if BaseDesign == "" && SnapshotFile == "" {
+		return nil, fmt.Errorf("missing base design or snapshot file specification")
+	}
+	current, err := snapshotDesign(codegen.DesignPackagePath)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	if SnapshotFile != "" {
+		if err := current.WriteSnapshot(SnapshotFile); err != nil {
+			return nil, err
+		}
+		files = append(files, SnapshotFile)
+	}
+	if BaseDesign == "" {
+		return files, nil
+	}
+	var base *Snapshot
+	if info, err := os.Stat(BaseDesign); err == nil && !info.IsDir() {
+		base, err = LoadSnapshot(BaseDesign)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		base, err = snapshotDesign(BaseDesign)
+		if err != nil {
+			return nil, err
+		}
+	}
+	changes := Compare(base, current)
+	Report(os.Stdout, changes)
+	if n := Breaking(changes); n > 0 {
+		return nil, fmt.Errorf("%d breaking change(s) found", n)
+	}
+	return files, nil
+}
+
+// snapshotDesign runs the meta generator on the given design package to produce its snapshot.
+func snapshotDesign(designPath string) (*Snapshot, error) {
+	tmp, err := ioutil.TempFile("", "goagen-diff")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	defer func(path string) { codegen.DesignPackagePath = path }(codegen.DesignPackagePath)
+	codegen.DesignPackagePath = designPath
+	gen := meta.NewGenerator(
+		"gendiff.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_diff")},
+		map[string]string{"snapshot": tmp.Name()},
+	)
+	if _, err := gen.Generate(); err != nil {
+		return nil, err
+	}
+	return LoadSnapshot(tmp.Name())
+}
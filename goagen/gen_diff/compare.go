@@ -0,0 +1,385 @@
+package gendiff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/goadesign/goa/dslengine"
+)
+
+// Change describes a difference between two versions of a design.
+type Change struct {
+	// Breaking is true if the change may break existing clients.
+	Breaking bool `json:"breaking"`
+	// Context identifies the part of the design that changed, e.g. "bottle#show params.id".
+	Context string `json:"context"`
+	// Message describes the change.
+	Message string `json:"message"`
+}
+
+// String returns a human readable representation of the change.
+func (c *Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	return fmt.Sprintf("[%s] %s: %s", kind, c.Context, c.Message)
+}
+
+// differ computes the changes between two snapshots.
+type differ struct {
+	old, new *Snapshot
+	changes  []*Change
+	// visited records the user types already compared in request and response contexts.
+	visited map[string]bool
+}
+
+// Compare returns the changes made to the design described by old to produce the design described
+// by new. The changes are classified as breaking if they may break existing clients: removed
+// resources, actions, routes, parameters and responses, new required request attributes, narrowed
+// request validations, removed or optional response attributes and changed types.
+func Compare(old, new *Snapshot) []*Change {
+	d := &differ{old: old, new: new, visited: make(map[string]bool)}
+	for _, n := range keys(old.Resources, new.Resources) {
+		or, nr := old.Resources[n], new.Resources[n]
+		switch {
+		case nr == nil:
+			d.report(true, fmt.Sprintf("resource %#v", n), "resource removed")
+		case or == nil:
+			d.report(false, fmt.Sprintf("resource %#v", n), "resource added")
+		default:
+			d.compareResource(n, or, nr)
+		}
+	}
+	return d.changes
+}
+
+// Breaking returns the number of breaking changes in the given list.
+func Breaking(changes []*Change) int {
+	count := 0
+	for _, c := range changes {
+		if c.Breaking {
+			count++
+		}
+	}
+	return count
+}
+
+// Report writes a human readable report of the given changes to w.
+func Report(w io.Writer, changes []*Change) {
+	for _, c := range changes {
+		fmt.Fprintln(w, c.String())
+	}
+	breaking := Breaking(changes)
+	fmt.Fprintf(w, "%d breaking change(s), %d non-breaking change(s)\n", breaking, len(changes)-breaking)
+}
+
+// compareResource records the changes made to the given resource.
+func (d *differ) compareResource(name string, old, new *ResourceSnapshot) {
+	var names []string
+	for n := range old.Actions {
+		names = append(names, n)
+	}
+	for n := range new.Actions {
+		if _, ok := old.Actions[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		oa, na := old.Actions[n], new.Actions[n]
+		ctx := name + "#" + n
+		switch {
+		case na == nil:
+			d.report(true, ctx, "action removed")
+		case oa == nil:
+			d.report(false, ctx, "action added")
+		default:
+			d.compareAction(ctx, oa, na)
+		}
+	}
+}
+
+// compareAction records the changes made to the given action.
+func (d *differ) compareAction(ctx string, old, new *ActionSnapshot) {
+	for _, r := range old.Routes {
+		if !contains(new.Routes, r) {
+			d.report(true, ctx, fmt.Sprintf("route %#v removed", r))
+		}
+	}
+	for _, r := range new.Routes {
+		if !contains(old.Routes, r) {
+			d.report(false, ctx, fmt.Sprintf("route %#v added", r))
+		}
+	}
+	d.compareRequest(ctx+" params", old.Params, new.Params)
+	d.compareRequest(ctx+" headers", old.Headers, new.Headers)
+	switch {
+	case old.Payload == nil && new.Payload != nil:
+		d.report(true, ctx, "payload added")
+	case old.Payload != nil && new.Payload == nil:
+		d.report(false, ctx, "payload removed")
+	default:
+		d.compareAttribute(ctx+" payload", old.Payload, new.Payload, true)
+	}
+	var names []string
+	for n := range old.Responses {
+		names = append(names, n)
+	}
+	for n := range new.Responses {
+		if _, ok := old.Responses[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		or, nr := old.Responses[n], new.Responses[n]
+		rctx := fmt.Sprintf("%s response %#v", ctx, n)
+		switch {
+		case nr == nil:
+			d.report(true, rctx, "response removed")
+		case or == nil:
+			d.report(false, rctx, "response added")
+		case or.Status != nr.Status:
+			d.report(true, rctx, fmt.Sprintf("status changed from %d to %d", or.Status, nr.Status))
+		case or.MediaType != nr.MediaType:
+			d.report(true, rctx, fmt.Sprintf("media type changed from %#v to %#v", or.MediaType, nr.MediaType))
+		case or.Body == nil || nr.Body == nil:
+			if or.Body != nil || nr.Body != nil {
+				d.report(true, rctx, "body changed")
+			}
+		default:
+			d.compareAttribute(rctx+" body", or.Body, nr.Body, false)
+		}
+	}
+}
+
+// compareRequest records the changes made to the given request parameters or headers.
+func (d *differ) compareRequest(ctx string, old, new *AttributeSnapshot) {
+	if old == nil {
+		old = &AttributeSnapshot{Type: "object"}
+	}
+	if new == nil {
+		new = &AttributeSnapshot{Type: "object"}
+	}
+	d.compareAttribute(ctx, old, new, true)
+}
+
+// compareAttribute records the changes made to the given attribute. request indicates whether
+// the attribute describes request or response data, narrowing request data breaks clients while
+// widening response data does.
+func (d *differ) compareAttribute(ctx string, old, new *AttributeSnapshot, request bool) {
+	if old == nil || new == nil {
+		return
+	}
+	if old.Type != new.Type {
+		d.report(true, ctx, fmt.Sprintf("type changed from %s to %s", old.Type, new.Type))
+		return
+	}
+	d.compareValidations(ctx, old.Validation, new.Validation, request)
+	switch old.Type {
+	case "object":
+		d.compareObject(ctx, old, new, request)
+	case "array":
+		d.compareAttribute(ctx+"[*]", old.Elem, new.Elem, request)
+	case "hash":
+		d.compareAttribute(ctx+"[key]", old.Key, new.Key, request)
+		d.compareAttribute(ctx+"[*]", old.Elem, new.Elem, request)
+	default:
+		ot, nt := d.old.Types[old.Type], d.new.Types[new.Type]
+		if ot == nil || nt == nil {
+			return
+		}
+		key := fmt.Sprintf("%s:%v", old.Type, request)
+		if d.visited[key] {
+			return
+		}
+		d.visited[key] = true
+		d.compareAttribute("type "+old.Type, ot, nt, request)
+	}
+}
+
+// compareObject records the changes made to the child attributes of the given object attributes.
+func (d *differ) compareObject(ctx string, old, new *AttributeSnapshot, request bool) {
+	var names []string
+	for n := range old.Attributes {
+		names = append(names, n)
+	}
+	for n := range new.Attributes {
+		if _, ok := old.Attributes[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		oa, na := old.Attributes[n], new.Attributes[n]
+		actx := ctx + "." + n
+		oreq, nreq := contains(old.Required, n), contains(new.Required, n)
+		switch {
+		case na == nil:
+			d.report(!request, actx, "attribute removed")
+		case oa == nil:
+			if nreq {
+				d.report(request, actx, "required attribute added")
+			} else {
+				d.report(false, actx, "attribute added")
+			}
+		default:
+			if !oreq && nreq {
+				d.report(request, actx, "attribute is now required")
+			} else if oreq && !nreq {
+				d.report(!request, actx, "attribute is no longer required")
+			}
+			d.compareAttribute(actx, oa, na, request)
+		}
+	}
+}
+
+// compareValidations records the changes made to the given validations.
+func (d *differ) compareValidations(ctx string, old, new *dslengine.ValidationDefinition, request bool) {
+	if old == nil {
+		old = &dslengine.ValidationDefinition{}
+	}
+	if new == nil {
+		new = &dslengine.ValidationDefinition{}
+	}
+	// narrowed records a change that restricts the set of valid values, widened one that
+	// extends it.
+	narrowed := func(msg string) { d.report(request, ctx, msg) }
+	widened := func(msg string) { d.report(!request, ctx, msg) }
+
+	switch {
+	case len(old.Values) == 0 && len(new.Values) > 0:
+		narrowed("enum validation added")
+	case len(old.Values) > 0 && len(new.Values) == 0:
+		widened("enum validation removed")
+	default:
+		for _, v := range old.Values {
+			if !containsValue(new.Values, v) {
+				narrowed(fmt.Sprintf("enum value %#v removed", v))
+			}
+		}
+		for _, v := range new.Values {
+			if !containsValue(old.Values, v) {
+				widened(fmt.Sprintf("enum value %#v added", v))
+			}
+		}
+	}
+	d.compareString(ctx, "format", old.Format, new.Format, narrowed, widened)
+	d.compareString(ctx, "pattern", old.Pattern, new.Pattern, narrowed, widened)
+	d.compareBound(ctx, "minimum", old.Minimum, new.Minimum, true, narrowed, widened)
+	d.compareBound(ctx, "maximum", old.Maximum, new.Maximum, false, narrowed, widened)
+	d.compareBound(ctx, "minimum length", intBound(old.MinLength), intBound(new.MinLength), true, narrowed, widened)
+	d.compareBound(ctx, "maximum length", intBound(old.MaxLength), intBound(new.MaxLength), false, narrowed, widened)
+	d.compareBound(ctx, "minimum properties", intBound(old.MinProperties), intBound(new.MinProperties), true, narrowed, widened)
+	d.compareBound(ctx, "maximum properties", intBound(old.MaxProperties), intBound(new.MaxProperties), false, narrowed, widened)
+	if old.Minimum != nil && new.Minimum != nil && *old.Minimum == *new.Minimum && old.ExclusiveMinimum != new.ExclusiveMinimum {
+		if new.ExclusiveMinimum {
+			narrowed("minimum is now exclusive")
+		} else {
+			widened("minimum is now inclusive")
+		}
+	}
+	if old.Maximum != nil && new.Maximum != nil && *old.Maximum == *new.Maximum && old.ExclusiveMaximum != new.ExclusiveMaximum {
+		if new.ExclusiveMaximum {
+			narrowed("maximum is now exclusive")
+		} else {
+			widened("maximum is now inclusive")
+		}
+	}
+	switch {
+	case old.MultipleOf == nil && new.MultipleOf != nil:
+		narrowed(fmt.Sprintf("multiple of %v validation added", *new.MultipleOf))
+	case old.MultipleOf != nil && new.MultipleOf == nil:
+		widened("multiple of validation removed")
+	case old.MultipleOf != nil && *old.MultipleOf != *new.MultipleOf:
+		narrowed(fmt.Sprintf("multiple of validation changed from %v to %v", *old.MultipleOf, *new.MultipleOf))
+	}
+	if !old.UniqueItems && new.UniqueItems {
+		narrowed("unique items validation added")
+	} else if old.UniqueItems && !new.UniqueItems {
+		widened("unique items validation removed")
+	}
+}
+
+// compareString records the changes made to a format or pattern validation.
+func (d *differ) compareString(ctx, name, old, new string, narrowed, widened func(string)) {
+	switch {
+	case old == new:
+	case old == "":
+		narrowed(fmt.Sprintf("%s validation %#v added", name, new))
+	case new == "":
+		widened(fmt.Sprintf("%s validation %#v removed", name, old))
+	default:
+		narrowed(fmt.Sprintf("%s validation changed from %#v to %#v", name, old, new))
+	}
+}
+
+// compareBound records the changes made to a minimum or maximum validation. lower is true if the
+// validation defines a lower bound.
+func (d *differ) compareBound(ctx, name string, old, new *float64, lower bool, narrowed, widened func(string)) {
+	switch {
+	case old == nil && new == nil:
+	case old == nil:
+		narrowed(fmt.Sprintf("%s validation %v added", name, *new))
+	case new == nil:
+		widened(fmt.Sprintf("%s validation %v removed", name, *old))
+	case *old == *new:
+	case (*new > *old) == lower:
+		narrowed(fmt.Sprintf("%s changed from %v to %v", name, *old, *new))
+	default:
+		widened(fmt.Sprintf("%s changed from %v to %v", name, *old, *new))
+	}
+}
+
+// report records a change.
+func (d *differ) report(breaking bool, ctx, msg string) {
+	d.changes = append(d.changes, &Change{Breaking: breaking, Context: ctx, Message: msg})
+}
+
+// keys returns the sorted union of the names of the given resources.
+func keys(old, new map[string]*ResourceSnapshot) []string {
+	var names []string
+	for n := range old {
+		names = append(names, n)
+	}
+	for n := range new {
+		if _, ok := old[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// intBound converts an integer validation value so that it can be compared with compareBound.
+func intBound(v *int) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+// contains returns true if vals contains val.
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// containsValue returns true if vals contains a value that has the same representation as val.
+// Values are compared using their representations as snapshots read from JSON use float64 for
+// all numbers.
+func containsValue(vals []interface{}, val interface{}) bool {
+	for _, v := range vals {
+		if fmt.Sprint(v) == fmt.Sprint(val) {
+			return true
+		}
+	}
+	return false
+}
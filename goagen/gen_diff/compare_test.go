@@ -0,0 +1,198 @@
+package gendiff_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_diff"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// snapshot runs the given DSL and returns the snapshot of the resulting design.
+func snapshot(dsl func()) *gendiff.Snapshot {
+	dslengine.Reset()
+	API("cellar", nil)
+	dsl()
+	Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+	return gendiff.NewSnapshot(Design)
+}
+
+// bottleDesign returns the DSL of a design whose DSL may be altered by the given function.
+func bottleDesign(payloadDSL, actionDSL func()) func() {
+	return func() {
+		BottleMedia := MediaType("application/vnd.bottle", func() {
+			Attributes(func() {
+				Attribute("id", Integer)
+				Attribute("name", String)
+				Required("id", "name")
+			})
+			View("default", func() {
+				Attribute("id")
+				Attribute("name")
+			})
+		})
+		Resource("bottle", func() {
+			BasePath("/bottles")
+			Action("show", func() {
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer)
+				})
+				Response(OK, BottleMedia)
+				Response(NotFound)
+			})
+			Action("create", func() {
+				Routing(POST(""))
+				Payload(func() {
+					Attribute("name", String, func() {
+						MinLength(1)
+					})
+					Attribute("vintage", Integer)
+					Required("name")
+					if payloadDSL != nil {
+						payloadDSL()
+					}
+				})
+				Response(Created)
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+		})
+	}
+}
+
+var _ = Describe("Compare", func() {
+	var old, new *gendiff.Snapshot
+	var payloadDSL, actionDSL func()
+	var changes []*gendiff.Change
+
+	BeforeEach(func() {
+		old = snapshot(bottleDesign(nil, nil))
+		payloadDSL = nil
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		new = snapshot(bottleDesign(payloadDSL, actionDSL))
+		changes = gendiff.Compare(old, new)
+	})
+
+	Context("with identical designs", func() {
+		It("does not report any change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+
+	Context("with a new required payload attribute", func() {
+		BeforeEach(func() {
+			payloadDSL = func() {
+				Attribute("color", String)
+				Required("color")
+			}
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Breaking).Should(BeTrue())
+			Ω(changes[0].String()).Should(Equal("[breaking] type CreateBottlePayload.color: required attribute added"))
+		})
+	})
+
+	Context("with a new optional payload attribute", func() {
+		BeforeEach(func() {
+			payloadDSL = func() {
+				Attribute("color", String)
+			}
+		})
+
+		It("reports a non-breaking change", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].Breaking).Should(BeFalse())
+			Ω(gendiff.Breaking(changes)).Should(Equal(0))
+		})
+	})
+
+	Context("with a narrowed validation", func() {
+		BeforeEach(func() {
+			payloadDSL = func() {
+				Attribute("vintage", Integer, func() {
+					Minimum(1900)
+				})
+			}
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].String()).Should(Equal("[breaking] type CreateBottlePayload.vintage: minimum validation 1900 added"))
+		})
+	})
+
+	Context("with new routes and responses", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Routing(PUT(""))
+				Response(BadRequest)
+			}
+		})
+
+		It("reports non-breaking changes", func() {
+			Ω(changes).Should(HaveLen(2))
+			Ω(changes[0].String()).Should(Equal(`[non-breaking] bottle#create: route "PUT /bottles" added`))
+			Ω(changes[1].String()).Should(Equal(`[non-breaking] bottle#create response "BadRequest": response added`))
+		})
+	})
+
+	Context("with removed routes and responses", func() {
+		BeforeEach(func() {
+			old = snapshot(bottleDesign(nil, func() {
+				Routing(PUT(""))
+				Response(BadRequest)
+			}))
+		})
+
+		It("reports breaking changes", func() {
+			Ω(changes).Should(HaveLen(2))
+			Ω(gendiff.Breaking(changes)).Should(Equal(2))
+		})
+	})
+
+	Context("with a removed resource", func() {
+		JustBeforeEach(func() {
+			new = snapshot(func() {})
+			changes = gendiff.Compare(old, new)
+		})
+
+		It("reports a breaking change", func() {
+			Ω(changes).Should(HaveLen(1))
+			Ω(changes[0].String()).Should(Equal(`[breaking] resource "bottle": resource removed`))
+		})
+	})
+
+	Context("with a snapshot read from a file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "gendiff")
+			Ω(err).ShouldNot(HaveOccurred())
+			path := filepath.Join(dir, "design.json")
+			Ω(old.WriteSnapshot(path)).ShouldNot(HaveOccurred())
+			old, err = gendiff.LoadSnapshot(path)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("does not report any change", func() {
+			Ω(changes).Should(BeEmpty())
+		})
+	})
+})
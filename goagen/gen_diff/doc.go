@@ -0,0 +1,10 @@
+/*
+Package gendiff provides a generator that detects the changes made to a design.
+The generator compares the design with a previous version of itself given either as a design
+package or as a snapshot file written by a previous invocation and reports the added and removed
+resources, actions, routes, parameters, attributes and responses as well as the changes made to
+validations and types. Each change is classified as breaking or non-breaking, the command exits with
+a non-zero status if any breaking change is found so that it can be used in continuous integration
+pipelines.
+*/
+package gendiff
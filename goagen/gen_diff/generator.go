@@ -0,0 +1,27 @@
+package gendiff
+
+import (
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/spf13/cobra"
+)
+
+// Generate is the generator entry point called by the meta generator. It writes the snapshot of
+// the design to the file given with the --snapshot flag.
+func Generate() (files []string, err error) {
+	api := design.Design
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "Design snapshot generator",
+		Long:  "Design snapshot generator",
+		Run: func(*cobra.Command, []string) {
+			if err = NewSnapshot(api).WriteSnapshot(SnapshotFile); err == nil {
+				files = []string{SnapshotFile}
+			}
+		},
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
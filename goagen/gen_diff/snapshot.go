@@ -0,0 +1,214 @@
+package gendiff
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// SnapshotVersion is the version of the snapshot format, it changes whenever the format changes in
+// an incompatible way.
+const SnapshotVersion = 1
+
+type (
+	// Snapshot describes the parts of a design that make up the API contract: the resources,
+	// their actions and the types used by the action requests and responses. Snapshots are
+	// serialized to JSON so that designs can be compared with previous versions of themselves.
+	Snapshot struct {
+		// Version is the snapshot format version.
+		Version int `json:"version"`
+		// Name is the API name.
+		Name string `json:"name"`
+		// Resources lists the API resources indexed by name.
+		Resources map[string]*ResourceSnapshot `json:"resources,omitempty"`
+		// Types lists the user types and media types used by the actions indexed by type
+		// name.
+		Types map[string]*AttributeSnapshot `json:"types,omitempty"`
+	}
+
+	// ResourceSnapshot describes a resource.
+	ResourceSnapshot struct {
+		// Actions lists the resource actions indexed by name.
+		Actions map[string]*ActionSnapshot `json:"actions,omitempty"`
+	}
+
+	// ActionSnapshot describes an action.
+	ActionSnapshot struct {
+		// Routes lists the action routes, e.g. "GET /bottles/:id".
+		Routes []string `json:"routes,omitempty"`
+		// Params describes the action path and query string parameters.
+		Params *AttributeSnapshot `json:"params,omitempty"`
+		// Headers describes the action request headers.
+		Headers *AttributeSnapshot `json:"headers,omitempty"`
+		// Payload describes the action request payload.
+		Payload *AttributeSnapshot `json:"payload,omitempty"`
+		// Responses lists the action responses indexed by name.
+		Responses map[string]*ResponseSnapshot `json:"responses,omitempty"`
+	}
+
+	// ResponseSnapshot describes an action response.
+	ResponseSnapshot struct {
+		// Status is the response HTTP status code.
+		Status int `json:"status"`
+		// MediaType is the identifier of the response media type if any.
+		MediaType string `json:"media_type,omitempty"`
+		// Body describes the response body if any.
+		Body *AttributeSnapshot `json:"body,omitempty"`
+	}
+
+	// AttributeSnapshot describes an attribute.
+	AttributeSnapshot struct {
+		// Type is the name of the attribute type: the name of a primitive type, "array",
+		// "hash", "object" or the name of a user type or media type.
+		Type string `json:"type"`
+		// Required lists the names of the required child attributes of object attributes.
+		Required []string `json:"required,omitempty"`
+		// Attributes lists the child attributes of object attributes.
+		Attributes map[string]*AttributeSnapshot `json:"attributes,omitempty"`
+		// Key describes the keys of hash attributes.
+		Key *AttributeSnapshot `json:"key,omitempty"`
+		// Elem describes the elements of array and hash attributes.
+		Elem *AttributeSnapshot `json:"elem,omitempty"`
+		// Validation describes the attribute validations other than required fields.
+		Validation *dslengine.ValidationDefinition `json:"validation,omitempty"`
+	}
+)
+
+// NewSnapshot produces the snapshot of the given API design.
+func NewSnapshot(api *design.APIDefinition) *Snapshot {
+	s := &Snapshot{
+		Version:   SnapshotVersion,
+		Name:      api.Name,
+		Resources: make(map[string]*ResourceSnapshot),
+		Types:     make(map[string]*AttributeSnapshot),
+	}
+	api.IterateResources(func(r *design.ResourceDefinition) error {
+		rs := &ResourceSnapshot{Actions: make(map[string]*ActionSnapshot)}
+		r.IterateActions(func(a *design.ActionDefinition) error {
+			rs.Actions[a.Name] = s.newActionSnapshot(api, a)
+			return nil
+		})
+		s.Resources[r.Name] = rs
+		return nil
+	})
+	return s
+}
+
+// LoadSnapshot reads the JSON representation of a snapshot from the given file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// WriteSnapshot writes the JSON representation of the snapshot to the given file.
+func (s *Snapshot) WriteSnapshot(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0644)
+}
+
+// newActionSnapshot produces the snapshot of the given action.
+func (s *Snapshot) newActionSnapshot(api *design.APIDefinition, a *design.ActionDefinition) *ActionSnapshot {
+	as := &ActionSnapshot{
+		Params:    s.newAttributeSnapshot(a.Params),
+		Headers:   s.newAttributeSnapshot(a.Headers),
+		Responses: make(map[string]*ResponseSnapshot),
+	}
+	for _, r := range a.Routes {
+		as.Routes = append(as.Routes, r.Verb+" "+r.FullPath())
+	}
+	sort.Strings(as.Routes)
+	if a.Payload != nil {
+		as.Payload = s.newAttributeSnapshot(&design.AttributeDefinition{Type: a.Payload})
+	}
+	for n, r := range a.Responses {
+		rs := &ResponseSnapshot{Status: r.Status, MediaType: r.MediaType}
+		if r.MediaType != "" {
+			if mt := api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+				rs.Body = s.newAttributeSnapshot(&design.AttributeDefinition{Type: mt})
+			}
+		} else if r.Type != nil {
+			rs.Body = s.newAttributeSnapshot(&design.AttributeDefinition{Type: r.Type})
+		}
+		as.Responses[n] = rs
+	}
+	return as
+}
+
+// newAttributeSnapshot produces the snapshot of the given attribute and records the snapshots of
+// the user types it uses.
+func (s *Snapshot) newAttributeSnapshot(att *design.AttributeDefinition) *AttributeSnapshot {
+	if att == nil || att.Type == nil {
+		return nil
+	}
+	as := &AttributeSnapshot{Validation: validationSnapshot(att.Validation)}
+	switch t := att.Type.(type) {
+	case *design.MediaTypeDefinition:
+		as.Type = t.TypeName
+		s.addType(t.UserTypeDefinition)
+	case *design.UserTypeDefinition:
+		as.Type = t.TypeName
+		s.addType(t)
+	case *design.Array:
+		as.Type = "array"
+		as.Elem = s.newAttributeSnapshot(t.ElemType)
+	case *design.Hash:
+		as.Type = "hash"
+		as.Key = s.newAttributeSnapshot(t.KeyType)
+		as.Elem = s.newAttributeSnapshot(t.ElemType)
+	case design.Object:
+		as.Type = "object"
+		as.Attributes = make(map[string]*AttributeSnapshot, len(t))
+		for n, child := range t {
+			as.Attributes[n] = s.newAttributeSnapshot(child)
+		}
+		if att.Validation != nil {
+			as.Required = append(as.Required, att.Validation.Required...)
+			sort.Strings(as.Required)
+		}
+	default:
+		as.Type = t.Name()
+	}
+	return as
+}
+
+// addType records the snapshot of the given user type if not already recorded.
+func (s *Snapshot) addType(ut *design.UserTypeDefinition) {
+	if _, ok := s.Types[ut.TypeName]; ok {
+		return
+	}
+	// Record the type before producing its snapshot to handle recursive types.
+	ts := &AttributeSnapshot{}
+	s.Types[ut.TypeName] = ts
+	if as := s.newAttributeSnapshot(ut.AttributeDefinition); as != nil {
+		*ts = *as
+	}
+}
+
+// validationSnapshot returns a copy of the given validation without the required fields or nil
+// if there is no validation left.
+func validationSnapshot(v *dslengine.ValidationDefinition) *dslengine.ValidationDefinition {
+	if v == nil {
+		return nil
+	}
+	cp := *v
+	cp.Required = nil
+	if cp.Values == nil && cp.Format == "" && cp.Pattern == "" && cp.Minimum == nil &&
+		cp.Maximum == nil && cp.MultipleOf == nil && cp.MinLength == nil && cp.MaxLength == nil &&
+		!cp.UniqueItems && cp.MinProperties == nil && cp.MaxProperties == nil {
+		return nil
+	}
+	return &cp
+}
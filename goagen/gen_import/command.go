@@ -0,0 +1,72 @@
+package genimport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+var (
+	// SpecFile is the path to the API specification file.
+	SpecFile string
+
+	// Package is the name of the generated design package.
+	Package string
+)
+
+// Command is the goa design import command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("import", "Generate a design package from an existing API specification (swagger)")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().StringVarP(&SpecFile, "file", "f", "", "path to the API specification file")
+	r.Flags().StringVar(&Package, "pkg", "design", "name of the generated design package")
+}
+
+// Run imports the specification and writes the design package into the "pkg" sub-directory of
+// the output directory. Warnings are written to stderr.
+func (c *Command) Run() ([]string, error) {
+	if len(codegen.ExtraFlags) != 1 || codegen.ExtraFlags[0] != "swagger" {
+		return nil, fmt.Errorf("missing or unsupported specification format, usage: goagen import swagger -f FILE")
+	}
+	if SpecFile == "" {
+		return nil, fmt.Errorf("missing specification file, use --file")
+	}
+	b, err := ioutil.ReadFile(SpecFile)
+	if err != nil {
+		return nil, err
+	}
+	s, err := LoadSwagger(b)
+	if err != nil {
+		return nil, err
+	}
+	src, warnings, err := ImportSwagger(s, Package)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(codegen.OutputDir, Package)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, "design.go")
+	if err := ioutil.WriteFile(file, src, 0644); err != nil {
+		return nil, err
+	}
+	return []string{file}, nil
+}
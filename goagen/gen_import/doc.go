@@ -0,0 +1,9 @@
+/*
+Package genimport provides a generator that produces the design package of an existing API from its
+specification. The "swagger" source reads a Swagger 2.0 document in JSON or YAML format and writes
+the corresponding API, resources, actions, types, media types and security schemes using the goa
+DSL. Definitions used in responses become media types, other object definitions become types.
+Constructs that cannot be represented with the DSL are skipped or simplified and reported as
+warnings.
+*/
+package genimport
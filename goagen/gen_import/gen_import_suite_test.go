@@ -0,0 +1,13 @@
+package genimport_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenImport(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenImport Suite")
+}
@@ -0,0 +1,130 @@
+package genimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	"gopkg.in/yaml.v2"
+)
+
+// LoadSwagger parses the given Swagger 2.0 document, either in JSON or YAML format. References to
+// global parameters and responses are replaced with the referenced definitions. Schemas that use
+// JSON schema constructs not supported by the genswagger data structures are simplified: a list
+// of types is replaced with the first type that is not "null" and an "additionalProperties" schema
+// is replaced with true, the schema itself is moved to the "items" field of the object schema so
+// that the importer may use it as the type of the hash values.
+func LoadSwagger(b []byte) (*genswagger.Swagger, error) {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		if err := yaml.Unmarshal(b, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse Swagger document: %s", err)
+		}
+		doc = jsonValue(doc)
+	}
+	root, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid Swagger document: not an object")
+	}
+	if v := fmt.Sprint(root["swagger"]); v != "2.0" && v != "2" {
+		return nil, fmt.Errorf("invalid Swagger document: unsupported version %#v, must be 2.0", v)
+	}
+	sanitize(root, root)
+
+	js, err := json.Marshal(root)
+	if err != nil {
+		return nil, err
+	}
+	dec = json.NewDecoder(bytes.NewReader(js))
+	dec.UseNumber()
+	var s genswagger.Swagger
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid Swagger document: %s", err)
+	}
+	return &s, nil
+}
+
+// jsonValue converts the maps produced by the YAML decoder into maps indexed by strings.
+func jsonValue(v interface{}) interface{} {
+	switch actual := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(actual))
+		for k, val := range actual {
+			m[fmt.Sprintf("%v", k)] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range actual {
+			actual[i] = jsonValue(val)
+		}
+	}
+	return v
+}
+
+// sanitize rewrites the given value recursively so that it can be decoded into the genswagger
+// data structures.
+func sanitize(v interface{}, root map[string]interface{}) {
+	switch actual := v.(type) {
+	case map[string]interface{}:
+		if params, ok := actual["parameters"].([]interface{}); ok {
+			for i, p := range params {
+				params[i] = resolve(p, root, "#/parameters/")
+			}
+		}
+		if resps, ok := actual["responses"].(map[string]interface{}); ok {
+			for code, r := range resps {
+				resps[code] = resolve(r, root, "#/responses/")
+			}
+		}
+		if types, ok := actual["type"].([]interface{}); ok {
+			delete(actual, "type")
+			for _, t := range types {
+				if t != "null" {
+					actual["type"] = t
+					break
+				}
+			}
+		}
+		if ap, ok := actual["additionalProperties"]; ok {
+			if _, ok := ap.(bool); !ok {
+				actual["additionalProperties"] = true
+				if _, ok := actual["items"]; !ok && actual["type"] != "array" {
+					actual["items"] = ap
+				}
+			}
+		}
+		for k, val := range actual {
+			if k == "example" || k == "default" || k == "enum" {
+				continue
+			}
+			sanitize(val, root)
+		}
+	case []interface{}:
+		for _, val := range actual {
+			sanitize(val, root)
+		}
+	}
+}
+
+// resolve returns the value referenced by v if v is a reference with the given prefix, v
+// otherwise.
+func resolve(v interface{}, root map[string]interface{}, prefix string) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	ref, ok := m["$ref"].(string)
+	if !ok || !strings.HasPrefix(ref, prefix) {
+		return v
+	}
+	section := strings.TrimSuffix(strings.TrimPrefix(prefix, "#/"), "/")
+	defs, _ := root[section].(map[string]interface{})
+	if def, ok := defs[strings.TrimPrefix(ref, prefix)]; ok {
+		return def
+	}
+	return v
+}
@@ -0,0 +1,1362 @@
+package genimport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"math"
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+)
+
+type (
+	// importer produces the design DSL of a Swagger specification.
+	importer struct {
+		swagger *genswagger.Swagger
+		buf     bytes.Buffer
+		// warnings lists the constructs that could not be imported.
+		warnings []string
+		// defs indexes the definitions by Swagger definition name.
+		defs map[string]*definition
+		// order lists the names of the definitions that produce a type or a media type.
+		order []string
+		// vars lists the Go variable names already in use.
+		vars map[string]bool
+		// refs counts the references to each definition.
+		refs map[string]int
+		// deps records the definitions each definition refers to.
+		deps map[string]map[string]bool
+		// current is the name of the definition being written if any.
+		current string
+		// inlining lists the definitions being inlined to detect recursive definitions.
+		inlining map[string]bool
+		// statuses maps HTTP status codes to the names of the goa default responses.
+		statuses map[int]string
+		// usesDesign is true if the generated code uses the design package.
+		usesDesign bool
+	}
+
+	// definition describes a Swagger definition.
+	definition struct {
+		// Name is the Swagger definition name.
+		Name string
+		// Schema is the definition schema.
+		Schema *genschema.JSONSchema
+		// Object is true if the definition produces a type or a media type, other
+		// definitions are inlined where used.
+		Object bool
+		// Media is true if the definition is used in responses and produces a media type.
+		Media bool
+		// Identifier is the media type identifier.
+		Identifier string
+		// VarName is the name of the Go variable holding the type or media type.
+		VarName string
+		// Inlined is true if the definition is inlined in the payload of the only action
+		// that uses it.
+		Inlined bool
+	}
+
+	// operation describes a Swagger operation and the action it maps to.
+	operation struct {
+		Path   string
+		Method string
+		Op     *genswagger.Operation
+		Params []*genswagger.Parameter
+	}
+
+	// action groups the operations that map to the same action.
+	action struct {
+		Name       string
+		Operations []*operation
+	}
+
+	// resource groups the actions that map to the same resource.
+	resource struct {
+		Name    string
+		Actions []*action
+	}
+)
+
+var (
+	// identifierTitle matches the title of the definitions produced by goa for media types.
+	identifierTitle = regexp.MustCompile(`^Mediatype identifier: (.+)$`)
+
+	// nonAlnum matches sequences of characters that may not appear in names.
+	nonAlnum = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+	// pathParam matches the Swagger path parameters.
+	pathParam = regexp.MustCompile(`{([^}]+)}`)
+
+	// methods lists the HTTP methods in the order operations are imported.
+	methods = []string{"GET", "PUT", "POST", "DELETE", "OPTIONS", "HEAD", "PATCH"}
+
+	// typedFormats maps the Swagger string formats to the corresponding goa primitive types.
+	typedFormats = map[string]string{
+		"date-time": "DateTime",
+		"uuid":      "UUID",
+		"date":      "Date",
+		"duration":  "Duration",
+		"byte":      "Bytes",
+		"decimal":   "Decimal",
+	}
+
+	// reserved lists the identifiers of the design and apidsl packages that could conflict
+	// with the names of the generated variables given the dot imports.
+	reserved = []string{"DataType", "DefaultMedia", "ErrorMedia", "Media", "MediaType", "Type", "UnsupportedMediaType"}
+
+	// supportedFormats lists the string formats supported by the Format DSL.
+	supportedFormats = map[string]bool{
+		"email":    true,
+		"hostname": true,
+		"ipv4":     true,
+		"ipv6":     true,
+		"ip":       true,
+		"uri":      true,
+		"mac":      true,
+		"cidr":     true,
+		"regexp":   true,
+		"rfc1123":  true,
+	}
+)
+
+// ImportSwagger produces the source code of a design package named pkg that describes the API
+// defined by the given Swagger specification. It also returns warnings that describe the parts of
+// the specification that could not be represented with the goa DSL and were skipped or
+// simplified.
+func ImportSwagger(s *genswagger.Swagger, pkg string) ([]byte, []string, error) {
+	i := &importer{
+		swagger:  s,
+		defs:     make(map[string]*definition),
+		vars:     make(map[string]bool),
+		refs:     make(map[string]int),
+		deps:     make(map[string]map[string]bool),
+		inlining: make(map[string]bool),
+		statuses: make(map[int]string),
+	}
+	for _, r := range reserved {
+		i.vars[r] = true
+	}
+	for name, r := range design.NewAPIDefinition().DefaultResponses {
+		i.statuses[r.Status] = name
+	}
+	i.analyze()
+
+	i.writeAPI()
+	i.writeSecurity()
+	i.writeResources()
+	i.writeDefinitions()
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\nimport (\n", pkg)
+	if i.usesDesign {
+		src.WriteString("\t. \"github.com/goadesign/goa/design\"\n")
+	}
+	src.WriteString("\t. \"github.com/goadesign/goa/design/apidsl\"\n)\n")
+	src.Write(i.buf.Bytes())
+	b, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, i.warnings, fmt.Errorf("failed to format generated design: %s", err)
+	}
+	return b, i.warnings, nil
+}
+
+// analyze records the definitions and identifies the ones used in responses.
+func (i *importer) analyze() {
+	names := make([]string, 0, len(i.swagger.Definitions))
+	for n := range i.swagger.Definitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		s := i.swagger.Definitions[n]
+		d := &definition{Name: n, Schema: s, Object: isObject(s)}
+		i.defs[n] = d
+		if !d.Object {
+			continue
+		}
+		i.order = append(i.order, n)
+		d.Identifier = "application/vnd." + codegen.SnakeCase(n) + "+json"
+		if m := identifierTitle.FindStringSubmatch(s.Title); m != nil {
+			d.Identifier = m[1]
+		}
+	}
+
+	markMedia := func(r *genswagger.Response) {
+		if r == nil || r.Schema == nil {
+			return
+		}
+		s := r.Schema
+		if d := i.definition(s.Ref); d != nil && !d.Object && d.Schema.Type == "array" {
+			s = d.Schema
+		}
+		if s.Type == "array" && s.Items != nil {
+			if d := i.collectionElem(s); d != nil {
+				d.Media = true
+			}
+			return
+		}
+		if d := i.definition(s.Ref); d != nil && d.Object {
+			d.Media = true
+		}
+	}
+	for _, s := range i.swagger.Definitions {
+		i.countRefs(s)
+	}
+	for _, r := range i.swagger.Responses {
+		markMedia(r)
+	}
+	for path, p := range i.swagger.Paths {
+		for m, op := range pathOperations(p) {
+			for _, r := range op.Responses {
+				markMedia(r)
+				if r != nil {
+					i.countRefs(r.Schema)
+				}
+			}
+			if _, _, merge := operationNames(path, m, op); merge {
+				// Additional routes share the payload of the action.
+				continue
+			}
+			for _, param := range mergeParams(p.Parameters, op.Parameters) {
+				i.countRefs(param.Schema)
+			}
+		}
+	}
+
+	for _, n := range i.order {
+		d := i.defs[n]
+		if d.Media {
+			if d.isErrorMedia() {
+				d.VarName = "ErrorMedia"
+				continue
+			}
+			d.VarName = i.varName(n, "Media")
+		} else {
+			d.VarName = i.varName(n, "Type")
+		}
+	}
+}
+
+// writeAPI writes the API definition.
+func (i *importer) writeAPI() {
+	s := i.swagger
+	name := "api"
+	if s.Info != nil && s.Info.Title != "" {
+		name = strings.Trim(nonAlnum.ReplaceAllString(strings.ToLower(s.Info.Title), "_"), "_")
+	}
+	i.line("var _ = API(%q, func() {", name)
+	if info := s.Info; info != nil {
+		if info.Title != "" {
+			i.line("Title(%q)", info.Title)
+		}
+		if info.Description != "" {
+			i.line("Description(%q)", info.Description)
+		}
+		if info.Version != "" {
+			i.line("Version(%q)", info.Version)
+		}
+		if info.TermsOfService != "" {
+			i.line("TermsOfService(%q)", info.TermsOfService)
+		}
+		if c := info.Contact; c != nil {
+			i.line("Contact(func() {")
+			if c.Name != "" {
+				i.line("Name(%q)", c.Name)
+			}
+			if c.Email != "" {
+				i.line("Email(%q)", c.Email)
+			}
+			if c.URL != "" {
+				i.line("URL(%q)", c.URL)
+			}
+			i.line("})")
+		}
+		if l := info.License; l != nil {
+			i.line("License(func() {")
+			if l.Name != "" {
+				i.line("Name(%q)", l.Name)
+			}
+			if l.URL != "" {
+				i.line("URL(%q)", l.URL)
+			}
+			i.line("})")
+		}
+	}
+	if s.Host != "" {
+		i.line("Host(%q)", s.Host)
+	}
+	if len(s.Schemes) > 0 {
+		i.line("Scheme(%s)", quoteAll(s.Schemes))
+	}
+	if s.BasePath != "" && s.BasePath != "/" {
+		i.line("BasePath(%q)", s.BasePath)
+	}
+	if mimes := i.encodings(s.Consumes, "consumes"); len(mimes) > 0 {
+		i.line("Consumes(%s)", quoteAll(mimes))
+	}
+	if mimes := i.encodings(s.Produces, "produces"); len(mimes) > 0 {
+		i.line("Produces(%s)", quoteAll(mimes))
+	}
+	i.line("})\n")
+}
+
+// encodings returns the MIME types that have known encoders and records a warning for the others.
+func (i *importer) encodings(mimes []string, ctx string) []string {
+	var known []string
+	for _, m := range mimes {
+		if _, ok := design.KnownEncoders[m]; ok {
+			known = append(known, m)
+		} else if m != "multipart/form-data" {
+			i.warn(ctx, "no known encoder for MIME type %#v, MIME type skipped", m)
+		}
+	}
+	return known
+}
+
+// writeSecurity writes the security scheme definitions.
+func (i *importer) writeSecurity() {
+	names := make([]string, 0, len(i.swagger.SecurityDefinitions))
+	for n := range i.swagger.SecurityDefinitions {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		sd := i.swagger.SecurityDefinitions[n]
+		ctx := fmt.Sprintf("security definition %#v", n)
+		switch sd.Type {
+		case "basic":
+			i.line("var _ = BasicAuthSecurity(%q, func() {", n)
+			i.description(sd.Description)
+		case "apiKey":
+			i.line("var _ = APIKeySecurity(%q, func() {", n)
+			i.description(sd.Description)
+			switch sd.In {
+			case "header":
+				i.line("Header(%q)", sd.Name)
+				if strings.EqualFold(sd.Name, "Authorization") {
+					// Swagger 2.0 describes JWT schemes as API keys.
+					i.warn(ctx, "API key read from the Authorization header imported with APIKeySecurity, use JWTSecurity if the key is a JSON Web Token")
+				}
+			case "query":
+				i.line("Query(%q)", sd.Name)
+			case "cookie":
+				i.line("Cookie(%q)", sd.Name)
+			default:
+				i.warn(ctx, "unsupported API key location %#v", sd.In)
+			}
+		case "oauth2":
+			i.line("var _ = OAuth2Security(%q, func() {", n)
+			i.description(sd.Description)
+			switch sd.Flow {
+			case "accessCode":
+				i.line("AccessCodeFlow(%q, %q)", sd.AuthorizationURL, sd.TokenURL)
+			case "implicit":
+				i.line("ImplicitFlow(%q)", sd.AuthorizationURL)
+			case "password":
+				i.line("PasswordFlow(%q)", sd.TokenURL)
+			case "application":
+				i.line("ApplicationFlow(%q)", sd.TokenURL)
+			default:
+				i.warn(ctx, "unsupported OAuth2 flow %#v", sd.Flow)
+			}
+			scopes := make([]string, 0, len(sd.Scopes))
+			for s := range sd.Scopes {
+				scopes = append(scopes, s)
+			}
+			sort.Strings(scopes)
+			for _, s := range scopes {
+				i.line("Scope(%q, %q)", s, sd.Scopes[s])
+			}
+		default:
+			i.warn(ctx, "unsupported security scheme type %#v, security definition skipped", sd.Type)
+			continue
+		}
+		i.line("})\n")
+	}
+}
+
+// writeResources writes the resource definitions.
+func (i *importer) writeResources() {
+	for _, r := range i.resources() {
+		i.line("var _ = Resource(%q, func() {", r.Name)
+		for _, t := range i.swagger.Tags {
+			if t.Name == r.Name {
+				i.description(t.Description)
+			}
+		}
+		for _, a := range r.Actions {
+			i.writeAction(r, a)
+		}
+		i.line("})\n")
+	}
+}
+
+// resources groups the Swagger operations into resources and actions. Operation identifiers of
+// the form "resource#action" produced by goa determine the resource and action names, other
+// operations are grouped using their first tag or the first segment of their path.
+func (i *importer) resources() []*resource {
+	paths := make([]string, 0, len(i.swagger.Paths))
+	for p := range i.swagger.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	resources := make(map[string]*resource)
+	actions := make(map[string]*action)
+	for _, p := range paths {
+		path := i.swagger.Paths[p]
+		if path.Ref != "" {
+			i.warn(fmt.Sprintf("path %#v", p), "external path definition %#v skipped", path.Ref)
+			continue
+		}
+		ops := pathOperations(path)
+		for _, m := range methods {
+			op, ok := ops[m]
+			if !ok {
+				continue
+			}
+			rname, aname, merge := operationNames(p, m, op)
+			r, ok := resources[rname]
+			if !ok {
+				r = &resource{Name: rname}
+				resources[rname] = r
+			}
+			key := rname + "#" + aname
+			a, ok := actions[key]
+			if ok && !merge {
+				for n := 2; ok; n++ {
+					key = fmt.Sprintf("%s#%s_%d", rname, aname, n)
+					_, ok = actions[key]
+				}
+				a = nil
+				aname = strings.SplitN(key, "#", 2)[1]
+			}
+			if a == nil {
+				a = &action{Name: aname}
+				actions[key] = a
+				r.Actions = append(r.Actions, a)
+			}
+			a.Operations = append(a.Operations, &operation{
+				Path:   p,
+				Method: m,
+				Op:     op,
+				Params: mergeParams(path.Parameters, op.Parameters),
+			})
+		}
+	}
+
+	names := make([]string, 0, len(resources))
+	for n := range resources {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	res := make([]*resource, len(names))
+	for j, n := range names {
+		res[j] = resources[n]
+	}
+	return res
+}
+
+// writeAction writes the definition of the given action. The first operation defines the action
+// parameters, payload and responses, the other operations only add routes.
+func (i *importer) writeAction(r *resource, a *action) {
+	first := a.Operations[0]
+	op := first.Op
+	ctx := fmt.Sprintf("%s %s", first.Method, first.Path)
+	i.line("Action(%q, func() {", a.Name)
+	if op.Description != "" {
+		i.description(op.Description)
+	} else if op.Summary != "" && op.Summary != a.Name {
+		i.description(op.Summary)
+	}
+	routes := make([]string, len(a.Operations))
+	for j, o := range a.Operations {
+		routes[j] = fmt.Sprintf("%s(%q)", o.Method, pathParam.ReplaceAllString(o.Path, ":$1"))
+	}
+	i.line("Routing(%s)", strings.Join(routes, ", "))
+	if op.Deprecated {
+		i.line("Deprecated()")
+	}
+
+	var params, headers, form []*genswagger.Parameter
+	var body *genswagger.Parameter
+	for _, p := range first.Params {
+		switch p.In {
+		case "path", "query":
+			params = append(params, p)
+		case "header":
+			headers = append(headers, p)
+		case "formData":
+			form = append(form, p)
+		case "body":
+			body = p
+		default:
+			i.warn(ctx, "parameter %#v in %#v skipped", p.Name, p.In)
+		}
+	}
+	i.writeParams("Params", "Param", params, ctx+" params")
+	i.writeParams("Headers", "Header", headers, ctx+" headers")
+	payload := codegen.Goify(a.Name, true) + codegen.Goify(r.Name, true) + "Payload"
+	i.writePayload(body, form, op, payload, ctx+" payload")
+	for _, m := range op.Consumes {
+		if m != "multipart/form-data" && !contains(i.swagger.Consumes, m) {
+			i.warn(ctx, "operation specific MIME type %#v skipped", m)
+		}
+	}
+	for _, m := range op.Produces {
+		if !contains(i.swagger.Produces, m) {
+			i.warn(ctx, "operation specific MIME type %#v skipped", m)
+		}
+	}
+	i.writeSecurityRequirement(op.Security, ctx)
+	i.writeResponses(op.Responses, ctx)
+	i.line("})")
+}
+
+// writeParams writes the Params or Headers DSL for the given parameters.
+func (i *importer) writeParams(dsl, fn string, params []*genswagger.Parameter, ctx string) {
+	if len(params) == 0 {
+		return
+	}
+	i.line("%s(func() {", dsl)
+	var required []string
+	for _, p := range params {
+		i.attribute(fn, p.Name, paramSchema(p), ctx)
+		if p.Required && p.In != "path" {
+			required = append(required, p.Name)
+		}
+	}
+	if len(required) > 0 {
+		i.line("Required(%s)", quoteAll(required))
+	}
+	i.line("})")
+}
+
+// writePayload writes the Payload DSL for the given body or form parameters. payload is the name
+// of the payload type generated by goa for the action. Definitions with the same name used only by
+// the action are inlined to avoid conflicts with the generated type, this is the case of the
+// payload definitions produced by goa.
+func (i *importer) writePayload(body *genswagger.Parameter, form []*genswagger.Parameter, op *genswagger.Operation, payload, ctx string) {
+	if body != nil && body.Schema != nil {
+		if len(form) > 0 {
+			i.warn(ctx, "form parameters skipped, the operation also defines a body parameter")
+		}
+		s := body.Schema
+		if d := i.definition(s.Ref); d != nil && d.Object && !d.Media && i.refs[d.Name] == 1 &&
+			codegen.Goify(d.Name, true) == payload {
+			d.Inlined = true
+			s = d.Schema
+		}
+		t, obj := i.typeOf(s, ctx, true)
+		if obj == nil {
+			i.line("Payload(%s)", t)
+			return
+		}
+		i.line("Payload(func() {")
+		i.description(s.Description)
+		i.attributes("Member", obj, ctx)
+		i.line("})")
+		return
+	}
+	if len(form) == 0 {
+		return
+	}
+	multipart := contains(op.Consumes, "multipart/form-data")
+	i.line("Payload(func() {")
+	var required []string
+	for _, p := range form {
+		if p.Type == "file" || p.Items != nil && p.Items.Type == "file" {
+			multipart = true
+		}
+		i.attribute("Member", p.Name, paramSchema(p), ctx)
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	if len(required) > 0 {
+		i.line("Required(%s)", quoteAll(required))
+	}
+	i.line("})")
+	if multipart {
+		i.line("MultipartForm()")
+	}
+}
+
+// writeSecurityRequirement writes the Security or NoSecurity DSL for the given operation security
+// requirements.
+func (i *importer) writeSecurityRequirement(reqs []map[string][]string, ctx string) {
+	if reqs == nil {
+		return
+	}
+	if len(reqs) == 0 {
+		i.line("NoSecurity()")
+		return
+	}
+	if len(reqs) > 1 || len(reqs[0]) > 1 {
+		i.warn(ctx, "alternative or combined security requirements are not supported, only the first scheme is used")
+	}
+	names := make([]string, 0, len(reqs[0]))
+	for n := range reqs[0] {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		i.line("NoSecurity()")
+		return
+	}
+	scopes := reqs[0][names[0]]
+	if len(scopes) == 0 {
+		i.line("Security(%q)", names[0])
+		return
+	}
+	i.line("Security(%q, func() {", names[0])
+	for _, s := range scopes {
+		i.line("Scope(%q)", s)
+	}
+	i.line("})")
+}
+
+// writeResponses writes the Response DSL for the given operation responses.
+func (i *importer) writeResponses(responses map[string]*genswagger.Response, ctx string) {
+	codes := make([]string, 0, len(responses))
+	for c := range responses {
+		codes = append(codes, c)
+	}
+	sort.Strings(codes)
+	for _, c := range codes {
+		r := responses[c]
+		rctx := fmt.Sprintf("%s response %s", ctx, c)
+		status, err := strconv.Atoi(c)
+		if err != nil {
+			i.warn(rctx, "response skipped, only responses with a status code are supported")
+			continue
+		}
+		if r.Ref != "" {
+			i.warn(rctx, "unknown response reference %#v, response skipped", r.Ref)
+			continue
+		}
+		name, ok := i.statuses[status]
+		var lines []string
+		if ok {
+			i.usesDesign = true
+		} else {
+			name = strconv.Quote(fmt.Sprintf("Status%d", status))
+			lines = append(lines, fmt.Sprintf("Status(%d)", status))
+		}
+		if r.Description != "" && r.Description != http.StatusText(status) {
+			lines = append(lines, fmt.Sprintf("Description(%q)", r.Description))
+		}
+		if media := i.responseMedia(r.Schema, rctx); media != "" {
+			lines = append(lines, fmt.Sprintf("Media(%s)", media))
+		}
+		if len(lines) == 0 && len(r.Headers) == 0 {
+			i.line("Response(%s)", name)
+			continue
+		}
+		i.line("Response(%s, func() {", name)
+		for _, l := range lines {
+			i.line("%s", l)
+		}
+		if len(r.Headers) > 0 {
+			names := make([]string, 0, len(r.Headers))
+			for n := range r.Headers {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			i.line("Headers(func() {")
+			for _, n := range names {
+				i.attribute("Header", n, headerSchema(r.Headers[n]), rctx+" headers")
+			}
+			i.line("})")
+		}
+		i.line("})")
+	}
+}
+
+// responseMedia returns the expression passed to Media for the given response schema.
+func (i *importer) responseMedia(s *genschema.JSONSchema, ctx string) string {
+	if s == nil {
+		return ""
+	}
+	if d := i.definition(s.Ref); d != nil {
+		if d.Media {
+			return i.mediaRef(d)
+		}
+		s = d.Schema
+	}
+	if s.Type == "array" && s.Items != nil {
+		if d := i.collectionElem(s); d != nil {
+			return fmt.Sprintf("CollectionOf(%s)", i.mediaRef(d))
+		}
+	}
+	i.warn(ctx, "response body skipped, only references to object definitions or arrays of object definitions are supported")
+	return ""
+}
+
+// mediaRef returns the expression that refers to the media type produced by the given
+// definition.
+func (i *importer) mediaRef(d *definition) string {
+	if d.isErrorMedia() {
+		i.usesDesign = true
+	}
+	return d.VarName
+}
+
+// collectionElem returns the definition of the elements of the given array schema if it
+// describes a collection of media types, nil otherwise.
+func (i *importer) collectionElem(s *genschema.JSONSchema) *definition {
+	if d := i.definition(s.Items.Ref); d != nil && d.Object {
+		return d
+	}
+	// goa inlines the element schema in collection definitions.
+	m := identifierTitle.FindStringSubmatch(s.Title)
+	if m == nil {
+		return nil
+	}
+	elem, params, err := mime.ParseMediaType(m[1])
+	if err != nil || params["type"] != "collection" {
+		return nil
+	}
+	for _, n := range i.order {
+		d := i.defs[n]
+		if id, _, err := mime.ParseMediaType(d.Identifier); err == nil && id == elem {
+			return d
+		}
+	}
+	return nil
+}
+
+// writeDefinitions writes the types and media types.
+func (i *importer) writeDefinitions() {
+	for j := 0; j < len(i.order); j++ {
+		d := i.defs[i.order[j]]
+		if d.isErrorMedia() || d.Inlined {
+			continue
+		}
+		i.current = d.Name
+		s := d.Schema
+		if d.Media {
+			i.line("var %s = MediaType(%q, func() {", d.VarName, d.Identifier)
+			i.description(s.Description)
+			i.line("TypeName(%q)", d.Name)
+			i.line("Attributes(func() {")
+			i.attributes("Attribute", s, d.Name)
+			i.line("})")
+			i.line("View(\"default\", func() {")
+			for _, n := range i.propertyNames(s) {
+				i.line("Attribute(%q)", n)
+			}
+			i.line("})")
+		} else {
+			i.line("var %s = Type(%q, func() {", d.VarName, d.Name)
+			i.description(s.Description)
+			i.attributes("Attribute", s, d.Name)
+		}
+		i.line("})\n")
+		i.current = ""
+	}
+}
+
+// countRefs increments the reference counts of the definitions referred to by the given schema.
+func (i *importer) countRefs(s *genschema.JSONSchema) {
+	if s == nil {
+		return
+	}
+	if d := i.definition(s.Ref); d != nil {
+		i.refs[d.Name]++
+	}
+	i.countRefs(s.Items)
+	for _, p := range s.Properties {
+		i.countRefs(p)
+	}
+	for _, l := range [][]*genschema.JSONSchema{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, c := range l {
+			i.countRefs(c)
+		}
+	}
+}
+
+// attributes writes the child attributes of the given object schema using the given DSL function,
+// e.g. "Attribute" or "Member".
+func (i *importer) attributes(fn string, s *genschema.JSONSchema, ctx string) {
+	props := make(map[string]*genschema.JSONSchema)
+	var required []string
+	for _, base := range s.AllOf {
+		if d := i.definition(base.Ref); d != nil && d.Object {
+			if i.dependOn(d) {
+				i.line("Extend(%s)", d.VarName)
+				continue
+			}
+			i.warn(ctx, "recursive extension of %#v skipped", d.Name)
+			continue
+		}
+		if base.Ref == "" && len(base.Properties) > 0 {
+			for n, p := range base.Properties {
+				props[n] = p
+			}
+			required = append(required, base.Required...)
+			continue
+		}
+		i.warn(ctx, "allOf element skipped, only references to object definitions and inline objects are supported")
+	}
+	for n, p := range s.Properties {
+		props[n] = p
+	}
+	required = append(required, s.Required...)
+	if s.Discriminator != "" {
+		i.warn(ctx, "discriminator %#v ignored, the schema is imported as a plain object", s.Discriminator)
+	}
+
+	names := make([]string, 0, len(props))
+	for n := range props {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		i.attribute(fn, n, props[n], ctx)
+	}
+	known := make(map[string]bool)
+	for _, n := range i.propertyNames(s) {
+		known[n] = true
+	}
+	var req []string
+	seen := make(map[string]bool)
+	for _, n := range required {
+		if known[n] && !seen[n] {
+			req = append(req, n)
+			seen[n] = true
+		}
+	}
+	if len(req) > 0 {
+		i.line("Required(%s)", quoteAll(req))
+	}
+}
+
+// attribute writes the DSL of the attribute with the given name and schema using the given DSL
+// function, e.g. "Attribute", "Param" or "Header".
+func (i *importer) attribute(fn, name string, s *genschema.JSONSchema, ctx string) {
+	ctx = ctx + "." + name
+	t, obj := i.typeOf(s, ctx, false)
+	desc := s.Description
+	if d := i.definition(s.Ref); d != nil && !d.Object {
+		// Inlined definitions carry their validations with them.
+		if desc == "" {
+			desc = d.Schema.Description
+		}
+		s = d.Schema
+	}
+	var vals []string
+	if obj == nil {
+		vals = i.validations(s, ctx)
+	}
+	args := []string{strconv.Quote(name)}
+	if t != "" {
+		args = append(args, t)
+		if desc != "" {
+			args = append(args, strconv.Quote(desc))
+		}
+	}
+	if obj == nil && len(vals) == 0 {
+		i.line("%s(%s)", fn, strings.Join(args, ", "))
+		return
+	}
+	i.line("%s(%s, func() {", fn, strings.Join(args, ", "))
+	if obj != nil {
+		i.description(desc)
+		i.attributes("Attribute", obj, ctx)
+	}
+	for _, v := range vals {
+		i.line("%s", v)
+	}
+	i.line("})")
+}
+
+// typeOf returns the expression of the type described by the given schema. It returns the schema
+// itself instead if the schema describes an inline object. Types defined in the design are
+// referred to by name unless asVar is true in which case the expression uses Go variables.
+func (i *importer) typeOf(s *genschema.JSONSchema, ctx string, asVar bool) (string, *genschema.JSONSchema) {
+	i.usesDesign = true
+	if s.Ref != "" {
+		return i.refType(s.Ref, ctx, asVar), nil
+	}
+	if len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		i.warn(ctx, "anyOf and oneOf are not supported, type imported as Any")
+		return "Any", nil
+	}
+	if len(s.AllOf) > 0 {
+		if len(s.AllOf) == 1 && len(s.Properties) == 0 && s.AllOf[0].Ref != "" {
+			return i.refType(s.AllOf[0].Ref, ctx, asVar), nil
+		}
+		return "", s
+	}
+	switch s.Type {
+	case "string":
+		if t, ok := typedFormats[s.Format]; ok {
+			return t, nil
+		}
+		return "String", nil
+	case "integer":
+		return "Integer", nil
+	case "number":
+		return "Number", nil
+	case "boolean":
+		return "Boolean", nil
+	case "file":
+		return "File", nil
+	case "array":
+		if s.Items == nil {
+			return "ArrayOf(Any)", nil
+		}
+		return fmt.Sprintf("ArrayOf(%s)", i.elemType(s.Items, ctx+"[*]")), nil
+	case "", "object":
+		// LoadSwagger moves the "additionalProperties" schemas to Items.
+		values := s.Items
+		if !s.AdditionalProperties {
+			values = nil
+		}
+		if len(s.Properties) > 0 {
+			if values != nil {
+				i.warn(ctx, "additionalProperties schema of object with properties skipped")
+			}
+			return "", s
+		}
+		if values != nil {
+			return fmt.Sprintf("HashOf(String, %s)", i.elemType(values, ctx+"[*]")), nil
+		}
+		if s.Type == "object" {
+			return "HashOf(String, Any)", nil
+		}
+	}
+	if s.Type != "" && s.Type != "null" {
+		i.warn(ctx, "unsupported type %#v imported as Any", s.Type)
+	}
+	return "Any", nil
+}
+
+// elemType returns the expression of the type of array elements.
+func (i *importer) elemType(s *genschema.JSONSchema, ctx string) string {
+	t, obj := i.typeOf(s, ctx, true)
+	if obj != nil {
+		return i.hoist(obj, ctx)
+	}
+	return t
+}
+
+// hoist records a type definition for the given inline object schema so that it may be used where
+// the DSL requires a type, e.g. as array element type, and returns the name of its Go variable.
+func (i *importer) hoist(s *genschema.JSONSchema, ctx string) string {
+	base := codegen.Goify(strings.Trim(nonAlnum.ReplaceAllString(ctx, "_"), "_"), true)
+	name := base
+	for n := 2; i.defs[name] != nil || i.swagger.Definitions[name] != nil; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	d := &definition{Name: name, Schema: s, Object: true, VarName: i.varName(name, "Type")}
+	i.defs[name] = d
+	i.order = append(i.order, name)
+	i.dependOn(d)
+	return d.VarName
+}
+
+// refType returns the expression of the type referred to by the given definition reference.
+func (i *importer) refType(ref, ctx string, asVar bool) string {
+	d := i.definition(ref)
+	if d == nil {
+		i.warn(ctx, "unsupported reference %#v imported as Any", ref)
+		return "Any"
+	}
+	if !d.Object {
+		if i.inlining[d.Name] {
+			i.warn(ctx, "recursive definition %#v imported as Any", d.Name)
+			return "Any"
+		}
+		i.inlining[d.Name] = true
+		defer delete(i.inlining, d.Name)
+		if asVar {
+			return i.elemType(d.Schema, ctx)
+		}
+		t, obj := i.typeOf(d.Schema, ctx, false)
+		if obj != nil {
+			return i.hoist(obj, ctx)
+		}
+		return t
+	}
+	if d.isErrorMedia() {
+		return "ErrorMedia"
+	}
+	if !i.dependOn(d) {
+		i.warn(ctx, "recursive reference to %#v imported as Any", d.Name)
+		return "Any"
+	}
+	if asVar {
+		return d.VarName
+	}
+	if d.Media {
+		return strconv.Quote(d.Identifier)
+	}
+	return strconv.Quote(d.Name)
+}
+
+// dependOn records that the definition being written refers to the given definition. It returns
+// false if doing so would create a cycle: recursive types are not supported by the code generators
+// and Go variables may not be initialized recursively.
+func (i *importer) dependOn(d *definition) bool {
+	if i.current == "" || d.isErrorMedia() {
+		return true
+	}
+	if i.reaches(d.Name, i.current, make(map[string]bool)) {
+		return false
+	}
+	if i.deps[i.current] == nil {
+		i.deps[i.current] = make(map[string]bool)
+	}
+	i.deps[i.current][d.Name] = true
+	return true
+}
+
+// reaches returns true if the definition named from depends on the definition named to.
+func (i *importer) reaches(from, to string, seen map[string]bool) bool {
+	if from == to {
+		return true
+	}
+	seen[from] = true
+	for n := range i.deps[from] {
+		if !seen[n] && i.reaches(n, to, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// validations returns the validation DSL for the given schema.
+func (i *importer) validations(s *genschema.JSONSchema, ctx string) []string {
+	var vals []string
+	if len(s.Enum) > 0 {
+		lits := make([]string, len(s.Enum))
+		for j, v := range s.Enum {
+			lits[j] = literal(v)
+		}
+		vals = append(vals, fmt.Sprintf("Enum(%s)", strings.Join(lits, ", ")))
+	}
+	if s.Format != "" && s.Type == "string" {
+		if _, ok := typedFormats[s.Format]; !ok {
+			if supportedFormats[s.Format] {
+				vals = append(vals, fmt.Sprintf("Format(%q)", s.Format))
+			} else {
+				i.warn(ctx, "unsupported format %#v skipped", s.Format)
+			}
+		}
+	}
+	if s.Pattern != "" {
+		vals = append(vals, fmt.Sprintf("Pattern(%q)", s.Pattern))
+	}
+	if s.HasMinimum {
+		fn := "Minimum"
+		if s.ExclusiveMinimum {
+			fn = "ExclusiveMinimum"
+		}
+		vals = append(vals, fmt.Sprintf("%s(%s)", fn, literal(s.Minimum)))
+	}
+	if s.HasMaximum {
+		fn := "Maximum"
+		if s.ExclusiveMaximum {
+			fn = "ExclusiveMaximum"
+		}
+		vals = append(vals, fmt.Sprintf("%s(%s)", fn, literal(s.Maximum)))
+	}
+	if s.MultipleOf > 0 {
+		vals = append(vals, fmt.Sprintf("MultipleOf(%s)", literal(s.MultipleOf)))
+	}
+	if s.MinLength > 0 {
+		vals = append(vals, fmt.Sprintf("MinLength(%d)", s.MinLength))
+	}
+	if s.MaxLength > 0 {
+		vals = append(vals, fmt.Sprintf("MaxLength(%d)", s.MaxLength))
+	}
+	if s.UniqueItems {
+		vals = append(vals, "UniqueItems()")
+	}
+	if s.MinProperties > 0 {
+		vals = append(vals, fmt.Sprintf("MinProperties(%d)", s.MinProperties))
+	}
+	if s.MaxProperties > 0 {
+		vals = append(vals, fmt.Sprintf("MaxProperties(%d)", s.MaxProperties))
+	}
+	if s.DefaultValue != nil && isScalar(s.DefaultValue) {
+		vals = append(vals, fmt.Sprintf("Default(%s)", literal(s.DefaultValue)))
+	}
+	if s.Example != nil && isScalar(s.Example) {
+		vals = append(vals, fmt.Sprintf("Example(%s)", literal(s.Example)))
+	}
+	return vals
+}
+
+// propertyNames returns the sorted names of the properties of the given object schema including
+// the properties of the definitions it extends.
+func (i *importer) propertyNames(s *genschema.JSONSchema) []string {
+	names := make(map[string]bool)
+	var collect func(*genschema.JSONSchema, map[string]bool)
+	collect = func(s *genschema.JSONSchema, seen map[string]bool) {
+		for n := range s.Properties {
+			names[n] = true
+		}
+		for _, base := range s.AllOf {
+			if d := i.definition(base.Ref); d != nil {
+				if !seen[d.Name] {
+					seen[d.Name] = true
+					collect(d.Schema, seen)
+				}
+			} else if base.Ref == "" {
+				collect(base, seen)
+			}
+		}
+	}
+	collect(s, make(map[string]bool))
+	res := make([]string, 0, len(names))
+	for n := range names {
+		res = append(res, n)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// definition returns the definition referred to by the given reference, nil if there is none.
+func (i *importer) definition(ref string) *definition {
+	if !strings.HasPrefix(ref, "#/definitions/") {
+		return nil
+	}
+	return i.defs[strings.TrimPrefix(ref, "#/definitions/")]
+}
+
+// varName returns a unique Go variable name for the given definition name and suffix.
+func (i *importer) varName(name, suffix string) string {
+	base := codegen.Goify(strings.Trim(nonAlnum.ReplaceAllString(name, "_"), "_"), true) + suffix
+	v := base
+	for n := 2; i.vars[v]; n++ {
+		v = fmt.Sprintf("%s%d", base, n)
+	}
+	i.vars[v] = true
+	return v
+}
+
+// description writes the Description DSL if the given description is not empty.
+func (i *importer) description(desc string) {
+	if desc != "" {
+		i.line("Description(%q)", desc)
+	}
+}
+
+// line writes a line of code.
+func (i *importer) line(format string, args ...interface{}) {
+	fmt.Fprintf(&i.buf, format+"\n", args...)
+}
+
+// warn records a warning.
+func (i *importer) warn(ctx, format string, args ...interface{}) {
+	i.warnings = append(i.warnings, ctx+": "+fmt.Sprintf(format, args...))
+}
+
+// isErrorMedia returns true if the definition describes the goa error media type.
+func (d *definition) isErrorMedia() bool {
+	return d.Media && design.CanonicalIdentifier(d.Identifier) == design.CanonicalIdentifier(design.ErrorMediaIdentifier)
+}
+
+// isObject returns true if the given definition schema describes an object with properties.
+func isObject(s *genschema.JSONSchema) bool {
+	if s.Type != "" && s.Type != "object" || len(s.AnyOf) > 0 || len(s.OneOf) > 0 {
+		return false
+	}
+	return len(s.Properties) > 0 || len(s.AllOf) > 0
+}
+
+// operationNames returns the names of the resource and action the given operation maps to. merge
+// is true if the operation identifier designates an additional route of an action produced by goa.
+func operationNames(path, method string, op *genswagger.Operation) (string, string, bool) {
+	if parts := strings.Split(op.OperationID, "#"); len(parts) > 1 && parts[0] != "" && parts[1] != "" {
+		return parts[0], parts[1], len(parts) > 2
+	}
+	var rname string
+	if len(op.Tags) > 0 {
+		rname = op.Tags[0]
+	} else {
+		for _, seg := range strings.Split(path, "/") {
+			if seg != "" && !strings.HasPrefix(seg, "{") {
+				rname = seg
+				break
+			}
+		}
+	}
+	rname = snake(rname)
+	if rname == "" {
+		rname = "root"
+	}
+	aname := snake(op.OperationID)
+	if aname == "" {
+		aname = snake(strings.ToLower(method) + " " + pathParam.ReplaceAllString(path, "$1"))
+	}
+	return rname, aname, false
+}
+
+// pathOperations returns the operations of the given path indexed by HTTP method.
+func pathOperations(p *genswagger.Path) map[string]*genswagger.Operation {
+	ops := make(map[string]*genswagger.Operation)
+	for m, op := range map[string]*genswagger.Operation{
+		"GET": p.Get, "PUT": p.Put, "POST": p.Post, "DELETE": p.Delete,
+		"OPTIONS": p.Options, "HEAD": p.Head, "PATCH": p.Patch,
+	} {
+		if op != nil {
+			ops[m] = op
+		}
+	}
+	return ops
+}
+
+// mergeParams returns the operation parameters including the path parameters it does not
+// override.
+func mergeParams(pathParams, opParams []*genswagger.Parameter) []*genswagger.Parameter {
+	params := append([]*genswagger.Parameter{}, opParams...)
+	for _, p := range pathParams {
+		overridden := false
+		for _, o := range opParams {
+			if o.Name == p.Name && o.In == p.In {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			params = append(params, p)
+		}
+	}
+	return params
+}
+
+// paramSchema returns the schema of the given non body parameter.
+func paramSchema(p *genswagger.Parameter) *genschema.JSONSchema {
+	s := &genschema.JSONSchema{
+		Type:         genschema.JSONType(p.Type),
+		Description:  p.Description,
+		Format:       p.Format,
+		DefaultValue: p.Default,
+		Enum:         p.Enum,
+		Pattern:      p.Pattern,
+		Minimum:      p.Minimum,
+		Maximum:      p.Maximum,
+		HasMinimum:   p.HasMinimum,
+		HasMaximum:   p.HasMaximum,
+		MultipleOf:   p.MultipleOf,
+		MinLength:    p.MinLength,
+		MaxLength:    p.MaxLength,
+		UniqueItems:  p.UniqueItems,
+
+		ExclusiveMinimum: p.ExclusiveMinimum,
+		ExclusiveMaximum: p.ExclusiveMaximum,
+	}
+	if p.Type == "array" {
+		s.MinLength, s.MaxLength = p.MinItems, p.MaxItems
+	}
+	if p.Items != nil {
+		s.Items = itemsSchema(p.Items)
+	}
+	return s
+}
+
+// headerSchema returns the schema of the given response header.
+func headerSchema(h *genswagger.Header) *genschema.JSONSchema {
+	return paramSchema(&genswagger.Parameter{
+		Type: h.Type, Description: h.Description, Format: h.Format, Items: h.Items,
+		Default: h.Default, Enum: h.Enum, Pattern: h.Pattern, Minimum: h.Minimum,
+		Maximum: h.Maximum, HasMinimum: h.HasMinimum, HasMaximum: h.HasMaximum,
+		ExclusiveMinimum: h.ExclusiveMinimum,
+		ExclusiveMaximum: h.ExclusiveMaximum, MultipleOf: h.MultipleOf,
+		MinLength: h.MinLength, MaxLength: h.MaxLength, MinItems: h.MinItems,
+		MaxItems: h.MaxItems, UniqueItems: h.UniqueItems,
+	})
+}
+
+// itemsSchema returns the schema of the given array items.
+func itemsSchema(it *genswagger.Items) *genschema.JSONSchema {
+	return paramSchema(&genswagger.Parameter{
+		Type: it.Type, Format: it.Format, Items: it.Items, Default: it.Default,
+		Enum: it.Enum, Pattern: it.Pattern, Minimum: it.Minimum, Maximum: it.Maximum,
+		HasMinimum: it.HasMinimum, HasMaximum: it.HasMaximum, ExclusiveMinimum: it.ExclusiveMinimum, ExclusiveMaximum: it.ExclusiveMaximum,
+		MultipleOf: it.MultipleOf, MinLength: it.MinLength, MaxLength: it.MaxLength,
+		MinItems: it.MinItems, MaxItems: it.MaxItems, UniqueItems: it.UniqueItems,
+	})
+}
+
+// literal returns the Go literal representing the given JSON value.
+func literal(v interface{}) string {
+	switch actual := v.(type) {
+	case nil:
+		return "nil"
+	case json.Number:
+		if n, err := actual.Int64(); err == nil {
+			return strconv.FormatInt(n, 10)
+		}
+		return actual.String()
+	case float64:
+		if actual == math.Trunc(actual) && math.Abs(actual) < 1e15 {
+			return strconv.FormatInt(int64(actual), 10)
+		}
+		return strconv.FormatFloat(actual, 'g', -1, 64)
+	case string:
+		return strconv.Quote(actual)
+	case []interface{}:
+		elems := make([]string, len(actual))
+		for j, e := range actual {
+			elems[j] = literal(e)
+		}
+		return fmt.Sprintf("[]interface{}{%s}", strings.Join(elems, ", "))
+	case map[string]interface{}:
+		keys := make([]string, 0, len(actual))
+		for k := range actual {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elems := make([]string, len(keys))
+		for j, k := range keys {
+			elems[j] = fmt.Sprintf("%q: %s", k, literal(actual[k]))
+		}
+		return fmt.Sprintf("map[string]interface{}{%s}", strings.Join(elems, ", "))
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// isScalar returns true if the given JSON value is not an object.
+func isScalar(v interface{}) bool {
+	_, ok := v.(map[string]interface{})
+	return !ok
+}
+
+// snake returns the snake_case version of the given name.
+func snake(name string) string {
+	return strings.Trim(nonAlnum.ReplaceAllString(codegen.SnakeCase(name), "_"), "_")
+}
+
+// quoteAll returns the comma separated list of the given quoted strings.
+func quoteAll(vals []string) string {
+	quoted := make([]string, len(vals))
+	for j, v := range vals {
+		quoted[j] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// contains returns true if vals contains val.
+func contains(vals []string, val string) bool {
+	for _, v := range vals {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package genimport_test
+
+import (
+	"github.com/goadesign/goa/goagen/gen_import"
+	"github.com/goadesign/goa/goagen/gen_swagger"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImportSwagger", func() {
+	var spec string
+	var swagger *genswagger.Swagger
+	var source string
+	var warnings []string
+
+	JustBeforeEach(func() {
+		var err error
+		swagger, err = genimport.LoadSwagger([]byte(spec))
+		Ω(err).ShouldNot(HaveOccurred())
+		var b []byte
+		b, warnings, err = genimport.ImportSwagger(swagger, "design")
+		Ω(err).ShouldNot(HaveOccurred())
+		source = string(b)
+	})
+
+	Context("with a Swagger specification", func() {
+		BeforeEach(func() {
+			spec = petstore
+		})
+
+		It("generates the API definition", func() {
+			Ω(source).Should(HavePrefix("package design\n"))
+			Ω(source).Should(ContainSubstring(`. "github.com/goadesign/goa/design/apidsl"`))
+			Ω(source).Should(ContainSubstring(apiDSL))
+		})
+
+		It("generates the security schemes", func() {
+			Ω(source).Should(ContainSubstring(securityDSL))
+		})
+
+		It("generates the resources and actions", func() {
+			Ω(source).Should(ContainSubstring(listDSL))
+			Ω(source).Should(ContainSubstring(showDSL))
+		})
+
+		It("generates types and media types", func() {
+			Ω(source).Should(ContainSubstring(newPetDSL))
+			Ω(source).Should(ContainSubstring(petDSL))
+		})
+
+		It("reports the constructs that cannot be represented", func() {
+			Ω(warnings).Should(ConsistOf(
+				`consumes: no known encoder for MIME type "text/csv", MIME type skipped`,
+				`GET /pets response default: response skipped, only responses with a status code are supported`,
+				`NewPet.code: unsupported format "ean13" skipped`,
+				`Pet.parent: recursive reference to "Pet" imported as Any`,
+			))
+		})
+	})
+
+	Context("with a YAML specification", func() {
+		BeforeEach(func() {
+			spec = "swagger: \"2.0\"\ninfo:\n  title: Test\npaths:\n  /ping:\n    get:\n      operationId: ping\n      responses:\n        \"204\":\n          description: No Content\n"
+		})
+
+		It("loads it", func() {
+			Ω(swagger.Info.Title).Should(Equal("Test"))
+			Ω(source).Should(ContainSubstring(`var _ = Resource("ping", func() {`))
+			Ω(source).Should(ContainSubstring(`Response(NoContent)`))
+		})
+	})
+
+	Context("with an API key read from the Authorization header", func() {
+		BeforeEach(func() {
+			spec = "swagger: \"2.0\"\ninfo:\n  title: Test\npaths: {}\nsecurityDefinitions:\n  jwt:\n    type: apiKey\n    name: Authorization\n    in: header\n"
+		})
+
+		It("imports it as an API key and reports that it may be a JWT", func() {
+			Ω(source).Should(ContainSubstring("var _ = APIKeySecurity(\"jwt\", func() {\n\tHeader(\"Authorization\")\n})"))
+			Ω(warnings).Should(ConsistOf(
+				`security definition "jwt": API key read from the Authorization header imported with APIKeySecurity, use JWTSecurity if the key is a JSON Web Token`,
+			))
+		})
+	})
+
+	Context("with additionalProperties schemas", func() {
+		BeforeEach(func() {
+			spec = `{
+  "swagger": "2.0",
+  "info": {"title": "Test"},
+  "paths": {},
+  "definitions": {
+    "Bottle": {
+      "type": "object",
+      "properties": {
+        "labels": {"type": "object", "additionalProperties": {"type": "string"}},
+        "ratings": {"type": "object", "additionalProperties": {"type": "array", "items": {"type": "integer"}}},
+        "extra": {"type": "object", "properties": {"name": {"type": "string"}}, "additionalProperties": {"type": "string"}}
+      }
+    }
+  }
+}`
+		})
+
+		It("imports them as the hash value types", func() {
+			Ω(source).Should(ContainSubstring(`Attribute("labels", HashOf(String, String))`))
+			Ω(source).Should(ContainSubstring(`Attribute("ratings", HashOf(String, ArrayOf(Integer)))`))
+			Ω(warnings).Should(ConsistOf(
+				`Bottle.extra: additionalProperties schema of object with properties skipped`,
+			))
+		})
+	})
+
+	Context("with payload definitions produced by goa", func() {
+		BeforeEach(func() {
+			spec = goaSpec
+		})
+
+		It("inlines the payload and merges the routes", func() {
+			Ω(source).Should(ContainSubstring(createDSL))
+			Ω(source).ShouldNot(ContainSubstring(`Type("CreateBottlePayload"`))
+		})
+	})
+})
+
+const petstore = `{
+  "swagger": "2.0",
+  "info": {"title": "Pet Store", "version": "1.0"},
+  "host": "petstore.example.com",
+  "basePath": "/v1",
+  "schemes": ["https"],
+  "consumes": ["application/json", "text/csv"],
+  "securityDefinitions": {
+    "oauth": {
+      "type": "oauth2",
+      "flow": "accessCode",
+      "authorizationUrl": "https://auth.example.com/authorize",
+      "tokenUrl": "https://auth.example.com/token",
+      "scopes": {"read": "Read pets"}
+    }
+  },
+  "parameters": {
+    "limit": {"name": "limit", "in": "query", "type": "integer", "minimum": 1, "default": 20}
+  },
+  "paths": {
+    "/pets": {
+      "get": {
+        "tags": ["pets"],
+        "operationId": "listPets",
+        "parameters": [
+          {"$ref": "#/parameters/limit"},
+          {"name": "X-Request-ID", "in": "header", "type": "string", "format": "uuid", "required": true}
+        ],
+        "security": [{"oauth": ["read"]}],
+        "responses": {
+          "200": {"description": "OK", "schema": {"type": "array", "items": {"$ref": "#/definitions/Pet"}}},
+          "default": {"description": "Error"}
+        }
+      }
+    },
+    "/pets/{petId}": {
+      "parameters": [{"name": "petId", "in": "path", "required": true, "type": "integer"}],
+      "get": {
+        "tags": ["pets"],
+        "operationId": "showPet",
+        "deprecated": true,
+        "responses": {
+          "200": {"description": "OK", "schema": {"$ref": "#/definitions/Pet"}},
+          "429": {"description": "Slow down"}
+        }
+      }
+    }
+  },
+  "definitions": {
+    "Status": {"type": "string", "enum": ["available", "sold"]},
+    "NewPet": {
+      "type": "object",
+      "required": ["name"],
+      "properties": {
+        "name": {"type": "string", "minLength": 1},
+        "status": {"$ref": "#/definitions/Status"},
+        "code": {"type": "string", "format": "ean13"},
+        "toys": {"type": "array", "items": {"type": "object", "properties": {"name": {"type": "string"}}}}
+      }
+    },
+    "Pet": {
+      "allOf": [
+        {"$ref": "#/definitions/NewPet"},
+        {
+          "type": "object",
+          "required": ["id"],
+          "properties": {
+            "id": {"type": "integer", "minimum": 0, "example": 12},
+            "parent": {"$ref": "#/definitions/Pet"}
+          }
+        }
+      ]
+    }
+  }
+}`
+
+const goaSpec = `{
+  "swagger": "2.0",
+  "info": {"version": ""},
+  "paths": {
+    "/bottles": {
+      "post": {
+        "operationId": "bottle#create",
+        "parameters": [{"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateBottlePayload"}}],
+        "responses": {"201": {"description": "Created"}}
+      }
+    },
+    "/bottles/new": {
+      "post": {
+        "operationId": "bottle#create#1",
+        "parameters": [{"name": "payload", "in": "body", "required": true, "schema": {"$ref": "#/definitions/CreateBottlePayload"}}],
+        "responses": {"201": {"description": "Created"}}
+      }
+    }
+  },
+  "definitions": {
+    "CreateBottlePayload": {
+      "title": "CreateBottlePayload",
+      "type": "object",
+      "properties": {"name": {"type": "string"}}
+    }
+  }
+}`
+
+const apiDSL = `var _ = API("pet_store", func() {
+	Title("Pet Store")
+	Version("1.0")
+	Host("petstore.example.com")
+	Scheme("https")
+	BasePath("/v1")
+	Consumes("application/json")
+})`
+
+const securityDSL = `var _ = OAuth2Security("oauth", func() {
+	AccessCodeFlow("https://auth.example.com/authorize", "https://auth.example.com/token")
+	Scope("read", "Read pets")
+})`
+
+const listDSL = `	Action("list_pets", func() {
+		Routing(GET("/pets"))
+		Params(func() {
+			Param("limit", Integer, func() {
+				Minimum(1)
+				Default(20)
+			})
+		})
+		Headers(func() {
+			Header("X-Request-ID", UUID)
+			Required("X-Request-ID")
+		})
+		Security("oauth", func() {
+			Scope("read")
+		})
+		Response(OK, func() {
+			Media(CollectionOf(PetMedia))
+		})
+	})`
+
+const showDSL = `	Action("show_pet", func() {
+		Routing(GET("/pets/:petId"))
+		Deprecated()
+		Params(func() {
+			Param("petId", Integer)
+		})
+		Response(OK, func() {
+			Media(PetMedia)
+		})
+		Response("Status429", func() {
+			Status(429)
+			Description("Slow down")
+		})
+	})`
+
+const newPetDSL = `var NewPetType = Type("NewPet", func() {
+	Attribute("code", String)
+	Attribute("name", String, func() {
+		MinLength(1)
+	})
+	Attribute("status", String, func() {
+		Enum("available", "sold")
+	})
+	Attribute("toys", ArrayOf(NewPetToysType))
+	Required("name")
+})`
+
+const petDSL = `var PetMedia = MediaType("application/vnd.pet+json", func() {
+	TypeName("Pet")
+	Attributes(func() {
+		Extend(NewPetType)
+		Attribute("id", Integer, func() {
+			Minimum(0)
+			Example(12)
+		})
+		Attribute("parent", Any)
+		Required("id")
+	})
+	View("default", func() {
+		Attribute("code")
+		Attribute("id")
+		Attribute("name")
+		Attribute("parent")
+		Attribute("status")
+		Attribute("toys")
+	})
+})`
+
+const createDSL = `var _ = Resource("bottle", func() {
+	Action("create", func() {
+		Routing(POST("/bottles"), POST("/bottles/new"))
+		Payload(func() {
+			Member("name", String)
+		})
+		Response(Created)
+	})
+})`
@@ -0,0 +1,110 @@
+package genlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+var (
+	// ConfigFile is the path to the lint configuration file if any.
+	ConfigFile string
+
+	// Format is the output format, "text" or "json".
+	Format string
+
+	// FindingsFile is the path to the file the generator writes the findings to.
+	FindingsFile string
+)
+
+// Command is the goa design lint command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("lint", "Check the design against style and consistency rules")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().StringVar(&ConfigFile, "config", "", "path to the JSON lint configuration file")
+	r.Flags().StringVar(&Format, "format", "text", `output format, "text" or "json"`)
+	r.Flags().StringVar(&FindingsFile, "findings", "", "path to the file the findings are written to")
+	r.Flags().MarkHidden("findings")
+}
+
+// Run compiles and runs the generator to lint the design and reports the findings. It returns an
+// error if there is any finding.
+func (c *Command) Run() ([]string, error) {
+	if Format != "text" && Format != "json" {
+		return nil, fmt.Errorf("invalid output format %#v, must be text or json", Format)
+	}
+	config := &Config{}
+	if ConfigFile != "" {
+		var err error
+		if config, err = LoadConfig(ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	tmp, err := ioutil.TempFile("", "goagen-lint")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	gen := meta.NewGenerator(
+		"genlint.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_lint")},
+		map[string]string{"findings": tmp.Name()},
+	)
+	if _, err := gen.Generate(); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(tmp.Name())
+	if err != nil {
+		return nil, err
+	}
+	var findings []*Finding
+	if err := json.Unmarshal(b, &findings); err != nil {
+		return nil, err
+	}
+
+	findings = config.Filter(findings)
+	if err := Report(os.Stdout, findings, Format); err != nil {
+		return nil, err
+	}
+	if len(findings) > 0 {
+		return nil, fmt.Errorf("%d lint finding(s)", len(findings))
+	}
+	return nil, nil
+}
+
+// Report writes the findings to w using the given format, "text" or "json".
+func Report(w io.Writer, findings []*Finding, format string) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []*Finding{}
+		}
+		b, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	for _, f := range findings {
+		fmt.Fprintln(w, f.String())
+	}
+	_, err := fmt.Fprintf(w, "%d finding(s)\n", len(findings))
+	return err
+}
@@ -0,0 +1,61 @@
+package genlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Config is the lint configuration.
+type Config struct {
+	// Disable lists the names of the rules disabled for the whole design.
+	Disable []string `json:"disable,omitempty"`
+}
+
+// LoadConfig reads the JSON representation of a configuration from the given file, e.g.:
+//
+//	{ "disable": ["attribute-description", "naming"] }
+func LoadConfig(path string) (*Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Config
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, fmt.Errorf("invalid lint configuration %s: %s", path, err)
+	}
+	for _, name := range c.Disable {
+		if !isRule(name) {
+			return nil, fmt.Errorf("invalid lint configuration %s: unknown rule %#v", path, name)
+		}
+	}
+	return &c, nil
+}
+
+// Filter returns the findings of the rules that are not disabled by the configuration.
+func (c *Config) Filter(findings []*Finding) []*Finding {
+	var res []*Finding
+	for _, f := range findings {
+		disabled := false
+		for _, name := range c.Disable {
+			if f.Rule == name {
+				disabled = true
+				break
+			}
+		}
+		if !disabled {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// isRule returns true if name is the name of a rule.
+func isRule(name string) bool {
+	for _, r := range Rules {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,8 @@
+/*
+Package genlint provides a generator that checks a design against a set of style and consistency
+rules such as the presence of descriptions, consistent naming or error responses. Rules may be
+disabled for a part of the design using the "lint:disable" metadata or for the whole design using a
+configuration file. The findings are reported in human readable or JSON format, the command exits
+with a non-zero status if there is any finding.
+*/
+package genlint
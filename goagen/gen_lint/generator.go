@@ -0,0 +1,34 @@
+package genlint
+
+import (
+	"encoding/json"
+	"io/ioutil"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/spf13/cobra"
+)
+
+// Generate is the generator entry point called by the meta generator. It lints the design and
+// writes the JSON representation of the findings to the file given with the --findings flag.
+func Generate() (files []string, err error) {
+	api := design.Design
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "Design linter",
+		Long:  "Design linter",
+		Run: func(*cobra.Command, []string) {
+			var b []byte
+			if b, err = json.Marshal(Lint(api)); err != nil {
+				return
+			}
+			if err = ioutil.WriteFile(FindingsFile, b, 0644); err == nil {
+				files = []string{FindingsFile}
+			}
+		},
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
@@ -0,0 +1,316 @@
+package genlint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+)
+
+// DisableMetadata is the name of the metadata that disables lint rules. The metadata values are the
+// names of the rules disabled for the definition it appears in and all the definitions it contains,
+// e.g.:
+//
+//	Metadata("lint:disable", "attribute-description", "example-or-validation")
+const DisableMetadata = "lint:disable"
+
+type (
+	// Rule is a design lint rule.
+	Rule struct {
+		// Name is the rule name used in findings and to disable the rule.
+		Name string
+		// Description describes what the rule checks.
+		Description string
+		// Check reports the findings of the rule for the given API.
+		Check func(api *design.APIDefinition, r *Reporter)
+	}
+
+	// Finding describes a design element that does not conform to a rule.
+	Finding struct {
+		// Rule is the name of the rule.
+		Rule string `json:"rule"`
+		// Context identifies the design element, e.g. "bottle#show params.id".
+		Context string `json:"context"`
+		// Message describes the issue.
+		Message string `json:"message"`
+	}
+
+	// Reporter collects the findings of a rule.
+	Reporter struct {
+		rule     string
+		findings []*Finding
+	}
+
+	// attributeInfo describes an attribute visited by walkAttributes.
+	attributeInfo struct {
+		// Context identifies the attribute.
+		Context string
+		// Name is the attribute name.
+		Name string
+		// Attribute is the attribute definition.
+		Attribute *design.AttributeDefinition
+		// Header is true if the attribute describes a request header.
+		Header bool
+		// Metadata lists the metadata of the attribute and its parent definitions.
+		Metadata []dslengine.MetadataDefinition
+	}
+)
+
+// Rules lists the lint rules.
+var Rules = []*Rule{
+	{
+		Name:        "missing-description",
+		Description: "API, resources, actions, types and media types must have a description",
+		Check:       checkDescriptions,
+	},
+	{
+		Name:        "attribute-description",
+		Description: "attributes must have a description",
+		Check:       checkAttributeDescriptions,
+	},
+	{
+		Name:        "naming",
+		Description: "resource, action and attribute names must be snake_case",
+		Check:       checkNaming,
+	},
+	{
+		Name:        "error-responses",
+		Description: "actions must define at least one error response",
+		Check:       checkErrorResponses,
+	},
+	{
+		Name:        "plural-collections",
+		Description: "route segments followed by a wildcard must be plural",
+		Check:       checkPluralCollections,
+	},
+	{
+		Name:        "example-or-validation",
+		Description: "attributes of primitive types must have an example or validations",
+		Check:       checkExamples,
+	},
+}
+
+// snakeCase matches snake_case names.
+var snakeCase = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// Lint runs the rules against the given API and returns the findings in the order of Rules.
+func Lint(api *design.APIDefinition) []*Finding {
+	var findings []*Finding
+	for _, rule := range Rules {
+		r := &Reporter{rule: rule.Name}
+		rule.Check(api, r)
+		findings = append(findings, r.findings...)
+	}
+	return findings
+}
+
+// Report records a finding unless the rule is disabled by the given metadata.
+func (r *Reporter) Report(context, message string, metadata ...dslengine.MetadataDefinition) {
+	for _, m := range metadata {
+		for _, name := range m[DisableMetadata] {
+			if name == r.rule {
+				return
+			}
+		}
+	}
+	r.findings = append(r.findings, &Finding{Rule: r.rule, Context: context, Message: message})
+}
+
+// String returns a human readable representation of the finding.
+func (f *Finding) String() string {
+	return fmt.Sprintf("[%s] %s: %s", f.Rule, f.Context, f.Message)
+}
+
+// checkDescriptions implements the missing-description rule.
+func checkDescriptions(api *design.APIDefinition, r *Reporter) {
+	if api.Description == "" {
+		r.Report(fmt.Sprintf("API %#v", api.Name), "missing description", api.Metadata)
+	}
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		if res.Description == "" {
+			r.Report(fmt.Sprintf("resource %#v", res.Name), "missing description", api.Metadata, res.Metadata)
+		}
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Description == "" {
+				r.Report(actionContext(a), "missing description", api.Metadata, res.Metadata, a.Metadata)
+			}
+			return nil
+		})
+	})
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		if ut.Description == "" {
+			r.Report(fmt.Sprintf("type %#v", ut.TypeName), "missing description", api.Metadata, ut.Metadata)
+		}
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt != design.ErrorMedia && mt.Description == "" {
+			r.Report(fmt.Sprintf("media type %#v", mt.Identifier), "missing description", api.Metadata, mt.Metadata)
+		}
+		return nil
+	})
+}
+
+// checkAttributeDescriptions implements the attribute-description rule.
+func checkAttributeDescriptions(api *design.APIDefinition, r *Reporter) {
+	walkAttributes(api, func(info *attributeInfo) {
+		if info.Attribute.Description == "" {
+			r.Report(info.Context, "missing description", info.Metadata...)
+		}
+	})
+}
+
+// checkNaming implements the naming rule. Header names are not checked as they follow HTTP
+// conventions.
+func checkNaming(api *design.APIDefinition, r *Reporter) {
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		if !snakeCase.MatchString(res.Name) {
+			r.Report(fmt.Sprintf("resource %#v", res.Name), "name is not snake_case", api.Metadata, res.Metadata)
+		}
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if !snakeCase.MatchString(a.Name) {
+				r.Report(actionContext(a), "name is not snake_case", api.Metadata, res.Metadata, a.Metadata)
+			}
+			return nil
+		})
+	})
+	walkAttributes(api, func(info *attributeInfo) {
+		if !info.Header && !snakeCase.MatchString(info.Name) {
+			r.Report(info.Context, "name is not snake_case", info.Metadata...)
+		}
+	})
+}
+
+// checkErrorResponses implements the error-responses rule.
+func checkErrorResponses(api *design.APIDefinition, r *Reporter) {
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if len(a.AllErrors()) > 0 {
+				return nil
+			}
+			for _, resp := range a.Responses {
+				if resp.Status >= 400 {
+					return nil
+				}
+			}
+			r.Report(actionContext(a), "no error response", api.Metadata, res.Metadata, a.Metadata)
+			return nil
+		})
+	})
+}
+
+// checkPluralCollections implements the plural-collections rule.
+func checkPluralCollections(api *design.APIDefinition, r *Reporter) {
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			for _, route := range a.Routes {
+				segments := strings.Split(route.FullPath(), "/")
+				for i := 1; i < len(segments); i++ {
+					if !isWildcard(segments[i]) || isWildcard(segments[i-1]) || segments[i-1] == "" {
+						continue
+					}
+					if !strings.HasSuffix(segments[i-1], "s") {
+						msg := fmt.Sprintf("route \"%s %s\": collection segment %#v is not plural",
+							route.Verb, route.FullPath(), segments[i-1])
+						r.Report(actionContext(a), msg, api.Metadata, res.Metadata, a.Metadata)
+					}
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// checkExamples implements the example-or-validation rule.
+func checkExamples(api *design.APIDefinition, r *Reporter) {
+	walkAttributes(api, func(info *attributeInfo) {
+		att := info.Attribute
+		if _, ok := att.Type.(design.Primitive); !ok {
+			return
+		}
+		if att.HasCustomExample() || hasValidation(att.Validation) {
+			return
+		}
+		r.Report(info.Context, "no example nor validation", info.Metadata...)
+	})
+}
+
+// walkAttributes calls fn with the attributes of the API user types and media types and with the
+// attributes of the action parameters, headers and inline payloads. It visits child attributes of
+// inline objects recursively.
+func walkAttributes(api *design.APIDefinition, fn func(*attributeInfo)) {
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		walkAttribute(fmt.Sprintf("type %#v", ut.TypeName), ut.AttributeDefinition, false,
+			[]dslengine.MetadataDefinition{api.Metadata, ut.Metadata}, fn)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		if mt == design.ErrorMedia {
+			return nil
+		}
+		walkAttribute(fmt.Sprintf("media type %#v", mt.Identifier), mt.AttributeDefinition, false,
+			[]dslengine.MetadataDefinition{api.Metadata, mt.Metadata}, fn)
+		return nil
+	})
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			ctx := actionContext(a)
+			meta := []dslengine.MetadataDefinition{api.Metadata, res.Metadata, a.Metadata}
+			walkAttribute(ctx+" params", a.Params, false, meta, fn)
+			walkAttribute(ctx+" headers", a.Headers, true, meta, fn)
+			if a.Payload != nil {
+				if _, ok := api.Types[a.Payload.TypeName]; !ok {
+					walkAttribute(ctx+" payload", a.Payload.AttributeDefinition, false, meta, fn)
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// walkAttribute calls fn with the child attributes of the given object attribute and recurses into
+// the inline objects and arrays.
+func walkAttribute(ctx string, att *design.AttributeDefinition, header bool, meta []dslengine.MetadataDefinition, fn func(*attributeInfo)) {
+	if att == nil {
+		return
+	}
+	switch t := att.Type.(type) {
+	case design.Object:
+		names := make([]string, 0, len(t))
+		for n := range t {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			child := t[n]
+			cmeta := append(append([]dslengine.MetadataDefinition{}, meta...), child.Metadata)
+			cctx := ctx + "." + n
+			fn(&attributeInfo{Context: cctx, Name: n, Attribute: child, Header: header, Metadata: cmeta})
+			walkAttribute(cctx, child, header, cmeta, fn)
+		}
+	case *design.Array:
+		walkAttribute(ctx+"[*]", t.ElemType, header, meta, fn)
+	}
+}
+
+// actionContext returns the context used to identify the given action in findings.
+func actionContext(a *design.ActionDefinition) string {
+	return a.Parent.Name + "#" + a.Name
+}
+
+// isWildcard returns true if the given path segment is a wildcard.
+func isWildcard(segment string) bool {
+	return strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*")
+}
+
+// hasValidation returns true if the given validation defines at least one rule.
+func hasValidation(v *dslengine.ValidationDefinition) bool {
+	if v == nil {
+		return false
+	}
+	return v.Values != nil || v.Format != "" || v.Pattern != "" || v.Minimum != nil ||
+		v.Maximum != nil || v.MultipleOf != nil || v.MinLength != nil || v.MaxLength != nil
+}
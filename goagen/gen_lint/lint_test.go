@@ -0,0 +1,164 @@
+package genlint_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_lint"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Lint", func() {
+	var resourceDSL, actionDSL func()
+	var findings []*genlint.Finding
+
+	BeforeEach(func() {
+		dslengine.Reset()
+		resourceDSL = nil
+		actionDSL = nil
+	})
+
+	JustBeforeEach(func() {
+		API("cellar", func() {
+			Description("The wine cellar API")
+		})
+		Resource("bottle", func() {
+			Description("A wine bottle")
+			BasePath("/bottles")
+			if resourceDSL != nil {
+				resourceDSL()
+			}
+			Action("show", func() {
+				Description("Retrieve a bottle")
+				Routing(GET("/:id"))
+				Params(func() {
+					Param("id", Integer, "Bottle ID", func() {
+						Minimum(1)
+					})
+				})
+				Response(OK)
+				Response(NotFound)
+				if actionDSL != nil {
+					actionDSL()
+				}
+			})
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		findings = genlint.Lint(Design)
+	})
+
+	Context("with a design that follows the rules", func() {
+		It("does not report any finding", func() {
+			Ω(findings).Should(BeEmpty())
+		})
+	})
+
+	Context("with issues", func() {
+		BeforeEach(func() {
+			actionDSL = func() {
+				Routing(GET("/bottle/:id"))
+				Headers(func() {
+					Header("X-Account", String, "Account name", func() {
+						MinLength(1)
+					})
+				})
+				Params(func() {
+					Param("id", Integer, "Bottle ID", func() {
+						Minimum(1)
+					})
+					Param("vintageYear", Integer, "Vintage year", func() {
+						Example(2012)
+					})
+					Param("color", String)
+				})
+			}
+		})
+
+		It("reports findings", func() {
+			var res []string
+			for _, f := range findings {
+				res = append(res, f.String())
+			}
+			Ω(res).Should(Equal([]string{
+				`[attribute-description] bottle#show params.color: missing description`,
+				`[naming] bottle#show params.vintageYear: name is not snake_case`,
+				`[plural-collections] bottle#show: route "GET /bottles/bottle/:id": collection segment "bottle" is not plural`,
+				`[example-or-validation] bottle#show params.color: no example nor validation`,
+			}))
+		})
+	})
+
+	Context("with an action without error response", func() {
+		BeforeEach(func() {
+			resourceDSL = func() {
+				Action("list", func() {
+					Description("List bottles")
+					Routing(GET(""))
+					Response(OK)
+				})
+			}
+		})
+
+		It("reports it", func() {
+			Ω(findings).Should(HaveLen(1))
+			Ω(findings[0].Rule).Should(Equal("error-responses"))
+			Ω(findings[0].Context).Should(Equal("bottle#list"))
+		})
+
+		Context("and the rule disabled with metadata", func() {
+			BeforeEach(func() {
+				resourceDSL = func() {
+					Metadata(genlint.DisableMetadata, "error-responses")
+					Action("list", func() {
+						Description("List bottles")
+						Routing(GET(""))
+						Response(OK)
+					})
+				}
+			})
+
+			It("does not report it", func() {
+				Ω(findings).Should(BeEmpty())
+			})
+		})
+
+		Context("and the rule disabled with a configuration file", func() {
+			var dir string
+			var config *genlint.Config
+
+			BeforeEach(func() {
+				var err error
+				dir, err = ioutil.TempDir("", "genlint")
+				Ω(err).ShouldNot(HaveOccurred())
+				path := filepath.Join(dir, "lint.json")
+				err = ioutil.WriteFile(path, []byte(`{"disable": ["error-responses"]}`), 0644)
+				Ω(err).ShouldNot(HaveOccurred())
+				config, err = genlint.LoadConfig(path)
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			AfterEach(func() {
+				os.RemoveAll(dir)
+			})
+
+			It("filters it", func() {
+				Ω(config.Filter(findings)).Should(BeEmpty())
+			})
+		})
+
+		It("reports it in JSON", func() {
+			var buf bytes.Buffer
+			Ω(genlint.Report(&buf, findings, "json")).ShouldNot(HaveOccurred())
+			var res []*genlint.Finding
+			Ω(json.Unmarshal(buf.Bytes(), &res)).ShouldNot(HaveOccurred())
+			Ω(res).Should(Equal(findings))
+		})
+	})
+})
@@ -10,6 +10,7 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/spf13/cobra"
 )
@@ -53,12 +54,7 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		os.Remove(mainFile)
 	}
 	g.genfiles = append(g.genfiles, mainFile)
-	funcs := template.FuncMap{
-		"tempvar":         tempvar,
-		"generateSwagger": generateSwagger,
-		"okResp":          okResp,
-		"targetPkg":       func() string { return TargetPackage },
-	}
+	funcs := scaffoldFuncs(TargetPackage, "")
 	imp, err := codegen.PackagePath(codegen.OutputDir)
 	if err != nil {
 		return nil, err
@@ -89,10 +85,23 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 			jsonSchemaPkg := path.Join(outPkg, "schema")
 			imports = append(imports, codegen.SimpleImport(jsonSchemaPkg))
 		}
+		versions := make([]map[string]interface{}, len(api.Versions))
+		for i, v := range api.Versions {
+			pkg := genapp.VersionPackage(v)
+			imports = append(imports, codegen.SimpleImport(path.Join(appPkg, pkg)))
+			versions[i] = map[string]interface{}{
+				"Name":      v.Name,
+				"Package":   pkg,
+				"Prefix":    codegen.Goify(pkg, true),
+				"Resources": v.API().Resources,
+			}
+		}
 		file.WriteHeader("", "main", imports)
 		data := map[string]interface{}{
-			"Name": AppName,
-			"API":  api,
+			"Name":          AppName,
+			"API":           api,
+			"Versions":      versions,
+			"SelectVersion": selectVersion(api),
 		}
 		if err2 = file.ExecuteTemplate("main", mainT, funcs, data); err2 != nil {
 			return nil, err2
@@ -108,44 +117,70 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		codegen.SimpleImport("golang.org/x/net/websocket"),
 	}
 	err = api.IterateResources(func(r *design.ResourceDefinition) error {
-		filename := filepath.Join(codegen.OutputDir, codegen.SnakeCase(r.Name)+".go")
-		if Force {
-			if err2 := os.Remove(filename); err2 != nil {
-				return err2
-			}
-		}
-		g.genfiles = append(g.genfiles, filename)
-		if _, e := os.Stat(filename); e != nil {
-			file, err2 := codegen.SourceFileFor(filename)
-			if err2 != nil {
-				return err
-			}
-			file.WriteHeader("", "main", imports)
-			if err2 = file.ExecuteTemplate("controller", ctrlT, funcs, r); err2 != nil {
-				return err
-			}
-			err2 = r.IterateActions(func(a *design.ActionDefinition) error {
-				if a.WebSocket() {
-					return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
-				}
-				return file.ExecuteTemplate("action", actionT, funcs, a)
-			})
-			if err2 != nil {
-				return err
-			}
-			if err2 = file.FormatCode(); err2 != nil {
-				return err2
-			}
-		}
-		return nil
+		return g.generateController(r, "", imports, funcs)
 	})
 	if err != nil {
 		return
 	}
+	for _, v := range api.Versions {
+		pkg := genapp.VersionPackage(v)
+		vimports := append(imports[:len(imports):len(imports)], codegen.SimpleImport(path.Join(imp, pkg)))
+		vfuncs := scaffoldFuncs(pkg, codegen.Goify(pkg, true))
+		err = v.API().IterateResources(func(r *design.ResourceDefinition) error {
+			return g.generateController(r, pkg, vimports, vfuncs)
+		})
+		if err != nil {
+			return
+		}
+	}
 
 	return g.genfiles, nil
 }
 
+// generateController generates the scaffold of the controller of the given resource if the
+// corresponding file does not exist. version is the package of the API version that defines the
+// resource, the empty string for the resources that do not belong to a version.
+func (g *Generator) generateController(r *design.ResourceDefinition, version string, imports []*codegen.ImportSpec, funcs template.FuncMap) error {
+	name := codegen.SnakeCase(r.Name)
+	if version != "" {
+		name = version + "_" + name
+	}
+	filename := filepath.Join(codegen.OutputDir, name+".go")
+	if Force {
+		if err := os.Remove(filename); err != nil {
+			return err
+		}
+	}
+	g.genfiles = append(g.genfiles, filename)
+	if _, err := os.Stat(filename); err == nil {
+		return nil
+	}
+	file, err := codegen.SourceFileFor(filename)
+	if err != nil {
+		return err
+	}
+	file.WriteHeader("", "main", imports)
+	if err = file.ExecuteTemplate("controller", ctrlT, funcs, r); err != nil {
+		return err
+	}
+	err = r.IterateActions(func(a *design.ActionDefinition) error {
+		if a.Messages != nil {
+			return file.ExecuteTemplate("actionMessages", actionMessagesT, funcs, a)
+		}
+		if a.WebSocket() {
+			return file.ExecuteTemplate("actionWS", actionWST, funcs, a)
+		}
+		if a.Stream != nil {
+			return file.ExecuteTemplate("actionStream", actionStreamT, funcs, a)
+		}
+		return file.ExecuteTemplate("action", actionT, funcs, a)
+	})
+	if err != nil {
+		return err
+	}
+	return file.FormatCode()
+}
+
 // Cleanup removes all the files generated by this generator during the last invokation of Generate.
 func (g *Generator) Cleanup() {
 	for _, f := range g.genfiles {
@@ -154,6 +189,21 @@ func (g *Generator) Cleanup() {
 	g.genfiles = nil
 }
 
+// scaffoldFuncs returns the functions used by the templates that generate the code of the
+// controllers implemented with the types of the given package. prefix is prepended to the names of
+// the controllers.
+func scaffoldFuncs(pkg, prefix string) template.FuncMap {
+	return template.FuncMap{
+		"tempvar":         tempvar,
+		"generateSwagger": generateSwagger,
+		"okResp":          func(a *design.ActionDefinition) map[string]interface{} { return okResp(a, pkg) },
+		"streamEvent":     func(a *design.ActionDefinition) string { return streamEvent(a, pkg) },
+		"outboundMessage": func(a *design.ActionDefinition) string { return outboundMessage(a, pkg) },
+		"targetPkg":       func() string { return pkg },
+		"ctrlPrefix":      func() string { return prefix },
+	}
+}
+
 // tempCount is the counter used to create unique temporary variable names.
 var tempCount int
 
@@ -171,7 +221,35 @@ func generateSwagger() bool {
 	return codegen.CommandName == "" || codegen.CommandName == "swagger"
 }
 
-func okResp(a *design.ActionDefinition) map[string]interface{} {
+// selectVersion returns the expression that initializes the SelectVersionFunc used to serve the
+// API versions, the empty string if the API does not define versions.
+func selectVersion(api *design.APIDefinition) string {
+	if len(api.Versions) == 0 {
+		return ""
+	}
+	var funcs []string
+	var paths []string
+	for _, v := range api.Versions {
+		if v.SelectedByPath() {
+			paths = append(paths, fmt.Sprintf("%q: %q", v.Name, v.BasePath))
+		}
+	}
+	if len(paths) > 0 {
+		funcs = append(funcs, fmt.Sprintf("goa.BasePathSelectVersionFunc(map[string]string{%s})", strings.Join(paths, ", ")))
+	}
+	if api.VersionHeader != "" {
+		funcs = append(funcs, fmt.Sprintf("goa.HeaderSelectVersionFunc(%q)", api.VersionHeader))
+	}
+	if api.VersionParam != "" {
+		funcs = append(funcs, fmt.Sprintf("goa.AcceptSelectVersionFunc(%q)", api.VersionParam))
+	}
+	if len(funcs) == 1 {
+		return funcs[0]
+	}
+	return fmt.Sprintf("goa.CombineSelectVersionFunc(\n\t\t%s,\n\t)", strings.Join(funcs, ",\n\t\t"))
+}
+
+func okResp(a *design.ActionDefinition, pkg string) map[string]interface{} {
 	var ok *design.ResponseDefinition
 	for _, resp := range a.Responses {
 		if resp.Status == 200 {
@@ -187,21 +265,47 @@ func okResp(a *design.ActionDefinition) map[string]interface{} {
 	if mt, ok2 = design.Design.MediaTypes[design.CanonicalIdentifier(ok.MediaType)]; !ok2 {
 		return nil
 	}
+	return map[string]interface{}{
+		"Name":    ok.Name,
+		"GoType":  codegen.GoNativeType(mt),
+		"TypeRef": typeRef(mt, pkg),
+	}
+}
+
+// streamEvent returns the type reference of the events streamed by the given action.
+func streamEvent(a *design.ActionDefinition, pkg string) string {
+	mt := a.Stream.EventMediaType()
+	if mt == nil {
+		return ""
+	}
+	return typeRef(mt, pkg)
+}
+
+// outboundMessage returns the type reference of the messages sent by the given WebSocket action.
+func outboundMessage(a *design.ActionDefinition, pkg string) string {
+	switch actual := a.Messages.Outbound.(type) {
+	case *design.MediaTypeDefinition:
+		return typeRef(actual, pkg)
+	case *design.UserTypeDefinition:
+		return fmt.Sprintf("&%s.%s", pkg, codegen.GoTypeName(actual, actual.AllRequired(), 1, false))
+	}
+	return ""
+}
+
+// typeRef returns the expression used to initialize a value of the media type defined in the given
+// package in the scaffold code.
+func typeRef(mt *design.MediaTypeDefinition, pkg string) string {
 	name := codegen.GoTypeRef(mt, mt.AllRequired(), 1, false)
 	var pointer string
 	if strings.HasPrefix(name, "*") {
 		name = name[1:]
 		pointer = "*"
 	}
-	typeref := fmt.Sprintf("%s%s.%s", pointer, TargetPackage, name)
+	typeref := fmt.Sprintf("%s%s.%s", pointer, pkg, name)
 	if strings.HasPrefix(typeref, "*") {
 		typeref = "&" + typeref[1:]
 	}
-	return map[string]interface{}{
-		"Name":    ok.Name,
-		"GoType":  codegen.GoNativeType(mt),
-		"TypeRef": typeref,
-	}
+	return typeref
 }
 
 const mainT = `
@@ -214,11 +318,17 @@ func main() {
 	service.Use(middleware.LogRequest(true))
 	service.Use(middleware.ErrorHandler(service, true))
 	service.Use(middleware.Recover())
-{{ $api := .API }}
+{{ $api := .API }}{{ if .SelectVersion }}
+	// Serve the API versions
+	service.Mux = goa.NewVersionMux(service.Mux, {{ .SelectVersion }})
+{{ end }}
 {{ range $name, $res := $api.Resources }}{{ $name := goify $res.Name true }} // Mount "{{$res.Name}}" controller
 	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $name }}Controller(service)
 	{{ targetPkg }}.Mount{{ $name }}Controller(service, {{ $tmp }})
-{{ end }}{{ if generateSwagger }}// Mount Swagger spec provider controller
+{{ end }}{{ range $v := .Versions }}{{ range $name, $res := $v.Resources }}{{ $name := goify $res.Name true }} // Mount "{{$res.Name}}" controller of API version "{{ $v.Name }}"
+	{{ $tmp := tempvar }}{{ $tmp }} := New{{ $v.Prefix }}{{ $name }}Controller(service)
+	{{ $v.Package }}.Mount{{ $name }}Controller(service, {{ $tmp }})
+{{ end }}{{ end }}{{ if generateSwagger }}// Mount Swagger spec provider controller
 	swagger.MountController(service)
 {{ end }}
 
@@ -228,7 +338,7 @@ func main() {
 }
 `
 
-const ctrlT = `// {{ $ctrlName := printf "%s%s" (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
+const ctrlT = `// {{ $ctrlName := printf "%s%s%s" ctrlPrefix (goify .Name true) "Controller" }}{{ $ctrlName }} implements the {{ .Name }} resource.
 type {{ $ctrlName }} struct {
 	*goa.Controller
 }
@@ -239,7 +349,7 @@ func New{{ $ctrlName }}(service *goa.Service) *{{ $ctrlName }} {
 }
 `
 
-const actionT = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+const actionT = `{{ $ctrlName := printf "%s%s%s" ctrlPrefix (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
 func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
 	// TBD: implement
 {{ $ok := okResp . }}{{ if $ok }} res := {{ $ok.TypeRef }}{}
@@ -247,7 +357,37 @@ func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .
 }
 `
 
-const actionWST = `{{ $ctrlName := printf "%s%s" (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+const actionStreamT = `{{ $ctrlName := printf "%s%s%s" ctrlPrefix (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	// TBD: implement, the context is canceled when the client disconnects.
+{{ $event := streamEvent . }}{{ if $event }}	if err := ctx.Send("", {{ $event }}{}); err != nil {
+		return err
+	}
+{{ end }}	return ctx.Close()
+}
+`
+
+const actionMessagesT = `{{ $ctrlName := printf "%s%s%s" ctrlPrefix (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
+func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
+	return ctx.Serve(func(conn *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Conn) error {
+		// TBD: implement, the connection context is canceled when the connection is closed.
+{{ $msg := outboundMessage . }}{{ if .Messages.Inbound }}		for {
+			if _, err := conn.Recv(); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+{{ if $msg }}			if err := conn.Send({{ $msg }}{}); err != nil {
+				return err
+			}
+{{ end }}		}
+{{ else }}		return conn.Send({{ $msg }}{})
+{{ end }}	})
+}
+`
+
+const actionWST = `{{ $ctrlName := printf "%s%s%s" ctrlPrefix (goify .Parent.Name true) "Controller" }}// {{ goify .Name true }} runs the {{ .Name }} action.
 func (c *{{ $ctrlName }}) {{ goify .Name true }}(ctx *{{ targetPkg }}.{{ goify .Name true }}{{ goify .Parent.Name true }}Context) error {
 	c.{{ goify .Name true }}WSHandler(ctx).ServeHTTP(ctx.ResponseWriter, ctx.Request)
 	return nil
@@ -7,13 +7,20 @@ import (
 	"strings"
 
 	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
 	"github.com/goadesign/goa/goagen/gen_main"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gexec"
 )
 
+// registeredDesign is the API definition registered with the DSL engine, the tests that use a
+// dummy API replace design.Design.
+var registeredDesign = design.Design
+
 var _ = Describe("Generate", func() {
 	const testgenPackagePath = "github.com/goadesign/goa/goagen/gen_main/goatest"
 
@@ -61,4 +68,52 @@ var _ = Describe("Generate", func() {
 			Ω(err).ShouldNot(HaveOccurred())
 		})
 	})
+
+	Context("with two API versions selected by header", func() {
+		BeforeEach(func() {
+			design.Design = registeredDesign
+			dslengine.Reset()
+			API("test api", func() {
+				VersionHeader("X-API-Version")
+			})
+			Resource("bottle", func() {
+				Action("show", func() {
+					Routing(GET("/bottles/:id"))
+					Response(design.NoContent)
+				})
+			})
+			APIVersion("v1", nil)
+			APIVersion("v2", func() {
+				Resource("bottle", func() {
+					Action("show", func() {
+						Routing(GET("/bottles/:id"))
+						Params(func() {
+							Param("id", design.Integer)
+						})
+						Response(design.NoContent)
+					})
+				})
+			})
+			Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+			_, err := genapp.Generate()
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+
+		It("serves the versions with a version mux", func() {
+			Ω(genErr).Should(BeNil())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "main.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			main := string(content)
+			Ω(main).Should(ContainSubstring(`service.Mux = goa.NewVersionMux(service.Mux, goa.HeaderSelectVersionFunc("X-API-Version"))`))
+			Ω(main).Should(ContainSubstring("app.MountBottleController(service, c)"))
+			Ω(main).Should(ContainSubstring("v1.MountBottleController(service, c2)"))
+			Ω(main).Should(ContainSubstring("v2.MountBottleController(service, c3)"))
+			Ω(filepath.Join(outDir, "v1_bottle.go")).Should(BeAnExistingFile())
+			content, err = ioutil.ReadFile(filepath.Join(outDir, "v2_bottle.go"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring("func (c *V2BottleController) Show(ctx *v2.ShowBottleContext) error {"))
+			_, err = gexec.Build(testgenPackagePath)
+			Ω(err).ShouldNot(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,28 @@
+package genopenapi
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+// Command is the goa OpenAPI specification generator command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("openapi", "Generate OpenAPI 3 specification, see https://www.openapis.org")
+	return &Command{BaseCommand: base}
+}
+
+// Run simply calls the meta generator.
+func (c *Command) Run() ([]string, error) {
+	gen := meta.NewGenerator(
+		"genopenapi.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_openapi")},
+		nil,
+	)
+	return gen.Generate()
+}
@@ -0,0 +1,13 @@
+/*
+Package genopenapi provides a generator for the OpenAPI 3 specification of the API, see
+https://www.openapis.org. Unlike the Swagger 2 specification produced by genswagger the OpenAPI 3
+specification describes the union types with "oneOf" schemas and discriminators, the cookie
+parameters, the request bodies for each content type the API consumes, the responses for each
+content type the API produces, the API servers and the JWT security schemes as bearer token
+schemes.
+
+The user types and media types are described by the schemas listed in the components of the
+specification. The generator honors the "swagger:tag" and "swagger:summary" metadata used by
+genswagger. It also generates a controller that serves the specification.
+*/
+package genopenapi
@@ -0,0 +1,121 @@
+package genopenapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+	"github.com/spf13/cobra"
+)
+
+// Generator is the OpenAPI specification generator.
+type Generator struct{}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	api := design.Design
+	g := new(Generator)
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "OpenAPI generator",
+		Long:  "OpenAPI generator",
+		Run:   func(*cobra.Command, []string) { files, err = g.Generate(api) },
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
+
+// Generate writes the OpenAPI specification of the API to the files "openapi.json" and
+// "openapi.yaml" of the "openapi" directory of the output directory together with the controller
+// that serves them.
+func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error) {
+	var genfiles []string
+
+	cleanup := func() {
+		for _, f := range genfiles {
+			os.Remove(f)
+		}
+	}
+
+	go utils.Catch(nil, cleanup)
+
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	openAPIDir := filepath.Join(codegen.OutputDir, "openapi")
+	os.RemoveAll(openAPIDir)
+	if err = os.MkdirAll(openAPIDir, 0755); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, openAPIDir)
+	s, err := New(api)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON
+	rawJSON, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	specFile := filepath.Join(openAPIDir, "openapi.json")
+	if err := ioutil.WriteFile(specFile, rawJSON, 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, specFile)
+
+	// YAML
+	var yamlSource interface{}
+	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
+		return nil, err
+	}
+	rawYAML, err := yaml.Marshal(yamlSource)
+	if err != nil {
+		return nil, err
+	}
+	specFile = filepath.Join(openAPIDir, "openapi.yaml")
+	if err := ioutil.WriteFile(specFile, rawYAML, 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, specFile)
+
+	// Go endpoint
+	controllerFile := filepath.Join(openAPIDir, "openapi.go")
+	genfiles = append(genfiles, controllerFile)
+	file, err := codegen.SourceFileFor(controllerFile)
+	if err != nil {
+		return nil, err
+	}
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("github.com/goadesign/goa"),
+	}
+	file.WriteHeader(fmt.Sprintf("%s OpenAPI Spec", api.Name), "openapi", imports)
+	if err = file.ExecuteTemplate("openapi", openAPIT, nil, api); err != nil {
+		return nil, err
+	}
+	if err = file.FormatCode(); err != nil {
+		return nil, err
+	}
+
+	return genfiles, nil
+}
+
+const openAPIT = `
+// MountController mounts the OpenAPI spec controller.
+func MountController(service *goa.Service) {
+	service.ServeFiles("/openapi.json", "openapi/openapi.json")
+	service.ServeFiles("/openapi.yaml", "openapi/openapi.yaml")
+}
+`
@@ -0,0 +1,867 @@
+package genopenapi
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_schema"
+)
+
+// Version is the version of the OpenAPI specification produced by the generator.
+const Version = "3.0.3"
+
+type (
+	// OpenAPI represents an instance of an OpenAPI 3 specification.
+	// See https://spec.openapis.org/oas/v3.0.3
+	OpenAPI struct {
+		OpenAPI      string               `json:"openapi"`
+		Info         *Info                `json:"info"`
+		Servers      []*Server            `json:"servers,omitempty"`
+		Paths        map[string]*PathItem `json:"paths"`
+		Components   *Components          `json:"components,omitempty"`
+		Tags         []*Tag               `json:"tags,omitempty"`
+		ExternalDocs *ExternalDocs        `json:"externalDocs,omitempty"`
+	}
+
+	// Info provides metadata about the API.
+	Info struct {
+		Title          string                    `json:"title"`
+		Description    string                    `json:"description,omitempty"`
+		TermsOfService string                    `json:"termsOfService,omitempty"`
+		Contact        *design.ContactDefinition `json:"contact,omitempty"`
+		License        *design.LicenseDefinition `json:"license,omitempty"`
+		Version        string                    `json:"version"`
+	}
+
+	// Server describes a server the clients may send requests to.
+	Server struct {
+		// URL is the server URL including the API base path.
+		URL string `json:"url"`
+		// Description is the server description.
+		Description string `json:"description,omitempty"`
+	}
+
+	// PathItem describes the operations available on a single path.
+	PathItem struct {
+		Get     *Operation `json:"get,omitempty"`
+		Put     *Operation `json:"put,omitempty"`
+		Post    *Operation `json:"post,omitempty"`
+		Delete  *Operation `json:"delete,omitempty"`
+		Options *Operation `json:"options,omitempty"`
+		Head    *Operation `json:"head,omitempty"`
+		Patch   *Operation `json:"patch,omitempty"`
+		Trace   *Operation `json:"trace,omitempty"`
+	}
+
+	// Operation describes a single API operation on a path.
+	Operation struct {
+		// Tags is a list of tags for API documentation control.
+		Tags []string `json:"tags,omitempty"`
+		// Summary is a short summary of what the operation does.
+		Summary string `json:"summary,omitempty"`
+		// Description is a verbose explanation of the operation behavior.
+		Description string `json:"description,omitempty"`
+		// ExternalDocs points to additional external documentation for this operation.
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+		// OperationID is a unique string used to identify the operation.
+		OperationID string `json:"operationId,omitempty"`
+		// Parameters lists the path, query, header and cookie parameters of the operation.
+		Parameters []*Parameter `json:"parameters,omitempty"`
+		// RequestBody describes the request body if the operation has a payload.
+		RequestBody *RequestBody `json:"requestBody,omitempty"`
+		// Responses is the list of possible responses indexed by HTTP status code.
+		Responses map[string]*Response `json:"responses"`
+		// Deprecated declares this operation to be deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// Security is a declaration of which security schemes are applied for this
+		// operation.
+		Security []map[string][]string `json:"security,omitempty"`
+		// Servers overrides the API servers for this operation when the action defines
+		// its own schemes.
+		Servers []*Server `json:"servers,omitempty"`
+	}
+
+	// Parameter describes a single operation parameter.
+	Parameter struct {
+		// Name of the parameter. Parameter names are case sensitive.
+		Name string `json:"name"`
+		// In is the location of the parameter.
+		// Possible values are "query", "header", "path" or "cookie".
+		In string `json:"in"`
+		// Description is a brief description of the parameter.
+		Description string `json:"description,omitempty"`
+		// Required determines whether this parameter is mandatory.
+		Required bool `json:"required,omitempty"`
+		// Deprecated declares this parameter to be deprecated.
+		Deprecated bool `json:"deprecated,omitempty"`
+		// Schema defines the type and validations of the parameter.
+		Schema *Schema `json:"schema,omitempty"`
+	}
+
+	// RequestBody describes the request body of an operation.
+	RequestBody struct {
+		// Description is a brief description of the request body.
+		Description string `json:"description,omitempty"`
+		// Content describes the request body for each supported content type.
+		Content map[string]*MediaType `json:"content"`
+		// Required determines whether the request body is mandatory.
+		Required bool `json:"required,omitempty"`
+	}
+
+	// MediaType describes the content of a request or response body for a given content type.
+	MediaType struct {
+		Schema *Schema `json:"schema,omitempty"`
+	}
+
+	// Response describes an operation response.
+	Response struct {
+		// Description of the response.
+		Description string `json:"description"`
+		// Headers lists the headers that are sent with the response.
+		Headers map[string]*Header `json:"headers,omitempty"`
+		// Content describes the response body for each content type the response may use.
+		Content map[string]*MediaType `json:"content,omitempty"`
+	}
+
+	// Header describes a response header.
+	Header struct {
+		Description string  `json:"description,omitempty"`
+		Schema      *Schema `json:"schema,omitempty"`
+	}
+
+	// Components holds the reusable objects referenced by the specification.
+	Components struct {
+		Schemas         map[string]*Schema         `json:"schemas,omitempty"`
+		SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes,omitempty"`
+	}
+
+	// SecurityScheme defines a security scheme that can be used by the operations.
+	SecurityScheme struct {
+		// Type of the security scheme: "apiKey", "http" or "oauth2".
+		Type string `json:"type"`
+		// Description of the security scheme.
+		Description string `json:"description,omitempty"`
+		// Name of the header, query or cookie parameter when type is "apiKey".
+		Name string `json:"name,omitempty"`
+		// In is the location of the API key when type is "apiKey".
+		In string `json:"in,omitempty"`
+		// Scheme is the HTTP authorization scheme when type is "http", "basic" or
+		// "bearer".
+		Scheme string `json:"scheme,omitempty"`
+		// BearerFormat hints at the format of the bearer tokens.
+		BearerFormat string `json:"bearerFormat,omitempty"`
+		// Flows describes the OAuth2 flow when type is "oauth2".
+		Flows *OAuthFlows `json:"flows,omitempty"`
+	}
+
+	// OAuthFlows lists the OAuth2 flows supported by a security scheme.
+	OAuthFlows struct {
+		Implicit          *OAuthFlow `json:"implicit,omitempty"`
+		Password          *OAuthFlow `json:"password,omitempty"`
+		ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+		AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	}
+
+	// OAuthFlow describes an OAuth2 flow.
+	OAuthFlow struct {
+		AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+		TokenURL         string            `json:"tokenUrl,omitempty"`
+		Scopes           map[string]string `json:"scopes"`
+	}
+
+	// Tag allows adding metadata to a single tag used by the operations.
+	Tag struct {
+		Name         string        `json:"name"`
+		Description  string        `json:"description,omitempty"`
+		ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	}
+
+	// ExternalDocs allows referencing an external resource for extended documentation.
+	ExternalDocs struct {
+		Description string `json:"description,omitempty"`
+		URL         string `json:"url"`
+	}
+
+	// Schema represents an OpenAPI 3 schema object, the subset of JSON schema extended with
+	// discriminators that OpenAPI supports.
+	Schema struct {
+		Ref                  string             `json:"$ref,omitempty"`
+		Title                string             `json:"title,omitempty"`
+		Description          string             `json:"description,omitempty"`
+		Type                 string             `json:"type,omitempty"`
+		Format               string             `json:"format,omitempty"`
+		Items                *Schema            `json:"items,omitempty"`
+		Properties           map[string]*Schema `json:"properties,omitempty"`
+		AdditionalProperties bool               `json:"additionalProperties,omitempty"`
+		Required             []string           `json:"required,omitempty"`
+		AllOf                []*Schema          `json:"allOf,omitempty"`
+		AnyOf                []*Schema          `json:"anyOf,omitempty"`
+		OneOf                []*Schema          `json:"oneOf,omitempty"`
+		Discriminator        *Discriminator     `json:"discriminator,omitempty"`
+		Default              interface{}        `json:"default,omitempty"`
+		Example              interface{}        `json:"example,omitempty"`
+		Enum                 []interface{}      `json:"enum,omitempty"`
+		Pattern              string             `json:"pattern,omitempty"`
+		Minimum              *float64           `json:"minimum,omitempty"`
+		Maximum              *float64           `json:"maximum,omitempty"`
+		ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     bool               `json:"exclusiveMaximum,omitempty"`
+		MultipleOf           float64            `json:"multipleOf,omitempty"`
+		MinLength            int                `json:"minLength,omitempty"`
+		MaxLength            int                `json:"maxLength,omitempty"`
+		UniqueItems          bool               `json:"uniqueItems,omitempty"`
+		MinProperties        int                `json:"minProperties,omitempty"`
+		MaxProperties        int                `json:"maxProperties,omitempty"`
+		ReadOnly             bool               `json:"readOnly,omitempty"`
+	}
+
+	// Discriminator identifies the schema used by a union value.
+	Discriminator struct {
+		// PropertyName is the name of the property that holds the discriminator value.
+		PropertyName string `json:"propertyName"`
+		// Mapping maps the discriminator values to the references of the variant schemas.
+		Mapping map[string]string `json:"mapping,omitempty"`
+	}
+)
+
+// New creates an OpenAPI 3 specification from an API definition.
+func New(api *design.APIDefinition) (*OpenAPI, error) {
+	if api == nil {
+		return nil, nil
+	}
+	// OpenAPI requires both the title and the version of the API.
+	title, version := api.Title, api.Version
+	if title == "" {
+		title = api.Name
+	}
+	if version == "" {
+		version = "1.0"
+	}
+	s := &OpenAPI{
+		OpenAPI: Version,
+		Info: &Info{
+			Title:          title,
+			Description:    api.Description,
+			TermsOfService: api.TermsOfService,
+			Contact:        api.Contact,
+			License:        api.License,
+			Version:        version,
+		},
+		Servers:      serversFromDefinition(api, api.Schemes),
+		Paths:        make(map[string]*PathItem),
+		Tags:         tagsFromDefinition(api.Metadata),
+		ExternalDocs: docsFromDefinition(api.Docs),
+	}
+	err := api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			for i, route := range a.Routes {
+				if err := buildPathFromDefinition(s, api, route, i); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	components := &Components{SecuritySchemes: securitySchemesFromDefinition(api.SecuritySchemes)}
+	if len(genschema.Definitions) > 0 {
+		components.Schemas = make(map[string]*Schema)
+		for n, d := range genschema.Definitions {
+			components.Schemas[n] = schemaFromJSONSchema(d)
+		}
+	}
+	if components.Schemas != nil || components.SecuritySchemes != nil {
+		s.Components = components
+	}
+	return s, nil
+}
+
+// serverBasePath returns the API base path included in the server URLs, the empty string if the
+// base path has wildcards in which case the paths include it and its wildcards are described
+// as path parameters.
+func serverBasePath(api *design.APIDefinition) string {
+	if len(design.ExtractWildcards(api.BasePath)) > 0 {
+		return ""
+	}
+	return api.BasePath
+}
+
+// serversFromDefinition returns the servers of the API using the given schemes.
+func serversFromDefinition(api *design.APIDefinition, schemes []string) []*Server {
+	basePath := serverBasePath(api)
+	if api.Host == "" {
+		if basePath == "" {
+			return nil
+		}
+		return []*Server{{URL: basePath}}
+	}
+	if len(schemes) == 0 {
+		schemes = []string{"http"}
+	}
+	servers := make([]*Server, len(schemes))
+	for i, scheme := range schemes {
+		servers[i] = &Server{URL: fmt.Sprintf("%s://%s%s", scheme, api.Host, basePath)}
+	}
+	return servers
+}
+
+// securitySchemesFromDefinition returns the OpenAPI security schemes corresponding to the given
+// design security schemes indexed by name.
+func securitySchemesFromDefinition(schemes []*design.SecuritySchemeDefinition) map[string]*SecurityScheme {
+	if len(schemes) == 0 {
+		return nil
+	}
+	res := make(map[string]*SecurityScheme)
+	for _, scheme := range schemes {
+		ss := &SecurityScheme{Description: scheme.Description}
+		switch scheme.Kind {
+		case design.BasicAuthSecurityKind:
+			ss.Type = "http"
+			ss.Scheme = "basic"
+		case design.APIKeySecurityKind:
+			ss.Type = "apiKey"
+			ss.Name = scheme.Name
+			ss.In = scheme.In
+		case design.JWTSecurityKind:
+			if scheme.In == "header" && strings.EqualFold(scheme.Name, "Authorization") {
+				ss.Type = "http"
+				ss.Scheme = "bearer"
+				ss.BearerFormat = "JWT"
+			} else {
+				ss.Type = "apiKey"
+				ss.Name = scheme.Name
+				ss.In = scheme.In
+			}
+			if scheme.TokenURL != "" {
+				ss.Description += fmt.Sprintf("\n\n**Token URL**: %s", scheme.TokenURL)
+			}
+			if len(scheme.Scopes) > 0 {
+				ss.Description += fmt.Sprintf("\n\n**Security Scopes**:\n%s", scopesMapList(scheme.Scopes))
+			}
+			ss.Description = strings.TrimPrefix(ss.Description, "\n\n")
+		case design.OAuth2SecurityKind:
+			ss.Type = "oauth2"
+			scopes := scheme.Scopes
+			if scopes == nil {
+				scopes = make(map[string]string)
+			}
+			flow := &OAuthFlow{
+				AuthorizationURL: scheme.AuthorizationURL,
+				TokenURL:         scheme.TokenURL,
+				Scopes:           scopes,
+			}
+			ss.Flows = new(OAuthFlows)
+			switch scheme.Flow {
+			case "implicit":
+				ss.Flows.Implicit = flow
+			case "password":
+				ss.Flows.Password = flow
+			case "application":
+				ss.Flows.ClientCredentials = flow
+			default:
+				ss.Flows.AuthorizationCode = flow
+			}
+		default:
+			continue
+		}
+		res[scheme.SchemeName] = ss
+	}
+	return res
+}
+
+// scopesMapList returns the Markdown list of the given scopes and their descriptions.
+func scopesMapList(scopes map[string]string) string {
+	names := make([]string, 0, len(scopes))
+	for name := range scopes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	lines := make([]string, len(names))
+	for i, name := range names {
+		lines[i] = fmt.Sprintf("  * `%s`: %s", name, scopes[name])
+	}
+	return strings.Join(lines, "\n")
+}
+
+// tagsFromDefinition returns the tags defined with the "swagger:tag" metadata.
+func tagsFromDefinition(mdata dslengine.MetadataDefinition) []*Tag {
+	var keys []string
+	for key := range mdata {
+		chunks := strings.Split(key, ":")
+		if len(chunks) != 3 || chunks[0] != "swagger" || chunks[1] != "tag" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var tags []*Tag
+	for _, key := range keys {
+		tag := &Tag{Name: strings.Split(key, ":")[2]}
+		if value := mdata[key+":desc"]; len(value) > 0 {
+			tag.Description = value[0]
+		}
+		if value := mdata[key+":url"]; len(value) > 0 {
+			tag.ExternalDocs = &ExternalDocs{URL: value[0]}
+			if desc := mdata[key+":url:desc"]; len(desc) > 0 {
+				tag.ExternalDocs.Description = desc[0]
+			}
+		}
+		tags = append(tags, tag)
+	}
+	return tags
+}
+
+// tagNamesFromDefinitions returns the names of the tags defined in the given metadata.
+func tagNamesFromDefinitions(mdatas ...dslengine.MetadataDefinition) []string {
+	var names []string
+	for _, mdata := range mdatas {
+		for _, tag := range tagsFromDefinition(mdata) {
+			names = append(names, tag.Name)
+		}
+	}
+	return names
+}
+
+// docsFromDefinition returns the external documentation described by docs if any.
+func docsFromDefinition(docs *design.DocsDefinition) *ExternalDocs {
+	if docs == nil {
+		return nil
+	}
+	return &ExternalDocs{Description: docs.Description, URL: docs.URL}
+}
+
+// buildPathFromDefinition records the operation describing the given route. index is the index of
+// the route in the action routes.
+func buildPathFromDefinition(s *OpenAPI, api *design.APIDefinition, route *design.RouteDefinition, index int) error {
+	action := route.Parent
+
+	params, err := paramsFromDefinition(api, action, route)
+	if err != nil {
+		return err
+	}
+	responses, err := responsesFromDefinition(api, action, route)
+	if err != nil {
+		return err
+	}
+	operationID := fmt.Sprintf("%s#%s", action.Parent.Name, action.Name)
+	if index > 0 {
+		operationID = fmt.Sprintf("%s#%d", operationID, index)
+	}
+	var summary string
+	if mdata := action.Metadata["swagger:summary"]; len(mdata) > 0 {
+		summary = mdata[0]
+	}
+	operation := &Operation{
+		Tags:         tagNamesFromDefinitions(action.Parent.Metadata, action.Metadata),
+		Summary:      summary,
+		Description:  action.Description,
+		ExternalDocs: docsFromDefinition(action.Docs),
+		OperationID:  operationID,
+		Parameters:   params,
+		RequestBody:  requestBodyFromDefinition(api, action),
+		Responses:    responses,
+		Deprecated:   action.Deprecation != nil,
+	}
+	if len(action.Schemes) > 0 && api.Host != "" && !sameSchemes(action.Schemes, api.Schemes) {
+		operation.Servers = serversFromDefinition(api, action.Schemes)
+	}
+	if sec := action.Security; sec != nil && sec.Scheme.Kind != design.NoSecurityKind {
+		scopes := []string{}
+		if sec.Scheme.Kind == design.OAuth2SecurityKind {
+			scopes = append(scopes, sec.Scopes...)
+		} else if len(sec.Scopes) > 0 {
+			sorted := append([]string(nil), sec.Scopes...)
+			sort.Strings(sorted)
+			lines := make([]string, len(sorted))
+			for i, scope := range sorted {
+				lines[i] = fmt.Sprintf("  * `%s`", scope)
+			}
+			desc := fmt.Sprintf("**Required security scopes**:\n%s", strings.Join(lines, "\n"))
+			operation.Description = strings.TrimPrefix(operation.Description+"\n\n"+desc, "\n\n")
+		}
+		operation.Security = []map[string][]string{{sec.Scheme.SchemeName: scopes}}
+	}
+
+	key := design.WildcardRegex.ReplaceAllStringFunc(
+		route.FullPath(),
+		func(w string) string {
+			return fmt.Sprintf("/{%s}", w[2:])
+		},
+	)
+	key = strings.TrimPrefix(key, serverBasePath(api))
+	if key == "" {
+		key = "/"
+	}
+	path, ok := s.Paths[key]
+	if !ok {
+		path = new(PathItem)
+		s.Paths[key] = path
+	}
+	switch route.Verb {
+	case "GET":
+		path.Get = operation
+	case "PUT":
+		path.Put = operation
+	case "POST":
+		path.Post = operation
+	case "DELETE":
+		path.Delete = operation
+	case "OPTIONS":
+		path.Options = operation
+	case "HEAD":
+		path.Head = operation
+	case "PATCH":
+		path.Patch = operation
+	case "TRACE":
+		path.Trace = operation
+	}
+	return nil
+}
+
+// sameSchemes returns true if a and b list the same schemes.
+func sameSchemes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// paramsFromDefinition returns the path, query, header and cookie parameters of the given action
+// route.
+func paramsFromDefinition(api *design.APIDefinition, action *design.ActionDefinition, route *design.RouteDefinition) ([]*Parameter, error) {
+	var res []*Parameter
+	if params := action.AllParams(); params != nil {
+		obj := params.Type.ToObject()
+		if obj == nil {
+			return nil, fmt.Errorf("invalid parameters definition, not an object")
+		}
+		wildcards := design.ExtractWildcards(route.FullPath())
+		for _, n := range sortedNames(obj) {
+			in := "query"
+			required := params.IsRequired(n)
+			for _, w := range wildcards {
+				if n == w {
+					in = "path"
+					required = true
+					break
+				}
+			}
+			res = append(res, paramFromDefinition(api, n, in, required, obj[n]))
+		}
+	}
+	for _, loc := range []struct {
+		in  string
+		att *design.AttributeDefinition
+	}{{"header", action.Headers}, {"cookie", action.Cookies}} {
+		if loc.att == nil {
+			continue
+		}
+		obj := loc.att.Type.ToObject()
+		if obj == nil {
+			return nil, fmt.Errorf("invalid %s definition, not an object", loc.in)
+		}
+		for _, n := range sortedNames(obj) {
+			res = append(res, paramFromDefinition(api, n, loc.in, loc.att.IsRequired(n), obj[n]))
+		}
+	}
+	return res, nil
+}
+
+// paramFromDefinition returns the parameter with the given name and location described by att.
+func paramFromDefinition(api *design.APIDefinition, name, in string, required bool, att *design.AttributeDefinition) *Parameter {
+	schema := attributeSchema(api, att)
+	schema.Description = ""
+	return &Parameter{
+		Name:        name,
+		In:          in,
+		Description: att.Description,
+		Required:    required,
+		Deprecated:  att.Deprecation != nil,
+		Schema:      schema,
+	}
+}
+
+// requestBodyFromDefinition returns the request body of the given action, nil if the action has
+// no payload. The body may use any of the content types the API consumes unless the payload is
+// a multipart form.
+func requestBodyFromDefinition(api *design.APIDefinition, action *design.ActionDefinition) *RequestBody {
+	if action.Payload == nil {
+		return nil
+	}
+	schema := schemaFromJSONSchema(genschema.TypeSchema(api, action.Payload))
+	content := make(map[string]*MediaType)
+	if action.PayloadMultipart {
+		content["multipart/form-data"] = &MediaType{Schema: schema}
+	} else {
+		for _, enc := range api.Consumes {
+			for _, mt := range enc.MIMETypes {
+				content[mt] = &MediaType{Schema: schema}
+			}
+		}
+		if len(content) == 0 {
+			content["application/json"] = &MediaType{Schema: schema}
+		}
+	}
+	return &RequestBody{
+		Description: action.Payload.Description,
+		Content:     content,
+		Required:    true,
+	}
+}
+
+// responsesFromDefinition returns the responses of the given action route indexed by status code.
+func responsesFromDefinition(api *design.APIDefinition, action *design.ActionDefinition, route *design.RouteDefinition) (map[string]*Response, error) {
+	responses := make(map[string]*Response, len(action.Responses))
+	for _, r := range action.Responses {
+		resp, err := responseFromDefinition(api, r)
+		if err != nil {
+			return nil, err
+		}
+		responses[strconv.Itoa(r.Status)] = resp
+	}
+
+	if rl := action.EffectiveRateLimit(); rl != nil {
+		if _, ok := responses["429"]; !ok {
+			headers, err := headersFromDefinition(api, &design.AttributeDefinition{Type: rl.Headers()})
+			if err != nil {
+				return nil, err
+			}
+			responses["429"] = &Response{
+				Description: fmt.Sprintf("Too Many Requests, the rate limit is %s", rl.Description()),
+				Headers:     headers,
+			}
+		}
+	}
+
+	errors := make(map[string][]*design.ErrorDefinition)
+	for _, e := range action.AllErrors() {
+		status := strconv.Itoa(e.Status)
+		errors[status] = append(errors[status], e)
+	}
+	for status, errs := range errors {
+		if resp, ok := responses[status]; ok {
+			resp.Description = strings.TrimPrefix(resp.Description+"\n\n"+errorsDescription(errs), "\n\n")
+			continue
+		}
+		schema := new(Schema)
+		if len(errs) == 1 {
+			schema = schemaFromJSONSchema(genschema.TypeSchema(api, errs[0].Type))
+		} else {
+			for _, e := range errs {
+				schema.OneOf = append(schema.OneOf, schemaFromJSONSchema(genschema.TypeSchema(api, e.Type)))
+			}
+		}
+		responses[status] = &Response{
+			Description: errorsDescription(errs),
+			Content:     map[string]*MediaType{design.ErrorMediaIdentifier: {Schema: schema}},
+		}
+	}
+
+	if action.Cacheable {
+		status, desc := "412", "Precondition Failed"
+		if route.Verb == "GET" || route.Verb == "HEAD" {
+			status, desc = "304", "Not Modified"
+		}
+		if _, ok := responses[status]; !ok {
+			responses[status] = &Response{Description: desc}
+		}
+	}
+
+	if action.Stream != nil {
+		resp, ok := responses["200"]
+		if !ok {
+			resp = &Response{Description: "Stream of server-sent events"}
+			responses["200"] = resp
+		}
+		var schema *Schema
+		if mt := action.Stream.EventMediaType(); mt != nil {
+			schema = schemaFromJSONSchema(genschema.TypeSchema(api, mt))
+		}
+		resp.Content = map[string]*MediaType{"text/event-stream": {Schema: schema}}
+	}
+
+	if action.WebSocket() {
+		if _, ok := responses["101"]; !ok {
+			responses["101"] = &Response{Description: "Switching Protocols, the connection is upgraded to a WebSocket"}
+		}
+	}
+
+	if len(responses) == 0 {
+		responses["default"] = &Response{Description: "Default response"}
+	}
+	return responses, nil
+}
+
+// responseFromDefinition returns the response described by r. The response body may use the
+// content type of the response media type or any of the content types the API produces.
+func responseFromDefinition(api *design.APIDefinition, r *design.ResponseDefinition) (*Response, error) {
+	headers, err := headersFromDefinition(api, r.Headers)
+	if err != nil {
+		return nil, err
+	}
+	desc := r.Description
+	if desc == "" {
+		desc = http.StatusText(r.Status)
+	}
+	resp := &Response{Description: desc, Headers: headers}
+	if r.MediaType == "" {
+		return resp, nil
+	}
+	mt, ok := api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]
+	if !ok {
+		return resp, nil
+	}
+	schema := schemaFromJSONSchema(genschema.TypeSchema(api, mt))
+	resp.Content = map[string]*MediaType{r.MediaType: {Schema: schema}}
+	if base, _, err := mime.ParseMediaType(r.MediaType); err == nil && isJSON(base) {
+		for _, enc := range api.Produces {
+			for _, ct := range enc.MIMETypes {
+				if !isJSON(ct) {
+					resp.Content[ct] = &MediaType{Schema: schema}
+				}
+			}
+		}
+	}
+	return resp, nil
+}
+
+// isJSON returns true if the given content type denotes JSON content.
+func isJSON(ct string) bool {
+	return ct == "application/json" || strings.HasSuffix(ct, "+json")
+}
+
+// headersFromDefinition returns the response headers described by headers indexed by name.
+func headersFromDefinition(api *design.APIDefinition, headers *design.AttributeDefinition) (map[string]*Header, error) {
+	if headers == nil {
+		return nil, nil
+	}
+	obj := headers.Type.ToObject()
+	if obj == nil {
+		return nil, fmt.Errorf("invalid headers definition, not an object")
+	}
+	res := make(map[string]*Header)
+	for n, att := range obj {
+		schema := attributeSchema(api, att)
+		schema.Description = ""
+		res[n] = &Header{Description: att.Description, Schema: schema}
+	}
+	return res, nil
+}
+
+// errorsDescription returns the description of the responses that carry the given typed errors.
+func errorsDescription(errors []*design.ErrorDefinition) string {
+	descs := make([]string, len(errors))
+	for i, e := range errors {
+		descs[i] = fmt.Sprintf("Error %#v", e.Name)
+		if d := e.Description(); d != "" {
+			descs[i] += ": " + d
+		}
+	}
+	return strings.Join(descs, "\n\n")
+}
+
+// attributeSchema returns the schema of the given attribute including its validations.
+func attributeSchema(api *design.APIDefinition, att *design.AttributeDefinition) *Schema {
+	// Describe the attribute as the property of an object to get the JSON schema of its
+	// validations and default value, not only the one of its type.
+	obj := genschema.TypeSchema(api, design.Object{"attribute": att})
+	return schemaFromJSONSchema(obj.Properties["attribute"])
+}
+
+// schemaFromJSONSchema converts the given JSON schema to an OpenAPI schema. The references to the
+// JSON schema definitions are rewritten to point to the specification components and union
+// discriminators map the discriminator values to the variant schemas.
+func schemaFromJSONSchema(js *genschema.JSONSchema) *Schema {
+	if js == nil {
+		return nil
+	}
+	s := &Schema{
+		Ref:                  strings.Replace(js.Ref, "#/definitions/", "#/components/schemas/", 1),
+		Title:                js.Title,
+		Description:          js.Description,
+		Type:                 string(js.Type),
+		Format:               js.Format,
+		Items:                schemaFromJSONSchema(js.Items),
+		AdditionalProperties: js.AdditionalProperties,
+		Required:             js.Required,
+		Default:              js.DefaultValue,
+		Example:              js.Example,
+		Enum:                 js.Enum,
+		Pattern:              js.Pattern,
+		ExclusiveMinimum:     js.ExclusiveMinimum,
+		ExclusiveMaximum:     js.ExclusiveMaximum,
+		MultipleOf:           js.MultipleOf,
+		MinLength:            js.MinLength,
+		MaxLength:            js.MaxLength,
+		UniqueItems:          js.UniqueItems,
+		MinProperties:        js.MinProperties,
+		MaxProperties:        js.MaxProperties,
+		ReadOnly:             js.ReadOnly,
+	}
+	if js.HasMinimum {
+		min := js.Minimum
+		s.Minimum = &min
+	}
+	if js.HasMaximum {
+		max := js.Maximum
+		s.Maximum = &max
+	}
+	switch s.Type {
+	case "any":
+		// OpenAPI schemas without type accept any value.
+		s.Type = ""
+	case "file":
+		s.Type = "string"
+		s.Format = "binary"
+	}
+	if len(js.Properties) > 0 {
+		s.Properties = make(map[string]*Schema, len(js.Properties))
+		for n, p := range js.Properties {
+			s.Properties[n] = schemaFromJSONSchema(p)
+		}
+	}
+	for _, a := range js.AllOf {
+		s.AllOf = append(s.AllOf, schemaFromJSONSchema(a))
+	}
+	for _, a := range js.AnyOf {
+		s.AnyOf = append(s.AnyOf, schemaFromJSONSchema(a))
+	}
+	for _, o := range js.OneOf {
+		s.OneOf = append(s.OneOf, schemaFromJSONSchema(o))
+	}
+	if js.Discriminator != "" {
+		s.Discriminator = &Discriminator{PropertyName: js.Discriminator}
+		// The discriminator property enumerates the variant values in the order of the
+		// variant schemas.
+		if prop, ok := js.Properties[js.Discriminator]; ok && len(prop.Enum) == len(s.OneOf) {
+			s.Discriminator.Mapping = make(map[string]string, len(s.OneOf))
+			for i, v := range prop.Enum {
+				s.Discriminator.Mapping[fmt.Sprint(v)] = s.OneOf[i].Ref
+			}
+		}
+	}
+	return s
+}
+
+// sortedNames returns the names of the attributes of the given object in alphabetical order.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,263 @@
+package genopenapi_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_schema"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var spec *genopenapi.OpenAPI
+	var newErr error
+
+	BeforeEach(func() {
+		spec = nil
+		newErr = nil
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		err := dslengine.Run()
+		Ω(err).ShouldNot(HaveOccurred())
+		spec, newErr = genopenapi.New(Design)
+	})
+
+	Context("with a valid API definition", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				Title("Cellar API")
+				Version("1.0")
+				Host("cellar.goa.design")
+				Scheme("https")
+				BasePath("/v1")
+				Consumes("application/json")
+				Consumes("application/xml")
+				Produces("application/json")
+				Produces("application/xml")
+				Metadata("swagger:tag:bottles")
+				Metadata("swagger:tag:bottles:desc", "Bottle operations")
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+					TokenURL("https://cellar.goa.design/token")
+					Scope("bottles:write", "Write bottles")
+				})
+				OAuth2Security("oauth2", func() {
+					AccessCodeFlow("https://cellar.goa.design/authorize", "https://cellar.goa.design/token")
+					Scope("bottles:read", "Read bottles")
+				})
+			})
+			BottleMedia := MediaType("application/vnd.bottle+json", func() {
+				Description("A wine bottle")
+				Attributes(func() {
+					Attribute("id", Integer, "ID")
+					Attribute("name", String)
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+				})
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				Metadata("swagger:tag:bottles")
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("id", Integer, func() {
+							Minimum(1)
+						})
+						Param("fields", String)
+					})
+					Headers(func() {
+						Header("X-Request-Id", String, "Request ID")
+						Required("X-Request-Id")
+					})
+					Cookies(func() {
+						Cookie("session", String)
+					})
+					Security("oauth2", func() {
+						Scope("bottles:read")
+					})
+					Error("not_found", 404, func() {
+						Description("The bottle does not exist")
+					})
+					Response(OK, BottleMedia)
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Member("name", String)
+						Required("name")
+					})
+					Security("jwt", func() {
+						Scope("bottles:write")
+					})
+					Response(Created)
+				})
+			})
+		})
+
+		It("describes the API", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec.OpenAPI).Should(Equal(genopenapi.Version))
+			Ω(spec.Info.Title).Should(Equal("Cellar API"))
+			Ω(spec.Info.Version).Should(Equal("1.0"))
+			Ω(spec.Servers).Should(Equal([]*genopenapi.Server{{URL: "https://cellar.goa.design/v1"}}))
+			Ω(spec.Tags).Should(Equal([]*genopenapi.Tag{{Name: "bottles", Description: "Bottle operations"}}))
+			Ω(spec.Paths).Should(HaveKey("/bottles/{id}"))
+			Ω(spec.Paths).Should(HaveKey("/bottles"))
+		})
+
+		It("describes the parameters", func() {
+			op := spec.Paths["/bottles/{id}"].Get
+			Ω(op).ShouldNot(BeNil())
+			Ω(op.OperationID).Should(Equal("bottle#show"))
+			Ω(op.Tags).Should(Equal([]string{"bottles"}))
+			Ω(op.Parameters).Should(HaveLen(4))
+			Ω(op.Parameters[0].Name).Should(Equal("fields"))
+			Ω(op.Parameters[0].In).Should(Equal("query"))
+			id := op.Parameters[1]
+			Ω(id.In).Should(Equal("path"))
+			Ω(id.Required).Should(BeTrue())
+			Ω(id.Schema.Type).Should(Equal("integer"))
+			Ω(*id.Schema.Minimum).Should(Equal(1.0))
+			Ω(op.Parameters[2]).Should(Equal(&genopenapi.Parameter{
+				Name:        "X-Request-Id",
+				In:          "header",
+				Description: "Request ID",
+				Required:    true,
+				Schema:      &genopenapi.Schema{Type: "string"},
+			}))
+			Ω(op.Parameters[3].Name).Should(Equal("session"))
+			Ω(op.Parameters[3].In).Should(Equal("cookie"))
+		})
+
+		It("describes the request bodies for each consumed content type", func() {
+			op := spec.Paths["/bottles"].Post
+			Ω(op).ShouldNot(BeNil())
+			Ω(op.RequestBody).ShouldNot(BeNil())
+			Ω(op.RequestBody.Required).Should(BeTrue())
+			Ω(op.RequestBody.Content).Should(HaveLen(2))
+			Ω(op.RequestBody.Content).Should(HaveKey("application/json"))
+			Ω(op.RequestBody.Content).Should(HaveKey("application/xml"))
+			ref := "#/components/schemas/CreateBottlePayload"
+			Ω(op.RequestBody.Content["application/json"].Schema.Ref).Should(Equal(ref))
+			Ω(spec.Components.Schemas).Should(HaveKey("CreateBottlePayload"))
+			Ω(spec.Components.Schemas["CreateBottlePayload"].Required).Should(Equal([]string{"name"}))
+		})
+
+		It("describes the responses for each produced content type", func() {
+			responses := spec.Paths["/bottles/{id}"].Get.Responses
+			Ω(responses).Should(HaveKey("200"))
+			ok := responses["200"]
+			Ω(ok.Description).Should(Equal("OK"))
+			Ω(ok.Content).Should(HaveLen(2))
+			Ω(ok.Content).Should(HaveKey("application/vnd.bottle+json"))
+			Ω(ok.Content).Should(HaveKey("application/xml"))
+			Ω(ok.Content["application/xml"].Schema.Ref).Should(Equal("#/components/schemas/Bottle"))
+			Ω(responses).Should(HaveKey("404"))
+			notFound := responses["404"]
+			Ω(notFound.Description).Should(Equal(`Error "not_found": The bottle does not exist`))
+			Ω(notFound.Content).Should(HaveKey(ErrorMediaIdentifier))
+			Ω(spec.Paths["/bottles"].Post.Responses["201"].Content).Should(BeNil())
+		})
+
+		It("describes the security schemes", func() {
+			schemes := spec.Components.SecuritySchemes
+			Ω(schemes).Should(HaveLen(2))
+			jwt := schemes["jwt"]
+			Ω(jwt.Type).Should(Equal("http"))
+			Ω(jwt.Scheme).Should(Equal("bearer"))
+			Ω(jwt.BearerFormat).Should(Equal("JWT"))
+			Ω(jwt.Description).Should(ContainSubstring("**Token URL**: https://cellar.goa.design/token"))
+			Ω(schemes["oauth2"].Type).Should(Equal("oauth2"))
+			Ω(schemes["oauth2"].Flows.AuthorizationCode).Should(Equal(&genopenapi.OAuthFlow{
+				AuthorizationURL: "https://cellar.goa.design/authorize",
+				TokenURL:         "https://cellar.goa.design/token",
+				Scopes:           map[string]string{"bottles:read": "Read bottles"},
+			}))
+
+			show := spec.Paths["/bottles/{id}"].Get
+			Ω(show.Security).Should(Equal([]map[string][]string{{"oauth2": {"bottles:read"}}}))
+			create := spec.Paths["/bottles"].Post
+			Ω(create.Security).Should(Equal([]map[string][]string{{"jwt": {}}}))
+			Ω(create.Description).Should(ContainSubstring("* `bottles:write`"))
+		})
+	})
+
+	Context("with a union type", func() {
+		BeforeEach(func() {
+			API("pay", func() {})
+			Card := Type("CardPayment", func() {
+				Attribute("number", String)
+			})
+			Bank := Type("BankPayment", func() {
+				Attribute("iban", String)
+			})
+			Payment := Type("Payment", func() {
+				Attribute("method", OneOf("type",
+					Variant("card", Card),
+					Variant("bank", Bank),
+				))
+			})
+			Resource("payment", func() {
+				Action("create", func() {
+					Routing(POST("/payments"))
+					Payload(Payment)
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("describes the union with oneOf and a discriminator", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(spec.Components.Schemas).Should(HaveKey("CreatePaymentPayload"))
+			method := spec.Components.Schemas["CreatePaymentPayload"].Properties["method"]
+			Ω(method).ShouldNot(BeNil())
+			Ω(method.OneOf).Should(HaveLen(2))
+			Ω(method.OneOf[0].Ref).Should(Equal("#/components/schemas/CardPayment"))
+			Ω(method.Discriminator).Should(Equal(&genopenapi.Discriminator{
+				PropertyName: "type",
+				Mapping: map[string]string{
+					"card": "#/components/schemas/CardPayment",
+					"bank": "#/components/schemas/BankPayment",
+				},
+			}))
+			Ω(spec.Components.Schemas).Should(HaveKey("CardPayment"))
+		})
+	})
+
+	Context("with a multipart form payload", func() {
+		BeforeEach(func() {
+			API("upload", func() {})
+			Resource("document", func() {
+				Action("upload", func() {
+					Routing(POST("/documents"))
+					Payload(func() {
+						Member("file", File)
+						Member("title", String)
+					})
+					MultipartForm()
+					Response(NoContent)
+				})
+			})
+		})
+
+		It("describes the payload with a multipart form request body", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			body := spec.Paths["/documents"].Post.RequestBody
+			Ω(body.Content).Should(HaveLen(1))
+			Ω(body.Content).Should(HaveKey("multipart/form-data"))
+			file := spec.Components.Schemas["UploadDocumentPayload"].Properties["file"]
+			Ω(file.Type).Should(Equal("string"))
+			Ω(file.Format).Should(Equal("binary"))
+			Ω(spec.Servers).Should(BeNil())
+		})
+	})
+})
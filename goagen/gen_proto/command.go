@@ -0,0 +1,47 @@
+package genproto
+
+import (
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+var (
+	// TargetPackage is the name of the Go package of the code generated from the .proto file
+	// and of the gRPC adapter.
+	TargetPackage string
+
+	// Adapter indicates whether to generate the gRPC adapter.
+	Adapter bool
+)
+
+// Command is the goa Protocol Buffers generator command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("proto", "Generate Protocol Buffers definitions and optional gRPC adapter")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().StringVar(&TargetPackage, "pkg", "proto", "Name of the Go package of the code generated from the .proto file (go_package option) and of the gRPC adapter")
+	r.Flags().BoolVar(&Adapter, "adapter", false, "Generate the gRPC adapter that dispatches the calls to the controllers mounted on the goa service")
+}
+
+// Run simply calls the meta generator.
+func (c *Command) Run() ([]string, error) {
+	flags := map[string]string{"pkg": TargetPackage}
+	if Adapter {
+		flags["adapter"] = "true"
+	}
+	gen := meta.NewGenerator(
+		"genproto.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_proto")},
+		flags,
+	)
+	return gen.Generate()
+}
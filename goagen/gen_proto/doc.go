@@ -0,0 +1,29 @@
+/*
+Package genproto provides a generator for the Protocol Buffers definitions of the API. The generated
+.proto file describes the user types, media types and payloads with messages, the resources with
+services and the actions with RPCs. Attributes validated with Enum are described by enums. Media
+type messages include all the media type attributes. WebSocket actions that declare their messages
+and streaming actions are described by streaming RPCs.
+
+The field numbers are set with the "proto:field:number" metadata of the corresponding attributes so
+that they do not depend on the output directory. The generator numbers the fields that do not set
+the metadata in alphabetical order the first time it runs and fails listing the metadata to add to
+the design the next times. Enum values and union variants keep the numbers recorded in the previously
+generated file and new values are numbered in order of declaration. The numbers may also be set with
+the "proto:enum:number" and "proto:variant:number" metadata of the attribute, for example
+Metadata("proto:enum:number", "shipped=3"), so that they do not depend on the previous file either.
+The generator reads the previously generated file to reserve the numbers of the removed fields and
+values and fails if the number of an existing field or value changes.
+The Protocol Buffers package defaults to the API name and may be set with the "proto:package"
+metadata of the API.
+
+The generator optionally writes a gRPC adapter that implements the server interfaces generated by
+protoc-gen-go-grpc. The adapter dispatches the unary calls to the controllers mounted on a goa
+service so that the same controller implementations serve both the HTTP and the gRPC requests.
+The adapter does not decode the binary messages into the goa contexts directly: it converts each
+call into an HTTP request whose body is the JSON encoding of the message, serves it with the service
+mux and converts the JSON response back. The calls therefore pay for the JSON encoding and run the
+HTTP middleware, the design must accept and produce "application/json" and the streaming RPCs are
+not implemented.
+*/
+package genproto
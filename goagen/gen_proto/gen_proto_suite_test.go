@@ -0,0 +1,13 @@
+package genproto_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGenProto(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "GenProto Suite")
+}
@@ -0,0 +1,634 @@
+package genproto
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+	"github.com/spf13/cobra"
+)
+
+// Generator is the Protocol Buffers definitions generator.
+type Generator struct {
+	genfiles []string
+}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	api := design.Design
+	g := new(Generator)
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "Protocol Buffers generator",
+		Long:  "Protocol Buffers definitions and gRPC adapter generator",
+		Run:   func(*cobra.Command, []string) { files, err = g.Generate(api) },
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
+
+// Generate writes the .proto file describing the API to the "proto" directory of the output
+// directory. The file is named after the Protocol Buffers package. Generate also writes the
+// gRPC adapter to the file "adapter.go" of the same directory if the adapter flag is set.
+func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error) {
+	go utils.Catch(nil, func() { g.Cleanup() })
+
+	defer func() {
+		if err != nil {
+			g.Cleanup()
+		}
+	}()
+
+	protoDir := filepath.Join(codegen.OutputDir, "proto")
+	if err = os.MkdirAll(protoDir, 0755); err != nil {
+		return nil, err
+	}
+	protoFile := filepath.Join(protoDir, packageName(api)+".proto")
+	var numbering *Numbering
+	if f, err2 := os.Open(protoFile); err2 == nil {
+		numbering, err = ParseNumbering(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	file, err := New(api, numbering)
+	if err != nil {
+		return nil, err
+	}
+	if imp, err2 := codegen.PackagePath(protoDir); err2 == nil {
+		imp = strings.TrimPrefix(filepath.ToSlash(imp), "src/")
+		file.GoPackage = imp + ";" + TargetPackage
+	}
+	if err = ioutil.WriteFile(protoFile, file.Source(), 0644); err != nil {
+		return nil, err
+	}
+	g.genfiles = append(g.genfiles, protoFile)
+
+	if Adapter {
+		if err = g.generateAdapter(protoDir, file); err != nil {
+			return nil, err
+		}
+	}
+	return g.genfiles, nil
+}
+
+// generateAdapter writes the gRPC adapter that dispatches the calls made to the services of the
+// given file to the controllers mounted on a goa service. The adapter goes through the HTTP
+// handlers: it encodes the messages to JSON and serves them with the service mux, see the package
+// documentation for the resulting limitations.
+func (g *Generator) generateAdapter(protoDir string, file *File) error {
+	adapterFile := filepath.Join(protoDir, "adapter.go")
+	os.Remove(adapterFile)
+	src, err := codegen.SourceFileFor(adapterFile)
+	if err != nil {
+		return err
+	}
+	g.genfiles = append(g.genfiles, adapterFile)
+	imports := []*codegen.ImportSpec{
+		codegen.SimpleImport("bytes"),
+		codegen.SimpleImport("encoding/base64"),
+		codegen.SimpleImport("encoding/json"),
+		codegen.SimpleImport("fmt"),
+		codegen.SimpleImport("io"),
+		codegen.SimpleImport("net/http"),
+		codegen.SimpleImport("net/url"),
+		codegen.SimpleImport("regexp"),
+		codegen.SimpleImport("strconv"),
+		codegen.SimpleImport("strings"),
+		codegen.SimpleImport("golang.org/x/net/context"),
+		codegen.SimpleImport("github.com/goadesign/goa"),
+		codegen.SimpleImport("google.golang.org/grpc/codes"),
+		codegen.SimpleImport("google.golang.org/grpc/metadata"),
+		codegen.SimpleImport("google.golang.org/grpc/status"),
+		codegen.SimpleImport("google.golang.org/protobuf/encoding/protojson"),
+		codegen.SimpleImport("google.golang.org/protobuf/proto"),
+		codegen.SimpleImport("google.golang.org/protobuf/reflect/protoreflect"),
+		codegen.SimpleImport("google.golang.org/protobuf/types/known/emptypb"),
+	}
+	title := fmt.Sprintf("API %q: gRPC adapter", file.Name)
+	if err := src.WriteHeader(title, TargetPackage, imports); err != nil {
+		return err
+	}
+	data := newAdapterData(file)
+	funcs := template.FuncMap{"goType": goType}
+	if err := src.ExecuteTemplate("adapter", adapterT, funcs, data); err != nil {
+		return err
+	}
+	return src.FormatCode()
+}
+
+// Cleanup removes the generated files.
+func (g *Generator) Cleanup() {
+	for _, f := range g.genfiles {
+		os.Remove(f)
+	}
+	g.genfiles = nil
+}
+
+// adapterData is the data used to render the adapter template.
+type adapterData struct {
+	// Services lists the services.
+	Services []*Service
+	// Enums lists all the enums including the nested ones.
+	Enums []*Enum
+	// Unions lists the messages that describe union values.
+	Unions []*Message
+	// Collections lists the messages that describe array types.
+	Collections []*Message
+	// Renamed lists the fields whose name differs from the attribute name.
+	Renamed []*Field
+}
+
+// newAdapterData collects the enums, unions, collections and renamed fields of the given file.
+func newAdapterData(file *File) *adapterData {
+	data := &adapterData{Services: file.Services}
+	var walk func(*Message)
+	walk = func(m *Message) {
+		data.Enums = append(data.Enums, m.Enums...)
+		if m.Discriminator != "" {
+			data.Unions = append(data.Unions, m)
+		}
+		if m.Collection {
+			data.Collections = append(data.Collections, m)
+		}
+		for _, f := range m.Fields {
+			if f.JSONName != "" {
+				data.Renamed = append(data.Renamed, f)
+			}
+		}
+		for _, n := range m.Messages {
+			walk(n)
+		}
+	}
+	for _, m := range file.Messages {
+		walk(m)
+	}
+	return data
+}
+
+// goType returns the Go type generated by protoc-gen-go for the given top level message type.
+func goType(typ string) string {
+	if typ == emptyType {
+		return "emptypb.Empty"
+	}
+	return typ
+}
+
+const adapterT = `// enumValues maps the enum value names to the values of the design attributes.
+var enumValues = map[protoreflect.FullName]map[protoreflect.Name]interface{}{
+{{ range .Enums }}	{{ printf "%q" .FullName }}: {
+{{ range .Values }}{{ if .Number }}		{{ printf "%q" .Name }}: {{ printf "%#v" .Value }},
+{{ end }}{{ end }}	},
+{{ end }}}
+
+// unions describes the messages that hold union values.
+var unions = map[protoreflect.FullName]*union{
+{{ range .Unions }}	{{ printf "%q" .FullName }}: {
+		discriminator: {{ printf "%q" .Discriminator }},
+		variants: map[protoreflect.Name]string{
+{{ range .Fields }}			{{ printf "%q" .Name }}: {{ printf "%q" .Value }},
+{{ end }}		},
+	},
+{{ end }}}
+
+// collections lists the messages that describe arrays with their "items" field.
+var collections = map[protoreflect.FullName]bool{
+{{ range .Collections }}	{{ printf "%q" .FullName }}: true,
+{{ end }}}
+
+// jsonNames maps the fields whose name differs from the design attribute name to the attribute
+// name.
+var jsonNames = map[protoreflect.FullName]string{
+{{ range .Renamed }}	{{ printf "%q" .FullName }}: {{ printf "%q" .JSONName }},
+{{ end }}}
+{{ range $svc := .Services }}
+// {{ .Name }}Adapter implements the {{ .Name }}Server gRPC interface by dispatching the unary
+// calls to the controllers of the {{ .Resource }} resource mounted on a goa service. The calls are
+// converted to HTTP requests with JSON bodies served by the service mux so that they go through the
+// HTTP middleware and encoding. The streaming calls are not implemented.
+type {{ .Name }}Adapter struct {
+	Unimplemented{{ .Name }}Server
+	service *goa.Service
+}
+
+// New{{ .Name }}Adapter creates a {{ .Name }} adapter that dispatches the calls to the
+// controllers mounted on the given service.
+func New{{ .Name }}Adapter(service *goa.Service) *{{ .Name }}Adapter {
+	return &{{ .Name }}Adapter{service: service}
+}
+{{ range .RPCs }}{{ if not (or .ClientStream .ServerStream) }}{{ if .Method }}
+// {{ .Name }} dispatches the call to the {{ .Action }} action of the {{ $svc.Resource }} resource.
+func (s *{{ $svc.Name }}Adapter) {{ .Name }}(ctx context.Context, req *{{ goType .Request }}) (*{{ goType .Response }}, error) {
+	res := new({{ goType .Response }})
+	if err := dispatch(ctx, s.service, {{ printf "%q" .Method }}, {{ printf "%q" .Path }}, req, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+{{ end }}{{ end }}{{ end }}{{ end }}
+// union describes a message that holds a union value.
+type union struct {
+	// discriminator is the name of the union discriminator attribute.
+	discriminator string
+	// variants maps the message field names to the discriminator values.
+	variants map[protoreflect.Name]string
+}
+
+// wildcardRegex matches the route wildcards.
+var wildcardRegex = regexp.MustCompile(` + "`" + `/(?::|\*)([a-zA-Z0-9_]+)` + "`" + `)
+
+// dispatch serves the HTTP request built from req with the service mux and decodes the response
+// body into res. The request fields that correspond to route wildcards are used to build the
+// request path, the "payload" field is encoded into the request body and the other fields are
+// encoded into the request query string. The incoming gRPC metadata is copied to the request
+// headers.
+func dispatch(ctx context.Context, service *goa.Service, method, path string, req, res proto.Message) error {
+	fields, _ := toValue(req.ProtoReflect()).(map[string]interface{})
+	path = wildcardRegex.ReplaceAllStringFunc(path, func(w string) string {
+		name := wildcardRegex.FindStringSubmatch(w)[1]
+		v := fmt.Sprint(fields[name])
+		delete(fields, name)
+		if w[1] == '*' {
+			return "/" + v
+		}
+		return "/" + url.PathEscape(v)
+	})
+	var body io.Reader
+	if p, ok := fields["payload"]; ok {
+		b, err := json.Marshal(p)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid payload: %s", err)
+		}
+		body = bytes.NewReader(b)
+		delete(fields, "payload")
+	}
+	query := make(url.Values)
+	for name, v := range fields {
+		if arr, ok := v.([]interface{}); ok {
+			for _, e := range arr {
+				query.Add(name, fmt.Sprint(e))
+			}
+			continue
+		}
+		query.Set(name, fmt.Sprint(v))
+	}
+	u := url.URL{Path: path, RawQuery: query.Encode()}
+	r, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	r = r.WithContext(ctx)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, vals := range md {
+			if strings.HasPrefix(k, ":") {
+				continue
+			}
+			for _, v := range vals {
+				r.Header.Add(k, v)
+			}
+		}
+	}
+	if body != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Header.Set("Accept", "application/json")
+
+	w := &responseWriter{header: make(http.Header)}
+	service.Mux.ServeHTTP(w, r)
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status >= 400 {
+		return status.Error(grpcCode(w.status), errorMessage(w))
+	}
+	if w.body.Len() == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(&w.body)
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return status.Errorf(codes.Internal, "invalid response body: %s", err)
+	}
+	if err := fromValue(v, res.ProtoReflect()); err != nil {
+		return status.Errorf(codes.Internal, "invalid response body: %s", err)
+	}
+	return nil
+}
+
+// responseWriter records the response written by the service mux.
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response headers.
+func (w *responseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the response status.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write records the response body.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// grpcCode returns the gRPC status code corresponding to the given HTTP status.
+func grpcCode(status int) codes.Code {
+	switch status {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	}
+	if status >= 500 {
+		return codes.Internal
+	}
+	return codes.Unknown
+}
+
+// errorMessage returns the detail of the goa error written in the response body if any, the
+// response body otherwise.
+func errorMessage(w *responseWriter) string {
+	var e struct {
+		Detail string ` + "`" + `json:"detail"` + "`" + `
+	}
+	if err := json.Unmarshal(w.body.Bytes(), &e); err == nil && e.Detail != "" {
+		return e.Detail
+	}
+	if msg := strings.TrimSpace(w.body.String()); msg != "" {
+		return msg
+	}
+	return http.StatusText(w.status)
+}
+
+// jsonName returns the name of the design attribute described by the given field.
+func jsonName(fd protoreflect.FieldDescriptor) string {
+	if n, ok := jsonNames[fd.FullName()]; ok {
+		return n
+	}
+	return string(fd.Name())
+}
+
+// toValue returns the JSON value described by the given message.
+func toValue(m protoreflect.Message) interface{} {
+	desc := m.Descriptor()
+	switch desc.FullName() {
+	case "google.protobuf.Value", "google.protobuf.ListValue", "google.protobuf.Struct":
+		b, err := protojson.Marshal(m.Interface())
+		if err != nil {
+			return nil
+		}
+		var v interface{}
+		json.Unmarshal(b, &v)
+		return v
+	}
+	if u, ok := unions[desc.FullName()]; ok {
+		var res map[string]interface{}
+		m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+			res, _ = toValue(v.Message()).(map[string]interface{})
+			if res == nil {
+				res = make(map[string]interface{})
+			}
+			res[u.discriminator] = u.variants[fd.Name()]
+			return false
+		})
+		return res
+	}
+	if collections[desc.FullName()] {
+		fd := desc.Fields().ByName("items")
+		return fieldValue(fd, m.Get(fd))
+	}
+	res := make(map[string]interface{})
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if (fd.HasPresence() || fd.IsList() || fd.IsMap()) && !m.Has(fd) {
+			continue
+		}
+		res[jsonName(fd)] = fieldValue(fd, m.Get(fd))
+	}
+	return res
+}
+
+// fieldValue returns the JSON value of the given field value.
+func fieldValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch {
+	case fd.IsList():
+		l := v.List()
+		res := make([]interface{}, l.Len())
+		for i := 0; i < l.Len(); i++ {
+			res[i] = scalarValue(fd, l.Get(i))
+		}
+		return res
+	case fd.IsMap():
+		res := make(map[string]interface{})
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			res[k.String()] = scalarValue(fd.MapValue(), v)
+			return true
+		})
+		return res
+	}
+	return scalarValue(fd, v)
+}
+
+// scalarValue returns the JSON value of the given singular value.
+func scalarValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return toValue(v.Message())
+	case protoreflect.EnumKind:
+		ev := fd.Enum().Values().ByNumber(v.Enum())
+		if ev == nil {
+			return nil
+		}
+		return enumValues[fd.Enum().FullName()][ev.Name()]
+	}
+	return v.Interface()
+}
+
+// fromValue initializes the given message with the given JSON value. Numbers must be decoded as
+// json.Number values.
+func fromValue(v interface{}, m protoreflect.Message) error {
+	desc := m.Descriptor()
+	switch desc.FullName() {
+	case "google.protobuf.Value", "google.protobuf.ListValue", "google.protobuf.Struct":
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return protojson.Unmarshal(b, m.Interface())
+	}
+	if collections[desc.FullName()] {
+		v = map[string]interface{}{"items": v}
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s: expected an object, got %T", desc.FullName(), v)
+	}
+	if u, ok := unions[desc.FullName()]; ok {
+		disc, _ := obj[u.discriminator].(string)
+		for name, value := range u.variants {
+			if value != disc {
+				continue
+			}
+			fd := desc.Fields().ByName(name)
+			val := m.NewField(fd)
+			if err := fromValue(obj, val.Message()); err != nil {
+				return err
+			}
+			m.Set(fd, val)
+			return nil
+		}
+		return fmt.Errorf("%s: unknown variant %#v", desc.FullName(), disc)
+	}
+	fields := desc.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		fv, ok := obj[jsonName(fd)]
+		if !ok || fv == nil {
+			continue
+		}
+		switch {
+		case fd.IsList():
+			arr, ok := fv.([]interface{})
+			if !ok {
+				return fmt.Errorf("%s: expected an array, got %T", fd.FullName(), fv)
+			}
+			l := m.Mutable(fd).List()
+			for _, e := range arr {
+				val, err := scalarFromValue(fd, e, l.NewElement)
+				if err != nil {
+					return err
+				}
+				l.Append(val)
+			}
+		case fd.IsMap():
+			h, ok := fv.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s: expected an object, got %T", fd.FullName(), fv)
+			}
+			mp := m.Mutable(fd).Map()
+			for k, e := range h {
+				key, err := mapKey(fd.MapKey(), k)
+				if err != nil {
+					return err
+				}
+				val, err := scalarFromValue(fd.MapValue(), e, mp.NewValue)
+				if err != nil {
+					return err
+				}
+				mp.Set(key, val)
+			}
+		default:
+			val, err := scalarFromValue(fd, fv, func() protoreflect.Value { return m.NewField(fd) })
+			if err != nil {
+				return err
+			}
+			m.Set(fd, val)
+		}
+	}
+	return nil
+}
+
+// scalarFromValue returns the singular value of the given field initialized with the given JSON
+// value. newMessage creates the message values.
+func scalarFromValue(fd protoreflect.FieldDescriptor, v interface{}, newMessage func() protoreflect.Value) (protoreflect.Value, error) {
+	invalid := func() (protoreflect.Value, error) {
+		return protoreflect.Value{}, fmt.Errorf("%s: invalid value %#v", fd.FullName(), v)
+	}
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		val := newMessage()
+		return val, fromValue(v, val.Message())
+	case protoreflect.EnumKind:
+		for name, value := range enumValues[fd.Enum().FullName()] {
+			if fmt.Sprint(value) == fmt.Sprint(v) {
+				return protoreflect.ValueOfEnum(fd.Enum().Values().ByName(name).Number()), nil
+			}
+		}
+		return invalid()
+	case protoreflect.BoolKind:
+		if b, ok := v.(bool); ok {
+			return protoreflect.ValueOfBool(b), nil
+		}
+	case protoreflect.StringKind:
+		if s, ok := v.(string); ok {
+			return protoreflect.ValueOfString(s), nil
+		}
+	case protoreflect.BytesKind:
+		if s, ok := v.(string); ok {
+			if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+				return protoreflect.ValueOfBytes(b), nil
+			}
+		}
+	case protoreflect.Int64Kind:
+		if n, ok := v.(json.Number); ok {
+			if i, err := n.Int64(); err == nil {
+				return protoreflect.ValueOfInt64(i), nil
+			}
+		}
+	case protoreflect.DoubleKind:
+		if n, ok := v.(json.Number); ok {
+			if f, err := n.Float64(); err == nil {
+				return protoreflect.ValueOfFloat64(f), nil
+			}
+		}
+	}
+	return invalid()
+}
+
+// mapKey returns the map key of the given kind corresponding to the given JSON object key.
+func mapKey(fd protoreflect.FieldDescriptor, k string) (protoreflect.MapKey, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, err := strconv.ParseBool(k)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("%s: invalid key %#v", fd.FullName(), k)
+		}
+		return protoreflect.ValueOfBool(b).MapKey(), nil
+	case protoreflect.Int64Kind:
+		i, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			return protoreflect.MapKey{}, fmt.Errorf("%s: invalid key %#v", fd.FullName(), k)
+		}
+		return protoreflect.ValueOfInt64(i).MapKey(), nil
+	}
+	return protoreflect.ValueOfString(k).MapKey(), nil
+}
+`
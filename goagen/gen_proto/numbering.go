@@ -0,0 +1,245 @@
+package genproto
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Numbering records the numbers of the fields and enum values of a previously generated
+	// .proto file. It makes it possible to regenerate the file without changing the numbers of
+	// the existing fields, which would break the compatibility of the encoded messages.
+	Numbering struct {
+		scopes map[string]*numberScope
+	}
+
+	// numberScope records the numbers used in a message or an enum.
+	numberScope struct {
+		// numbers indexes the field or value numbers by name.
+		numbers map[string]int
+		// reserved lists the reserved numbers.
+		reserved []int
+		// reservedNames lists the reserved names.
+		reservedNames []string
+	}
+)
+
+var (
+	// scopeRegex matches the lines that open a message or enum definition.
+	scopeRegex = regexp.MustCompile(`^(message|enum|oneof|service)\s+(\w+)\s*{`)
+	// numberRegex matches the field and enum value definitions.
+	numberRegex = regexp.MustCompile(`^(?:(?:optional|repeated)\s+)?(?:(?:map<[^>]*>|[\w.]+)\s+)?(\w+)\s*=\s*(-?\d+)\s*[;\[]`)
+	// reservedRegex matches the reserved statements.
+	reservedRegex = regexp.MustCompile(`^reserved\s+([^;]+);`)
+)
+
+// ParseNumbering reads the .proto file generated by goagen from r and records the numbers of its
+// fields and enum values.
+func ParseNumbering(r io.Reader) (*Numbering, error) {
+	n := &Numbering{scopes: make(map[string]*numberScope)}
+	var stack []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		if m := scopeRegex.FindStringSubmatch(line); m != nil {
+			name := m[2]
+			switch m[1] {
+			case "oneof":
+				// Oneof fields belong to the enclosing message.
+				name = ""
+			case "service":
+				name = "-"
+			}
+			stack = append(stack, name)
+			continue
+		}
+		if line == "}" {
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("unexpected closing brace")
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		scope := scopeName(stack)
+		if scope == "" {
+			continue
+		}
+		if m := reservedRegex.FindStringSubmatch(line); m != nil {
+			s := n.scope(scope)
+			for _, r := range strings.Split(m[1], ",") {
+				r = strings.TrimSpace(r)
+				if strings.HasPrefix(r, `"`) {
+					s.reservedNames = append(s.reservedNames, strings.Trim(r, `"`))
+					continue
+				}
+				num, err := strconv.Atoi(r)
+				if err != nil {
+					return nil, fmt.Errorf("invalid reserved number %#v in %s", r, scope)
+				}
+				s.reserved = append(s.reserved, num)
+			}
+			continue
+		}
+		if m := numberRegex.FindStringSubmatch(line); m != nil {
+			num, _ := strconv.Atoi(m[2])
+			n.scope(scope).numbers[m[1]] = num
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// scopeName returns the name of the scope identified by the given stack of message and enum
+// names, empty if the stack does not identify a message or an enum.
+func scopeName(stack []string) string {
+	var elems []string
+	for _, s := range stack {
+		if s == "-" {
+			return ""
+		}
+		if s != "" {
+			elems = append(elems, s)
+		}
+	}
+	return strings.Join(elems, ".")
+}
+
+// scope returns the numbers recorded for the given scope creating it if needed.
+func (n *Numbering) scope(name string) *numberScope {
+	if n.scopes == nil {
+		n.scopes = make(map[string]*numberScope)
+	}
+	s, ok := n.scopes[name]
+	if !ok {
+		s = &numberScope{numbers: make(map[string]int)}
+		n.scopes[name] = s
+	}
+	return s
+}
+
+// number returns the number of the field or enum value with the given name of the given scope in
+// the previous file if any.
+func (n *Numbering) number(scope, name string) (int, bool) {
+	s, ok := n.scopes[scope]
+	if !ok {
+		return 0, false
+	}
+	num, ok := s.numbers[name]
+	return num, ok
+}
+
+// assign computes the numbers of the fields or enum values of the given scope. Pinned numbers are
+// used as is and must match the previous numbers, names that were previously numbered keep their
+// number and the other names get numbers following the largest number used or reserved in
+// alphabetical order. The numbers and names of the previous fields or values that no longer exist
+// are returned as reserved so that they cannot be reused.
+func (n *Numbering) assign(scope string, names []string, pinned map[string]int) (map[string]int, []int, []string, error) {
+	prev := n.scopes[scope]
+	if prev == nil {
+		prev = &numberScope{numbers: make(map[string]int)}
+	}
+	current := make(map[string]bool, len(names))
+	for _, name := range names {
+		current[name] = true
+	}
+	for name := range pinned {
+		current[name] = true
+	}
+
+	reserved := make(map[int]bool)
+	for _, r := range prev.reserved {
+		reserved[r] = true
+	}
+	reservedNames := make(map[string]bool)
+	for _, r := range prev.reservedNames {
+		if !current[r] {
+			reservedNames[r] = true
+		}
+	}
+	for name, num := range prev.numbers {
+		if !current[name] {
+			reserved[num] = true
+			reservedNames[name] = true
+		}
+	}
+
+	numbers := make(map[string]int, len(current))
+	used := make(map[int]string)
+	pinnedNames := make([]string, 0, len(pinned))
+	for name := range pinned {
+		pinnedNames = append(pinnedNames, name)
+	}
+	sort.Strings(pinnedNames)
+	for _, name := range pinnedNames {
+		num := pinned[name]
+		if other, ok := used[num]; ok {
+			return nil, nil, nil, fmt.Errorf("%s and %s use the same number %d", other, name, num)
+		}
+		if reserved[num] {
+			return nil, nil, nil, fmt.Errorf("number %d of %s is reserved", num, name)
+		}
+		if prevNum, ok := prev.numbers[name]; ok && prevNum != num {
+			return nil, nil, nil, fmt.Errorf("the number of %s changes from %d to %d, which breaks the compatibility of the encoded messages", name, prevNum, num)
+		}
+		numbers[name] = num
+		used[num] = name
+	}
+	for _, name := range names {
+		if _, ok := numbers[name]; ok {
+			continue
+		}
+		if num, ok := prev.numbers[name]; ok && !reserved[num] {
+			if _, taken := used[num]; !taken {
+				numbers[name] = num
+				used[num] = name
+			}
+		}
+	}
+	next := 1
+	for num := range used {
+		if num >= next {
+			next = num + 1
+		}
+	}
+	for num := range reserved {
+		if num >= next {
+			next = num + 1
+		}
+	}
+	for _, name := range names {
+		if _, ok := numbers[name]; ok {
+			continue
+		}
+		if next >= firstReservedNumber && next <= lastReservedNumber {
+			next = lastReservedNumber + 1
+		}
+		numbers[name] = next
+		used[next] = name
+		next++
+	}
+
+	var res []int
+	for num := range reserved {
+		res = append(res, num)
+	}
+	sort.Ints(res)
+	var resNames []string
+	for name := range reservedNames {
+		resNames = append(resNames, name)
+	}
+	sort.Strings(resNames)
+	return numbers, res, resNames, nil
+}
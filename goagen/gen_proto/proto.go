@@ -0,0 +1,856 @@
+package genproto
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+const (
+	// emptyType is the name of the message returned by the RPCs of actions that do not
+	// define a response media type.
+	emptyType = "google.protobuf.Empty"
+	// valueType is the name of the message used to describe values that cannot be described
+	// with a Protocol Buffers type, e.g. nested arrays or values of type Any.
+	valueType = "google.protobuf.Value"
+	// listValueType is the name of the message used to describe the elements of nested arrays.
+	listValueType = "google.protobuf.ListValue"
+	// structType is the name of the message used to describe hashes whose keys cannot be
+	// described with a Protocol Buffers map key type.
+	structType = "google.protobuf.Struct"
+
+	// maxFieldNumber is the largest valid field number.
+	maxFieldNumber = 1<<29 - 1
+	// maxEnumNumber is the largest valid enum value number.
+	maxEnumNumber = 1<<31 - 1
+	// firstReservedNumber and lastReservedNumber delimit the field numbers reserved by the
+	// Protocol Buffers implementation.
+	firstReservedNumber = 19000
+	lastReservedNumber  = 19999
+)
+
+// identifierRegex matches the valid Protocol Buffers identifiers.
+var identifierRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+type (
+	// File describes a .proto file.
+	File struct {
+		// Name is the API name.
+		Name string
+		// Package is the Protocol Buffers package.
+		Package string
+		// GoPackage is the value of the go_package option, the option is omitted if empty.
+		GoPackage string
+		// Imports lists the imported .proto files.
+		Imports []string
+		// Messages lists the top level messages in alphabetical order.
+		Messages []*Message
+		// Services lists the services in alphabetical order.
+		Services []*Service
+	}
+
+	// Message describes a message.
+	Message struct {
+		// Name is the message name.
+		Name string
+		// FullName is the fully qualified message name.
+		FullName string
+		// Description is the message description.
+		Description string
+		// Fields lists the message fields ordered by number.
+		Fields []*Field
+		// Oneof is the name of the oneof that groups all the message fields if any. Messages
+		// describing union values use a oneof with one field per variant.
+		Oneof string
+		// Discriminator is the name of the discriminator attribute of the union described by
+		// the message if any.
+		Discriminator string
+		// Collection is true if the message describes an array type, the array elements are
+		// described by the "items" field.
+		Collection bool
+		// Messages lists the nested messages.
+		Messages []*Message
+		// Enums lists the nested enums.
+		Enums []*Enum
+		// Reserved lists the reserved field numbers.
+		Reserved []int
+		// ReservedNames lists the reserved field names.
+		ReservedNames []string
+	}
+
+	// Field describes a message field.
+	Field struct {
+		// Name is the field name.
+		Name string
+		// FullName is the fully qualified field name.
+		FullName string
+		// JSONName is the name of the attribute described by the field if different from
+		// the field name.
+		JSONName string
+		// Type is the field type, e.g. "string", "Bottle" or "map<string, int64>".
+		Type string
+		// Label is "optional", "repeated" or empty.
+		Label string
+		// Number is the field number.
+		Number int
+		// Description is the field description.
+		Description string
+		// Value is the discriminator value of the union variant described by the field if
+		// any.
+		Value string
+	}
+
+	// Enum describes an enum built from the values of an Enum validation.
+	Enum struct {
+		// Name is the enum name.
+		Name string
+		// FullName is the fully qualified enum name.
+		FullName string
+		// Description is the enum description.
+		Description string
+		// Values lists the enum values ordered by number, the first value is the zero value.
+		Values []*EnumValue
+		// Reserved lists the reserved value numbers.
+		Reserved []int
+		// ReservedNames lists the reserved value names.
+		ReservedNames []string
+	}
+
+	// EnumValue describes an enum value.
+	EnumValue struct {
+		// Name is the value name.
+		Name string
+		// Number is the value number.
+		Number int
+		// Value is the value of the design attribute, nil for the zero value.
+		Value interface{}
+	}
+
+	// Service describes the service built from a resource.
+	Service struct {
+		// Name is the service name.
+		Name string
+		// Resource is the name of the resource.
+		Resource string
+		// Description is the service description.
+		Description string
+		// RPCs lists the service RPCs in alphabetical order.
+		RPCs []*RPC
+	}
+
+	// RPC describes the RPC built from an action.
+	RPC struct {
+		// Name is the RPC name.
+		Name string
+		// Action is the name of the action.
+		Action string
+		// Description is the RPC description.
+		Description string
+		// Request is the request message type.
+		Request string
+		// Response is the response message type.
+		Response string
+		// ClientStream is true if the client sends a stream of messages.
+		ClientStream bool
+		// ServerStream is true if the server sends a stream of messages.
+		ServerStream bool
+		// Method is the HTTP method of the action first route.
+		Method string
+		// Path is the full path of the action first route.
+		Path string
+	}
+
+	// builder builds the messages and services of a file.
+	builder struct {
+		api       *design.APIDefinition
+		numbering *Numbering
+		file      *File
+		imports   map[string]bool
+		// unpinned lists the fields numbered by the previous file whose attributes do not
+		// set the "proto:field:number" metadata.
+		unpinned []string
+	}
+
+	// messagesByName sorts messages by name.
+	messagesByName []*Message
+
+	// fieldsByNumber sorts fields by number.
+	fieldsByNumber []*Field
+
+	// enumValuesByNumber sorts enum values by number.
+	enumValuesByNumber []*EnumValue
+
+	// responsesByStatus sorts responses by status.
+	responsesByStatus []*design.ResponseDefinition
+)
+
+// New builds the .proto file describing the given API. numbering records the numbers of the
+// previously generated file if any, the numbers of the fields and enum values that were removed
+// are reserved. New returns an error if a field of the previous file is not pinned with the
+// "proto:field:number" metadata or if the number of a field or enum value changes: the numbers
+// must not depend on the presence of the previous file.
+func New(api *design.APIDefinition, numbering *Numbering) (*File, error) {
+	if numbering == nil {
+		numbering = &Numbering{}
+	}
+	b := &builder{
+		api:       api,
+		numbering: numbering,
+		file:      &File{Name: api.Name, Package: packageName(api)},
+		imports:   make(map[string]bool),
+	}
+	if err := b.build(); err != nil {
+		return nil, err
+	}
+	return b.file, nil
+}
+
+// packageName returns the Protocol Buffers package of the API: the value of the "proto:package"
+// metadata if any, the snake case version of the API name otherwise.
+func packageName(api *design.APIDefinition) string {
+	if p, ok := api.Metadata["proto:package"]; ok && len(p) > 0 && p[0] != "" {
+		return p[0]
+	}
+	return identifier(codegen.SnakeCase(api.Name), "api")
+}
+
+// build builds the file messages and services.
+func (b *builder) build() error {
+	var types []*design.UserTypeDefinition
+	for _, ut := range b.api.Types {
+		types = append(types, ut)
+	}
+	for _, mt := range b.api.MediaTypes {
+		types = append(types, mt.UserTypeDefinition)
+	}
+	var services []*Service
+	err := b.api.IterateResources(func(res *design.ResourceDefinition) error {
+		svc := &Service{
+			Name:        codegen.Goify(res.Name, true) + "Service",
+			Resource:    res.Name,
+			Description: res.Description,
+		}
+		err := res.IterateActions(func(a *design.ActionDefinition) error {
+			if a.Payload != nil && a.Payload.Type.IsObject() {
+				types = append(types, a.Payload)
+			}
+			rpc, err := b.buildRPC(a)
+			if err != nil || rpc == nil {
+				return err
+			}
+			svc.RPCs = append(svc.RPCs, rpc)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(svc.RPCs) > 0 {
+			services = append(services, svc)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	seen := make(map[*design.UserTypeDefinition]bool)
+	for _, ut := range types {
+		if seen[ut] {
+			continue
+		}
+		seen[ut] = true
+		if err := b.buildTypeMessage(ut); err != nil {
+			return err
+		}
+	}
+	names := make(map[string]bool)
+	for _, m := range b.file.Messages {
+		if names[m.Name] {
+			return fmt.Errorf("proto: message name %s is used by multiple types", m.Name)
+		}
+		names[m.Name] = true
+	}
+	for _, s := range services {
+		if names[s.Name] {
+			return fmt.Errorf("proto: service name %s of resource %#v is already used by a message", s.Name, s.Resource)
+		}
+	}
+	if len(b.unpinned) > 0 {
+		sort.Strings(b.unpinned)
+		return fmt.Errorf("proto: the numbers of the fields of the previously generated file must be set with the \"proto:field:number\" metadata of the corresponding attributes:\n\t%s",
+			strings.Join(b.unpinned, "\n\t"))
+	}
+	sort.Sort(messagesByName(b.file.Messages))
+	b.file.Services = services
+	for imp := range b.imports {
+		b.file.Imports = append(b.file.Imports, imp)
+	}
+	sort.Strings(b.file.Imports)
+	return nil
+}
+
+// buildTypeMessage builds the message describing the given user type or media type. Media type
+// messages include all the media type attributes: views render subsets of them and all the fields
+// of the message are optional.
+func (b *builder) buildTypeMessage(ut *design.UserTypeDefinition) error {
+	name := codegen.Goify(ut.TypeName, true)
+	if arr := ut.Type.ToArray(); arr != nil {
+		// Collections are described by a message whose "items" field lists the elements.
+		obj := design.Object{"items": &design.AttributeDefinition{
+			Type:     arr,
+			Metadata: dslengine.MetadataDefinition{"proto:field:number": {"1"}},
+		}}
+		m, err := b.buildMessage(name, name, ut.Description, &design.AttributeDefinition{Type: obj}, obj)
+		if err != nil {
+			return err
+		}
+		m.Collection = true
+		b.file.Messages = append(b.file.Messages, m)
+		return nil
+	}
+	if _, ok := ut.Type.(*design.Union); ok {
+		m, err := b.buildUnion(name, name, ut.Description, ut.AttributeDefinition)
+		if err != nil {
+			return err
+		}
+		b.file.Messages = append(b.file.Messages, m)
+		return nil
+	}
+	obj := ut.Type.ToObject()
+	if obj == nil {
+		return fmt.Errorf("proto: type %s cannot be described by a message, it must be an object or an array", ut.TypeName)
+	}
+	m, err := b.buildMessage(name, name, ut.Description, ut.AttributeDefinition, obj)
+	if err != nil {
+		return err
+	}
+	b.file.Messages = append(b.file.Messages, m)
+	return nil
+}
+
+// buildRPC builds the RPC describing the given action. It returns nil if the action cannot be
+// described by a RPC, that is if it is a WebSocket action that does not declare its messages.
+func (b *builder) buildRPC(a *design.ActionDefinition) (*RPC, error) {
+	if a.WebSocket() && a.Messages == nil {
+		return nil, nil
+	}
+	rpc := &RPC{
+		Name:        codegen.Goify(a.Name, true),
+		Action:      a.Name,
+		Description: a.Description,
+		Response:    emptyType,
+	}
+	if len(a.Routes) > 0 {
+		rpc.Method = a.Routes[0].Verb
+		rpc.Path = a.Routes[0].FullPath()
+	}
+	if m := a.Messages; m != nil && m.Inbound != nil {
+		rpc.Request = codegen.Goify(m.InboundType().TypeName, true)
+		rpc.ClientStream = true
+	} else {
+		req, err := b.buildRequest(a)
+		if err != nil {
+			return nil, err
+		}
+		b.file.Messages = append(b.file.Messages, req)
+		rpc.Request = req.Name
+	}
+	switch {
+	case a.Messages != nil:
+		if a.Messages.Outbound != nil {
+			rpc.Response = codegen.Goify(a.Messages.OutboundType().TypeName, true)
+			rpc.ServerStream = true
+		}
+	case a.Stream != nil:
+		mt := b.api.MediaTypeWithIdentifier(a.Stream.MediaType)
+		if mt == nil {
+			return nil, fmt.Errorf("proto: unknown media type %#v streamed by %s", a.Stream.MediaType, a.Context())
+		}
+		rpc.Response = codegen.Goify(mt.TypeName, true)
+		rpc.ServerStream = true
+	default:
+		if mt := b.successMediaType(a); mt != nil {
+			rpc.Response = codegen.Goify(mt.TypeName, true)
+		}
+	}
+	if rpc.Response == emptyType {
+		b.imports["google/protobuf/empty.proto"] = true
+	}
+	return rpc, nil
+}
+
+// buildRequest builds the message describing the requests made to the given action. The message
+// has one field per parameter and a "payload" field describing the request body if any.
+func (b *builder) buildRequest(a *design.ActionDefinition) (*Message, error) {
+	name := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true) + "Request"
+	desc := fmt.Sprintf("%s is the request message of the %s action of the %s resource.", name, a.Name, a.Parent.Name)
+	att := &design.AttributeDefinition{Type: design.Object{}}
+	obj := att.Type.ToObject()
+	if params := a.AllParams(); params != nil {
+		for n, p := range params.Type.ToObject() {
+			obj[n] = p
+		}
+		att.Validation = params.Validation
+	}
+	if a.Payload != nil {
+		if _, ok := obj["payload"]; ok {
+			return nil, fmt.Errorf("proto: %s defines both a payload and a parameter named \"payload\"", a.Context())
+		}
+		payload := &design.AttributeDefinition{
+			Type:        a.Payload,
+			Description: "payload is the request body.",
+			Metadata:    a.Payload.Metadata,
+		}
+		if !a.Payload.Type.IsObject() {
+			payload.Type = a.Payload.Type
+		}
+		obj["payload"] = payload
+	}
+	return b.buildMessage(name, name, desc, att, obj)
+}
+
+// successMediaType returns the media type of the first successful response of the given action,
+// nil if there isn't one.
+func (b *builder) successMediaType(a *design.ActionDefinition) *design.MediaTypeDefinition {
+	var responses []*design.ResponseDefinition
+	for _, r := range a.Responses {
+		if r.Status >= 200 && r.Status < 300 && r.MediaType != "" {
+			responses = append(responses, r)
+		}
+	}
+	sort.Sort(responsesByStatus(responses))
+	for _, r := range responses {
+		if mt := b.api.MediaTypeWithIdentifier(r.MediaType); mt != nil {
+			return mt
+		}
+	}
+	return nil
+}
+
+// buildMessage builds the message with the given name describing the attributes of obj. parent
+// is the attribute whose type is obj, it defines the required attributes. fullName is the message
+// name qualified with the names of its parent messages.
+func (b *builder) buildMessage(name, fullName, desc string, parent *design.AttributeDefinition, obj design.Object) (*Message, error) {
+	m := &Message{Name: name, FullName: b.file.Package + "." + fullName, Description: desc}
+	attNames := make([]string, 0, len(obj))
+	for n := range obj {
+		attNames = append(attNames, n)
+	}
+	sort.Strings(attNames)
+	fieldNames := make([]string, len(attNames))
+	pinned := make(map[string]int)
+	seen := make(map[string]string)
+	for i, n := range attNames {
+		fn := identifier(n, "field")
+		if other, ok := seen[fn]; ok {
+			return nil, fmt.Errorf("proto: attributes %#v and %#v of %s map to the same field name %s", other, n, name, fn)
+		}
+		seen[fn] = n
+		fieldNames[i] = fn
+		if num, ok, err := pinnedNumber(obj[n]); err != nil {
+			return nil, fmt.Errorf("proto: invalid field number of attribute %#v of %s: %s", n, name, err)
+		} else if ok {
+			pinned[fn] = num
+		} else if num, ok := b.numbering.number(fullName, fn); ok {
+			b.unpinned = append(b.unpinned, fmt.Sprintf("attribute %#v of %s: Metadata(\"proto:field:number\", \"%d\")", n, fullName, num))
+		}
+	}
+	numbers, reserved, reservedNames, err := b.numbering.assign(fullName, fieldNames, pinned)
+	if err != nil {
+		return nil, fmt.Errorf("proto: %s: %s", name, err)
+	}
+	m.Reserved, m.ReservedNames = reserved, reservedNames
+	nested := make(map[string]bool)
+	for i, n := range attNames {
+		att := obj[n]
+		f := &Field{
+			Name:        fieldNames[i],
+			FullName:    m.FullName + "." + fieldNames[i],
+			Number:      numbers[fieldNames[i]],
+			Description: att.Description,
+		}
+		if f.Name != n {
+			f.JSONName = n
+		}
+		typ, repeated, err := b.fieldType(m, fullName, n, att, nested)
+		if err != nil {
+			return nil, err
+		}
+		f.Type = typ
+		switch {
+		case repeated:
+			f.Label = "repeated"
+		case !parent.IsRequired(n) && (isScalar(typ) || m.enum(typ) != nil):
+			f.Label = "optional"
+		}
+		m.Fields = append(m.Fields, f)
+	}
+	sort.Sort(fieldsByNumber(m.Fields))
+	return m, nil
+}
+
+// buildUnion builds the message describing the values of the union type of the given attribute.
+// The message fields are grouped in a oneof and describe the union variants. The variants keep the
+// numbers of the previous file or the numbers set with the "proto:variant:number" metadata of the
+// attribute, the new variants are numbered in order of declaration.
+func (b *builder) buildUnion(name, fullName, desc string, att *design.AttributeDefinition) (*Message, error) {
+	u := att.Type.(*design.Union)
+	pins, err := pinnedValueNumbers(att, "proto:variant:number", fieldNumber)
+	if err != nil {
+		return nil, fmt.Errorf("proto: invalid variant number of %s: %s", name, err)
+	}
+	m := &Message{
+		Name:          name,
+		FullName:      b.file.Package + "." + fullName,
+		Description:   desc,
+		Oneof:         "value",
+		Discriminator: u.Discriminator,
+	}
+	fieldNames := make([]string, len(u.Variants))
+	values := make(map[string]*design.UnionVariant)
+	pinned := make(map[string]int)
+	for i, v := range u.Variants {
+		fn := identifier(codegen.SnakeCase(v.Value), "variant")
+		if _, ok := values[fn]; ok {
+			return nil, fmt.Errorf("proto: variants of %s map to the same field name %s", name, fn)
+		}
+		values[fn] = v
+		fieldNames[i] = fn
+		if num, ok := pins[v.Value]; ok {
+			pinned[fn] = num
+			delete(pins, v.Value)
+		}
+	}
+	if err := unknownPins(pins); err != nil {
+		return nil, fmt.Errorf("proto: invalid variant number of %s: %s", name, err)
+	}
+	numbers, reserved, reservedNames, err := b.numbering.assign(fullName, fieldNames, pinned)
+	if err != nil {
+		return nil, fmt.Errorf("proto: %s: %s", name, err)
+	}
+	m.Reserved, m.ReservedNames = reserved, reservedNames
+	for _, fn := range fieldNames {
+		v := values[fn]
+		m.Fields = append(m.Fields, &Field{
+			Name:        fn,
+			FullName:    m.FullName + "." + fn,
+			Type:        codegen.Goify(v.Type.TypeName, true),
+			Number:      numbers[fn],
+			Description: v.Type.Description,
+			Value:       v.Value,
+		})
+	}
+	sort.Sort(fieldsByNumber(m.Fields))
+	return m, nil
+}
+
+// fieldType returns the type of the field describing the attribute with the given name of message
+// m. It creates the nested messages and enums needed to describe inline objects, unions and Enum
+// validations. fullName is the name of m qualified with the names of its parent messages, nested
+// records the names of the nested messages and enums already created.
+func (b *builder) fieldType(m *Message, fullName, name string, att *design.AttributeDefinition, nested map[string]bool) (string, bool, error) {
+	nestedName := func() (string, error) {
+		n := codegen.Goify(name, true)
+		if !identifierRegex.MatchString(n) {
+			n = "Value" + n
+		}
+		if nested[n] {
+			return "", fmt.Errorf("proto: attributes of %s map to the same nested type name %s", m.Name, n)
+		}
+		nested[n] = true
+		return n, nil
+	}
+	if att.Validation != nil && len(att.Validation.Values) > 0 &&
+		(att.Type == design.String || att.Type == design.Integer) {
+		n, err := nestedName()
+		if err != nil {
+			return "", false, err
+		}
+		e, err := b.buildEnum(n, fullName+"."+n, att)
+		if err != nil {
+			return "", false, err
+		}
+		m.Enums = append(m.Enums, e)
+		return n, false, nil
+	}
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		return b.scalarType(actual), false, nil
+	case *design.UserTypeDefinition:
+		if !actual.Type.IsObject() && !actual.Type.IsArray() {
+			return b.fieldType(m, fullName, name, actual.AttributeDefinition, nested)
+		}
+		return codegen.Goify(actual.TypeName, true), false, nil
+	case *design.MediaTypeDefinition:
+		return codegen.Goify(actual.TypeName, true), false, nil
+	case design.Object:
+		n, err := nestedName()
+		if err != nil {
+			return "", false, err
+		}
+		msg, err := b.buildMessage(n, fullName+"."+n, att.Description, att, actual)
+		if err != nil {
+			return "", false, err
+		}
+		m.Messages = append(m.Messages, msg)
+		return n, false, nil
+	case *design.Union:
+		n, err := nestedName()
+		if err != nil {
+			return "", false, err
+		}
+		msg, err := b.buildUnion(n, fullName+"."+n, att.Description, att)
+		if err != nil {
+			return "", false, err
+		}
+		m.Messages = append(m.Messages, msg)
+		return n, false, nil
+	case *design.Array:
+		if actual.ElemType.Type.IsArray() {
+			b.imports["google/protobuf/struct.proto"] = true
+			return listValueType, true, nil
+		}
+		typ, _, err := b.fieldType(m, fullName, name, actual.ElemType, nested)
+		return typ, true, err
+	case *design.Hash:
+		key, ok := mapKeyType(actual.KeyType)
+		if !ok || actual.ElemType.Type.IsArray() || actual.ElemType.Type.IsHash() {
+			b.imports["google/protobuf/struct.proto"] = true
+			return structType, false, nil
+		}
+		typ, _, err := b.fieldType(m, fullName, name, actual.ElemType, nested)
+		if err != nil {
+			return "", false, err
+		}
+		return fmt.Sprintf("map<%s, %s>", key, typ), false, nil
+	}
+	return "", false, fmt.Errorf("proto: unsupported type %s of attribute %#v of %s", att.Type.Name(), name, m.Name)
+}
+
+// buildEnum builds the enum describing the values of the Enum validation of the given attribute.
+// The value names are prefixed with the enum name as enum values are scoped to the enclosing
+// message. The zero value is named after the enum name suffixed with "_UNSPECIFIED". The values
+// keep the numbers of the previous file or the numbers set with the "proto:enum:number" metadata of
+// the attribute, the new values are numbered in order of declaration.
+func (b *builder) buildEnum(name, fullName string, att *design.AttributeDefinition) (*Enum, error) {
+	pins, err := pinnedValueNumbers(att, "proto:enum:number", enumNumber)
+	if err != nil {
+		return nil, fmt.Errorf("proto: invalid enum value number of %s: %s", name, err)
+	}
+	e := &Enum{Name: name, FullName: b.file.Package + "." + fullName, Description: att.Description}
+	prefix := enumValueName(codegen.SnakeCase(name))
+	zero := prefix + "_UNSPECIFIED"
+	names := []string{zero}
+	values := map[string]interface{}{zero: nil}
+	pinned := map[string]int{zero: 0}
+	for _, v := range att.Validation.Values {
+		n := prefix + "_" + enumValueName(fmt.Sprint(v))
+		if _, ok := values[n]; ok {
+			return nil, fmt.Errorf("proto: enum values of %s map to the same value name %s", name, n)
+		}
+		values[n] = v
+		names = append(names, n)
+		if num, ok := pins[fmt.Sprint(v)]; ok {
+			pinned[n] = num
+			delete(pins, fmt.Sprint(v))
+		}
+	}
+	if err := unknownPins(pins); err != nil {
+		return nil, fmt.Errorf("proto: invalid enum value number of %s: %s", name, err)
+	}
+	numbers, reserved, reservedNames, err := b.numbering.assign(fullName, names, pinned)
+	if err != nil {
+		return nil, fmt.Errorf("proto: %s: %s", name, err)
+	}
+	e.Reserved, e.ReservedNames = reserved, reservedNames
+	for _, n := range names {
+		e.Values = append(e.Values, &EnumValue{Name: n, Number: numbers[n], Value: values[n]})
+	}
+	sort.Sort(enumValuesByNumber(e.Values))
+	return e, nil
+}
+
+// scalarType returns the Protocol Buffers type of the given primitive type.
+func (b *builder) scalarType(p design.Primitive) string {
+	switch p.Kind() {
+	case design.BooleanKind:
+		return "bool"
+	case design.IntegerKind:
+		return "int64"
+	case design.NumberKind:
+		return "double"
+	case design.BytesKind, design.FileKind:
+		return "bytes"
+	case design.AnyKind:
+		b.imports["google/protobuf/struct.proto"] = true
+		return valueType
+	default:
+		// DateTime, Date, Duration, UUID and Decimal values use their JSON string
+		// representation.
+		return "string"
+	}
+}
+
+// mapKeyType returns the Protocol Buffers map key type of the given hash key attribute.
+func mapKeyType(att *design.AttributeDefinition) (string, bool) {
+	p, ok := att.Type.(design.Primitive)
+	if !ok {
+		return "", false
+	}
+	switch p.Kind() {
+	case design.BooleanKind:
+		return "bool", true
+	case design.IntegerKind:
+		return "int64", true
+	case design.NumberKind, design.AnyKind, design.FileKind:
+		return "", false
+	}
+	return "string", true
+}
+
+// enum returns the nested enum with the given name, nil if there isn't one.
+func (m *Message) enum(name string) *Enum {
+	for _, e := range m.Enums {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
+
+// isScalar returns true if typ is a scalar value type.
+func isScalar(typ string) bool {
+	switch typ {
+	case "bool", "int64", "double", "string", "bytes":
+		return true
+	}
+	return false
+}
+
+// pinnedNumber returns the field number set with the "proto:field:number" metadata of the given
+// attribute if any.
+func pinnedNumber(att *design.AttributeDefinition) (int, bool, error) {
+	vals, ok := att.Metadata["proto:field:number"]
+	if !ok || len(vals) == 0 {
+		return 0, false, nil
+	}
+	n, err := fieldNumber(vals[0])
+	if err != nil {
+		return 0, false, err
+	}
+	return n, true, nil
+}
+
+// fieldNumber parses the given field number.
+func fieldNumber(val string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("%#v is not an integer", val)
+	}
+	if n < 1 || n > maxFieldNumber {
+		return 0, fmt.Errorf("%d is not between 1 and %d", n, maxFieldNumber)
+	}
+	if n >= firstReservedNumber && n <= lastReservedNumber {
+		return 0, fmt.Errorf("%d is reserved by the Protocol Buffers implementation", n)
+	}
+	return n, nil
+}
+
+// enumNumber parses the given enum value number, 0 is used by the zero value.
+func enumNumber(val string) (int, error) {
+	n, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("%#v is not an integer", val)
+	}
+	if n < 1 || n > maxEnumNumber {
+		return 0, fmt.Errorf("%d is not between 1 and %d", n, maxEnumNumber)
+	}
+	return n, nil
+}
+
+// pinnedValueNumbers returns the numbers of the enum values or union variants set with the given
+// metadata of att indexed by value. The metadata values have the form "value=number", parse parses
+// the numbers.
+func pinnedValueNumbers(att *design.AttributeDefinition, key string, parse func(string) (int, error)) (map[string]int, error) {
+	pins := make(map[string]int)
+	for _, val := range att.Metadata[key] {
+		i := strings.LastIndex(val, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%#v does not have the form \"value=number\"", val)
+		}
+		v, num := val[:i], val[i+1:]
+		if _, ok := pins[v]; ok {
+			return nil, fmt.Errorf("value %#v is pinned multiple times", v)
+		}
+		n, err := parse(num)
+		if err != nil {
+			return nil, fmt.Errorf("value %#v: %s", v, err)
+		}
+		pins[v] = n
+	}
+	return pins, nil
+}
+
+// unknownPins returns an error listing the pinned values that do not exist, nil if pins is empty.
+func unknownPins(pins map[string]int) error {
+	if len(pins) == 0 {
+		return nil
+	}
+	var vals []string
+	for v := range pins {
+		vals = append(vals, fmt.Sprintf("%#v", v))
+	}
+	sort.Strings(vals)
+	return fmt.Errorf("unknown values %s", strings.Join(vals, ", "))
+}
+
+// identifier returns a valid Protocol Buffers identifier built from name by replacing the invalid
+// characters with underscores. It prefixes the result with prefix if it does not start with a
+// letter.
+func identifier(name, prefix string) string {
+	if identifierRegex.MatchString(name) {
+		return name
+	}
+	id := strings.Trim(nonIdentifierRegex.ReplaceAllString(name, "_"), "_")
+	if id == "" || !identifierRegex.MatchString(id) {
+		id = prefix + "_" + id
+	}
+	return strings.TrimSuffix(id, "_")
+}
+
+// enumValueName returns the upper snake case version of the given value suitable for use in an
+// enum value name.
+func enumValueName(v string) string {
+	n := strings.Trim(nonIdentifierRegex.ReplaceAllString(v, "_"), "_")
+	if strings.HasPrefix(v, "-") {
+		n = "MINUS_" + n
+	}
+	if n == "" {
+		return "EMPTY"
+	}
+	return strings.ToUpper(n)
+}
+
+// nonIdentifierRegex matches sequences of characters that are not valid in identifiers.
+var nonIdentifierRegex = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+
+func (m messagesByName) Len() int           { return len(m) }
+func (m messagesByName) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m messagesByName) Less(i, j int) bool { return m[i].Name < m[j].Name }
+
+func (f fieldsByNumber) Len() int           { return len(f) }
+func (f fieldsByNumber) Swap(i, j int)      { f[i], f[j] = f[j], f[i] }
+func (f fieldsByNumber) Less(i, j int) bool { return f[i].Number < f[j].Number }
+
+func (e enumValuesByNumber) Len() int           { return len(e) }
+func (e enumValuesByNumber) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
+func (e enumValuesByNumber) Less(i, j int) bool { return e[i].Number < e[j].Number }
+
+func (r responsesByStatus) Len() int           { return len(r) }
+func (r responsesByStatus) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+func (r responsesByStatus) Less(i, j int) bool { return r[i].Status < r[j].Status }
@@ -0,0 +1,495 @@
+package genproto_test
+
+import (
+	"strings"
+
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_proto"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// message returns the top level message with the given name.
+func message(f *genproto.File, name string) *genproto.Message {
+	for _, m := range f.Messages {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// field returns the field of m with the given name.
+func field(m *genproto.Message, name string) *genproto.Field {
+	for _, f := range m.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+var _ = Describe("New", func() {
+	var numbering *genproto.Numbering
+	var file *genproto.File
+	var newErr error
+
+	BeforeEach(func() {
+		numbering = nil
+		dslengine.Reset()
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		file, newErr = genproto.New(Design, numbering)
+	})
+
+	Context("with a design", func() {
+		BeforeEach(func() {
+			API("cellar", func() {})
+			Country := Type("country", func() {
+				Attribute("code", String)
+				Required("code")
+			})
+			BottleMedia := MediaType("application/vnd.bottle+json", func() {
+				Description("A wine bottle")
+				Attributes(func() {
+					Attribute("id", Integer, "ID")
+					Attribute("name", String)
+					Attribute("color", String, func() {
+						Enum("red", "white", "rosé")
+					})
+					Attribute("country", Country)
+					Attribute("created-at", DateTime)
+					Attribute("ratings", HashOf(String, Integer))
+					Attribute("tags", ArrayOf(String))
+					Required("id")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+				})
+			})
+			Resource("bottle", func() {
+				Description("Bottles of wine")
+				BasePath("/bottles")
+				DefaultMedia(BottleMedia)
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("id", Integer)
+					})
+					Response(OK)
+					Response(NotFound)
+				})
+				Action("list", func() {
+					Routing(GET(""))
+					Response(OK, CollectionOf(BottleMedia))
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Member("name", String)
+						Required("name")
+					})
+					Response(Created)
+				})
+			})
+		})
+
+		It("describes the types with messages", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(file.Package).Should(Equal("cellar"))
+			bottle := message(file, "Bottle")
+			Ω(bottle).ShouldNot(BeNil())
+			Ω(bottle.FullName).Should(Equal("cellar.Bottle"))
+			Ω(bottle.Description).Should(Equal("A wine bottle"))
+			Ω(field(bottle, "id")).Should(Equal(&genproto.Field{
+				Name: "id", FullName: "cellar.Bottle.id", Type: "int64", Number: 4, Description: "ID",
+			}))
+			Ω(field(bottle, "name").Label).Should(Equal("optional"))
+			Ω(field(bottle, "country").Type).Should(Equal("Country"))
+			Ω(field(bottle, "created_at").JSONName).Should(Equal("created-at"))
+			Ω(field(bottle, "ratings").Type).Should(Equal("map<string, int64>"))
+			Ω(field(bottle, "tags").Label).Should(Equal("repeated"))
+			Ω(message(file, "Country")).ShouldNot(BeNil())
+		})
+
+		It("numbers the fields in alphabetical order", func() {
+			bottle := message(file, "Bottle")
+			var names []string
+			for _, f := range bottle.Fields {
+				names = append(names, f.Name)
+			}
+			Ω(names).Should(Equal([]string{"color", "country", "created_at", "id", "name", "ratings", "tags"}))
+			Ω(bottle.Fields[0].Number).Should(Equal(1))
+			Ω(bottle.Fields[6].Number).Should(Equal(7))
+		})
+
+		It("describes Enum validations with enums numbered in order of declaration", func() {
+			bottle := message(file, "Bottle")
+			Ω(field(bottle, "color").Type).Should(Equal("Color"))
+			Ω(field(bottle, "color").Label).Should(Equal("optional"))
+			Ω(bottle.Enums).Should(HaveLen(1))
+			color := bottle.Enums[0]
+			Ω(color.FullName).Should(Equal("cellar.Bottle.Color"))
+			Ω(color.Values).Should(Equal([]*genproto.EnumValue{
+				{Name: "COLOR_UNSPECIFIED", Number: 0},
+				{Name: "COLOR_RED", Number: 1, Value: "red"},
+				{Name: "COLOR_WHITE", Number: 2, Value: "white"},
+				{Name: "COLOR_ROS", Number: 3, Value: "rosé"},
+			}))
+		})
+
+		It("describes the resources with services", func() {
+			Ω(file.Services).Should(HaveLen(1))
+			svc := file.Services[0]
+			Ω(svc.Name).Should(Equal("BottleService"))
+			Ω(svc.Description).Should(Equal("Bottles of wine"))
+			Ω(svc.RPCs).Should(HaveLen(3))
+
+			create := svc.RPCs[0]
+			Ω(create.Name).Should(Equal("Create"))
+			Ω(create.Request).Should(Equal("CreateBottleRequest"))
+			Ω(create.Response).Should(Equal("google.protobuf.Empty"))
+			req := message(file, "CreateBottleRequest")
+			Ω(field(req, "payload").Type).Should(Equal("CreateBottlePayload"))
+			Ω(message(file, "CreateBottlePayload")).ShouldNot(BeNil())
+
+			list := svc.RPCs[1]
+			Ω(list.Response).Should(Equal("BottleCollection"))
+			Ω(message(file, "BottleCollection").Collection).Should(BeTrue())
+			Ω(field(message(file, "BottleCollection"), "items").Type).Should(Equal("Bottle"))
+
+			show := svc.RPCs[2]
+			Ω(show.Description).Should(Equal("Show a bottle"))
+			Ω(show.Response).Should(Equal("Bottle"))
+			Ω(show.Method).Should(Equal("GET"))
+			Ω(show.Path).Should(Equal("/bottles/:id"))
+			Ω(field(message(file, "ShowBottleRequest"), "id").Type).Should(Equal("int64"))
+			Ω(file.Imports).Should(Equal([]string{"google/protobuf/empty.proto"}))
+		})
+
+		It("renders the .proto file", func() {
+			src := string(file.Source())
+			Ω(src).Should(ContainSubstring("syntax = \"proto3\";\n\npackage cellar;\n"))
+			Ω(src).Should(ContainSubstring(bottleMessage))
+			Ω(src).Should(ContainSubstring(bottleService))
+		})
+
+		Context("with a previously generated file", func() {
+			BeforeEach(func() {
+				prev := `
+message Bottle {
+	optional int64 vintage = 2;
+	int64 id = 10;
+	optional string name = 11 [json_name = "name"];
+}
+`
+				var err error
+				numbering, err = genproto.ParseNumbering(strings.NewReader(prev))
+				Ω(err).ShouldNot(HaveOccurred())
+			})
+
+			It("requires the numbers of the existing fields to be pinned", func() {
+				Ω(newErr).Should(HaveOccurred())
+				Ω(newErr.Error()).Should(ContainSubstring("\n\tattribute \"id\" of Bottle: Metadata(\"proto:field:number\", \"10\")\n"))
+				Ω(newErr.Error()).Should(HaveSuffix("\n\tattribute \"name\" of Bottle: Metadata(\"proto:field:number\", \"11\")"))
+			})
+		})
+	})
+
+	Context("with pinned field numbers", func() {
+		var number string
+
+		BeforeEach(func() {
+			number = "5"
+			API("cellar", func() {
+				Metadata("proto:package", "goa.cellar.v1")
+			})
+			Type("Account", func() {
+				Attribute("id", Integer, func() {
+					Metadata("proto:field:number", number)
+				})
+				Attribute("name", String)
+				Attribute("status", String, func() {
+					Enum("active", "closed")
+					Metadata("proto:field:number", "2")
+				})
+			})
+		})
+
+		It("uses the metadata", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(file.Package).Should(Equal("goa.cellar.v1"))
+			account := message(file, "Account")
+			Ω(field(account, "id").Number).Should(Equal(5))
+			Ω(field(account, "name").Number).Should(Equal(6))
+			Ω(field(account, "status").Number).Should(Equal(2))
+		})
+
+		Context("with a previously generated file", func() {
+			var prev string
+
+			BeforeEach(func() {
+				prev = `
+message Account {
+	reserved 12;
+	enum Status {
+		STATUS_UNSPECIFIED = 0;
+		STATUS_ACTIVE = 1;
+		STATUS_CLOSED = 2;
+		STATUS_FROZEN = 3;
+	}
+	int64 id = 5;
+	optional Status status = 2;
+	optional string email = 7;
+}
+`
+			})
+
+			JustBeforeEach(func() {
+				var err error
+				numbering, err = genproto.ParseNumbering(strings.NewReader(prev))
+				Ω(err).ShouldNot(HaveOccurred())
+				file, newErr = genproto.New(Design, numbering)
+			})
+
+			It("reserves the numbers of the removed fields and enum values", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				account := message(file, "Account")
+				Ω(field(account, "name").Number).Should(Equal(13))
+				Ω(account.Reserved).Should(Equal([]int{7, 12}))
+				Ω(account.ReservedNames).Should(Equal([]string{"email"}))
+				status := account.Enums[0]
+				Ω(status.Values).Should(HaveLen(3))
+				Ω(status.Reserved).Should(Equal([]int{3}))
+				Ω(status.ReservedNames).Should(Equal([]string{"STATUS_FROZEN"}))
+			})
+
+			Context("that numbers a field differently", func() {
+				BeforeEach(func() {
+					number = "6"
+				})
+
+				It("returns an error", func() {
+					Ω(newErr).Should(MatchError("proto: Account: the number of id changes from 5 to 6, which breaks the compatibility of the encoded messages"))
+				})
+			})
+		})
+
+		Context("using a number reserved by the implementation", func() {
+			BeforeEach(func() {
+				number = "19000"
+			})
+
+			It("returns an error", func() {
+				Ω(newErr).Should(MatchError(`proto: invalid field number of attribute "id" of Account: 19000 is reserved by the Protocol Buffers implementation`))
+			})
+		})
+	})
+
+	Context("with enum values and union variants removed from the middle", func() {
+		var prev, enumPin, variantPin string
+
+		BeforeEach(func() {
+			prev, enumPin, variantPin = "", "", ""
+			API("shop", func() {})
+			Card := Type("CardPayment", func() {
+				Attribute("number", String)
+			})
+			Bank := Type("BankPayment", func() {
+				Attribute("iban", String)
+			})
+			Type("Order", func() {
+				Attribute("status", String, func() {
+					Enum("pending", "shipped")
+					Metadata("proto:field:number", "1")
+					if enumPin != "" {
+						Metadata("proto:enum:number", enumPin)
+					}
+				})
+				Attribute("method", OneOf("type",
+					Variant("card", Card),
+					Variant("bank", Bank),
+				), func() {
+					Metadata("proto:field:number", "2")
+					if variantPin != "" {
+						Metadata("proto:variant:number", variantPin)
+					}
+				})
+			})
+		})
+
+		JustBeforeEach(func() {
+			if prev == "" {
+				return
+			}
+			var err error
+			numbering, err = genproto.ParseNumbering(strings.NewReader(prev))
+			Ω(err).ShouldNot(HaveOccurred())
+			file, newErr = genproto.New(Design, numbering)
+		})
+
+		Context("with a previously generated file", func() {
+			BeforeEach(func() {
+				prev = `
+message Order {
+	enum Status {
+		STATUS_UNSPECIFIED = 0;
+		STATUS_PENDING = 1;
+		STATUS_PAID = 2;
+		STATUS_SHIPPED = 3;
+	}
+	message Method {
+		oneof value {
+			CardPayment card = 1;
+			CashPayment cash = 2;
+			BankPayment bank = 3;
+		}
+	}
+	optional Status status = 1;
+	Method method = 2;
+}
+`
+			})
+
+			It("keeps the numbers of the following values", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				order := message(file, "Order")
+				status := order.Enums[0]
+				Ω(status.Values).Should(Equal([]*genproto.EnumValue{
+					{Name: "STATUS_UNSPECIFIED", Number: 0},
+					{Name: "STATUS_PENDING", Number: 1, Value: "pending"},
+					{Name: "STATUS_SHIPPED", Number: 3, Value: "shipped"},
+				}))
+				Ω(status.Reserved).Should(Equal([]int{2}))
+				Ω(status.ReservedNames).Should(Equal([]string{"STATUS_PAID"}))
+				method := order.Messages[0]
+				Ω(field(method, "card").Number).Should(Equal(1))
+				Ω(field(method, "bank").Number).Should(Equal(3))
+				Ω(method.Reserved).Should(Equal([]int{2}))
+				Ω(method.ReservedNames).Should(Equal([]string{"cash"}))
+			})
+		})
+
+		Context("with pinned numbers", func() {
+			BeforeEach(func() {
+				enumPin, variantPin = "shipped=3", "bank=3"
+			})
+
+			It("uses the metadata", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				order := message(file, "Order")
+				Ω(order.Enums[0].Values).Should(Equal([]*genproto.EnumValue{
+					{Name: "STATUS_UNSPECIFIED", Number: 0},
+					{Name: "STATUS_SHIPPED", Number: 3, Value: "shipped"},
+					{Name: "STATUS_PENDING", Number: 4, Value: "pending"},
+				}))
+				method := order.Messages[0]
+				Ω(field(method, "bank").Number).Should(Equal(3))
+				Ω(field(method, "card").Number).Should(Equal(4))
+			})
+
+			Context("of unknown values", func() {
+				BeforeEach(func() {
+					enumPin = "paid=2"
+				})
+
+				It("returns an error", func() {
+					Ω(newErr).Should(MatchError(`proto: invalid enum value number of Status: unknown values "paid"`))
+				})
+			})
+		})
+	})
+
+	Context("with a union and a WebSocket action", func() {
+		BeforeEach(func() {
+			API("pay", func() {})
+			Card := Type("CardPayment", func() {
+				Attribute("number", String)
+			})
+			Bank := Type("BankPayment", func() {
+				Attribute("iban", String)
+			})
+			Type("Payment", func() {
+				Attribute("method", OneOf("type",
+					Variant("card", Card),
+					Variant("bank", Bank),
+				))
+			})
+			Resource("payment", func() {
+				Action("watch", func() {
+					Routing(GET("/watch"))
+					Scheme("ws")
+					Messages(func() {
+						Inbound(Card)
+						Outbound(Bank)
+					})
+				})
+				Action("raw", func() {
+					Routing(GET("/raw"))
+					Scheme("ws")
+				})
+			})
+		})
+
+		It("describes the union with a oneof", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			payment := message(file, "Payment")
+			Ω(field(payment, "method").Type).Should(Equal("Method"))
+			Ω(payment.Messages).Should(HaveLen(1))
+			method := payment.Messages[0]
+			Ω(method.Oneof).Should(Equal("value"))
+			Ω(method.Discriminator).Should(Equal("type"))
+			Ω(field(method, "card")).Should(Equal(&genproto.Field{
+				Name: "card", FullName: "pay.Payment.Method.card", Type: "CardPayment", Number: 1, Value: "card",
+			}))
+		})
+
+		It("describes the WebSocket messages with a bidirectional streaming RPC", func() {
+			Ω(file.Services).Should(HaveLen(1))
+			Ω(file.Services[0].RPCs).Should(HaveLen(1))
+			watch := file.Services[0].RPCs[0]
+			Ω(watch.Request).Should(Equal("CardPayment"))
+			Ω(watch.Response).Should(Equal("BankPayment"))
+			Ω(watch.ClientStream).Should(BeTrue())
+			Ω(watch.ServerStream).Should(BeTrue())
+		})
+	})
+})
+
+const (
+	bottleMessage = `// A wine bottle
+message Bottle {
+	enum Color {
+		COLOR_UNSPECIFIED = 0;
+		COLOR_RED = 1;
+		COLOR_WHITE = 2;
+		COLOR_ROS = 3;
+	}
+	optional Color color = 1;
+	Country country = 2;
+	optional string created_at = 3 [json_name = "created-at"];
+	// ID
+	int64 id = 4;
+	optional string name = 5;
+	map<string, int64> ratings = 6;
+	repeated string tags = 7;
+}
+`
+
+	bottleService = `// Bottles of wine
+service BottleService {
+	rpc Create(CreateBottleRequest) returns (google.protobuf.Empty);
+
+	rpc List(ListBottleRequest) returns (BottleCollection);
+
+	// Show a bottle
+	rpc Show(ShowBottleRequest) returns (Bottle);
+}
+`
+)
@@ -0,0 +1,139 @@
+package genproto
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// Source returns the content of the .proto file.
+func (f *File) Source() []byte {
+	var b bytes.Buffer
+	b.WriteString("//************************************************************************//\n")
+	fmt.Fprintf(&b, "// API %q: Protocol Buffers definitions\n", f.Name)
+	b.WriteString("//\n")
+	fmt.Fprintf(&b, "// Generated with goagen v%s, command line:\n", codegen.Version)
+	b.WriteString(codegen.Comment(codegen.CommandLine()))
+	b.WriteString("\n//\n")
+	b.WriteString("// The content of this file is auto-generated, DO NOT MODIFY\n")
+	b.WriteString("//************************************************************************//\n\n")
+	b.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&b, "package %s;\n", f.Package)
+	if f.GoPackage != "" {
+		fmt.Fprintf(&b, "\noption go_package = %q;\n", f.GoPackage)
+	}
+	if len(f.Imports) > 0 {
+		b.WriteString("\n")
+		for _, imp := range f.Imports {
+			fmt.Fprintf(&b, "import %q;\n", imp)
+		}
+	}
+	for _, s := range f.Services {
+		b.WriteString("\n")
+		writeService(&b, s)
+	}
+	for _, m := range f.Messages {
+		b.WriteString("\n")
+		writeMessage(&b, m, 0)
+	}
+	return b.Bytes()
+}
+
+// writeService writes the definition of the given service.
+func writeService(b *bytes.Buffer, s *Service) {
+	writeDescription(b, s.Description, 0)
+	fmt.Fprintf(b, "service %s {\n", s.Name)
+	for i, rpc := range s.RPCs {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDescription(b, rpc.Description, 1)
+		req, resp := rpc.Request, rpc.Response
+		if rpc.ClientStream {
+			req = "stream " + req
+		}
+		if rpc.ServerStream {
+			resp = "stream " + resp
+		}
+		fmt.Fprintf(b, "\trpc %s(%s) returns (%s);\n", rpc.Name, req, resp)
+	}
+	b.WriteString("}\n")
+}
+
+// writeMessage writes the definition of the given message indented with depth tabs.
+func writeMessage(b *bytes.Buffer, m *Message, depth int) {
+	tabs := codegen.Tabs(depth)
+	writeDescription(b, m.Description, depth)
+	fmt.Fprintf(b, "%smessage %s {\n", tabs, m.Name)
+	for _, e := range m.Enums {
+		writeEnum(b, e, depth+1)
+	}
+	for _, n := range m.Messages {
+		writeMessage(b, n, depth+1)
+	}
+	writeReserved(b, m.Reserved, m.ReservedNames, depth+1)
+	fieldDepth := depth + 1
+	if m.Oneof != "" {
+		fmt.Fprintf(b, "%s\toneof %s {\n", tabs, m.Oneof)
+		fieldDepth++
+	}
+	for _, f := range m.Fields {
+		writeDescription(b, f.Description, fieldDepth)
+		b.WriteString(codegen.Tabs(fieldDepth))
+		if f.Label != "" {
+			b.WriteString(f.Label + " ")
+		}
+		fmt.Fprintf(b, "%s %s = %d", f.Type, f.Name, f.Number)
+		if f.JSONName != "" {
+			fmt.Fprintf(b, " [json_name = %q]", f.JSONName)
+		}
+		b.WriteString(";\n")
+	}
+	if m.Oneof != "" {
+		fmt.Fprintf(b, "%s\t}\n", tabs)
+	}
+	fmt.Fprintf(b, "%s}\n", tabs)
+}
+
+// writeEnum writes the definition of the given enum indented with depth tabs.
+func writeEnum(b *bytes.Buffer, e *Enum, depth int) {
+	tabs := codegen.Tabs(depth)
+	writeDescription(b, e.Description, depth)
+	fmt.Fprintf(b, "%senum %s {\n", tabs, e.Name)
+	writeReserved(b, e.Reserved, e.ReservedNames, depth+1)
+	for _, v := range e.Values {
+		fmt.Fprintf(b, "%s\t%s = %d;\n", tabs, v.Name, v.Number)
+	}
+	fmt.Fprintf(b, "%s}\n", tabs)
+}
+
+// writeReserved writes the reserved statements listing the given numbers and names.
+func writeReserved(b *bytes.Buffer, numbers []int, names []string, depth int) {
+	if len(numbers) > 0 {
+		nums := make([]string, len(numbers))
+		for i, n := range numbers {
+			nums[i] = strconv.Itoa(n)
+		}
+		fmt.Fprintf(b, "%sreserved %s;\n", codegen.Tabs(depth), strings.Join(nums, ", "))
+	}
+	if len(names) > 0 {
+		quoted := make([]string, len(names))
+		for i, n := range names {
+			quoted[i] = strconv.Quote(n)
+		}
+		fmt.Fprintf(b, "%sreserved %s;\n", codegen.Tabs(depth), strings.Join(quoted, ", "))
+	}
+}
+
+// writeDescription writes the given description as comment lines indented with depth tabs.
+func writeDescription(b *bytes.Buffer, desc string, depth int) {
+	if desc == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(desc), "\n") {
+		fmt.Fprintf(b, "%s// %s\n", codegen.Tabs(depth), strings.TrimSpace(line))
+	}
+}
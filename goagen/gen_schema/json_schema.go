@@ -38,13 +38,33 @@ type (
 		Pattern              string        `json:"pattern,omitempty"`
 		Minimum              float64       `json:"minimum,omitempty"`
 		Maximum              float64       `json:"maximum,omitempty"`
+		ExclusiveMinimum     bool          `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum     bool          `json:"exclusiveMaximum,omitempty"`
+		MultipleOf           float64       `json:"multipleOf,omitempty"`
 		MinLength            int           `json:"minLength,omitempty"`
 		MaxLength            int           `json:"maxLength,omitempty"`
+		UniqueItems          bool          `json:"uniqueItems,omitempty"`
+		MinProperties        int           `json:"minProperties,omitempty"`
+		MaxProperties        int           `json:"maxProperties,omitempty"`
 		Required             []string      `json:"required,omitempty"`
 		AdditionalProperties bool          `json:"additionalProperties,omitempty"`
+		// HasMinimum and HasMaximum are true if Minimum and Maximum are set, a bound of 0 is
+		// otherwise indistinguishable from no bound.
+		HasMinimum bool `json:"-"`
+		HasMaximum bool `json:"-"`
+
+		// Inheritance
+		AllOf []*JSONSchema `json:"allOf,omitempty"`
 
 		// Union
 		AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+		OneOf []*JSONSchema `json:"oneOf,omitempty"`
+		// Discriminator is the name of the property that identifies the schema a value uses
+		// when the schema describes a union (OpenAPI extension).
+		Discriminator string `json:"discriminator,omitempty"`
+		// DiscriminatorValue is the value of the discriminator property that identifies the
+		// schema when it describes a union variant (Swagger vendor extension).
+		DiscriminatorValue string `json:"x-discriminator-value,omitempty"`
 	}
 
 	// JSONType is the JSON type enum.
@@ -259,22 +279,37 @@ func GenerateTypeDefinition(api *design.APIDefinition, ut *design.UserTypeDefini
 	buildAttributeSchema(api, s, ut.AttributeDefinition)
 }
 
+// TypeFormat returns the JSON schema format of the given primitive type, the empty string if
+// the type has no specific format.
+func TypeFormat(t design.DataType) string {
+	switch t.Kind() {
+	case design.UUIDKind:
+		return "uuid"
+	case design.DateTimeKind:
+		return "date-time"
+	case design.NumberKind:
+		return "double"
+	case design.IntegerKind:
+		return "int64"
+	case design.DateKind:
+		return "date"
+	case design.DurationKind:
+		return "duration"
+	case design.BytesKind:
+		return "byte"
+	case design.DecimalKind:
+		return "decimal"
+	}
+	return ""
+}
+
 // TypeSchema produces the JSON schema corresponding to the given data type.
 func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	s := NewJSONSchema()
 	switch actual := t.(type) {
 	case design.Primitive:
 		s.Type = JSONType(actual.Name())
-		switch actual.Kind() {
-		case design.UUIDKind:
-			s.Format = "uuid"
-		case design.DateTimeKind:
-			s.Format = "date-time"
-		case design.NumberKind:
-			s.Format = "double"
-		case design.IntegerKind:
-			s.Format = "int64"
-		}
+		s.Format = TypeFormat(actual)
 	case *design.Array:
 		s.Type = JSONArray
 		s.Items = NewJSONSchema()
@@ -289,6 +324,18 @@ func TypeSchema(api *design.APIDefinition, t design.DataType) *JSONSchema {
 	case *design.Hash:
 		s.Type = JSONObject
 		s.AdditionalProperties = true
+	case *design.Union:
+		s.Type = JSONObject
+		prop := NewJSONSchema()
+		buildAttributeSchema(api, prop, actual.DiscriminatorAttribute())
+		s.Properties[actual.Discriminator] = prop
+		s.Required = []string{actual.Discriminator}
+		s.Discriminator = actual.Discriminator
+		for _, v := range actual.Variants {
+			variant := NewJSONSchema()
+			variant.Ref = TypeRef(api, v.Type)
+			s.OneOf = append(s.OneOf, variant)
+		}
 	case *design.UserTypeDefinition:
 		s.Ref = TypeRef(api, actual)
 	case *design.MediaTypeDefinition:
@@ -316,15 +363,29 @@ func (s *JSONSchema) Merge(other *JSONSchema) {
 		{&s.Format, other.Format, s.Format == ""},
 		{&s.Pattern, other.Pattern, s.Pattern == ""},
 		{&s.AdditionalProperties, other.AdditionalProperties, s.AdditionalProperties == false},
-		{&s.Minimum, other.Minimum, s.Minimum > other.Minimum},
-		{&s.Maximum, other.Maximum, s.Maximum < other.Maximum},
+		{&s.AllOf, other.AllOf, s.AllOf == nil},
+		{&s.OneOf, other.OneOf, s.OneOf == nil},
+		{&s.Discriminator, other.Discriminator, s.Discriminator == ""},
+		{&s.DiscriminatorValue, other.DiscriminatorValue, s.DiscriminatorValue == ""},
+		{&s.ExclusiveMinimum, other.ExclusiveMinimum, s.ExclusiveMinimum == false},
+		{&s.ExclusiveMaximum, other.ExclusiveMaximum, s.ExclusiveMaximum == false},
+		{&s.MultipleOf, other.MultipleOf, s.MultipleOf == 0},
 		{&s.MinLength, other.MinLength, s.MinLength > other.MinLength},
 		{&s.MaxLength, other.MaxLength, s.MaxLength < other.MaxLength},
+		{&s.UniqueItems, other.UniqueItems, s.UniqueItems == false},
+		{&s.MinProperties, other.MinProperties, s.MinProperties > other.MinProperties},
+		{&s.MaxProperties, other.MaxProperties, s.MaxProperties < other.MaxProperties},
 	} {
 		if v.needed && v.b != nil {
 			reflect.Indirect(reflect.ValueOf(v.a)).Set(reflect.ValueOf(v.b))
 		}
 	}
+	if other.HasMinimum && (!s.HasMinimum || s.Minimum > other.Minimum) {
+		s.Minimum, s.HasMinimum = other.Minimum, true
+	}
+	if other.HasMaximum && (!s.HasMaximum || s.Maximum < other.Maximum) {
+		s.Maximum, s.HasMaximum = other.Maximum, true
+	}
 
 	for n, p := range other.Properties {
 		if _, ok := s.Properties[n]; !ok {
@@ -369,10 +430,20 @@ func (s *JSONSchema) Dup() *JSONSchema {
 		Pattern:              s.Pattern,
 		Minimum:              s.Minimum,
 		Maximum:              s.Maximum,
+		HasMinimum:           s.HasMinimum,
+		HasMaximum:           s.HasMaximum,
+		ExclusiveMinimum:     s.ExclusiveMinimum,
+		ExclusiveMaximum:     s.ExclusiveMaximum,
+		MultipleOf:           s.MultipleOf,
 		MinLength:            s.MinLength,
 		MaxLength:            s.MaxLength,
+		UniqueItems:          s.UniqueItems,
+		MinProperties:        s.MinProperties,
+		MaxProperties:        s.MaxProperties,
 		Required:             s.Required,
 		AdditionalProperties: s.AdditionalProperties,
+		Discriminator:        s.Discriminator,
+		DiscriminatorValue:   s.DiscriminatorValue,
 	}
 	for n, p := range s.Properties {
 		js.Properties[n] = p.Dup()
@@ -380,6 +451,12 @@ func (s *JSONSchema) Dup() *JSONSchema {
 	if s.Items != nil {
 		js.Items = s.Items.Dup()
 	}
+	for _, a := range s.AllOf {
+		js.AllOf = append(js.AllOf, a.Dup())
+	}
+	for _, o := range s.OneOf {
+		js.OneOf = append(js.OneOf, o.Dup())
+	}
 	for n, d := range s.Definitions {
 		js.Definitions[n] = d.Dup()
 	}
@@ -404,10 +481,10 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	s.Format = val.Format
 	s.Pattern = val.Pattern
 	if val.Minimum != nil {
-		s.Minimum = *val.Minimum
+		s.Minimum, s.HasMinimum = *val.Minimum, true
 	}
 	if val.Maximum != nil {
-		s.Maximum = *val.Maximum
+		s.Maximum, s.HasMaximum = *val.Maximum, true
 	}
 	if val.MinLength != nil {
 		s.MinLength = *val.MinLength
@@ -415,10 +492,56 @@ func buildAttributeSchema(api *design.APIDefinition, s *JSONSchema, at *design.A
 	if val.MaxLength != nil {
 		s.MaxLength = *val.MaxLength
 	}
+	s.ExclusiveMinimum = val.ExclusiveMinimum
+	s.ExclusiveMaximum = val.ExclusiveMaximum
+	if val.MultipleOf != nil {
+		s.MultipleOf = *val.MultipleOf
+	}
+	s.UniqueItems = val.UniqueItems
+	if val.MinProperties != nil {
+		s.MinProperties = *val.MinProperties
+	}
+	if val.MaxProperties != nil {
+		s.MaxProperties = *val.MaxProperties
+	}
 	s.Required = val.Required
+	if len(at.Bases) > 0 {
+		buildBasesSchema(api, s, at)
+	}
 	return s
 }
 
+// buildBasesSchema replaces the properties at inherits from the types it extends with "allOf"
+// references to the base type schemas.
+func buildBasesSchema(api *design.APIDefinition, s *JSONSchema, at *design.AttributeDefinition) {
+	obj := at.Type.ToObject()
+	for _, base := range at.Bases {
+		s.AllOf = append(s.AllOf, TypeSchema(api, base))
+		for n, batt := range base.ToObject() {
+			if att, ok := obj[n]; ok && sameSchema(api, att, batt) {
+				delete(s.Properties, n)
+			}
+		}
+		def := base.(design.DataStructure).Definition()
+		var required []string
+		for _, r := range s.Required {
+			if !def.IsRequired(r) {
+				required = append(required, r)
+			}
+		}
+		s.Required = required
+	}
+}
+
+// sameSchema returns true if the JSON schemas of the given attributes are identical regardless of
+// their examples.
+func sameSchema(api *design.APIDefinition, a, b *design.AttributeDefinition) bool {
+	as := buildAttributeSchema(api, NewJSONSchema(), a)
+	bs := buildAttributeSchema(api, NewJSONSchema(), b)
+	as.Example, bs.Example = nil, nil
+	return reflect.DeepEqual(as, bs)
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -511,3 +634,54 @@ func buildMediaTypeSchema(api *design.APIDefinition, mt *design.MediaTypeDefinit
 	}
 	buildAttributeSchema(api, s, mt.AttributeDefinition)
 }
+
+// MarshalJSON marshals the schema, including the bounds set to 0.
+func (s JSONSchema) MarshalJSON() ([]byte, error) {
+	type schema JSONSchema
+	return MarshalBounds(schema(s), s.HasMinimum && s.Minimum == 0, s.HasMaximum && s.Maximum == 0)
+}
+
+// UnmarshalJSON unmarshals the schema and records whether it sets its bounds.
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	type schema JSONSchema
+	var err error
+	s.HasMinimum, s.HasMaximum, err = UnmarshalBounds(data, (*schema)(s))
+	return err
+}
+
+// MarshalBounds marshals v, a value whose type has Minimum and Maximum float64 fields serialized
+// as "minimum" and "maximum" with omitempty, and adds the bounds set to 0 that omitempty drops.
+// The type of v must not implement json.Marshaler itself.
+func MarshalBounds(v interface{}, zeroMin, zeroMax bool) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || !zeroMin && !zeroMax {
+		return b, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	if zeroMin {
+		fields["minimum"] = json.RawMessage("0")
+	}
+	if zeroMax {
+		fields["maximum"] = json.RawMessage("0")
+	}
+	return json.Marshal(fields)
+}
+
+// UnmarshalBounds unmarshals data into v, a pointer to a value whose type has Minimum and Maximum
+// float64 fields, and returns whether data sets the "minimum" and "maximum" fields. The type of v
+// must not implement json.Unmarshaler itself.
+func UnmarshalBounds(data []byte, v interface{}) (hasMin, hasMax bool, err error) {
+	if err = json.Unmarshal(data, v); err != nil {
+		return
+	}
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return
+	}
+	_, hasMin = fields["minimum"]
+	_, hasMax = fields["maximum"]
+	return
+}
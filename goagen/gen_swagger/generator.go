@@ -11,6 +11,8 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/gen_schema"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/spf13/cobra"
 )
@@ -65,33 +67,32 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 	if err != nil {
 		return nil, err
 	}
-
-	// JSON
-	rawJSON, err := json.Marshal(s)
+	files, err := writeSpec(swaggerDir, s)
+	genfiles = append(genfiles, files...)
 	if err != nil {
 		return nil, err
 	}
-	swaggerFile := filepath.Join(swaggerDir, "swagger.json")
-	if err := ioutil.WriteFile(swaggerFile, rawJSON, 0644); err != nil {
-		return nil, err
-	}
-	genfiles = append(genfiles, swaggerFile)
-
-	// YAML
-	var yamlSource interface{}
-	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
-		return nil, err
-	}
 
-	rawYAML, err := yaml.Marshal(yamlSource)
-	if err != nil {
-		return nil, err
-	}
-	swaggerFile = filepath.Join(swaggerDir, "swagger.yaml")
-	if err := ioutil.WriteFile(swaggerFile, rawYAML, 0644); err != nil {
-		return nil, err
+	// API versions
+	var versions []string
+	for _, v := range api.Versions {
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		vs, err := NewVersion(v)
+		if err != nil {
+			return nil, err
+		}
+		pkg := genapp.VersionPackage(v)
+		dir := filepath.Join(swaggerDir, pkg)
+		if err = os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+		files, err := writeSpec(dir, vs)
+		genfiles = append(genfiles, files...)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, pkg)
 	}
-	genfiles = append(genfiles, swaggerFile)
 
 	// Go endpoint
 	controllerFile := filepath.Join(swaggerDir, "swagger.go")
@@ -104,7 +105,7 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 		codegen.SimpleImport("github.com/goadesign/goa"),
 	}
 	file.WriteHeader(fmt.Sprintf("%s Swagger Spec", api.Name), "swagger", imports)
-	err = file.ExecuteTemplate("swagger", swaggerT, nil, api)
+	err = file.ExecuteTemplate("swagger", swaggerT, nil, versions)
 	if err != nil {
 		return nil, err
 	}
@@ -115,9 +116,42 @@ func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error)
 	return genfiles, nil
 }
 
+// writeSpec writes the JSON and YAML representations of the given spec in dir.
+func writeSpec(dir string, s *Swagger) ([]string, error) {
+	var files []string
+
+	// JSON
+	rawJSON, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	swaggerFile := filepath.Join(dir, "swagger.json")
+	if err := ioutil.WriteFile(swaggerFile, rawJSON, 0644); err != nil {
+		return nil, err
+	}
+	files = append(files, swaggerFile)
+
+	// YAML
+	var yamlSource interface{}
+	if err = json.Unmarshal(rawJSON, &yamlSource); err != nil {
+		return files, err
+	}
+
+	rawYAML, err := yaml.Marshal(yamlSource)
+	if err != nil {
+		return files, err
+	}
+	swaggerFile = filepath.Join(dir, "swagger.yaml")
+	if err := ioutil.WriteFile(swaggerFile, rawYAML, 0644); err != nil {
+		return files, err
+	}
+	return append(files, swaggerFile), nil
+}
+
 const swaggerT = `
 // MountController mounts the swagger spec controller.
 func MountController(service *goa.Service) {
 	service.ServeFiles("/swagger.json", "swagger/swagger.json")
-}
+{{ range . }}	service.ServeFiles("/swagger/{{ . }}/swagger.json", "swagger/{{ . }}/swagger.json")
+{{ end }}}
 `
@@ -8,6 +8,7 @@ import (
 
 	"github.com/goadesign/goa/design"
 	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_schema"
 )
 
@@ -144,6 +145,13 @@ type (
 		UniqueItems      bool          `json:"uniqueItems,omitempty"`
 		Enum             []interface{} `json:"enum,omitempty"`
 		MultipleOf       float64       `json:"multipleOf,omitempty"`
+		// HasMaximum and HasMinimum are true if Maximum and Minimum are set, a bound of 0 is
+		// otherwise indistinguishable from no bound.
+		HasMaximum bool `json:"-"`
+		HasMinimum bool `json:"-"`
+		// Deprecated declares this parameter to be deprecated. Swagger has no standard
+		// property for deprecating parameters so this uses a vendor extension.
+		Deprecated bool `json:"x-deprecated,omitempty"`
 	}
 
 	// Response describes an operation response.
@@ -192,6 +200,10 @@ type (
 		UniqueItems      bool          `json:"uniqueItems,omitempty"`
 		Enum             []interface{} `json:"enum,omitempty"`
 		MultipleOf       float64       `json:"multipleOf,omitempty"`
+		// HasMaximum and HasMinimum are true if Maximum and Minimum are set, a bound of 0 is
+		// otherwise indistinguishable from no bound.
+		HasMaximum bool `json:"-"`
+		HasMinimum bool `json:"-"`
 	}
 
 	// SecurityDefinition allows the definition of a security scheme that can be used by the
@@ -262,6 +274,10 @@ type (
 		UniqueItems      bool          `json:"uniqueItems,omitempty"`
 		Enum             []interface{} `json:"enum,omitempty"`
 		MultipleOf       float64       `json:"multipleOf,omitempty"`
+		// HasMaximum and HasMinimum are true if Maximum and Minimum are set, a bound of 0 is
+		// otherwise indistinguishable from no bound.
+		HasMaximum bool `json:"-"`
+		HasMinimum bool `json:"-"`
 	}
 
 	// Tag allows adding meta data to a single tag that is used by the Operation Object. It is
@@ -359,10 +375,142 @@ func New(api *design.APIDefinition) (*Swagger, error) {
 			d.Links = nil
 			s.Definitions[n] = d
 		}
+		for n, d := range genschema.Definitions {
+			addVariants(s.Definitions, n, d)
+		}
 	}
 	return s, nil
 }
 
+// NewVersion creates the Swagger spec of an API version. The operations of versions that share the
+// API base path require the version header so that the spec describes how requests select the
+// version.
+func NewVersion(v *design.APIVersionDefinition) (*Swagger, error) {
+	s, err := New(v.API())
+	if err != nil || s == nil || v.SelectedByPath() {
+		return s, err
+	}
+	if header := design.Design.VersionHeader; header != "" {
+		for _, p := range s.Paths {
+			for _, o := range []*Operation{p.Get, p.Put, p.Post, p.Delete, p.Options, p.Head, p.Patch} {
+				if o == nil {
+					continue
+				}
+				o.Parameters = append(o.Parameters, &Parameter{
+					Name:        header,
+					In:          "header",
+					Description: "API version",
+					Required:    true,
+					Type:        "string",
+					Enum:        []interface{}{v.Name},
+				})
+			}
+		}
+		return s, nil
+	}
+	if param := design.Design.VersionParam; param != "" {
+		note := fmt.Sprintf("Requests select the version with the %q parameter of the Accept header, for example \"application/json; %s=%s\".", param, param, v.Name)
+		if s.Info.Description != "" {
+			note = s.Info.Description + "\n\n" + note
+		}
+		s.Info.Description = note
+	}
+	return s, nil
+}
+
+// MarshalJSON marshals the parameter, including the bounds set to 0.
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type param Parameter
+	return genschema.MarshalBounds(param(p), p.HasMinimum && p.Minimum == 0, p.HasMaximum && p.Maximum == 0)
+}
+
+// UnmarshalJSON unmarshals the parameter and records whether it sets its bounds.
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type param Parameter
+	var err error
+	p.HasMinimum, p.HasMaximum, err = genschema.UnmarshalBounds(data, (*param)(p))
+	return err
+}
+
+// MarshalJSON marshals the header, including the bounds set to 0.
+func (h Header) MarshalJSON() ([]byte, error) {
+	type header Header
+	return genschema.MarshalBounds(header(h), h.HasMinimum && h.Minimum == 0, h.HasMaximum && h.Maximum == 0)
+}
+
+// UnmarshalJSON unmarshals the header and records whether it sets its bounds.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type header Header
+	var err error
+	h.HasMinimum, h.HasMaximum, err = genschema.UnmarshalBounds(data, (*header)(h))
+	return err
+}
+
+// MarshalJSON marshals the items, including the bounds set to 0.
+func (i Items) MarshalJSON() ([]byte, error) {
+	type items Items
+	return genschema.MarshalBounds(items(i), i.HasMinimum && i.Minimum == 0, i.HasMaximum && i.Maximum == 0)
+}
+
+// UnmarshalJSON unmarshals the items and records whether they set their bounds.
+func (i *Items) UnmarshalJSON(data []byte) error {
+	type items Items
+	var err error
+	i.HasMinimum, i.HasMaximum, err = genschema.UnmarshalBounds(data, (*items)(i))
+	return err
+}
+
+// addVariants replaces the "oneOf" keywords that Swagger does not support with the Swagger
+// polymorphism pattern: union schemas become definitions that keep their discriminator property
+// and each variant gets a definition that extends the union definition with allOf. The variant
+// definitions are named after the union definition and the discriminator value, the
+// x-discriminator-value extension maps them back to the discriminator value.
+func addVariants(defs map[string]*genschema.JSONSchema, name string, s *genschema.JSONSchema) {
+	if s == nil {
+		return
+	}
+	if isUnion(s) {
+		var values []interface{}
+		if d, ok := s.Properties[s.Discriminator]; ok {
+			values = d.Enum
+		}
+		for i, v := range s.OneOf {
+			if i >= len(values) {
+				break
+			}
+			value := fmt.Sprintf("%v", values[i])
+			defs[name+codegen.Goify(value, true)] = &genschema.JSONSchema{
+				AllOf: []*genschema.JSONSchema{
+					{Ref: "#/definitions/" + name},
+					v,
+				},
+				DiscriminatorValue: value,
+			}
+		}
+		s.OneOf = nil
+		return
+	}
+	for n, p := range s.Properties {
+		pname := name + codegen.Goify(n, true)
+		if isUnion(p) {
+			defs[pname] = p
+			s.Properties[n] = &genschema.JSONSchema{Ref: "#/definitions/" + pname}
+		}
+		addVariants(defs, pname, p)
+	}
+	items := s.Items
+	if isUnion(items) {
+		defs[name+"Item"] = items
+		s.Items = &genschema.JSONSchema{Ref: "#/definitions/" + name + "Item"}
+	}
+	addVariants(defs, name+"Item", items)
+}
+
+// isUnion returns true if s describes a union.
+func isUnion(s *genschema.JSONSchema) bool {
+	return s != nil && s.Discriminator != "" && s.OneOf != nil
+}
+
 func securityDefsFromDefinition(schemes []*design.SecuritySchemeDefinition) map[string]*SecurityDefinition {
 	if len(schemes) == 0 {
 		return nil
@@ -380,6 +528,14 @@ func securityDefsFromDefinition(schemes []*design.SecuritySchemeDefinition) map[
 			TokenURL:         scheme.TokenURL,
 			Scopes:           scheme.Scopes,
 		}
+		if def.In == "cookie" {
+			// Swagger 2.0 API keys may only be read from headers or query strings, describe
+			// the cookie as the Cookie header that carries it.
+			def.Description += fmt.Sprintf("\n\n**Cookie**: the key is sent in the `%s` cookie", def.Name)
+			def.Description = strings.TrimPrefix(def.Description, "\n\n")
+			def.In = "header"
+			def.Name = "Cookie"
+		}
 		if scheme.Kind == design.JWTSecurityKind {
 			if def.TokenURL != "" {
 				def.Description += fmt.Sprintf("\n\n**Token URL**: %s", def.TokenURL)
@@ -498,12 +654,11 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 			Required:    required,
 			In:          in,
 			Type:        at.Type.Name(),
+			Deprecated:  at.Deprecation != nil,
 		}
-		var items *Items
 		if at.Type.IsArray() {
-			items = itemsFromDefinition(at)
+			param.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
 		}
-		param.Items = items
 		initValidations(at, param)
 		res[i] = param
 		i++
@@ -512,6 +667,30 @@ func paramsFromDefinition(params *design.AttributeDefinition, path string) ([]*P
 	return res, nil
 }
 
+// formDataFromDefinition returns the "formData" parameters corresponding to the attributes of a
+// multipart form payload.
+func formDataFromDefinition(payload *design.UserTypeDefinition) []*Parameter {
+	var res []*Parameter
+	payload.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		param := &Parameter{
+			Name:        n,
+			Default:     toStringMap(at.DefaultValue),
+			Description: at.Description,
+			Required:    payload.IsRequired(n),
+			In:          "formData",
+			Type:        at.Type.Name(),
+		}
+		if at.Type.IsArray() {
+			param.Items = itemsFromDefinition(at.Type.ToArray().ElemType)
+			param.CollectionFormat = "multi"
+		}
+		initValidations(at, param)
+		res = append(res, param)
+		return nil
+	})
+	return res
+}
+
 // toStringMap converts map[interface{}]interface{} to a map[string]interface{} when possible.
 func toStringMap(val interface{}) interface{} {
 	switch actual := val.(type) {
@@ -621,6 +800,18 @@ func headersFromDefinition(headers *design.AttributeDefinition) (map[string]*Hea
 	return res, nil
 }
 
+// errorsDescription returns the description of the responses that carry the given typed errors.
+func errorsDescription(errors []*design.ErrorDefinition) string {
+	descs := make([]string, len(errors))
+	for i, e := range errors {
+		descs[i] = fmt.Sprintf("Error %#v", e.Name)
+		if d := e.Description(); d != "" {
+			descs[i] += ": " + d
+		}
+	}
+	return strings.Join(descs, "\n\n")
+}
+
 func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *design.RouteDefinition) error {
 	action := route.Parent
 
@@ -639,7 +830,63 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		responses[strconv.Itoa(r.Status)] = resp
 	}
 
-	if action.Payload != nil {
+	if rl := action.EffectiveRateLimit(); rl != nil {
+		if _, ok := responses["429"]; !ok {
+			headers, err := headersFromDefinition(&design.AttributeDefinition{Type: rl.Headers()})
+			if err != nil {
+				return err
+			}
+			responses["429"] = &Response{
+				Description: fmt.Sprintf("Too Many Requests, the rate limit is %s", rl.Description()),
+				Headers:     headers,
+			}
+		}
+	}
+
+	errors := make(map[string][]*design.ErrorDefinition)
+	for _, e := range action.AllErrors() {
+		status := strconv.Itoa(e.Status)
+		errors[status] = append(errors[status], e)
+	}
+	for status, errs := range errors {
+		if resp, ok := responses[status]; ok {
+			resp.Description = strings.TrimPrefix(resp.Description+"\n\n"+errorsDescription(errs), "\n\n")
+			continue
+		}
+		resp := &Response{Description: errorsDescription(errs)}
+		if len(errs) == 1 {
+			resp.Schema = genschema.TypeSchema(api, errs[0].Type)
+		}
+		responses[status] = resp
+	}
+
+	if action.Cacheable {
+		status, desc := "412", "Precondition Failed"
+		if route.Verb == "GET" || route.Verb == "HEAD" {
+			status, desc = "304", "Not Modified"
+		}
+		if _, ok := responses[status]; !ok {
+			responses[status] = &Response{Description: desc}
+		}
+	}
+
+	var produces []string
+	if action.Stream != nil {
+		produces = []string{"text/event-stream"}
+		if _, ok := responses["200"]; !ok {
+			resp := &Response{Description: "Stream of server-sent events"}
+			if mt := action.Stream.EventMediaType(); mt != nil {
+				resp.Schema = genschema.TypeSchema(api, mt)
+			}
+			responses["200"] = resp
+		}
+	}
+
+	var consumes []string
+	if action.PayloadMultipart {
+		params = append(params, formDataFromDefinition(action.Payload)...)
+		consumes = []string{"multipart/form-data"}
+	} else if action.Payload != nil {
 		payloadSchema := genschema.TypeSchema(api, action.Payload)
 		pp := &Parameter{
 			Name:        "payload",
@@ -668,16 +915,24 @@ func buildPathFromDefinition(s *Swagger, api *design.APIDefinition, route *desig
 		schemes = api.Schemes
 	}
 
+	// Swagger 2.0 has no cookie parameters, list the cookies in the description instead.
+	description := action.Description
+	if action.Cookies != nil {
+		description = strings.TrimPrefix(description+"\n\n"+cookiesDescription(action.Cookies), "\n\n")
+	}
+
 	operation := &Operation{
 		Tags:         tagNames,
-		Description:  action.Description,
+		Description:  description,
 		Summary:      summaryFromDefinition(action),
 		ExternalDocs: docsFromDefinition(action.Docs),
 		OperationID:  operationID,
+		Consumes:     consumes,
+		Produces:     produces,
 		Parameters:   params,
 		Responses:    responses,
 		Schemes:      schemes,
-		Deprecated:   false,
+		Deprecated:   action.Deprecation != nil,
 	}
 
 	if action.Security != nil {
@@ -740,6 +995,24 @@ func applySecurityForAction(operation *Operation, action *design.ActionDefinitio
 	}
 }
 
+// cookiesDescription returns the Markdown list of the given request cookies.
+func cookiesDescription(cookies *design.AttributeDefinition) string {
+	lines := []string{"**Cookies**:"}
+	cookies.Type.ToObject().IterateAttributes(func(n string, at *design.AttributeDefinition) error {
+		line := fmt.Sprintf("  * `%s` (%s", n, at.Type.Name())
+		if cookies.IsRequired(n) {
+			line += ", required"
+		}
+		line += ")"
+		if at.Description != "" {
+			line += ": " + at.Description
+		}
+		lines = append(lines, line)
+		return nil
+	})
+	return strings.Join(lines, "\n")
+}
+
 func scopesList(scopes []string) string {
 	sort.Strings(scopes)
 
@@ -793,31 +1066,53 @@ func initPatternValidation(def interface{}, pattern string) {
 	}
 }
 
-func initMinimumValidation(def interface{}, min float64) {
+func initMinimumValidation(def interface{}, min float64, exclusive bool) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.Minimum, actual.HasMinimum = min, true
+		actual.ExclusiveMinimum = exclusive
+	case *Header:
+		actual.Minimum, actual.HasMinimum = min, true
+		actual.ExclusiveMinimum = exclusive
+	case *Items:
+		actual.Minimum, actual.HasMinimum = min, true
+		actual.ExclusiveMinimum = exclusive
+	}
+}
+
+func initMaximumValidation(def interface{}, max float64, exclusive bool) {
 	switch actual := def.(type) {
 	case *Parameter:
-		actual.Minimum = min
-		actual.ExclusiveMinimum = true
+		actual.Maximum, actual.HasMaximum = max, true
+		actual.ExclusiveMaximum = exclusive
 	case *Header:
-		actual.Minimum = min
-		actual.ExclusiveMinimum = true
+		actual.Maximum, actual.HasMaximum = max, true
+		actual.ExclusiveMaximum = exclusive
 	case *Items:
-		actual.Minimum = min
-		actual.ExclusiveMinimum = true
+		actual.Maximum, actual.HasMaximum = max, true
+		actual.ExclusiveMaximum = exclusive
 	}
 }
 
-func initMaximumValidation(def interface{}, max float64) {
+func initMultipleOfValidation(def interface{}, multiple float64) {
 	switch actual := def.(type) {
 	case *Parameter:
-		actual.Maximum = max
-		actual.ExclusiveMaximum = true
+		actual.MultipleOf = multiple
 	case *Header:
-		actual.Maximum = max
-		actual.ExclusiveMaximum = true
+		actual.MultipleOf = multiple
 	case *Items:
-		actual.Maximum = max
-		actual.ExclusiveMaximum = true
+		actual.MultipleOf = multiple
+	}
+}
+
+func initUniqueItemsValidation(def interface{}, unique bool) {
+	switch actual := def.(type) {
+	case *Parameter:
+		actual.UniqueItems = unique
+	case *Header:
+		actual.UniqueItems = unique
+	case *Items:
+		actual.UniqueItems = unique
 	}
 }
 
@@ -844,19 +1139,26 @@ func initMaxLengthValidation(def interface{}, max int) {
 }
 
 func initValidations(attr *design.AttributeDefinition, def interface{}) {
+	initFormatValidation(def, genschema.TypeFormat(attr.Type))
 	val := attr.Validation
 	if val == nil {
 		return
 	}
 	initEnumValidation(def, val.Values)
-	initFormatValidation(def, val.Format)
+	if val.Format != "" {
+		initFormatValidation(def, val.Format)
+	}
 	initPatternValidation(def, val.Pattern)
 	if val.Minimum != nil {
-		initMinimumValidation(def, *val.Minimum)
+		initMinimumValidation(def, *val.Minimum, val.ExclusiveMinimum)
 	}
 	if val.Maximum != nil {
-		initMaximumValidation(def, *val.Maximum)
+		initMaximumValidation(def, *val.Maximum, val.ExclusiveMaximum)
+	}
+	if val.MultipleOf != nil {
+		initMultipleOfValidation(def, *val.MultipleOf)
 	}
+	initUniqueItemsValidation(def, val.UniqueItems)
 	if val.MinLength != nil {
 		initMinLengthValidation(def, *val.MinLength)
 	}
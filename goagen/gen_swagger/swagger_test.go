@@ -2,8 +2,10 @@ package genswagger_test
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/go-openapi/loads"
+	"github.com/goadesign/goa"
 	_ "github.com/goadesign/goa-cellar/design"
 	. "github.com/goadesign/goa/design"
 	. "github.com/goadesign/goa/design/apidsl"
@@ -281,6 +283,412 @@ var _ = Describe("New", func() {
 			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
 		})
 
+		Context("with exclusive bounds and multiple of validations", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("list", func() {
+						Routing(GET("/list"))
+						Params(func() {
+							Param("amount", Number, func() {
+								ExclusiveMinimum(0)
+								MultipleOf(0.5)
+							})
+							Param("ids", ArrayOf(Integer), func() {
+								UniqueItems()
+							})
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("sets the parameter validations", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				params := swagger.Paths["/list"].Get.Parameters
+				Ω(params).Should(HaveLen(2))
+				for _, p := range params {
+					switch p.Name {
+					case "amount":
+						Ω(p.HasMinimum).Should(BeTrue())
+						Ω(p.Minimum).Should(Equal(0.0))
+						b, err := json.Marshal(p)
+						Ω(err).ShouldNot(HaveOccurred())
+						Ω(string(b)).Should(ContainSubstring(`"minimum":0`))
+						var decoded genswagger.Parameter
+						Ω(json.Unmarshal(b, &decoded)).ShouldNot(HaveOccurred())
+						Ω(decoded.HasMinimum).Should(BeTrue())
+						Ω(decoded.HasMaximum).Should(BeFalse())
+						Ω(p.ExclusiveMinimum).Should(BeTrue())
+						Ω(p.MultipleOf).Should(Equal(0.5))
+					case "ids":
+						Ω(p.UniqueItems).Should(BeTrue())
+					}
+				}
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with field selection", func() {
+			BeforeEach(func() {
+				Bottle := MediaType("application/vnd.bottle+json", func() {
+					Attributes(func() {
+						Attribute("id", Integer)
+						Attribute("name", String)
+					})
+					View("default", func() {
+						Attribute("id")
+					})
+				})
+				Resource("res", func() {
+					Action("list", func() {
+						Routing(GET("/list"))
+						Fields()
+						Response(OK, Bottle)
+					})
+				})
+			})
+
+			It("documents the fields parameter", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				params := swagger.Paths["/list"].Get.Parameters
+				Ω(params).Should(HaveLen(1))
+				Ω(params[0].Name).Should(Equal(FieldsParam))
+				Ω(params[0].Type).Should(Equal("array"))
+				Ω(params[0].Items.Type).Should(Equal("string"))
+				Ω(params[0].Items.Items).Should(BeNil())
+				Ω(params[0].Items.Enum).Should(Equal([]interface{}{"id", "name"}))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with custom formats", func() {
+			BeforeEach(func() {
+				goa.RegisterFormat("iban", func(string) error { return nil })
+				Resource("res", func() {
+					Action("list", func() {
+						Routing(GET("/list"))
+						Params(func() {
+							Param("account", String, func() {
+								Format("iban")
+							})
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("sets the parameter format", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				params := swagger.Paths["/list"].Get.Parameters
+				Ω(params).Should(HaveLen(1))
+				Ω(params[0].Format).Should(Equal("iban"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with deprecated actions and parameters", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("list", func() {
+						Routing(GET("/list"))
+						Deprecated("2017-01-01")
+						Params(func() {
+							Param("sort", String, func() {
+								Deprecated()
+							})
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("marks the operation and the parameter as deprecated", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				op := swagger.Paths["/list"].Get
+				Ω(op.Deprecated).Should(BeTrue())
+				Ω(op.Parameters).Should(HaveLen(1))
+				Ω(op.Parameters[0].Deprecated).Should(BeTrue())
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with streaming actions", func() {
+			BeforeEach(func() {
+				Tick := MediaType("application/vnd.tick+json", func() {
+					Attributes(func() {
+						Attribute("seq", Integer)
+					})
+					View("default", func() {
+						Attribute("seq")
+					})
+				})
+				Resource("res", func() {
+					Action("watch", func() {
+						Routing(GET("/watch"))
+						Stream(Tick)
+					})
+				})
+			})
+
+			It("produces server-sent events", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				op := swagger.Paths["/watch"].Get
+				Ω(op.Produces).Should(Equal([]string{"text/event-stream"}))
+				Ω(op.Responses).Should(HaveKey("200"))
+				Ω(op.Responses["200"].Schema).ShouldNot(BeNil())
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with multipart payloads", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("upload", func() {
+						Routing(POST("/upload"))
+						Payload(func() {
+							Member("label", File)
+							Member("photos", ArrayOf(File))
+							Member("year", Integer)
+							Required("label")
+						})
+						MultipartForm()
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("describes the payload with form data parameters", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				op := swagger.Paths["/upload"].Post
+				Ω(op.Consumes).Should(Equal([]string{"multipart/form-data"}))
+				Ω(op.Parameters).Should(HaveLen(3))
+				params := make(map[string]*genswagger.Parameter)
+				for _, p := range op.Parameters {
+					Ω(p.In).Should(Equal("formData"))
+					params[p.Name] = p
+				}
+				Ω(params["label"].Type).Should(Equal("file"))
+				Ω(params["label"].Required).Should(BeTrue())
+				Ω(params["photos"].Type).Should(Equal("array"))
+				Ω(params["photos"].Items.Type).Should(Equal("file"))
+				Ω(params["photos"].CollectionFormat).Should(Equal("multi"))
+				Ω(params["year"].Type).Should(Equal("integer"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with cookies", func() {
+			BeforeEach(func() {
+				base := Design.DSLFunc
+				Design.DSLFunc = func() {
+					base()
+					APIKeySecurity("cookie_key", func() {
+						Description("Session key")
+						Cookie("api_key")
+					})
+				}
+				Resource("res", func() {
+					Action("show", func() {
+						Description("Show the resource")
+						Routing(GET("/show"))
+						Cookies(func() {
+							Cookie("session", String, "Session ID")
+							Cookie("visits", Integer)
+							Required("session")
+						})
+						Security("cookie_key")
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("describes the cookie API keys as Cookie headers", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				def := swagger.SecurityDefinitions["cookie_key"]
+				Ω(def.In).Should(Equal("header"))
+				Ω(def.Name).Should(Equal("Cookie"))
+				Ω(def.Description).Should(Equal("Session key\n\n**Cookie**: the key is sent in the `api_key` cookie"))
+			})
+
+			It("lists the cookies in the operation description", func() {
+				op := swagger.Paths["/show"].Get
+				Ω(op.Parameters).Should(BeEmpty())
+				Ω(op.Description).Should(Equal("Show the resource\n\n**Cookies**:\n  * `session` (string, required): Session ID\n  * `visits` (integer)"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with rate limited actions", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					RateLimit(100, time.Minute)
+					Action("list", func() {
+						Routing(GET("/list"))
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("documents the 429 responses", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				resp := swagger.Paths["/list"].Get.Responses["429"]
+				Ω(resp).ShouldNot(BeNil())
+				Ω(resp.Description).Should(ContainSubstring("100 requests per 1m0s"))
+				Ω(resp.Headers).Should(HaveKey("Retry-After"))
+				Ω(resp.Headers).Should(HaveKey("RateLimit-Remaining"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with cacheable actions", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Action("show", func() {
+						Routing(GET("/:id"), PUT("/:id"))
+						Cacheable()
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("documents the 304 and 412 responses", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Paths["/{id}"].Get.Responses).Should(HaveKey("304"))
+				Ω(swagger.Paths["/{id}"].Get.Responses).ShouldNot(HaveKey("412"))
+				Ω(swagger.Paths["/{id}"].Put.Responses).Should(HaveKey("412"))
+				Ω(swagger.Paths["/{id}"].Put.Responses).ShouldNot(HaveKey("304"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with typed errors", func() {
+			BeforeEach(func() {
+				Resource("res", func() {
+					Error("not_found", 404, func() {
+						Description("The resource does not exist")
+						Attribute("id", Integer)
+					})
+					Action("show", func() {
+						Routing(GET("/:id"))
+						Error("locked", 423)
+						Response(OK)
+					})
+				})
+			})
+
+			It("documents the error responses", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				responses := swagger.Paths["/{id}"].Get.Responses
+				Ω(responses).Should(HaveKey("404"))
+				Ω(responses["404"].Description).Should(ContainSubstring(`Error "not_found": The resource does not exist`))
+				Ω(responses["404"].Schema).ShouldNot(BeNil())
+				Ω(responses["404"].Schema.Ref).Should(Equal("#/definitions/NotFoundError"))
+				Ω(responses).Should(HaveKey("423"))
+				Ω(swagger.Definitions).Should(HaveKey("NotFoundError"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with extended types", func() {
+			BeforeEach(func() {
+				Audit := Type("Audit", func() {
+					Attribute("created_by", String)
+					Required("created_by")
+				})
+				Bottle := Type("Bottle", func() {
+					Extend(Audit)
+					Attribute("name", String)
+					Required("name")
+				})
+				Resource("res", func() {
+					Action("create", func() {
+						Routing(POST("/"))
+						Payload(Bottle)
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("references the base type definition", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				Ω(swagger.Definitions).Should(HaveKey("CreateResPayload"))
+				bottle := swagger.Definitions["CreateResPayload"]
+				Ω(bottle.AllOf).Should(HaveLen(1))
+				Ω(bottle.AllOf[0].Ref).Should(Equal("#/definitions/Audit"))
+				Ω(bottle.Properties).Should(HaveLen(1))
+				Ω(bottle.Properties).Should(HaveKey("name"))
+				Ω(bottle.Required).Should(Equal([]string{"name"}))
+				Ω(swagger.Definitions["Audit"].Properties).Should(HaveKey("created_by"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
+		Context("with unions", func() {
+			BeforeEach(func() {
+				Card := Type("CardPayment", func() {
+					Attribute("number", String)
+					Required("number")
+				})
+				Bank := Type("BankPayment", func() {
+					Attribute("iban", String)
+					Required("iban")
+				})
+				Resource("res", func() {
+					Action("pay", func() {
+						Routing(POST("/"))
+						Payload(func() {
+							Member("method", OneOf("type",
+								Variant("card", Card),
+								Variant("bank", Bank),
+							))
+						})
+						Response(NoContent)
+					})
+				})
+			})
+
+			It("defines the union and its variants with the polymorphism pattern", func() {
+				Ω(newErr).ShouldNot(HaveOccurred())
+				payload := swagger.Definitions["PayResPayload"]
+				Ω(payload).ShouldNot(BeNil())
+				Ω(payload.Properties["method"].Ref).Should(Equal("#/definitions/PayResPayloadMethod"))
+
+				union := swagger.Definitions["PayResPayloadMethod"]
+				Ω(union).ShouldNot(BeNil())
+				Ω(union.OneOf).Should(BeNil())
+				Ω(union.Discriminator).Should(Equal("type"))
+				Ω(union.Required).Should(Equal([]string{"type"}))
+				Ω(union.Properties["type"].Enum).Should(Equal([]interface{}{"card", "bank"}))
+
+				card := swagger.Definitions["PayResPayloadMethodCard"]
+				Ω(card).ShouldNot(BeNil())
+				Ω(card.DiscriminatorValue).Should(Equal("card"))
+				Ω(card.AllOf).Should(HaveLen(2))
+				Ω(card.AllOf[0].Ref).Should(Equal("#/definitions/PayResPayloadMethod"))
+				Ω(card.AllOf[1].Ref).Should(Equal("#/definitions/CardPayment"))
+
+				bank := swagger.Definitions["PayResPayloadMethodBank"]
+				Ω(bank).ShouldNot(BeNil())
+				Ω(bank.DiscriminatorValue).Should(Equal("bank"))
+				Ω(bank.AllOf[1].Ref).Should(Equal("#/definitions/BankPayment"))
+				Ω(swagger.Definitions).Should(HaveKey("CardPayment"))
+				Ω(swagger.Definitions).Should(HaveKey("BankPayment"))
+			})
+
+			It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+		})
+
 		Context("with resources", func() {
 			BeforeEach(func() {
 				Country := MediaType("application/vnd.goa.example.origin", func() {
@@ -401,3 +809,79 @@ var _ = Describe("New", func() {
 		})
 	})
 })
+
+var _ = Describe("NewVersion", func() {
+	var versionHeader string
+	var swagger *genswagger.Swagger
+	var newErr error
+
+	BeforeEach(func() {
+		versionHeader = "X-API-Version"
+		dslengine.Reset()
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+	})
+
+	JustBeforeEach(func() {
+		API("test", func() {
+			BasePath("/api")
+			if versionHeader != "" {
+				VersionHeader(versionHeader)
+			} else {
+				VersionParam("version")
+			}
+		})
+		Resource("bottle", func() {
+			Action("show", func() {
+				Routing(GET("/bottles/:id"))
+				Response(NoContent)
+			})
+		})
+		APIVersion("v1", nil)
+		APIVersion("v2", func() {
+			BasePath("/v2")
+		})
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		swagger, newErr = genswagger.NewVersion(Design.Versions[0])
+	})
+
+	It("requires the version header in the operations of the version", func() {
+		Ω(newErr).ShouldNot(HaveOccurred())
+		Ω(swagger.Info.Version).Should(Equal("v1"))
+		Ω(swagger.BasePath).Should(Equal("/api"))
+		op := swagger.Paths["/bottles/{id}"].Get
+		Ω(op).ShouldNot(BeNil())
+		var header *genswagger.Parameter
+		for _, p := range op.Parameters {
+			if p.In == "header" {
+				header = p
+			}
+		}
+		Ω(header).ShouldNot(BeNil())
+		Ω(header.Name).Should(Equal("X-API-Version"))
+		Ω(header.Required).Should(BeTrue())
+		Ω(header.Enum).Should(Equal([]interface{}{"v1"}))
+	})
+
+	It("serializes into valid swagger JSON", func() { validateSwagger(swagger) })
+
+	It("uses the version base path of versions selected by path", func() {
+		genschema.Definitions = make(map[string]*genschema.JSONSchema)
+		s, err := genswagger.NewVersion(Design.Versions[1])
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(s.BasePath).Should(Equal("/v2"))
+		for _, p := range s.Paths["/bottles/{id}"].Get.Parameters {
+			Ω(p.In).ShouldNot(Equal("header"))
+		}
+	})
+
+	Context("with a version parameter", func() {
+		BeforeEach(func() {
+			versionHeader = ""
+		})
+
+		It("describes how requests select the version", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(swagger.Info.Description).Should(ContainSubstring(`application/json; version=v1`))
+		})
+	})
+})
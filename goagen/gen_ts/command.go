@@ -0,0 +1,48 @@
+package gents
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/meta"
+)
+
+var (
+	// Timeout is the default request timeout before it gets aborted.
+	Timeout time.Duration
+
+	// Scheme is the URL scheme used to make requests to the API.
+	Scheme string
+
+	// Host is the API hostname.
+	Host string
+)
+
+// Command is the goa TypeScript client generator command line data structure.
+// It implements meta.Command.
+type Command struct {
+	*codegen.BaseCommand
+}
+
+// NewCommand instantiates a new command.
+func NewCommand() *Command {
+	base := codegen.NewBaseCommand("ts", "Generate TypeScript client module with typed models")
+	return &Command{BaseCommand: base}
+}
+
+// RegisterFlags registers the command line flags with the given registry.
+func (c *Command) RegisterFlags(r codegen.FlagRegistry) {
+	r.Flags().DurationVar(&Timeout, "timeout", time.Duration(20)*time.Second, `the duration before the request times out, 0 means no timeout.`)
+	r.Flags().StringVar(&Scheme, "scheme", "", `the URL scheme used to make requests to the API, defaults to the scheme defined in the API design if any.`)
+	r.Flags().StringVar(&Host, "host", "", `the API hostname, defaults to the hostname defined in the API design if any, requests use relative URLs if there is none.`)
+}
+
+// Run simply calls the meta generator.
+func (c *Command) Run() ([]string, error) {
+	gen := meta.NewGenerator(
+		"gents.Generate",
+		[]*codegen.ImportSpec{codegen.SimpleImport("github.com/goadesign/goa/goagen/gen_ts")},
+		nil,
+	)
+	return gen.Generate()
+}
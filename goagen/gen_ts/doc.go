@@ -0,0 +1,16 @@
+/*
+Package gents provides a goa generator for a TypeScript client module. The generated "models.ts"
+file declares the user types, the views of the media types, the payloads and the typed errors of
+the API with interfaces and type aliases. Attributes validated with Enum are described by unions
+of literal types and union attributes by unions of their variants tagged with the discriminator.
+
+The generated "client.ts" file implements a client class with one method per action. The method
+accepts a typed request that groups the path parameters, the query string parameters, the headers
+and the payload of the action and returns the typed response body. Error responses are thrown as
+APIError values whose body is typed with the errors the action may return. The client sends the
+requests with the global fetch function by default, a different transport may be given in the
+client options together with the signers that add the credentials of each security scheme.
+
+Actions that stream server-sent events and WebSocket actions are not implemented by the client.
+*/
+package gents
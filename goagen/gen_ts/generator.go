@@ -0,0 +1,102 @@
+package gents
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/utils"
+	"github.com/spf13/cobra"
+)
+
+// Generator is the TypeScript client generator.
+type Generator struct{}
+
+// Generate is the generator entry point called by the meta generator.
+func Generate() (files []string, err error) {
+	api := design.Design
+	g := new(Generator)
+	root := &cobra.Command{
+		Use:   "goagen",
+		Short: "TypeScript generator",
+		Long:  "TypeScript client module with typed models",
+		Run:   func(*cobra.Command, []string) { files, err = g.Generate(api) },
+	}
+	codegen.RegisterFlags(root)
+	NewCommand().RegisterFlags(root)
+	root.Execute()
+	return
+}
+
+// Generate writes the TypeScript models of the API to the file "models.ts" of the "ts" directory
+// of the output directory and the client to the file "client.ts".
+func (g *Generator) Generate(api *design.APIDefinition) (_ []string, err error) {
+	var genfiles []string
+
+	cleanup := func() {
+		for _, f := range genfiles {
+			os.Remove(f)
+		}
+	}
+
+	go utils.Catch(nil, cleanup)
+
+	defer func() {
+		if err != nil {
+			cleanup()
+		}
+	}()
+
+	tsDir := filepath.Join(codegen.OutputDir, "ts")
+	os.RemoveAll(tsDir)
+	if err = os.MkdirAll(tsDir, 0755); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, tsDir)
+
+	m, err := New(api, baseURL(api), int64(Timeout/time.Millisecond))
+	if err != nil {
+		return nil, err
+	}
+
+	modelsFile := filepath.Join(tsDir, "models.ts")
+	if err = ioutil.WriteFile(modelsFile, m.ModelsSource(), 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, modelsFile)
+
+	src, err := m.ClientSource()
+	if err != nil {
+		return nil, err
+	}
+	clientFile := filepath.Join(tsDir, "client.ts")
+	if err = ioutil.WriteFile(clientFile, src, 0644); err != nil {
+		return nil, err
+	}
+	genfiles = append(genfiles, clientFile)
+
+	return genfiles, nil
+}
+
+// baseURL returns the default base URL of the client requests built from the command line flags
+// and the API design. It returns an empty string if there is no host so that requests use
+// relative URLs.
+func baseURL(api *design.APIDefinition) string {
+	host, scheme := Host, Scheme
+	if host == "" {
+		host = api.Host
+	}
+	if host == "" {
+		return ""
+	}
+	if scheme == "" {
+		scheme = "http"
+		if len(api.Schemes) > 0 {
+			scheme = api.Schemes[0]
+		}
+	}
+	return scheme + "://" + host
+}
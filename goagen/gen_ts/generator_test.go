@@ -0,0 +1,66 @@
+package gents_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/goagen/codegen"
+	"github.com/goadesign/goa/goagen/gen_ts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generate", func() {
+	var outDir string
+	var files []string
+	var genErr error
+
+	var oldCommand string
+	var oldDesign *design.APIDefinition
+
+	BeforeEach(func() {
+		var err error
+		outDir, err = ioutil.TempDir("", "gents")
+		Ω(err).ShouldNot(HaveOccurred())
+		os.Args = []string{"codegen", "--out=" + outDir, "--design=foo", "--host=baz", "--timeout=5s"}
+		oldCommand = codegen.CommandName
+		codegen.CommandName = "ts"
+		oldDesign = design.Design
+	})
+
+	JustBeforeEach(func() {
+		files, genErr = gents.Generate()
+	})
+
+	AfterEach(func() {
+		codegen.CommandName = oldCommand
+		design.Design = oldDesign
+		os.RemoveAll(outDir)
+	})
+
+	Context("with a dummy API", func() {
+		BeforeEach(func() {
+			design.Design = &design.APIDefinition{
+				Name:        "testapi",
+				Title:       "dummy API with no resource",
+				Description: "I told you it's dummy",
+				Schemes:     []string{"https"},
+			}
+		})
+
+		It("generates the models and the client", func() {
+			Ω(genErr).Should(BeNil())
+			Ω(files).Should(HaveLen(3))
+			_, err := os.Stat(filepath.Join(outDir, "ts", "models.ts"))
+			Ω(err).ShouldNot(HaveOccurred())
+			content, err := ioutil.ReadFile(filepath.Join(outDir, "ts", "client.ts"))
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(content)).Should(ContainSubstring(`import * as models from "./models";`))
+			Ω(string(content)).Should(ContainSubstring(`: "https://baz";`))
+			Ω(string(content)).Should(ContainSubstring(`: 5000;`))
+			Ω(string(content)).Should(ContainSubstring("export type SecurityScheme = never;"))
+		})
+	})
+})
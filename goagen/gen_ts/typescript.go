@@ -0,0 +1,687 @@
+package gents
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/goadesign/goa/design"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+type (
+	// Module describes the TypeScript client module of an API: the models declared in
+	// "models.ts" and the client methods implemented in "client.ts".
+	Module struct {
+		// Name is the API name.
+		Name string
+		// BaseURL is the default base URL of the requests.
+		BaseURL string
+		// Timeout is the default request timeout in milliseconds.
+		Timeout int64
+		// Models lists the declarations of the models sorted by name.
+		Models []*Model
+		// Actions lists the client methods sorted by resource and action name.
+		Actions []*Action
+		// Schemes lists the security schemes of the API sorted by name.
+		Schemes []*SecurityScheme
+		// Skipped lists the actions that the client does not implement.
+		Skipped []string
+
+		api    *design.APIDefinition
+		models map[string]*Model
+		err    error
+	}
+
+	// Model is a TypeScript type declaration.
+	Model struct {
+		// Name is the type name.
+		Name string
+		// Description is the documentation of the type.
+		Description string
+		// Interface is true if the model is declared with an interface, false if it is
+		// declared with a type alias.
+		Interface bool
+		// Type is the interface body or the aliased type.
+		Type string
+	}
+
+	// Action describes the client method that sends the requests of an API action.
+	Action struct {
+		// Name is the method name, e.g. "showBottle".
+		Name string
+		// Description is the action description.
+		Description string
+		// Deprecation is the JSDoc deprecation tag of the action if deprecated.
+		Deprecation string
+		// Method is the HTTP method of the action first route.
+		Method string
+		// Path is the TypeScript template literal that builds the request path.
+		Path string
+		// Request is the name of the request model, empty if the action has no parameter
+		// and no payload.
+		Request string
+		// RequestOptional is true if all the fields of the request model are optional.
+		RequestOptional bool
+		// Query, Headers and Payload are true if the request model has the corresponding
+		// field.
+		Query, Headers, Payload bool
+		// Multipart is true if the payload is sent as a multipart form.
+		Multipart bool
+		// Sparse is true if the action renders the fields listed in the "fields" query
+		// string parameter only.
+		Sparse bool
+		// Result is the type of the values returned by the method.
+		Result string
+		// Error is the name of the type of the errors thrown by the method.
+		Error string
+		// ErrorBody is the type of the bodies of the error responses.
+		ErrorBody string
+		// Scheme is the name of the security scheme of the action if any.
+		Scheme string
+	}
+
+	// SecurityScheme describes a security scheme and the signer that adds its credentials to the
+	// requests.
+	SecurityScheme struct {
+		// Name is the security scheme name.
+		Name string
+		// Description is the security scheme description.
+		Description string
+		// Signer is the name of the function that creates the signers of the scheme.
+		Signer string
+		// Params lists the parameters of the signer function.
+		Params string
+		// Body is the expression that creates the signer.
+		Body string
+	}
+)
+
+// reserved lists the names of the TypeScript and DOM global types that models must not shadow.
+var reserved = map[string]bool{
+	"Array": true, "ArrayBuffer": true, "Blob": true, "Boolean": true, "Date": true,
+	"Error": true, "Event": true, "File": true, "FormData": true, "Function": true,
+	"Headers": true, "JSON": true, "Map": true, "Math": true, "Number": true, "Object": true,
+	"Omit": true, "Partial": true, "Pick": true, "Promise": true, "Record": true,
+	"RegExp": true, "Request": true, "Required": true, "Response": true, "Set": true,
+	"String": true, "Symbol": true, "URL": true,
+}
+
+// identifierRegex matches the property names that do not need quoting.
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// New creates the TypeScript module of the given API. The client sends the requests to the given
+// base URL by default and aborts them after the given number of milliseconds if not zero.
+func New(api *design.APIDefinition, baseURL string, timeout int64) (*Module, error) {
+	m := &Module{
+		Name:    api.Name,
+		BaseURL: baseURL,
+		Timeout: timeout,
+		api:     api,
+		models:  make(map[string]*Model),
+	}
+	api.IterateUserTypes(func(ut *design.UserTypeDefinition) error {
+		m.userType(ut, ut.Description)
+		return nil
+	})
+	api.IterateMediaTypes(func(mt *design.MediaTypeDefinition) error {
+		return mt.IterateViews(func(v *design.ViewDefinition) error {
+			m.mediaType(mt, v.Name)
+			return nil
+		})
+	})
+	for _, s := range api.SecuritySchemes {
+		m.Schemes = append(m.Schemes, schemeFromDefinition(s))
+	}
+	sort.Sort(schemesByName(m.Schemes))
+	api.IterateResources(func(res *design.ResourceDefinition) error {
+		return res.IterateActions(func(a *design.ActionDefinition) error {
+			if a.WebSocket() || a.Stream != nil || len(a.Routes) == 0 {
+				m.Skipped = append(m.Skipped, fmt.Sprintf("%s#%s", res.Name, a.Name))
+				return nil
+			}
+			m.Actions = append(m.Actions, m.action(a))
+			return nil
+		})
+	})
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, model := range m.models {
+		m.Models = append(m.Models, model)
+	}
+	sort.Sort(modelsByName(m.Models))
+	return m, nil
+}
+
+// Model returns the model with the given name, nil if there is none.
+func (m *Module) Model(name string) *Model {
+	return m.models[name]
+}
+
+// Action returns the client method with the given name, nil if there is none.
+func (m *Module) Action(name string) *Action {
+	for _, a := range m.Actions {
+		if a.Name == name {
+			return a
+		}
+	}
+	return nil
+}
+
+// userType declares the model of the given user type if not declared yet and returns its name.
+func (m *Module) userType(ut *design.UserTypeDefinition, desc string) string {
+	name := modelName(ut.TypeName)
+	if _, ok := m.models[name]; ok {
+		return name
+	}
+	// Declare the model before computing its type so that recursive types terminate.
+	model := &Model{Name: name, Description: desc}
+	m.models[name] = model
+	if _, ok := ut.Type.(design.Object); ok {
+		model.Interface = true
+		model.Type = m.objectType(ut.AttributeDefinition, "")
+	} else {
+		model.Type = m.typeRef(ut.AttributeDefinition, "")
+	}
+	return name
+}
+
+// mediaType declares the model of the given view of a media type if not declared yet and returns
+// its name. The view of projected media types defaults to their only view.
+func (m *Module) mediaType(mt *design.MediaTypeDefinition, view string) string {
+	if view == "" {
+		view = "default"
+		if len(mt.Views) == 1 {
+			for n := range mt.Views {
+				view = n
+			}
+		}
+	}
+	p, _, err := mt.Project(view)
+	if err != nil {
+		if m.err == nil {
+			m.err = fmt.Errorf("media type %q: %s", mt.Identifier, err)
+		}
+		return "unknown"
+	}
+	desc := mt.Description
+	if orig, ok := m.api.MediaTypes[design.CanonicalIdentifier(mt.Identifier)]; ok && desc == "" {
+		desc = orig.Description
+	}
+	if desc != "" {
+		desc += "\n\n"
+	}
+	desc += fmt.Sprintf("View %q of media type %q.", view, mt.Identifier)
+	return m.userType(p.UserTypeDefinition, desc)
+}
+
+// typeRef returns the TypeScript type of the given attribute. indent is the indentation of the
+// line where the type starts.
+func (m *Module) typeRef(att *design.AttributeDefinition, indent string) string {
+	switch actual := att.Type.(type) {
+	case design.Primitive:
+		if enum := enumType(att); enum != "" {
+			return enum
+		}
+		switch actual.Kind() {
+		case design.BooleanKind:
+			return "boolean"
+		case design.IntegerKind, design.NumberKind:
+			return "number"
+		case design.AnyKind:
+			return "unknown"
+		case design.FileKind:
+			return "Blob"
+		default:
+			return "string"
+		}
+	case *design.Array:
+		elem := m.typeRef(actual.ElemType, indent)
+		if strings.ContainsAny(elem, " |&") {
+			return "Array<" + elem + ">"
+		}
+		return elem + "[]"
+	case *design.Hash:
+		return "{ [key: string]: " + m.typeRef(actual.ElemType, indent) + " }"
+	case design.Object:
+		return m.objectType(att, indent)
+	case *design.Union:
+		variants := make([]string, len(actual.Variants))
+		for i, v := range actual.Variants {
+			value, _ := json.Marshal(v.Value)
+			variants[i] = fmt.Sprintf("(%s & { %s: %s })",
+				m.userType(v.Type, v.Type.Description), propertyName(actual.Discriminator), value)
+		}
+		return strings.Join(variants, " | ")
+	case *design.MediaTypeDefinition:
+		return m.mediaType(actual, att.View)
+	case *design.UserTypeDefinition:
+		return m.userType(actual, actual.Description)
+	}
+	return "unknown"
+}
+
+// objectType returns the TypeScript object type literal of the given object attribute.
+func (m *Module) objectType(att *design.AttributeDefinition, indent string) string {
+	obj := att.Type.ToObject()
+	if len(obj) == 0 {
+		return "{}"
+	}
+	var b bytes.Buffer
+	b.WriteString("{\n")
+	for _, n := range sortedNames(obj) {
+		child := obj[n]
+		name, ok := jsonName(n, child)
+		if !ok {
+			continue
+		}
+		writeDoc(&b, indent+"  ", child.Description, child.Deprecation)
+		optional := "?"
+		if att.IsRequired(n) {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, propertyName(name), optional, m.typeRef(child, indent+"  "))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// action returns the client method of the given action. The method uses the first route of the
+// action.
+func (m *Module) action(a *design.ActionDefinition) *Action {
+	prefix := codegen.Goify(a.Name, true) + codegen.Goify(a.Parent.Name, true)
+	route := a.Routes[0]
+	act := &Action{
+		Name:        codegen.Goify(a.Name, false) + codegen.Goify(a.Parent.Name, true),
+		Description: a.Description,
+		Method:      route.Verb,
+		Path:        pathTemplate(route.FullPath()),
+		Multipart:   a.PayloadMultipart,
+		Sparse:      a.Fields != nil,
+		Result:      m.resultType(a),
+		Error:       prefix + "Error",
+		ErrorBody:   m.errorType(a),
+	}
+	if a.Deprecation != nil {
+		act.Deprecation = deprecated(a.Deprecation)
+	}
+	if a.Security != nil {
+		act.Scheme = a.Security.Scheme.SchemeName
+	}
+
+	var b bytes.Buffer
+	optional := true
+	group := func(name, desc string, att *design.AttributeDefinition) {
+		if att == nil || len(att.Type.ToObject()) == 0 {
+			return
+		}
+		required := att.Validation != nil && len(att.Validation.Required) > 0
+		opt := "?"
+		if required {
+			opt = ""
+			optional = false
+		}
+		writeDoc(&b, "  ", desc, nil)
+		fmt.Fprintf(&b, "  %s%s: %s;\n", name, opt, m.objectType(att, "  "))
+	}
+	path, query := splitParams(a.AllParams(), route)
+	group("path", "Path parameters.", path)
+	group("query", "Query string parameters.", query)
+	group("headers", "Request headers.", a.Headers)
+	if a.Payload != nil {
+		writeDoc(&b, "  ", "Request body.", nil)
+		fmt.Fprintf(&b, "  payload: %s;\n", m.userType(a.Payload, a.Payload.Description))
+		optional = false
+	}
+	if b.Len() == 0 {
+		return act
+	}
+	act.Request = prefix + "Request"
+	act.RequestOptional = optional
+	act.Query = query != nil && len(query.Type.ToObject()) > 0
+	act.Headers = a.Headers != nil && len(a.Headers.Type.ToObject()) > 0
+	act.Payload = a.Payload != nil
+	if _, ok := m.models[act.Request]; !ok {
+		m.models[act.Request] = &Model{
+			Name:        act.Request,
+			Description: fmt.Sprintf("%s is the request of the %q action of the %q resource.", act.Request, a.Name, a.Parent.Name),
+			Interface:   true,
+			Type:        "{\n" + b.String() + "}",
+		}
+	}
+	return act
+}
+
+// resultType returns the type of the bodies of the successful responses of the given action.
+func (m *Module) resultType(a *design.ActionDefinition) string {
+	var types []string
+	var empty bool
+	for _, r := range sortedResponses(a) {
+		if r.Status < 200 || r.Status >= 300 {
+			continue
+		}
+		t := m.responseType(r)
+		if t == "" {
+			empty = true
+			continue
+		}
+		types = appendUnique(types, t)
+	}
+	if len(types) == 0 {
+		return "void"
+	}
+	if empty {
+		types = append(types, "undefined")
+	}
+	return strings.Join(types, " | ")
+}
+
+// errorType returns the type of the bodies of the error responses of the given action: the
+// typed errors and the media types of the error responses.
+func (m *Module) errorType(a *design.ActionDefinition) string {
+	var types []string
+	for _, e := range a.AllErrors() {
+		types = appendUnique(types, m.userType(e.Type, e.Description()))
+	}
+	for _, r := range sortedResponses(a) {
+		if r.Status < 400 {
+			continue
+		}
+		if t := m.responseType(r); t != "" {
+			types = appendUnique(types, t)
+		}
+	}
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, " | ")
+}
+
+// responseType returns the type of the body of the given response, the union of the views of
+// the response media type. It returns an empty string if the response has no body.
+func (m *Module) responseType(r *design.ResponseDefinition) string {
+	var mt *design.MediaTypeDefinition
+	switch actual := r.Type.(type) {
+	case *design.MediaTypeDefinition:
+		mt = actual
+	case *design.UserTypeDefinition:
+		return m.userType(actual, actual.Description)
+	}
+	if mt == nil && r.MediaType != "" {
+		mt = m.api.MediaTypes[design.CanonicalIdentifier(r.MediaType)]
+		if mt == nil {
+			return "string"
+		}
+	}
+	if mt == nil {
+		return ""
+	}
+	views := make([]string, 0, len(mt.Views))
+	for n := range mt.Views {
+		views = append(views, n)
+	}
+	sort.Sort(viewsDefaultFirst(views))
+	types := make([]string, len(views))
+	for i, v := range views {
+		types[i] = m.mediaType(mt, v)
+	}
+	return strings.Join(types, " | ")
+}
+
+// schemeFromDefinition returns the scheme and signer function of the given security scheme.
+func schemeFromDefinition(s *design.SecuritySchemeDefinition) *SecurityScheme {
+	scheme := &SecurityScheme{
+		Name:        s.SchemeName,
+		Description: s.Description,
+		Signer:      codegen.Goify(s.SchemeName, false) + "Signer",
+		Params:      "token: Credential",
+		Body:        "bearerAuth(token)",
+	}
+	switch s.Kind {
+	case design.BasicAuthSecurityKind:
+		scheme.Params = "username: string, password: string"
+		scheme.Body = "basicAuth(username, password)"
+	case design.APIKeySecurityKind:
+		scheme.Params = "key: Credential"
+		scheme.Body = fmt.Sprintf("apiKeyAuth(%q, %q, key)", s.In, s.Name)
+	case design.JWTSecurityKind:
+		if s.In != "header" || s.Name != "Authorization" {
+			scheme.Body = fmt.Sprintf("apiKeyAuth(%q, %q, token)", s.In, s.Name)
+		}
+	}
+	return scheme
+}
+
+// splitParams returns the path and query string parameters of the given route.
+func splitParams(params *design.AttributeDefinition, route *design.RouteDefinition) (path, query *design.AttributeDefinition) {
+	if params == nil {
+		return nil, nil
+	}
+	wildcards := make(map[string]bool)
+	for _, w := range design.ExtractWildcards(route.FullPath()) {
+		wildcards[w] = true
+	}
+	path = &design.AttributeDefinition{Type: make(design.Object), Validation: new(dslengine.ValidationDefinition)}
+	query = &design.AttributeDefinition{Type: make(design.Object), Validation: new(dslengine.ValidationDefinition)}
+	for n, att := range params.Type.ToObject() {
+		if wildcards[n] {
+			path.Type.(design.Object)[n] = att
+			path.Validation.Required = append(path.Validation.Required, n)
+			continue
+		}
+		query.Type.(design.Object)[n] = att
+		if params.IsRequired(n) {
+			query.Validation.Required = append(query.Validation.Required, n)
+		}
+	}
+	return path, query
+}
+
+// pathTemplate returns the TypeScript template literal that builds the given path from the path
+// parameters of the request.
+func pathTemplate(path string) string {
+	var b bytes.Buffer
+	b.WriteString("`")
+	start := 0
+	for _, loc := range design.WildcardRegex.FindAllStringSubmatchIndex(path, -1) {
+		b.WriteString(escapeTemplate(path[start:loc[0]]))
+		b.WriteString("/")
+		encode := "encodeURIComponent"
+		if path[loc[0]+1] == '*' {
+			encode = "encodeURI"
+		}
+		fmt.Fprintf(&b, "${%s(String(req.path%s))}", encode, propertyAccess(path[loc[2]:loc[3]]))
+		start = loc[1]
+	}
+	b.WriteString(escapeTemplate(path[start:]))
+	b.WriteString("`")
+	return b.String()
+}
+
+// modelName returns the name of the model of the user type with the given name.
+func modelName(typeName string) string {
+	name := codegen.Goify(typeName, true)
+	if reserved[name] {
+		name += "Model"
+	}
+	return name
+}
+
+// jsonName returns the name of the JSON field of the given attribute, the name set with the
+// "struct:tag:json" metadata if any. It returns false if the attribute is not rendered.
+func jsonName(name string, att *design.AttributeDefinition) (string, bool) {
+	tag, ok := att.Metadata["struct:tag:json"]
+	if !ok || len(tag) == 0 {
+		return name, true
+	}
+	n := strings.Split(tag[0], ",")[0]
+	if n == "-" {
+		return "", false
+	}
+	if n == "" {
+		return name, true
+	}
+	return n, true
+}
+
+// enumType returns the union of the literal types of the values of the Enum validation of the
+// given attribute, an empty string if there is no such validation.
+func enumType(att *design.AttributeDefinition) string {
+	if att.Validation == nil || len(att.Validation.Values) == 0 {
+		return ""
+	}
+	values := make([]string, len(att.Validation.Values))
+	for i, v := range att.Validation.Values {
+		js, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		values[i] = string(js)
+	}
+	return strings.Join(values, " | ")
+}
+
+// propertyName returns the given property name quoted if it is not a valid identifier.
+func propertyName(name string) string {
+	if identifierRegex.MatchString(name) {
+		return name
+	}
+	js, _ := json.Marshal(name)
+	return string(js)
+}
+
+// propertyAccess returns the expression that accesses the property with the given name.
+func propertyAccess(name string) string {
+	if identifierRegex.MatchString(name) {
+		return "." + name
+	}
+	return "[" + propertyName(name) + "]"
+}
+
+// escapeTemplate escapes the characters of s that have a special meaning in template literals.
+func escapeTemplate(s string) string {
+	s = strings.Replace(s, "\\", "\\\\", -1)
+	s = strings.Replace(s, "`", "\\`", -1)
+	return strings.Replace(s, "${", "\\${", -1)
+}
+
+// writeDoc writes the JSDoc comment with the given description and deprecation if any.
+func writeDoc(b *bytes.Buffer, indent, desc string, dep *design.DeprecationDefinition) {
+	if dep != nil {
+		writeDocLines(b, indent, desc, deprecated(dep))
+		return
+	}
+	writeDocLines(b, indent, desc)
+}
+
+// writeDocLines writes the JSDoc comment made of the given paragraphs, it writes nothing if they
+// are all empty.
+func writeDocLines(b *bytes.Buffer, indent string, paragraphs ...string) {
+	var lines []string
+	for _, p := range paragraphs {
+		lines = append(lines, docLines(p)...)
+	}
+	switch len(lines) {
+	case 0:
+	case 1:
+		fmt.Fprintf(b, "%s/** %s */\n", indent, lines[0])
+	default:
+		fmt.Fprintf(b, "%s/**\n", indent)
+		for _, l := range lines {
+			fmt.Fprintf(b, "%s%s\n", indent, strings.TrimRight(" * "+l, " "))
+		}
+		fmt.Fprintf(b, "%s */\n", indent)
+	}
+}
+
+// deprecated returns the JSDoc tag of the given deprecation.
+func deprecated(dep *design.DeprecationDefinition) string {
+	tag := "@deprecated"
+	if !dep.Sunset.IsZero() {
+		tag += " May be removed after " + dep.Sunset.Format("2006-01-02") + "."
+	}
+	return tag
+}
+
+// docLines returns the lines of the given description with the comment terminators escaped.
+func docLines(desc string) []string {
+	desc = strings.TrimSpace(desc)
+	if desc == "" {
+		return nil
+	}
+	return strings.Split(strings.Replace(desc, "*/", "*\\/", -1), "\n")
+}
+
+// sortedResponses returns the responses of the given action sorted by status.
+func sortedResponses(a *design.ActionDefinition) []*design.ResponseDefinition {
+	responses := make([]*design.ResponseDefinition, 0, len(a.Responses))
+	for _, r := range a.Responses {
+		responses = append(responses, r)
+	}
+	sort.Sort(responsesByStatus(responses))
+	return responses
+}
+
+// sortedNames returns the names of the attributes of obj sorted alphabetically.
+func sortedNames(obj design.Object) []string {
+	names := make([]string, 0, len(obj))
+	for n := range obj {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// appendUnique appends s to list unless list already contains it.
+func appendUnique(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// schemesByName makes it possible to sort security schemes by name.
+type schemesByName []*SecurityScheme
+
+func (s schemesByName) Len() int           { return len(s) }
+func (s schemesByName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s schemesByName) Less(i, j int) bool { return s[i].Name < s[j].Name }
+
+// modelsByName makes it possible to sort models by name.
+type modelsByName []*Model
+
+func (m modelsByName) Len() int           { return len(m) }
+func (m modelsByName) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+func (m modelsByName) Less(i, j int) bool { return m[i].Name < m[j].Name }
+
+// viewsDefaultFirst makes it possible to sort view names alphabetically with the default view
+// first.
+type viewsDefaultFirst []string
+
+func (v viewsDefaultFirst) Len() int      { return len(v) }
+func (v viewsDefaultFirst) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v viewsDefaultFirst) Less(i, j int) bool {
+	if v[i] == "default" || v[j] == "default" {
+		return v[i] == "default" && v[j] != "default"
+	}
+	return v[i] < v[j]
+}
+
+// responsesByStatus makes it possible to sort responses by status and name.
+type responsesByStatus []*design.ResponseDefinition
+
+func (r responsesByStatus) Len() int      { return len(r) }
+func (r responsesByStatus) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r responsesByStatus) Less(i, j int) bool {
+	if r[i].Status == r[j].Status {
+		return r[i].Name < r[j].Name
+	}
+	return r[i].Status < r[j].Status
+}
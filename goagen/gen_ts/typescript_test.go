@@ -0,0 +1,286 @@
+package gents_test
+
+import (
+	. "github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+	"github.com/goadesign/goa/dslengine"
+	"github.com/goadesign/goa/goagen/gen_ts"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var module *gents.Module
+	var newErr error
+
+	BeforeEach(func() {
+		dslengine.Reset()
+	})
+
+	JustBeforeEach(func() {
+		Ω(dslengine.Run()).ShouldNot(HaveOccurred())
+		module, newErr = gents.New(Design, "https://cellar.goa.design", 20000)
+	})
+
+	Context("with a design", func() {
+		BeforeEach(func() {
+			API("cellar", func() {
+				JWTSecurity("jwt", func() {
+					Header("Authorization")
+				})
+				APIKeySecurity("key", func() {
+					Query("api_key")
+				})
+			})
+			Country := Type("country", func() {
+				Attribute("code", String)
+				Required("code")
+			})
+			BottleMedia := MediaType("application/vnd.bottle+json", func() {
+				Description("A wine bottle")
+				Attributes(func() {
+					Attribute("id", Integer, "ID")
+					Attribute("name", String)
+					Attribute("color", String, func() {
+						Enum("red", "white")
+					})
+					Attribute("country", Country)
+					Attribute("ratings", HashOf(String, Integer))
+					Attribute("created-at", DateTime)
+					Required("id", "name")
+				})
+				View("default", func() {
+					Attribute("id")
+					Attribute("name")
+					Attribute("color")
+					Attribute("country")
+					Attribute("ratings")
+					Attribute("created-at")
+				})
+				View("tiny", func() {
+					Attribute("id")
+				})
+			})
+			Resource("bottle", func() {
+				BasePath("/bottles")
+				DefaultMedia(BottleMedia)
+				Action("show", func() {
+					Description("Show a bottle")
+					Routing(GET("/:id"))
+					Params(func() {
+						Param("id", Integer)
+						Param("fields", ArrayOf(String))
+					})
+					Headers(func() {
+						Header("X-Request-Id", String)
+					})
+					Security("jwt")
+					Error("not_found", 404)
+					Response(OK)
+					Response(NotFound, ErrorMedia)
+				})
+				Action("list", func() {
+					Routing(GET(""))
+					Response(OK, CollectionOf(BottleMedia))
+				})
+				Action("create", func() {
+					Routing(POST(""))
+					Payload(func() {
+						Member("name", String)
+						Required("name")
+					})
+					Security("key")
+					Response(Created)
+				})
+			})
+		})
+
+		It("declares the user types and the media type views", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(module.Model("Country")).Should(Equal(&gents.Model{
+				Name:      "Country",
+				Interface: true,
+				Type:      "{\n  code: string;\n}",
+			}))
+			bottle := module.Model("Bottle")
+			Ω(bottle).ShouldNot(BeNil())
+			Ω(bottle.Description).Should(Equal("A wine bottle\n\nView \"default\" of media type \"application/vnd.bottle+json\"."))
+			Ω(bottle.Type).Should(Equal(bottleType))
+			Ω(module.Model("BottleTiny").Type).Should(Equal("{\n  /** ID */\n  id: number;\n}"))
+			collection := module.Model("BottleCollection")
+			Ω(collection).ShouldNot(BeNil())
+			Ω(collection.Interface).Should(BeFalse())
+			Ω(collection.Type).Should(Equal("Bottle[]"))
+			Ω(module.Model("BottleTinyCollection").Type).Should(Equal("BottleTiny[]"))
+		})
+
+		It("renames the models that shadow global types", func() {
+			Ω(module.Model("Error")).Should(BeNil())
+			Ω(module.Model("ErrorModel")).ShouldNot(BeNil())
+			Ω(module.Model("NotFoundError")).ShouldNot(BeNil())
+		})
+
+		It("describes the actions", func() {
+			Ω(module.Actions).Should(HaveLen(3))
+			show := module.Action("showBottle")
+			Ω(show).Should(Equal(&gents.Action{
+				Name:        "showBottle",
+				Description: "Show a bottle",
+				Method:      "GET",
+				Path:        "`/bottles/${encodeURIComponent(String(req.path.id))}`",
+				Request:     "ShowBottleRequest",
+				Query:       true,
+				Headers:     true,
+				Result:      "Bottle | BottleTiny",
+				Error:       "ShowBottleError",
+				ErrorBody:   "NotFoundError | ErrorModel",
+				Scheme:      "jwt",
+			}))
+			Ω(module.Model("ShowBottleRequest").Type).Should(Equal(showRequestType))
+
+			list := module.Action("listBottle")
+			Ω(list.Request).Should(BeEmpty())
+			Ω(list.Result).Should(Equal("BottleCollection | BottleTinyCollection"))
+			Ω(list.ErrorBody).Should(Equal("unknown"))
+
+			create := module.Action("createBottle")
+			Ω(create.Request).Should(Equal("CreateBottleRequest"))
+			Ω(create.RequestOptional).Should(BeFalse())
+			Ω(create.Payload).Should(BeTrue())
+			Ω(create.Result).Should(Equal("void"))
+			Ω(module.Model("CreateBottlePayload").Type).Should(Equal("{\n  name: string;\n}"))
+		})
+
+		It("describes the security schemes", func() {
+			Ω(module.Schemes).Should(Equal([]*gents.SecurityScheme{
+				{Name: "jwt", Signer: "jwtSigner", Params: "token: Credential", Body: "bearerAuth(token)"},
+				{Name: "key", Signer: "keySigner", Params: "key: Credential", Body: `apiKeyAuth("query", "api_key", key)`},
+			}))
+		})
+
+		It("renders the models", func() {
+			src := string(module.ModelsSource())
+			Ω(src).Should(ContainSubstring("// API \"cellar\": TypeScript models\n"))
+			Ω(src).Should(ContainSubstring("export interface Country {\n  code: string;\n}\n"))
+			Ω(src).Should(ContainSubstring("export type BottleCollection = Bottle[];\n"))
+		})
+
+		It("renders the client", func() {
+			src, err := module.ClientSource()
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(string(src)).Should(ContainSubstring(`export type SecurityScheme = "jwt" | "key";`))
+			Ω(string(src)).Should(ContainSubstring("export type ShowBottleError = APIError<models.NotFoundError | models.ErrorModel>;\n"))
+			Ω(string(src)).Should(ContainSubstring(showMethod))
+			Ω(string(src)).Should(ContainSubstring(`"https://cellar.goa.design"`))
+			Ω(string(src)).Should(ContainSubstring("async listBottle(options?: RequestOptions): Promise<models.BottleCollection | models.BottleTinyCollection> {"))
+		})
+	})
+
+	Context("with a union, a multipart payload and a streaming action", func() {
+		BeforeEach(func() {
+			API("pay", func() {})
+			Card := Type("CardPayment", func() {
+				Attribute("number", String)
+			})
+			Bank := Type("BankPayment", func() {
+				Attribute("iban", String)
+			})
+			Type("Payment", func() {
+				Attribute("method", OneOf("type",
+					Variant("card", Card),
+					Variant("bank", Bank),
+				))
+			})
+			Tick := MediaType("application/vnd.tick+json", func() {
+				Attributes(func() {
+					Attribute("seq", Integer)
+				})
+				View("default", func() {
+					Attribute("seq")
+				})
+			})
+			Resource("document", func() {
+				Action("upload", func() {
+					Routing(POST("/documents/*path"))
+					Params(func() {
+						Param("path", String)
+					})
+					Payload(func() {
+						Member("file", File)
+						Member("x-title", String)
+					})
+					MultipartForm()
+					Response(NoContent)
+				})
+				Action("watch", func() {
+					Routing(GET("/documents/watch"))
+					Stream(Tick)
+				})
+			})
+		})
+
+		It("describes the union with the intersections of its variants and discriminator", func() {
+			Ω(newErr).ShouldNot(HaveOccurred())
+			Ω(module.Model("Payment").Type).Should(Equal(
+				`{
+  method?: (CardPayment & { type: "card" }) | (BankPayment & { type: "bank" });
+}`))
+			Ω(module.Model("CardPayment")).ShouldNot(BeNil())
+		})
+
+		It("sends the multipart payload and skips the streaming action", func() {
+			Ω(module.Actions).Should(HaveLen(1))
+			upload := module.Actions[0]
+			Ω(upload.Multipart).Should(BeTrue())
+			Ω(upload.Path).Should(Equal("`/documents/${encodeURI(String(req.path.path))}`"))
+			Ω(module.Model("UploadDocumentPayload").Type).Should(Equal("{\n  file?: Blob;\n  \"x-title\"?: string;\n}"))
+			Ω(module.Skipped).Should(Equal([]string{"document#watch"}))
+		})
+	})
+})
+
+const (
+	bottleType = `{
+  color?: "red" | "white";
+  country?: Country;
+  "created-at"?: string;
+  /** ID */
+  id: number;
+  name: string;
+  ratings?: { [key: string]: number };
+}`
+
+	showRequestType = `{
+  /** Path parameters. */
+  path: {
+    /** ID */
+    id: number;
+  };
+  /** Query string parameters. */
+  query?: {
+    fields?: string[];
+  };
+  /** Request headers. */
+  headers?: {
+    "X-Request-Id"?: string;
+  };
+}`
+
+	showMethod = `  /**
+   * Show a bottle
+   * @throws {ShowBottleError}
+   */
+  async showBottle(req: models.ShowBottleRequest, options?: RequestOptions): Promise<models.Bottle | models.BottleTiny> {
+    return (await this.request(
+      {
+        method: "GET",
+        path: ` + "`/bottles/${encodeURIComponent(String(req.path.id))}`" + `,
+        query: req.query,
+        headers: req.headers,
+        scheme: "jwt",
+      },
+      options,
+    )) as models.Bottle | models.BottleTiny;
+  }
+`
+)
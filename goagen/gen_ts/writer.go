@@ -0,0 +1,358 @@
+package gents
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/goadesign/goa/goagen/codegen"
+)
+
+// clientTmpl is the template used to render client.ts.
+var clientTmpl = template.Must(template.New("client").Funcs(template.FuncMap{
+	"doc":    doc,
+	"models": qualify,
+	"result": result,
+}).Parse(clientT))
+
+// ModelsSource returns the content of models.ts.
+func (m *Module) ModelsSource() []byte {
+	var b bytes.Buffer
+	writeHeader(&b, fmt.Sprintf("API %q: TypeScript models", m.Name))
+	if len(m.Models) == 0 {
+		// Make the file a module so that client.ts may import it.
+		b.WriteString("export {};\n")
+	}
+	for i, model := range m.Models {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		writeDoc(&b, "", model.Description, nil)
+		if model.Interface {
+			fmt.Fprintf(&b, "export interface %s %s\n", model.Name, model.Type)
+		} else {
+			fmt.Fprintf(&b, "export type %s = %s;\n", model.Name, model.Type)
+		}
+	}
+	return b.Bytes()
+}
+
+// ClientSource returns the content of client.ts.
+func (m *Module) ClientSource() ([]byte, error) {
+	var b bytes.Buffer
+	writeHeader(&b, fmt.Sprintf("API %q: TypeScript client", m.Name))
+	if err := clientTmpl.Execute(&b, m); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// writeHeader writes the comment that starts the generated files.
+func writeHeader(b *bytes.Buffer, title string) {
+	b.WriteString("//************************************************************************//\n")
+	fmt.Fprintf(b, "// %s\n", title)
+	b.WriteString("//\n")
+	fmt.Fprintf(b, "// Generated with goagen v%s, command line:\n", codegen.Version)
+	b.WriteString(codegen.Comment(codegen.CommandLine()))
+	b.WriteString("\n//\n")
+	b.WriteString("// The content of this file is auto-generated, DO NOT MODIFY\n")
+	b.WriteString("//************************************************************************//\n\n")
+}
+
+// doc returns the JSDoc comment made of the given paragraphs indented with indent, an empty
+// string if they are all empty.
+func doc(indent string, paragraphs ...string) string {
+	var b bytes.Buffer
+	writeDocLines(&b, indent, paragraphs...)
+	return b.String()
+}
+
+// qualify prefixes the names of the models used in the given type with the name of the models
+// module import.
+func qualify(typ string) string {
+	elems := strings.Split(typ, " | ")
+	for i, e := range elems {
+		switch e {
+		case "void", "undefined", "unknown", "string":
+		default:
+			elems[i] = "models." + e
+		}
+	}
+	return strings.Join(elems, " | ")
+}
+
+// result returns the type of the values returned by the client method of the given action.
+func result(a *Action) string {
+	if a.Sparse && a.Result != "void" {
+		return "Sparse<" + qualify(a.Result) + ">"
+	}
+	return qualify(a.Result)
+}
+
+const clientT = `import * as models from "./models";
+
+/** Transport sends the HTTP requests, it defaults to the global fetch function. */
+export type Transport = (url: string, init: RequestInit) => Promise<Response>;
+
+/** SignedRequest is the request that signers add credentials to. */
+export interface SignedRequest {
+  method: string;
+  url: URL;
+  headers: Headers;
+}
+
+/** Signer adds the credentials of a security scheme to a request. */
+export type Signer = (req: SignedRequest) => void | Promise<void>;
+
+/** Credential is a secret or a function that returns the secret to use for each request. */
+export type Credential = string | (() => string | Promise<string>);
+
+/** SecurityScheme is the name of a security scheme of the API. */
+export type SecurityScheme = {{ if .Schemes }}{{ range $i, $s := .Schemes }}{{ if $i }} | {{ end }}"{{ $s.Name }}"{{ end }}{{ else }}never{{ end }};
+
+/** ClientOptions configures a client. */
+export interface ClientOptions {
+  /** baseURL is prepended to the request paths, it defaults to "{{ .BaseURL }}". */
+  baseURL?: string;
+  /** transport sends the HTTP requests, it defaults to the global fetch function. */
+  transport?: Transport;
+  /** headers are added to all the requests. */
+  headers?: { [name: string]: string };
+  /** signers add the credentials to the requests of the actions secured with the corresponding scheme. */
+  signers?: { [S in SecurityScheme]?: Signer };
+  /** timeout is the number of milliseconds after which requests are aborted, 0 means no timeout. */
+  timeout?: number;
+}
+
+/** RequestOptions configures a single request. */
+export interface RequestOptions {
+  /** signal aborts the request. */
+  signal?: AbortSignal;
+  /** headers are added to the request. */
+  headers?: { [name: string]: string };
+}
+
+/**
+ * Sparse is the type of the responses of the actions that render the fields listed in the
+ * "fields" query string parameter only.
+ */
+export type Sparse<T> = T extends Array<infer E>
+  ? Array<Sparse<E>>
+  : T extends object
+  ? { [K in keyof T]?: Sparse<T[K]> }
+  : T;
+
+/** APIError is thrown when the API responds with a status code that is not 2xx. */
+export class APIError<T = unknown> extends Error {
+  constructor(
+    /** status is the response status code. */
+    readonly status: number,
+    /** body is the decoded response body. */
+    readonly body: T,
+    /** response is the HTTP response. */
+    readonly response: Response,
+  ) {
+    super(` + "`${status} ${response.statusText}`" + `);
+    Object.setPrototypeOf(this, new.target.prototype);
+    this.name = "APIError";
+  }
+}
+{{ range .Actions }}
+/** {{ .Error }} is the error thrown by {{ .Name }}. */
+export type {{ .Error }} = APIError<{{ models .ErrorBody }}>;
+{{ end }}
+/** bearerAuth returns a signer that sets the Authorization header with the given token. */
+export function bearerAuth(token: Credential): Signer {
+  return async (req) => {
+    req.headers.set("Authorization", "Bearer " + (await credential(token)));
+  };
+}
+
+/** basicAuth returns a signer that sets the Authorization header with the given credentials. */
+export function basicAuth(username: string, password: string): Signer {
+  return (req) => {
+    req.headers.set("Authorization", "Basic " + base64(username + ":" + password));
+  };
+}
+
+/**
+ * apiKeyAuth returns a signer that sets the given header, query string parameter or cookie with a
+ * key. Note that browsers do not let scripts set the Cookie header, they send the cookies of the
+ * document instead.
+ */
+export function apiKeyAuth(location: string, name: string, key: Credential): Signer {
+  return async (req) => {
+    const value = await credential(key);
+    if (location === "query") {
+      req.url.searchParams.set(name, value);
+    } else if (location === "cookie") {
+      req.headers.append("Cookie", name + "=" + encodeURIComponent(value));
+    } else {
+      req.headers.set(name, value);
+    }
+  };
+}
+{{ range .Schemes }}
+{{ doc "" (printf "%s returns the signer of the %q security scheme." .Signer .Name) .Description }}export function {{ .Signer }}({{ .Params }}): Signer {
+  return {{ .Body }};
+}
+{{ end }}
+/** Client gives access to the {{ .Name }} API. */
+export class Client {
+  private readonly options: ClientOptions;
+
+  constructor(options: ClientOptions = {}) {
+    this.options = options;
+  }
+{{ range .Actions }}
+{{ doc "  " .Description .Deprecation (printf "@throws {%s}" .Error) }}  async {{ .Name }}({{ if .Request }}req: models.{{ .Request }}{{ if .RequestOptional }} = {}{{ end }}, {{ end }}options?: RequestOptions): Promise<{{ result . }}> {
+    {{ if ne .Result "void" }}return (await {{ else }}await {{ end }}this.request(
+      {
+        method: "{{ .Method }}",
+        path: {{ .Path }},{{ if .Query }}
+        query: req.query,{{ end }}{{ if .Headers }}
+        headers: req.headers,{{ end }}{{ if .Payload }}
+        body: req.payload,{{ end }}{{ if .Multipart }}
+        multipart: true,{{ end }}{{ if .Scheme }}
+        scheme: "{{ .Scheme }}",{{ end }}
+      },
+      options,
+    ){{ if ne .Result "void" }}) as {{ result . }}{{ end }};
+  }
+{{ end }}
+  private async request(call: Call, options: RequestOptions = {}): Promise<unknown> {
+    const base = this.options.baseURL !== undefined ? this.options.baseURL : "{{ .BaseURL }}";
+    const loc = (globalThis as unknown as { location?: { href: string } }).location;
+    const url = new URL(base + call.path, loc ? loc.href : undefined);
+    setParams(url.searchParams, call.query);
+    const headers = new Headers(this.options.headers);
+    setParams(headers, call.headers);
+    setParams(headers, options.headers);
+    let body: BodyInit | undefined;
+    if (call.body !== undefined) {
+      if (call.multipart) {
+        body = formData(call.body);
+      } else {
+        body = JSON.stringify(call.body);
+        headers.set("Content-Type", "application/json");
+      }
+    }
+    if (call.scheme !== undefined) {
+      const signers: { [S in SecurityScheme]?: Signer } = this.options.signers || {};
+      const signer = signers[call.scheme];
+      if (signer) {
+        await signer({ method: call.method, url, headers });
+      }
+    }
+
+    const controller = new AbortController();
+    const abort = () => controller.abort();
+    if (options.signal) {
+      if (options.signal.aborted) {
+        abort();
+      }
+      options.signal.addEventListener("abort", abort);
+    }
+    const timeout = this.options.timeout !== undefined ? this.options.timeout : {{ .Timeout }};
+    const timer = timeout > 0 ? setTimeout(abort, timeout) : undefined;
+    try {
+      const transport: Transport = this.options.transport || ((input, init) => fetch(input, init));
+      const res = await transport(url.toString(), {
+        method: call.method,
+        headers,
+        body,
+        signal: controller.signal,
+      });
+      const data = await decode(res);
+      if (!res.ok) {
+        throw new APIError(res.status, data, res);
+      }
+      return data;
+    } finally {
+      if (timer !== undefined) {
+        clearTimeout(timer);
+      }
+      if (options.signal) {
+        options.signal.removeEventListener("abort", abort);
+      }
+    }
+  }
+}
+
+/** Call describes the request sent by a client method. */
+interface Call {
+  method: string;
+  path: string;
+  query?: object;
+  headers?: object;
+  body?: unknown;
+  multipart?: boolean;
+  scheme?: SecurityScheme;
+}
+
+/** setParams sets the given query string parameters or headers, arrays are comma separated. */
+function setParams(target: { set(name: string, value: string): void }, params?: object): void {
+  if (!params) {
+    return;
+  }
+  const values = params as { [name: string]: unknown };
+  Object.keys(values).forEach((name) => {
+    const value = values[name];
+    if (value === undefined || value === null) {
+      return;
+    }
+    target.set(name, Array.isArray(value) ? value.map(String).join(",") : String(value));
+  });
+}
+
+/** formData encodes the given payload as a multipart form. */
+function formData(payload: unknown): FormData {
+  const form = new FormData();
+  const values = payload as { [name: string]: unknown };
+  Object.keys(values).forEach((name) => {
+    const value = values[name];
+    if (value === undefined || value === null) {
+      return;
+    }
+    const items: unknown[] = Array.isArray(value) ? value : [value];
+    items.forEach((v) => {
+      if (v instanceof Blob) {
+        form.append(name, v);
+      } else if (typeof v === "object") {
+        form.append(name, JSON.stringify(v));
+      } else {
+        form.append(name, String(v));
+      }
+    });
+  });
+  return form;
+}
+
+/** decode returns the JSON decoded response body, the raw body if it is not JSON. */
+async function decode(res: Response): Promise<unknown> {
+  if (res.status === 204 || res.status === 304) {
+    return undefined;
+  }
+  const text = await res.text();
+  if (text === "") {
+    return undefined;
+  }
+  const type = res.headers.get("Content-Type") || "";
+  return /[\/+]json\b/i.test(type) ? JSON.parse(text) : text;
+}
+
+/** credential returns the secret of the given credential. */
+async function credential(c: Credential): Promise<string> {
+  return typeof c === "function" ? c() : c;
+}
+
+/** base64 returns the base64 encoding of the UTF-8 encoding of s. */
+function base64(s: string): string {
+  let bin = "";
+  new TextEncoder().encode(s).forEach((b) => {
+    bin += String.fromCharCode(b);
+  });
+  return btoa(bin);
+}
+`
@@ -8,12 +8,20 @@ import (
 
 	"github.com/goadesign/goa/goagen/codegen"
 	"github.com/goadesign/goa/goagen/gen_app"
+	"github.com/goadesign/goa/goagen/gen_asyncapi"
 	"github.com/goadesign/goa/goagen/gen_client"
+	"github.com/goadesign/goa/goagen/gen_design"
+	"github.com/goadesign/goa/goagen/gen_diff"
 	"github.com/goadesign/goa/goagen/gen_gen"
+	"github.com/goadesign/goa/goagen/gen_import"
 	"github.com/goadesign/goa/goagen/gen_js"
+	"github.com/goadesign/goa/goagen/gen_lint"
 	"github.com/goadesign/goa/goagen/gen_main"
+	"github.com/goadesign/goa/goagen/gen_openapi"
+	"github.com/goadesign/goa/goagen/gen_proto"
 	"github.com/goadesign/goa/goagen/gen_schema"
 	"github.com/goadesign/goa/goagen/gen_swagger"
+	"github.com/goadesign/goa/goagen/gen_ts"
 	"github.com/goadesign/goa/goagen/utils"
 	"github.com/spf13/cobra"
 )
@@ -25,9 +33,17 @@ var Commands = []codegen.Command{
 	genmain.NewCommand(),
 	genclient.NewCommand(),
 	genswagger.NewCommand(),
+	genopenapi.NewCommand(),
 	genjs.NewCommand(),
+	gents.NewCommand(),
 	genschema.NewCommand(),
 	gengen.NewCommand(),
+	gendiff.NewCommand(),
+	genlint.NewCommand(),
+	genimport.NewCommand(),
+	gendesign.NewCommand(),
+	genasyncapi.NewCommand(),
+	genproto.NewCommand(),
 }
 
 func main() {
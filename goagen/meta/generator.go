@@ -118,6 +118,13 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 		codegen.SimpleImport("github.com/goadesign/goa/dslengine"),
 		codegen.NewImport("_", filepath.ToSlash(codegen.DesignPackagePath)),
 	)
+	resources := make([]string, len(codegen.Resources))
+	for i, name := range codegen.Resources {
+		resources[i] = fmt.Sprintf("%#v", name)
+	}
+	if len(resources) > 0 {
+		imports = append(imports, codegen.SimpleImport("github.com/goadesign/goa/design"))
+	}
 	file.WriteHeader("Code Generator", "main", imports)
 	tmpl, err := template.New("generator").Parse(mainTmpl)
 	if err != nil {
@@ -131,6 +138,7 @@ func (m *Generator) generateToolSourceCode(pkg *codegen.Package) {
 		"Genfunc":       m.Genfunc,
 		"DesignPackage": codegen.DesignPackagePath,
 		"PkgName":       pkgName,
+		"Resources":     strings.Join(resources, ", "),
 	}
 	err = tmpl.Execute(file, context)
 	if err != nil {
@@ -173,7 +181,10 @@ func main() {
 
 	// Now run the secondary DSLs
 	dslengine.FailOnError(dslengine.Run())
-
+{{ if .Resources }}
+	// Restrict the API to the resources given on the command line
+	dslengine.FailOnError(design.Design.Restrict({{ .Resources }}))
+{{ end }}
 	files, err := {{.Genfunc}}()
 	dslengine.FailOnError(err)
 
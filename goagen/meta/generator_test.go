@@ -226,6 +226,35 @@ var _ = Describe("Run", func() {
 			})
 		})
 
+		Context("with resources given on the command line", func() {
+			var resources []string
+
+			BeforeEach(func() {
+				designPackageSource = resourcesSource
+				resources = codegen.Resources
+				codegen.Resources = []string{"bottle"}
+			})
+
+			AfterEach(func() {
+				codegen.Resources = resources
+			})
+
+			It("generates the sub-API made of the resources", func() {
+				Ω(compileError).ShouldNot(HaveOccurred())
+				Ω(compiledFiles).Should(Equal([]string{"bottle"}))
+			})
+
+			Context("that the design does not define", func() {
+				BeforeEach(func() {
+					codegen.Resources = []string{"unknown"}
+				})
+
+				It("fails with a useful error message", func() {
+					Ω(compileError).Should(MatchError(ContainSubstring(`API "test" does not define resource "unknown"`)))
+				})
+			})
+		})
+
 		Context("with code that returns generated file paths", func() {
 			var filePaths = []string{"foo", "bar"}
 
@@ -263,6 +292,30 @@ func init() { panic("kaboom") }
 func Generate() ([]string, error) {
 	return nil, nil
 }
+`
+
+	resourcesSource = `package foo
+import (
+	"fmt"
+	"sort"
+
+	"github.com/goadesign/goa/design"
+	. "github.com/goadesign/goa/design/apidsl"
+)
+var _ = API("test", nil)
+var _ = Resource("bottle", nil)
+var _ = Resource("account", nil)
+func Generate() ([]string, error) {
+	var names []string
+	for n := range design.Design.Resources {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Println(n)
+	}
+	return nil, nil
+}
 `
 
 	validSourceTmpl = `package foo
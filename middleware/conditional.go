@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/goadesign/goa"
+
+	"golang.org/x/net/context"
+)
+
+// bufferedResponseWriter wraps an http.ResponseWriter and records the response status and body
+// instead of writing them.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the response status.
+func (brw *bufferedResponseWriter) WriteHeader(status int) {
+	if brw.status == 0 {
+		brw.status = status
+	}
+}
+
+// Write records the response body.
+func (brw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	if brw.status == 0 {
+		brw.status = http.StatusOK
+	}
+	return brw.body.Write(b)
+}
+
+// ConditionalRequest creates a middleware that evaluates the If-None-Match and If-Modified-Since
+// headers of GET and HEAD requests and responds with 304 Not Modified if the response was not
+// modified. The entity tag of the response is the value of the ETag header set by the handler, if
+// the handler does not set it then the middleware computes a tag by hashing the response body.
+// Requests made with other methods are handled as is, the handlers of these requests should use
+// goa.SetETag and goa.SetLastModified to evaluate the If-Match and If-Unmodified-Since headers.
+func ConditionalRequest() goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			if req.Method != "GET" && req.Method != "HEAD" {
+				return h(ctx, rw, req)
+			}
+
+			// Record the response written by the handler.
+			resp := goa.ContextResponse(ctx)
+			w := resp.SwitchWriter(nil)
+			brw := &bufferedResponseWriter{ResponseWriter: w}
+			resp.SwitchWriter(brw)
+			err := h(ctx, rw, req)
+			resp.SwitchWriter(w)
+
+			if err == nil && brw.status == http.StatusOK {
+				header := w.Header()
+				etag := header.Get("ETag")
+				if etag == "" {
+					sum := sha1.Sum(brw.body.Bytes())
+					etag = `"` + hex.EncodeToString(sum[:]) + `"`
+					header.Set("ETag", etag)
+				}
+				if notModified(req, etag, header.Get("Last-Modified")) {
+					header.Del("Content-Type")
+					header.Del("Content-Length")
+					resp.Status = http.StatusNotModified
+					resp.Length = 0
+					w.WriteHeader(http.StatusNotModified)
+					return nil
+				}
+			}
+
+			// Write the recorded response.
+			if brw.status != 0 {
+				w.WriteHeader(brw.status)
+			}
+			if brw.body.Len() > 0 {
+				if _, werr := w.Write(brw.body.Bytes()); werr != nil && err == nil {
+					err = werr
+				}
+			}
+			return err
+		}
+	}
+}
+
+// notModified evaluates the If-None-Match and If-Modified-Since request headers against the
+// response entity tag and last modification date. If-Modified-Since is ignored when the request
+// has an If-None-Match header.
+func notModified(req *http.Request, etag, lastModified string) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		return goa.MatchETag(inm, etag, true)
+	}
+	ims, err := http.ParseTime(req.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lm, err := http.ParseTime(lastModified)
+	if err != nil {
+		return false
+	}
+	return !lm.After(ims)
+}
@@ -0,0 +1,122 @@
+package middleware_test
+
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ConditionalRequest", func() {
+	var method string
+	var header http.Header
+	var etag, lastModified string
+	var rw *testResponseWriter
+	var resp *goa.ResponseData
+	var err error
+
+	BeforeEach(func() {
+		method = "GET"
+		header = make(http.Header)
+		etag = ""
+		lastModified = ""
+	})
+
+	JustBeforeEach(func() {
+		req, _ := http.NewRequest(method, "/bottles/1", nil)
+		req.Header = header
+		rw = newTestResponseWriter()
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		resp = goa.ContextResponse(ctx)
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			resp := goa.ContextResponse(ctx)
+			if etag != "" {
+				resp.Header().Set("ETag", etag)
+			}
+			if lastModified != "" {
+				resp.Header().Set("Last-Modified", lastModified)
+			}
+			resp.WriteHeader(200)
+			resp.Write([]byte(`{"id":1}`))
+			return nil
+		}
+		err = middleware.ConditionalRequest()(h)(ctx, rw, req)
+	})
+
+	It("writes the response and computes the entity tag", func() {
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(rw.Status).Should(Equal(200))
+		Ω(string(rw.Body)).Should(Equal(`{"id":1}`))
+		Ω(rw.Header().Get("ETag")).Should(Equal(`"87911d1aed509877b83a48ec536f768cdcd22df1"`))
+		Ω(resp.Length).Should(Equal(8))
+	})
+
+	Context("with a matching If-None-Match header", func() {
+		BeforeEach(func() {
+			header.Set("If-None-Match", `"87911d1aed509877b83a48ec536f768cdcd22df1"`)
+		})
+
+		It("responds with Not Modified", func() {
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(rw.Status).Should(Equal(304))
+			Ω(rw.Body).Should(BeEmpty())
+			Ω(resp.Status).Should(Equal(304))
+			Ω(resp.Length).Should(Equal(0))
+		})
+	})
+
+	Context("with an explicit entity tag", func() {
+		BeforeEach(func() {
+			etag = `W/"v1"`
+			header.Set("If-None-Match", `"v1"`)
+		})
+
+		It("uses it to evaluate If-None-Match", func() {
+			Ω(rw.Status).Should(Equal(304))
+			Ω(rw.Header().Get("ETag")).Should(Equal(`W/"v1"`))
+		})
+	})
+
+	Context("with an If-Modified-Since header", func() {
+		BeforeEach(func() {
+			lastModified = "Sun, 01 May 2016 12:00:00 GMT"
+		})
+
+		Context("and a resource that was not modified", func() {
+			BeforeEach(func() {
+				header.Set("If-Modified-Since", "Sun, 01 May 2016 12:00:00 GMT")
+			})
+
+			It("responds with Not Modified", func() {
+				Ω(rw.Status).Should(Equal(304))
+			})
+		})
+
+		Context("and a resource that was modified", func() {
+			BeforeEach(func() {
+				header.Set("If-Modified-Since", "Sun, 01 May 2016 11:00:00 GMT")
+			})
+
+			It("writes the response", func() {
+				Ω(rw.Status).Should(Equal(200))
+				Ω(string(rw.Body)).Should(Equal(`{"id":1}`))
+			})
+		})
+	})
+
+	Context("with a PUT request", func() {
+		BeforeEach(func() {
+			method = "PUT"
+			header.Set("If-None-Match", "*")
+		})
+
+		It("writes the response as is", func() {
+			Ω(rw.Status).Should(Equal(200))
+			Ω(rw.Header().Get("ETag")).Should(BeEmpty())
+		})
+	})
+})
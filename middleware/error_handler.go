@@ -9,8 +9,9 @@ import (
 
 // ErrorHandler turns a Go error into an HTTP response. It should be placed in the middleware chain
 // below the logger middleware so the logger properly logs the HTTP response. ErrorHandler
-// understands instances of goa.Error and returns the status and response body embodied in them,
-// it turns other Go error types into a 500 internal error response.
+// understands instances of goa.ServiceError such as goa.Error and the typed errors generated by
+// goagen and returns the status and response body embodied in them, it turns other Go error types
+// into a 500 internal error response.
 // If verbose is false the details of internal errors is not included in HTTP responses.
 func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 	return func(h goa.Handler) goa.Handler {
@@ -22,10 +23,10 @@ func ErrorHandler(service *goa.Service, verbose bool) goa.Middleware {
 
 			status := http.StatusInternalServerError
 			var respBody interface{}
-			if err, ok := e.(*goa.Error); ok {
-				status = err.Status
+			if err, ok := e.(goa.ServiceError); ok {
+				status = err.ResponseStatus()
 				respBody = err
-				goa.ContextResponse(ctx).ErrorCode = err.Code
+				goa.ContextResponse(ctx).ErrorCode = err.ErrorCode()
 				rw.Header().Set("Content-Type", goa.ErrorMediaIdentifier)
 			} else {
 				respBody = e.Error()
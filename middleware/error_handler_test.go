@@ -101,4 +101,34 @@ var _ = Describe("ErrorHandler", func() {
 			Ω(fmt.Sprintf("%v", decoded)).Should(Equal(fmt.Sprintf("%v", *gerr)))
 		})
 	})
+
+	Context("with a handler returning a typed error", func() {
+		BeforeEach(func() {
+			service = newService(nil)
+			h = func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+				return &notFoundError{Code: "not_found", Status: 404, Detail: "no bottle", ID: 42}
+			}
+		})
+
+		It("maps typed errors to HTTP responses", func() {
+			var decoded notFoundError
+			Ω(rw.Status).Should(Equal(404))
+			Ω(rw.ParentHeader["Content-Type"]).Should(Equal([]string{goa.ErrorMediaIdentifier}))
+			err := service.Decode(&decoded, bytes.NewBuffer(rw.Body), "application/json")
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(decoded).Should(Equal(notFoundError{Code: "not_found", Status: 404, Detail: "no bottle", ID: 42}))
+		})
+	})
 })
+
+// notFoundError is a typed error similar to the ones generated by goagen.
+type notFoundError struct {
+	Code   string `json:"code"`
+	Status int    `json:"status"`
+	Detail string `json:"detail"`
+	ID     int    `json:"id"`
+}
+
+func (e *notFoundError) Error() string       { return e.Detail }
+func (e *notFoundError) ResponseStatus() int { return e.Status }
+func (e *notFoundError) ErrorCode() string   { return e.Code }
@@ -0,0 +1,19 @@
+/*
+Package ratelimit provides a middleware that limits the number of requests made by each principal
+using the token bucket algorithm. Each bucket holds up to limit tokens and is refilled at a rate of
+limit tokens per period, each request consumes one token. Requests made when the bucket is empty
+are rejected with a ErrTooManyRequests error.
+
+The buckets are stored in a Store, the package provides an in-memory implementation in
+MemoryStore. Other implementations (e.g. backed by a shared database) make it possible to enforce
+rate limits across multiple service instances.
+
+The middleware sets the RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset response headers
+as well as the Retry-After header when the request is rejected.
+
+The code generated by goagen for actions that define a rate limit in the design uses the
+middleware returned by Default. Applications may override DefaultStore and DefaultKey before
+serving requests to change how requests are counted, for example to count requests per
+authenticated user rather than per client address.
+*/
+package ratelimit
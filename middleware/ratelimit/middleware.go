@@ -0,0 +1,80 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+)
+
+// KeyFunc returns the key that identifies the principal making the request, for example the
+// client address or the ID of the authenticated user. Requests that share the same key are
+// counted together.
+type KeyFunc func(ctx context.Context, req *http.Request) string
+
+var (
+	// DefaultStore is the store used by the middleware returned by Default.
+	DefaultStore Store = NewMemoryStore()
+
+	// DefaultKey is the function used by the middleware returned by Default to identify
+	// principals.
+	DefaultKey KeyFunc = RemoteAddr
+)
+
+// RemoteAddr is a KeyFunc that identifies principals by the IP address of the client.
+func RemoteAddr(ctx context.Context, req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// New returns a middleware that limits the number of requests made by each principal to limit
+// requests per period. scope identifies the requests counted together, store holds the buckets
+// and key identifies the principal making the request. Requests that exceed the limit are
+// rejected with a ErrTooManyRequests error.
+func New(scope string, limit int, per time.Duration, store Store, key KeyFunc) goa.Middleware {
+	return func(h goa.Handler) goa.Handler {
+		return func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			s, k := store, key
+			if s == nil {
+				s = DefaultStore
+			}
+			if k == nil {
+				k = DefaultKey
+			}
+			res, err := s.Take(scope+"|"+k(ctx, req), limit, per)
+			if err != nil {
+				goa.LogError(ctx, "rate limit", "err", err)
+				return h(ctx, rw, req)
+			}
+			header := rw.Header()
+			header.Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			header.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			header.Set("RateLimit-Reset", seconds(res.Reset))
+			if !res.Allowed {
+				header.Set("Retry-After", seconds(res.RetryAfter))
+				return goa.ErrTooManyRequests("rate limit of %d requests per %s exceeded", limit, per)
+			}
+			return h(ctx, rw, req)
+		}
+	}
+}
+
+// Default returns a middleware that limits the number of requests using DefaultStore and
+// DefaultKey. The default values are read when handling requests so that they may be overridden
+// after the middleware is created.
+func Default(scope string, limit int, per time.Duration) goa.Middleware {
+	return New(scope, limit, per, nil, nil)
+}
+
+// seconds returns the number of seconds in d rounded up.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
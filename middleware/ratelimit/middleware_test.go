@@ -0,0 +1,82 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	"github.com/goadesign/goa/middleware/ratelimit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("New", func() {
+	var store *ratelimit.MemoryStore
+	var key ratelimit.KeyFunc
+	var rw *httptest.ResponseRecorder
+	var req *http.Request
+	var called int
+
+	BeforeEach(func() {
+		store = ratelimit.NewMemoryStore()
+		key = nil
+		called = 0
+		var err error
+		req, err = http.NewRequest("GET", "/bottles", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.RemoteAddr = "10.0.0.1:4242"
+	})
+
+	serve := func() error {
+		rw = httptest.NewRecorder()
+		h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+			called++
+			return nil
+		}
+		return ratelimit.New("cellar", 1, time.Minute, store, key)(h)(context.Background(), rw, req)
+	}
+
+	It("sets the rate limit headers", func() {
+		Ω(serve()).ShouldNot(HaveOccurred())
+		Ω(called).Should(Equal(1))
+		Ω(rw.Header().Get("RateLimit-Limit")).Should(Equal("1"))
+		Ω(rw.Header().Get("RateLimit-Remaining")).Should(Equal("0"))
+		Ω(rw.Header().Get("RateLimit-Reset")).Should(Equal("60"))
+	})
+
+	It("rejects requests that exceed the limit", func() {
+		Ω(serve()).ShouldNot(HaveOccurred())
+		err := serve()
+		Ω(err).Should(HaveOccurred())
+		Ω(err.(*goa.Error).Status).Should(Equal(http.StatusTooManyRequests))
+		Ω(rw.Header().Get("Retry-After")).Should(Equal("60"))
+		Ω(called).Should(Equal(1))
+	})
+
+	It("counts requests per client address", func() {
+		Ω(serve()).ShouldNot(HaveOccurred())
+		req.RemoteAddr = "10.0.0.2:4242"
+		Ω(serve()).ShouldNot(HaveOccurred())
+		Ω(called).Should(Equal(2))
+	})
+
+	Context("with a key function", func() {
+		BeforeEach(func() {
+			key = func(ctx context.Context, req *http.Request) string {
+				return req.Header.Get("X-User")
+			}
+			req.Header.Set("X-User", "alice")
+		})
+
+		It("counts requests per principal", func() {
+			Ω(serve()).ShouldNot(HaveOccurred())
+			req.RemoteAddr = "10.0.0.2:4242"
+			Ω(serve()).Should(HaveOccurred())
+			req.Header.Set("X-User", "bob")
+			Ω(serve()).ShouldNot(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+type (
+	// Store stores the token buckets.
+	Store interface {
+		// Take consumes a token from the bucket identified by key. The bucket holds up to
+		// limit tokens and is refilled at a rate of limit tokens per period.
+		Take(key string, limit int, per time.Duration) (*Result, error)
+	}
+
+	// Result describes the state of a bucket after a token was taken from it.
+	Result struct {
+		// Allowed is true if a token was available, false if the request must be rejected.
+		Allowed bool
+		// Limit is the capacity of the bucket.
+		Limit int
+		// Remaining is the number of tokens left in the bucket.
+		Remaining int
+		// Reset is the time it takes for the bucket to be full again.
+		Reset time.Duration
+		// RetryAfter is the time to wait before a token is available, zero if Allowed is
+		// true.
+		RetryAfter time.Duration
+	}
+
+	// MemoryStore is a Store that keeps the buckets in memory. It is suitable for services
+	// that run a single instance. The buckets that are full again are dropped so that the
+	// memory used by the store is bounded by the number of keys active during a period.
+	MemoryStore struct {
+		// Now returns the current time, it defaults to time.Now and may be overridden in
+		// tests.
+		Now func() time.Time
+
+		lock    sync.Mutex
+		buckets map[string]*bucket
+		swept   time.Time
+	}
+
+	// bucket is a token bucket.
+	bucket struct {
+		tokens float64
+		last   time.Time
+		// full is the time at which the bucket is full again.
+		full time.Time
+	}
+)
+
+// sweepInterval is the minimum duration between two removals of the full buckets of a
+// MemoryStore.
+const sweepInterval = time.Minute
+
+// NewMemoryStore returns an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{Now: time.Now, buckets: make(map[string]*bucket)}
+}
+
+// Take consumes a token from the bucket identified by key creating a full bucket if needed.
+func (s *MemoryStore) Take(key string, limit int, per time.Duration) (*Result, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.Now()
+	if now.Sub(s.swept) >= sweepInterval {
+		s.sweep(now)
+	}
+	capacity := float64(limit)
+	b, ok := s.buckets[key]
+	if ok && !now.Before(b.full) {
+		// A bucket that was refilled is the same as a new one.
+		ok = false
+	}
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		s.buckets[key] = b
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+float64(elapsed)*capacity/float64(per))
+		b.last = now
+	}
+	res := &Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = refill(1-b.tokens, capacity, per)
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = refill(capacity-b.tokens, capacity, per)
+	b.full = now.Add(res.Reset)
+	return res, nil
+}
+
+// Len returns the number of buckets held by the store.
+func (s *MemoryStore) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.buckets)
+}
+
+// sweep deletes the buckets that are full at the given time.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.swept = now
+}
+
+// refill returns the time it takes to add the given number of tokens to a bucket of the given
+// capacity refilled at a rate of capacity tokens per period.
+func refill(tokens, capacity float64, per time.Duration) time.Duration {
+	return time.Duration(math.Ceil(tokens * float64(per) / capacity))
+}
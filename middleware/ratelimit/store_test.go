@@ -0,0 +1,69 @@
+package ratelimit_test
+
+import (
+	"time"
+
+	"github.com/goadesign/goa/middleware/ratelimit"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("MemoryStore", func() {
+	var now time.Time
+	var store *ratelimit.MemoryStore
+
+	BeforeEach(func() {
+		now = time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+		store = ratelimit.NewMemoryStore()
+		store.Now = func() time.Time { return now }
+	})
+
+	It("allows up to limit requests", func() {
+		for i := 0; i < 3; i++ {
+			res, err := store.Take("key", 3, time.Minute)
+			Ω(err).ShouldNot(HaveOccurred())
+			Ω(res.Allowed).Should(BeTrue())
+			Ω(res.Remaining).Should(Equal(2 - i))
+		}
+		res, err := store.Take("key", 3, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeFalse())
+		Ω(res.Remaining).Should(Equal(0))
+		Ω(res.RetryAfter).Should(Equal(20 * time.Second))
+		Ω(res.Reset).Should(Equal(time.Minute))
+	})
+
+	It("refills the bucket over time", func() {
+		for i := 0; i < 3; i++ {
+			store.Take("key", 3, time.Minute)
+		}
+		now = now.Add(20 * time.Second)
+		res, err := store.Take("key", 3, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeTrue())
+	})
+
+	It("counts keys separately", func() {
+		store.Take("key", 1, time.Minute)
+		res, err := store.Take("other", 1, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeTrue())
+	})
+	It("drops the buckets that are full again", func() {
+		store.Take("key", 3, time.Minute)
+		store.Take("other", 3, time.Minute)
+		Ω(store.Len()).Should(Equal(2))
+		now = now.Add(30 * time.Second)
+		for i := 0; i < 3; i++ {
+			store.Take("other", 3, time.Minute)
+		}
+		now = now.Add(45 * time.Second)
+		res, err := store.Take("new", 3, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Allowed).Should(BeTrue())
+		Ω(store.Len()).Should(Equal(2))
+		res, err = store.Take("other", 3, time.Minute)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(res.Remaining).Should(Equal(1))
+	})
+})
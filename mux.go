@@ -1,8 +1,10 @@
 package goa
 
 import (
+	"mime"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/dimfeld/httptreemux"
 )
@@ -32,6 +34,23 @@ type (
 		MuxHandler(string, Handler, Unmarshaler) MuxHandler
 	}
 
+	// SelectVersionFunc computes the API version targeted by a request. It returns the empty
+	// string if the request does not target a specific version.
+	SelectVersionFunc func(*http.Request) string
+
+	// VersionMux is a ServeMux that dispatches requests to per API version muxes. The version
+	// targeted by a request is computed by a SelectVersionFunc. Requests that do not target a
+	// known version as well as requests that don't match any handler of the version mux are
+	// handled by the wrapped mux. The generated code mounts the controllers of each API version
+	// on the corresponding mux, see Service.VersionMux.
+	VersionMux struct {
+		ServeMux
+		// SelectVersion computes the version targeted by incoming requests.
+		SelectVersion SelectVersionFunc
+
+		versions map[string]ServeMux
+	}
+
 	// mux is the default ServeMux implementation.
 	mux struct {
 		router  *httptreemux.TreeMux
@@ -82,3 +101,135 @@ func (m *mux) Lookup(method, path string) MuxHandler {
 func (m *mux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	m.router.ServeHTTP(rw, req)
 }
+
+// NewVersionMux returns a VersionMux that uses selectVersion to dispatch requests to the version
+// muxes and mux to handle requests that target no known version. Replace the service mux with the
+// result to serve multiple versions of the API:
+//
+//	service.Mux = goa.NewVersionMux(service.Mux, goa.HeaderSelectVersionFunc("X-API-Version"))
+func NewVersionMux(mux ServeMux, selectVersion SelectVersionFunc) *VersionMux {
+	return &VersionMux{
+		ServeMux:      mux,
+		SelectVersion: selectVersion,
+		versions:      make(map[string]ServeMux),
+	}
+}
+
+// Version returns the mux of the given API version, creating it if needed.
+func (m *VersionMux) Version(version string) ServeMux {
+	if vm, ok := m.versions[version]; ok {
+		return vm
+	}
+	vm := NewMux()
+	vm.HandleNotFound(func(rw http.ResponseWriter, req *http.Request, _ url.Values) {
+		m.ServeMux.ServeHTTP(rw, req)
+	})
+	m.versions[version] = vm
+	return vm
+}
+
+// ServeHTTP dispatches the request to the mux of the version it targets.
+func (m *VersionMux) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if m.SelectVersion != nil {
+		if vm, ok := m.versions[m.SelectVersion(req)]; ok {
+			vm.ServeHTTP(rw, req)
+			return
+		}
+	}
+	m.ServeMux.ServeHTTP(rw, req)
+}
+
+// PathSelectVersionFunc returns a SelectVersionFunc that uses the first segment of the request path
+// following the given prefix as version, e.g. "v2" for the path "/api/v2/bottles" and the prefix
+// "/api".
+func PathSelectVersionFunc(prefix string) SelectVersionFunc {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(req *http.Request) string {
+		p := req.URL.Path
+		if !strings.HasPrefix(p, prefix+"/") {
+			return ""
+		}
+		p = p[len(prefix)+1:]
+		if i := strings.Index(p, "/"); i >= 0 {
+			p = p[:i]
+		}
+		return p
+	}
+}
+
+// HeaderSelectVersionFunc returns a SelectVersionFunc that uses the value of the given request
+// header as version.
+func HeaderSelectVersionFunc(header string) SelectVersionFunc {
+	return func(req *http.Request) string {
+		return req.Header.Get(header)
+	}
+}
+
+// AcceptSelectVersionFunc returns a SelectVersionFunc that uses the value of the given media type
+// parameter of the request Accept header as version, e.g. "v2" for the Accept header
+// "application/json; version=v2" and the parameter "version".
+func AcceptSelectVersionFunc(param string) SelectVersionFunc {
+	return func(req *http.Request) string {
+		for _, accept := range strings.Split(req.Header.Get("Accept"), ",") {
+			_, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err != nil {
+				continue
+			}
+			if v, ok := params[param]; ok {
+				return v
+			}
+		}
+		return ""
+	}
+}
+
+// BasePathSelectVersionFunc returns a SelectVersionFunc that uses the version whose base path
+// prefixes the request path, basePaths maps the versions to their base paths. Base path segments
+// that start with ":" or "*" match any request path segment. The version with the longest base path
+// wins if several match.
+func BasePathSelectVersionFunc(basePaths map[string]string) SelectVersionFunc {
+	segments := make(map[string][]string, len(basePaths))
+	for v, p := range basePaths {
+		segments[v] = strings.Split(strings.Trim(p, "/"), "/")
+	}
+	return func(req *http.Request) string {
+		path := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+		var version string
+		for v, segs := range segments {
+			if len(segs) > len(path) || !matchSegments(segs, path) {
+				continue
+			}
+			if best := segments[version]; version == "" || len(segs) > len(best) ||
+				len(segs) == len(best) && v < version {
+				version = v
+			}
+		}
+		return version
+	}
+}
+
+// matchSegments returns true if the given base path segments match the first segments of path.
+func matchSegments(segs, path []string) bool {
+	for i, s := range segs {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			continue
+		}
+		if s != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// CombineSelectVersionFunc returns a SelectVersionFunc that returns the first non empty version
+// computed by the given functions.
+func CombineSelectVersionFunc(funcs ...SelectVersionFunc) SelectVersionFunc {
+	return func(req *http.Request) string {
+		for _, f := range funcs {
+			if v := f(req); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+}
@@ -67,3 +67,107 @@ var _ = Describe("Mux", func() {
 	})
 
 })
+
+var _ = Describe("VersionMux", func() {
+	var mux *goa.VersionMux
+	var handled string
+
+	var req *http.Request
+	var rw *TestResponseWriter
+
+	BeforeEach(func() {
+		handled = ""
+		mux = goa.NewVersionMux(goa.NewMux(), goa.HeaderSelectVersionFunc("X-API-Version"))
+		mux.Handle("GET", "/foo", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+			handled = "default"
+		})
+		mux.Version("v2").Handle("GET", "/foo", func(rw http.ResponseWriter, req *http.Request, vals url.Values) {
+			handled = "v2"
+		})
+		var err error
+		req, err = http.NewRequest("GET", "/foo", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	JustBeforeEach(func() {
+		rw = &TestResponseWriter{ParentHeader: http.Header{}}
+		mux.ServeHTTP(rw, req)
+	})
+
+	It("uses the default mux for requests with no version", func() {
+		Ω(handled).Should(Equal("default"))
+	})
+
+	Context("with a request targeting a version", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Version", "v2")
+		})
+
+		It("uses the version mux", func() {
+			Ω(handled).Should(Equal("v2"))
+		})
+	})
+
+	Context("with a request targeting an unknown version", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Version", "v3")
+		})
+
+		It("uses the default mux", func() {
+			Ω(handled).Should(Equal("default"))
+		})
+	})
+
+	Context("with a request not matching any version handler", func() {
+		BeforeEach(func() {
+			req.Header.Set("X-API-Version", "v2")
+			req.URL.Path = "/bar"
+		})
+
+		It("falls back to the default mux", func() {
+			Ω(handled).Should(Equal(""))
+			Ω(rw.Status).Should(Equal(404))
+		})
+	})
+})
+
+var _ = Describe("SelectVersionFunc", func() {
+	var req *http.Request
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("GET", "/api/v2/bottles", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Accept", "text/plain, application/json; version=v3")
+		req.Header.Set("X-API-Version", "v4")
+	})
+
+	It("selects the version using the path", func() {
+		Ω(goa.PathSelectVersionFunc("/api")(req)).Should(Equal("v2"))
+		Ω(goa.PathSelectVersionFunc("/other")(req)).Should(Equal(""))
+	})
+
+	It("selects the version using the Accept header", func() {
+		Ω(goa.AcceptSelectVersionFunc("version")(req)).Should(Equal("v3"))
+	})
+
+	It("selects the version using a header", func() {
+		Ω(goa.HeaderSelectVersionFunc("X-API-Version")(req)).Should(Equal("v4"))
+	})
+
+	It("selects the version using the version base paths", func() {
+		sel := goa.BasePathSelectVersionFunc(map[string]string{"v1": "/api", "v2": "/api/v2", "v3": "/:tenant/v3"})
+		Ω(sel(req)).Should(Equal("v2"))
+		req.URL.Path = "/api/bottles"
+		Ω(sel(req)).Should(Equal("v1"))
+		req.URL.Path = "/acme/v3/bottles"
+		Ω(sel(req)).Should(Equal("v3"))
+		req.URL.Path = "/bottles"
+		Ω(sel(req)).Should(Equal(""))
+	})
+
+	It("combines selection functions", func() {
+		sel := goa.CombineSelectVersionFunc(goa.HeaderSelectVersionFunc("X-Other"), goa.AcceptSelectVersionFunc("version"))
+		Ω(sel(req)).Should(Equal("v3"))
+	})
+})
@@ -0,0 +1,76 @@
+package goa
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageLimit is the page size used by paginated actions when the request does not specify
+// one. The maximum page size is enforced by the validation of the generated "limit" parameter, see
+// design.MaxPageLimit.
+const DefaultPageLimit = 20
+
+// OffsetPageRequest returns the page number and page size of a request made to an action that
+// uses offset pagination. Page numbers start at 1, values lower than 1 select the first page and
+// the default page size respectively.
+func OffsetPageRequest(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	return page, PageLimit(limit)
+}
+
+// PageLimit returns the given page size or DefaultPageLimit if it is lower than 1.
+func PageLimit(limit int) int {
+	if limit < 1 {
+		return DefaultPageLimit
+	}
+	return limit
+}
+
+// OffsetPageLinks returns the value of the Link header listing the first, previous, next and last
+// pages of a response to an action that uses offset pagination. u is the URL of the request, page
+// and limit the requested page number and size and total the total number of items.
+func OffsetPageLinks(u *url.URL, page, limit, total int) string {
+	limit = PageLimit(limit)
+	last := (total + limit - 1) / limit
+	if last < 1 {
+		last = 1
+	}
+	link := func(p int, rel string) string {
+		return pageLink(u, rel, "page", strconv.Itoa(p), limit)
+	}
+	links := []string{link(1, "first")}
+	if page > 1 {
+		prev := page - 1
+		if prev > last {
+			prev = last
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if page < last {
+		links = append(links, link(page+1, "next"))
+	}
+	links = append(links, link(last, "last"))
+	return strings.Join(links, ", ")
+}
+
+// CursorPageLinks returns the value of the Link header pointing to the next page of a response to
+// an action that uses cursor pagination. u is the URL of the request, cursor identifies the next
+// page and limit is the requested page size.
+func CursorPageLinks(u *url.URL, cursor string, limit int) string {
+	return pageLink(u, "next", "cursor", cursor, PageLimit(limit))
+}
+
+// pageLink builds a single Link header value by setting the page and size query string parameters
+// of the request URL.
+func pageLink(u *url.URL, rel, param, value string, limit int) string {
+	l := *u
+	q := l.Query()
+	q.Set(param, value)
+	q.Set("limit", strconv.Itoa(limit))
+	l.RawQuery = q.Encode()
+	return fmt.Sprintf("<%s>; rel=%q", l.String(), rel)
+}
@@ -0,0 +1,62 @@
+package goa_test
+
+import (
+	"net/url"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OffsetPageRequest", func() {
+	It("defaults to the first page and the default page size", func() {
+		page, limit := goa.OffsetPageRequest(0, 0)
+		Ω(page).Should(Equal(1))
+		Ω(limit).Should(Equal(goa.DefaultPageLimit))
+	})
+
+	It("returns the requested page and page size", func() {
+		page, limit := goa.OffsetPageRequest(3, 10)
+		Ω(page).Should(Equal(3))
+		Ω(limit).Should(Equal(10))
+	})
+})
+
+var _ = Describe("OffsetPageLinks", func() {
+	var u *url.URL
+
+	BeforeEach(func() {
+		var err error
+		u, err = url.Parse("/bottles?sort=name&page=2&limit=10")
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	It("lists the first, previous, next and last pages", func() {
+		links := goa.OffsetPageLinks(u, 2, 10, 35)
+		Ω(links).Should(Equal(`</bottles?limit=10&page=1&sort=name>; rel="first", ` +
+			`</bottles?limit=10&page=1&sort=name>; rel="prev", ` +
+			`</bottles?limit=10&page=3&sort=name>; rel="next", ` +
+			`</bottles?limit=10&page=4&sort=name>; rel="last"`))
+	})
+
+	It("omits the next link on the last page", func() {
+		links := goa.OffsetPageLinks(u, 4, 10, 35)
+		Ω(links).ShouldNot(ContainSubstring(`rel="next"`))
+		Ω(links).Should(ContainSubstring(`</bottles?limit=10&page=3&sort=name>; rel="prev"`))
+	})
+
+	It("returns a single page when there are no items", func() {
+		links := goa.OffsetPageLinks(u, 1, 10, 0)
+		Ω(links).Should(Equal(`</bottles?limit=10&page=1&sort=name>; rel="first", ` +
+			`</bottles?limit=10&page=1&sort=name>; rel="last"`))
+	})
+})
+
+var _ = Describe("CursorPageLinks", func() {
+	It("links to the next page", func() {
+		u, err := url.Parse("http://example.com/bottles?cursor=abc")
+		Ω(err).ShouldNot(HaveOccurred())
+		links := goa.CursorPageLinks(u, "def", 0)
+		Ω(links).Should(Equal(`<http://example.com/bottles?cursor=def&limit=20>; rel="next"`))
+	})
+})
@@ -1,9 +1,13 @@
 package goa
 
-import "golang.org/x/net/context"
+import (
+	"net/http"
+
+	"golang.org/x/net/context"
+)
 
 // Location is the enum defining where the value of key based security schemes should be read:
-// either a HTTP request header or a URL querystring value
+// either a HTTP request header, a URL querystring value or a cookie
 type Location string
 
 // LocHeader indicates the secret value should be loaded from the request headers.
@@ -12,6 +16,9 @@ const LocHeader Location = "header"
 // LocQuery indicates the secret value should be loaded from the request URL querystring.
 const LocQuery Location = "query"
 
+// LocCookie indicates the secret value should be loaded from the request cookies.
+const LocCookie Location = "cookie"
+
 // OAuth2Security represents the `oauth2` security scheme. It is instantiated by the generated code
 // accordingly to the use of the different `*Security()` DSL functions and `Security()` in the
 // design.
@@ -45,17 +52,33 @@ type BasicAuthSecurity struct {
 type BasicAuthSecurityConfigFunc func(scheme *BasicAuthSecurity) Middleware
 
 // APIKeySecurity represents the `apiKey` security scheme. It handles a key that can be in the
-// headers or in the query parameters, and does authentication based on that.  The Name field
-// represents the key of either the query string parameter or the header, depending on the In field.
+// headers, in the query parameters or in a cookie, and does authentication based on that.  The
+// Name field represents the key of either the query string parameter, the header or the cookie,
+// depending on the In field.
 type APIKeySecurity struct {
 	// Description of the security scheme
 	Description string
-	// In represents where to check for some data, `query` or `header`
+	// In represents where to check for some data, `query`, `header` or `cookie`
 	In Location
-	// Name is the name of the `header` or `query` parameter to check for data.
+	// Name is the name of the `header`, `query` parameter or `cookie` to check for data.
 	Name string
 }
 
+// Key returns the API key contained in the request, the empty string if there is none.
+func (s *APIKeySecurity) Key(req *http.Request) string {
+	switch s.In {
+	case LocQuery:
+		return req.URL.Query().Get(s.Name)
+	case LocCookie:
+		if c, err := req.Cookie(s.Name); err == nil {
+			return c.Value
+		}
+		return ""
+	default:
+		return req.Header.Get(s.Name)
+	}
+}
+
 // APIKeySecurityConfigFunc is the callback given to the generated security configuration function
 // in charge of setting up the security scheme.
 type APIKeySecurityConfigFunc func(scheme *APIKeySecurity) Middleware
@@ -23,6 +23,10 @@ var (
 	// MaxRequestBodyLength is the maximum length read from request bodies.
 	// Set to 0 to remove the limit altogether.
 	MaxRequestBodyLength int64 = 1073741824 // 1 GB
+
+	// MaxMultipartMemory is the maximum number of bytes of a multipart form request body kept
+	// in memory, the remainder is stored on disk in temporary files.
+	MaxMultipartMemory int64 = 33554432 // 32 MB
 )
 
 type (
@@ -103,6 +107,16 @@ func New(name string) *Service {
 	return service
 }
 
+// VersionMux returns the mux used to serve the requests made to the given API version. It panics
+// if the service mux is not a VersionMux, see NewVersionMux.
+func (service *Service) VersionMux(version string) ServeMux {
+	vm, ok := service.Mux.(*VersionMux)
+	if !ok {
+		panic(fmt.Sprintf("goa: cannot mount the controllers of API version %q, the service mux is not a VersionMux", version))
+	}
+	return vm.Version(version)
+}
+
 // CancelAll sends a cancel signals to all request handlers via the context.
 // See https://godoc.org/golang.org/x/net/context for details on how to handle the signal.
 func (service *Service) CancelAll() {
@@ -83,6 +83,49 @@ var _ = Describe("Service", func() {
 		})
 	})
 
+	Describe("VersionMux", func() {
+		It("panics if the service mux is not a VersionMux", func() {
+			Ω(func() { s.VersionMux("v1") }).Should(Panic())
+		})
+
+		Context("with two versions selected by header", func() {
+			var rw *TestResponseWriter
+			var req *http.Request
+
+			BeforeEach(func() {
+				s.Mux = goa.NewVersionMux(s.Mux, goa.HeaderSelectVersionFunc("X-API-Version"))
+				for _, v := range []string{"v1", "v2"} {
+					version := v
+					ctrl := s.NewController("bottles " + version)
+					h := func(ctx context.Context, rw http.ResponseWriter, req *http.Request) error {
+						return s.Send(ctx, 200, version)
+					}
+					s.VersionMux(version).Handle("GET", "/bottles", ctrl.MuxHandler("list", h, nil))
+				}
+				req, _ = http.NewRequest("GET", "/bottles", nil)
+				rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+			})
+
+			It("serves the version selected by the request", func() {
+				req.Header.Set("X-API-Version", "v1")
+				s.Mux.ServeHTTP(rw, req)
+				Ω(rw.Status).Should(Equal(200))
+				Ω(string(rw.Body)).Should(Equal("\"v1\"\n"))
+
+				rw = &TestResponseWriter{ParentHeader: make(http.Header)}
+				req.Header.Set("X-API-Version", "v2")
+				s.Mux.ServeHTTP(rw, req)
+				Ω(rw.Status).Should(Equal(200))
+				Ω(string(rw.Body)).Should(Equal("\"v2\"\n"))
+			})
+
+			It("does not serve requests that select no version", func() {
+				s.Mux.ServeHTTP(rw, req)
+				Ω(rw.Status).Should(Equal(404))
+			})
+		})
+	})
+
 	Describe("MaxRequestBodyLength", func() {
 		var oldMax int64
 		var rw *TestResponseWriter
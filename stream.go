@@ -0,0 +1,158 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/context"
+)
+
+var (
+	// ErrStreamClosed is the error returned when sending an event on a closed event stream.
+	ErrStreamClosed = errors.New("event stream closed")
+
+	// ErrInvalidEventField is the error returned when sending an event whose ID or type contains
+	// a line break.
+	ErrInvalidEventField = errors.New("event stream: event ID and type must not contain line breaks")
+)
+
+// EventStream writes server-sent events to a HTTP response using the text/event-stream format.
+// Each event is flushed as soon as it is sent. The stream context is canceled when the client
+// disconnects, when the stream is closed or when the context used to create the stream is.
+type EventStream struct {
+	ctx         context.Context
+	cancel      context.CancelFunc
+	resp        *ResponseData
+	lastEventID string
+
+	lock    sync.Mutex
+	started bool
+	closed  bool
+}
+
+// NewEventStream creates an event stream that writes to the response of the given goa request
+// context.
+func NewEventStream(ctx context.Context) *EventStream {
+	req := ContextRequest(ctx)
+	resp := ContextResponse(ctx)
+	cctx, cancel := context.WithCancel(ctx)
+	s := &EventStream{
+		ctx:         cctx,
+		cancel:      cancel,
+		resp:        resp,
+		lastEventID: req.Header.Get("Last-Event-ID"),
+	}
+	if cn, ok := resp.ResponseWriter.(http.CloseNotifier); ok {
+		closed := cn.CloseNotify()
+		go func() {
+			select {
+			case <-closed:
+				cancel()
+			case <-cctx.Done():
+			}
+		}()
+	}
+	return s
+}
+
+// Context returns the stream context. The context is canceled when the client disconnects or when
+// the stream is closed.
+func (s *EventStream) Context() context.Context {
+	return s.ctx
+}
+
+// LastEventID returns the value of the Last-Event-ID request header sent by reconnecting clients,
+// the empty string if there is none.
+func (s *EventStream) LastEventID() string {
+	return s.lastEventID
+}
+
+// Send writes the event with the given ID, type and JSON encoded data to the response and flushes
+// it. id and event may be empty in which case the corresponding fields are omitted, they may not
+// contain CR or LF characters. Send returns an error if the stream is closed or the client
+// disconnected.
+func (s *EventStream) Send(id, event string, data interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return ErrStreamClosed
+	}
+	if err := s.ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(id, "\r\n") || strings.ContainsAny(event, "\r\n") {
+		return ErrInvalidEventField
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if !s.started {
+		s.start()
+	}
+	var buf bytes.Buffer
+	if id != "" {
+		fmt.Fprintf(&buf, "id: %s\n", id)
+	}
+	if event != "" {
+		fmt.Fprintf(&buf, "event: %s\n", event)
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		fmt.Fprintf(&buf, "data: %s\n", line)
+	}
+	buf.WriteByte('\n')
+	if _, err := s.resp.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+// Close ends the stream. It writes the response headers if no event was sent and cancels the
+// stream context. Sending events after Close returns ErrStreamClosed.
+func (s *EventStream) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.closed {
+		return nil
+	}
+	if !s.started {
+		s.start()
+	}
+	s.closed = true
+	s.cancel()
+	return nil
+}
+
+// Release cancels the stream context and stops watching for client disconnection without writing
+// to the response. Sending events after Release returns ErrStreamClosed and Close does nothing.
+// The generated handlers release the stream of the action context once the action returns.
+func (s *EventStream) Release() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.closed = true
+	s.cancel()
+}
+
+// start writes the response headers, it must be called with the lock held.
+func (s *EventStream) start() {
+	s.started = true
+	h := s.resp.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	s.resp.WriteHeader(http.StatusOK)
+	s.flush()
+}
+
+// flush flushes the response if the underlying writer supports it.
+func (s *EventStream) flush() {
+	if f, ok := s.resp.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
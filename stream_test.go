@@ -0,0 +1,87 @@
+package goa_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"golang.org/x/net/context"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// closeNotifyingRecorder is a response recorder that implements http.CloseNotifier.
+type closeNotifyingRecorder struct {
+	*httptest.ResponseRecorder
+	closed chan bool
+}
+
+func (r *closeNotifyingRecorder) CloseNotify() <-chan bool {
+	return r.closed
+}
+
+var _ = Describe("EventStream", func() {
+	var rw *closeNotifyingRecorder
+	var req *http.Request
+	var stream *goa.EventStream
+
+	BeforeEach(func() {
+		var err error
+		req, err = http.NewRequest("GET", "/ticks", nil)
+		Ω(err).ShouldNot(HaveOccurred())
+		req.Header.Set("Last-Event-ID", "41")
+		rw = &closeNotifyingRecorder{ResponseRecorder: httptest.NewRecorder(), closed: make(chan bool, 1)}
+	})
+
+	JustBeforeEach(func() {
+		ctx := goa.NewContext(context.Background(), rw, req, nil)
+		stream = goa.NewEventStream(ctx)
+	})
+
+	AfterEach(func() {
+		stream.Close()
+	})
+
+	It("returns the Last-Event-ID header value", func() {
+		Ω(stream.LastEventID()).Should(Equal("41"))
+	})
+
+	It("writes and flushes the events", func() {
+		Ω(stream.Send("42", "tick", map[string]int{"seq": 42})).ShouldNot(HaveOccurred())
+		Ω(stream.Send("", "", "hello")).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Flushed).Should(BeTrue())
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("text/event-stream"))
+		Ω(rw.Body.String()).Should(Equal("id: 42\nevent: tick\ndata: {\"seq\":42}\n\ndata: \"hello\"\n\n"))
+	})
+
+	It("rejects event IDs and types that contain line breaks", func() {
+		Ω(stream.Send("42\ndata: forged", "tick", 42)).Should(Equal(goa.ErrInvalidEventField))
+		Ω(stream.Send("42", "tick\r", 42)).Should(Equal(goa.ErrInvalidEventField))
+		Ω(rw.Body.String()).Should(BeEmpty())
+	})
+
+	It("writes the headers and cancels the context when closed", func() {
+		Ω(stream.Close()).ShouldNot(HaveOccurred())
+		Ω(rw.Code).Should(Equal(200))
+		Ω(rw.Header().Get("Content-Type")).Should(Equal("text/event-stream"))
+		Eventually(stream.Context().Done()).Should(BeClosed())
+		Ω(stream.Send("", "", "late")).Should(Equal(goa.ErrStreamClosed))
+	})
+
+	It("cancels the context without writing the response when released", func() {
+		stream.Release()
+		Eventually(stream.Context().Done()).Should(BeClosed())
+		Ω(stream.Send("", "", "late")).Should(Equal(goa.ErrStreamClosed))
+		Ω(stream.Close()).ShouldNot(HaveOccurred())
+		Ω(rw.Header().Get("Content-Type")).Should(BeEmpty())
+		Ω(rw.Body.String()).Should(BeEmpty())
+	})
+
+	It("cancels the context when the client disconnects", func() {
+		rw.closed <- true
+		Eventually(stream.Context().Done()).Should(BeClosed())
+		Ω(stream.Send("", "", "late")).Should(HaveOccurred())
+	})
+})
@@ -0,0 +1,150 @@
+package goa
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/goadesign/goa/format"
+)
+
+type (
+	// Date is the Go type used by generated code for attributes of type Date. Date wraps a
+	// time.Time whose time of day is ignored. Date values are marshaled to and from the RFC3339
+	// full-date format (e.g. "2016-07-11"). Generated code does not use time.Time directly as
+	// it marshals to full date-time values, use the embedded Time field to get the time.Time.
+	Date struct {
+		time.Time
+	}
+
+	// Duration is the Go type used by generated code for attributes of type Duration. Duration
+	// values are marshaled to and from the ISO 8601 duration format (e.g. "PT1H30M"). Generated
+	// code does not use time.Duration directly as it marshals to a number of nanoseconds, convert
+	// with time.Duration(d) and Duration(d).
+	Duration time.Duration
+
+	// Decimal is the Go type used by generated code for attributes of type Decimal. Decimal
+	// holds the exact textual representation of a decimal number so that no precision is lost
+	// in transit. Decimal values are marshaled to JSON strings and may be unmarshaled from
+	// either JSON strings or JSON numbers.
+	Decimal string
+)
+
+// DateLayout is the time layout used to format and parse Date values.
+const DateLayout = format.DateLayout
+
+// ParseDate parses a RFC3339 full-date value (e.g. "2016-07-11").
+func ParseDate(s string) (Date, error) {
+	t, err := format.ParseDate(s)
+	if err != nil {
+		return Date{}, err
+	}
+	return Date{Time: t}, nil
+}
+
+// String returns the RFC3339 full-date representation of the date.
+func (d Date) String() string {
+	return d.Format(DateLayout)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Date) UnmarshalText(text []byte) error {
+	parsed, err := ParseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// ParseDuration parses an ISO 8601 duration value (e.g. "P1DT12H" or "PT0.5S"). Years and months
+// are not supported as their length varies.
+func ParseDuration(s string) (Duration, error) {
+	d, err := format.ParseDuration(s)
+	return Duration(d), err
+}
+
+// String returns the ISO 8601 representation of the duration using hours, minutes and seconds
+// (e.g. "PT36H0.5S").
+func (d Duration) String() string {
+	return format.FormatDuration(time.Duration(d))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// ParseDecimal validates and returns the given decimal number (e.g. "-12.345").
+func ParseDecimal(s string) (Decimal, error) {
+	if err := format.ValidateDecimal(s); err != nil {
+		return "", err
+	}
+	return Decimal(s), nil
+}
+
+// Rat returns the exact value of the decimal as a big.Rat. It returns nil if the decimal is
+// invalid.
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// Float64 returns the nearest float64 value of the decimal.
+func (d Decimal) Float64() (float64, error) {
+	return strconv.ParseFloat(string(d), 64)
+}
+
+// String returns the decimal textual representation.
+func (d Decimal) String() string {
+	return string(d)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	}
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,107 @@
+package goa_test
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Date", func() {
+	It("parses and formats full dates", func() {
+		d, err := goa.ParseDate("2016-07-11")
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(d.Year()).Should(Equal(2016))
+		Ω(d.Month()).Should(Equal(time.July))
+		Ω(d.Day()).Should(Equal(11))
+		Ω(d.String()).Should(Equal("2016-07-11"))
+	})
+
+	It("rejects date times", func() {
+		_, err := goa.ParseDate("2016-07-11T10:00:00Z")
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("marshals to and from JSON strings", func() {
+		d, _ := goa.ParseDate("2016-07-11")
+		b, err := json.Marshal(d)
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"2016-07-11"`))
+		var decoded goa.Date
+		Ω(json.Unmarshal(b, &decoded)).Should(Succeed())
+		Ω(decoded).Should(Equal(d))
+	})
+})
+
+var _ = Describe("Duration", func() {
+	It("parses ISO 8601 durations", func() {
+		durations := map[string]time.Duration{
+			"PT0S":    0,
+			"PT1H30M": 90 * time.Minute,
+			"P1DT12H": 36 * time.Hour,
+			"P2W":     14 * 24 * time.Hour,
+			"PT0.5S":  500 * time.Millisecond,
+			"-PT10M":  -10 * time.Minute,
+		}
+		for s, expected := range durations {
+			d, err := goa.ParseDuration(s)
+			Ω(err).ShouldNot(HaveOccurred(), s)
+			Ω(time.Duration(d)).Should(Equal(expected), s)
+		}
+	})
+
+	It("rejects invalid durations", func() {
+		for _, s := range []string{"", "P", "PT", "PT10", "P1M", "1h30m"} {
+			_, err := goa.ParseDuration(s)
+			Ω(err).Should(HaveOccurred(), s)
+		}
+	})
+
+	It("formats using hours, minutes and seconds", func() {
+		Ω(goa.Duration(36*time.Hour + 500*time.Millisecond).String()).Should(Equal("PT36H0.5S"))
+		Ω(goa.Duration(-90 * time.Second).String()).Should(Equal("-PT1M30S"))
+		Ω(goa.Duration(0).String()).Should(Equal("PT0S"))
+	})
+
+	It("marshals to and from JSON strings", func() {
+		b, err := json.Marshal(goa.Duration(90 * time.Minute))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"PT1H30M"`))
+		var decoded goa.Duration
+		Ω(json.Unmarshal(b, &decoded)).Should(Succeed())
+		Ω(time.Duration(decoded)).Should(Equal(90 * time.Minute))
+	})
+})
+
+var _ = Describe("Decimal", func() {
+	It("validates decimal numbers", func() {
+		_, err := goa.ParseDecimal("-12.345")
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = goa.ParseDecimal("1e10")
+		Ω(err).ShouldNot(HaveOccurred())
+		_, err = goa.ParseDecimal("12,3")
+		Ω(err).Should(HaveOccurred())
+	})
+
+	It("computes the exact value", func() {
+		d, _ := goa.ParseDecimal("0.1")
+		Ω(d.Rat().String()).Should(Equal("1/10"))
+	})
+
+	It("marshals to JSON strings", func() {
+		b, err := json.Marshal(goa.Decimal("0.10"))
+		Ω(err).ShouldNot(HaveOccurred())
+		Ω(string(b)).Should(Equal(`"0.10"`))
+	})
+
+	It("unmarshals JSON strings and numbers", func() {
+		var d goa.Decimal
+		Ω(json.Unmarshal([]byte(`"0.10"`), &d)).Should(Succeed())
+		Ω(d).Should(Equal(goa.Decimal("0.10")))
+		Ω(json.Unmarshal([]byte(`12.50`), &d)).Should(Succeed())
+		Ω(d).Should(Equal(goa.Decimal("12.50")))
+		Ω(json.Unmarshal([]byte(`"foo"`), &d)).ShouldNot(Succeed())
+	})
+})
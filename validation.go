@@ -2,12 +2,15 @@ package goa
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"net/mail"
 	"net/url"
+	"reflect"
 	"regexp"
 	"time"
 
+	"github.com/goadesign/goa/format"
 	"github.com/satori/go.uuid"
 )
 
@@ -54,6 +57,41 @@ var (
 	ipv4Regex = regexp.MustCompile(`^(?:[0-9]{1,3}\.){3}[0-9]{1,3}$`)
 )
 
+// RegisterFormat registers a custom validation format. ValidateFormat calls the validator function
+// to validate values against the format, the function must return an error if the value does not
+// conform. RegisterFormat panics if the name is the name of a standard format. Registering a
+// format prior to running goagen also makes it possible to use it with the Format DSL, e.g.:
+//
+//	func init() {
+//		goa.RegisterFormat("semver", func(val string) error {
+//			if !semverRegex.MatchString(val) {
+//				return fmt.Errorf("%#v is not a semantic version", val)
+//			}
+//			return nil
+//		})
+//	}
+func RegisterFormat(f Format, validator func(string) error) {
+	if isStandardFormat(f) {
+		panic(fmt.Sprintf("goa: cannot override standard format %#v", f)) // bug
+	}
+	format.Register(string(f), validator)
+}
+
+// IsRegisteredFormat returns true if f was registered with RegisterFormat.
+func IsRegisteredFormat(f Format) bool {
+	return format.IsRegistered(string(f))
+}
+
+// isStandardFormat returns true if f is one of the formats supported natively by ValidateFormat.
+func isStandardFormat(f Format) bool {
+	switch f {
+	case FormatDateTime, FormatUUID, FormatEmail, FormatHostname, FormatIPv4, FormatIPv6,
+		FormatURI, FormatMAC, FormatCIDR, FormatRegexp:
+		return true
+	}
+	return false
+}
+
 // ValidateFormat validates a string against a standard format.
 // It returns nil if the string conforms to the format, an error otherwise.
 // The format specification follows the json schema draft 4 validation extension.
@@ -68,6 +106,8 @@ var (
 //     - "mac": IEEE 802 MAC-48, EUI-48 or EUI-64 MAC address value
 //     - "cidr": RFC4632 and RFC4291 CIDR notation IP address value
 //     - "regexp": Regular expression syntax accepted by RE2
+//
+// ValidateFormat also supports the custom formats registered with RegisterFormat.
 func ValidateFormat(f Format, val string) error {
 	var err error
 	switch f {
@@ -101,7 +141,11 @@ func ValidateFormat(f Format, val string) error {
 	case FormatRegexp:
 		_, err = regexp.Compile(val)
 	default:
-		return fmt.Errorf("unknown format %#v", f)
+		validator, ok := format.Validator(string(f))
+		if !ok {
+			return fmt.Errorf("unknown format %#v", f)
+		}
+		err = validator(val)
 	}
 	if err != nil {
 		go IncrCounter([]string{"goa", "validation", "error", string(f)}, 1.0)
@@ -123,3 +167,62 @@ func ValidatePattern(p string, val string) bool {
 	}
 	return r.MatchString(val)
 }
+
+// ValidateMultipleOf returns true if val is a multiple of factor. factor must be strictly greater
+// than 0. The comparison tolerates the rounding errors inherent to floating point arithmetic.
+func ValidateMultipleOf(val, factor float64) bool {
+	q := val / factor
+	return math.Abs(q-math.Floor(q+0.5)) <= 1e-9*math.Max(1, math.Abs(q))
+}
+
+// ValidateUniqueItems returns true if the elements of the slice val are all different. Elements
+// are compared with reflect.DeepEqual so that pointers to identical values are considered equal.
+// Elements whose values do not contain pointers are looked up in a map, the others are compared
+// pairwise.
+func ValidateUniqueItems(val interface{}) bool {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return true
+	}
+	seen := make(map[interface{}]struct{}, v.Len())
+	var others []interface{}
+	for i := 0; i < v.Len(); i++ {
+		elem := v.Index(i).Interface()
+		if elem != nil && isFlat(reflect.TypeOf(elem)) {
+			if _, ok := seen[elem]; ok {
+				return false
+			}
+			seen[elem] = struct{}{}
+			continue
+		}
+		for _, o := range others {
+			if reflect.DeepEqual(elem, o) {
+				return false
+			}
+		}
+		others = append(others, elem)
+	}
+	return true
+}
+
+// isFlat returns true if the values of type t can be used as map keys and if comparing them with
+// == is the same as comparing them with reflect.DeepEqual.
+func isFlat(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return isFlat(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if !isFlat(t.Field(i).Type) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
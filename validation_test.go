@@ -1,6 +1,8 @@
 package goa_test
 
 import (
+	"fmt"
+
 	"github.com/goadesign/goa"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -286,4 +288,87 @@ var _ = Describe("ValidateFormat", func() {
 		})
 
 	})
+
+	Context("Custom", func() {
+		BeforeEach(func() {
+			f = goa.Format("country-code")
+			goa.RegisterFormat(f, func(val string) error {
+				if len(val) != 2 {
+					return fmt.Errorf("%#v is not a country code", val)
+				}
+				return nil
+			})
+		})
+
+		It("is registered", func() {
+			Ω(goa.IsRegisteredFormat(f)).Should(BeTrue())
+		})
+
+		Context("with an invalid value", func() {
+			BeforeEach(func() {
+				val = "foo"
+			})
+
+			It("does not validates", func() {
+				Ω(valErr).Should(HaveOccurred())
+			})
+		})
+
+		Context("with a valid value", func() {
+			BeforeEach(func() {
+				val = "fr"
+			})
+
+			It("validates", func() {
+				Ω(valErr).ShouldNot(HaveOccurred())
+			})
+		})
+
+	})
+
+	Context("Unknown", func() {
+		BeforeEach(func() {
+			f = goa.Format("unknown")
+			val = "foo"
+		})
+
+		It("does not validates", func() {
+			Ω(valErr).Should(HaveOccurred())
+		})
+	})
+})
+
+var _ = Describe("ValidateMultipleOf", func() {
+	It("accepts multiples", func() {
+		Ω(goa.ValidateMultipleOf(10, 5)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(-10, 5)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(0.3, 0.1)).Should(BeTrue())
+		Ω(goa.ValidateMultipleOf(19.99, 0.01)).Should(BeTrue())
+	})
+
+	It("rejects other values", func() {
+		Ω(goa.ValidateMultipleOf(11, 5)).Should(BeFalse())
+		Ω(goa.ValidateMultipleOf(0.305, 0.01)).Should(BeFalse())
+	})
+})
+
+var _ = Describe("ValidateUniqueItems", func() {
+	It("accepts unique elements", func() {
+		Ω(goa.ValidateUniqueItems([]string{"a", "b"})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]int(nil))).Should(BeTrue())
+	})
+
+	It("rejects duplicate elements", func() {
+		Ω(goa.ValidateUniqueItems([]int{1, 2, 1})).Should(BeFalse())
+		a, b := "a", "a"
+		Ω(goa.ValidateUniqueItems([]*string{&a, &b})).Should(BeFalse())
+		Ω(goa.ValidateUniqueItems([]interface{}{1, "a", &a, []int{1}, &b})).Should(BeFalse())
+		Ω(goa.ValidateUniqueItems([]interface{}{nil, 1, nil})).Should(BeFalse())
+	})
+
+	It("compares elements of different types", func() {
+		a := "a"
+		Ω(goa.ValidateUniqueItems([]interface{}{1, int64(1), "a", &a, nil})).Should(BeTrue())
+		Ω(goa.ValidateUniqueItems([]interface{}{[]int{1}, []int{2}, map[string]int{"a": 1}})).Should(BeTrue())
+	})
 })
@@ -0,0 +1,273 @@
+package goa
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+)
+
+// WebSocket close codes defined by RFC 6455 section 7.4.1.
+const (
+	// WSCloseNormal indicates a normal closure.
+	WSCloseNormal = 1000
+	// WSCloseGoingAway indicates that the server is going down.
+	WSCloseGoingAway = 1001
+	// WSCloseProtocolError indicates that the peer did not follow the WebSocket protocol.
+	WSCloseProtocolError = 1002
+	// WSCloseUnsupportedData indicates that the peer sent a message that cannot be accepted.
+	WSCloseUnsupportedData = 1003
+	// WSCloseInvalidPayload indicates that the peer sent a message that could not be decoded
+	// or that is not valid.
+	WSCloseInvalidPayload = 1007
+	// WSClosePolicyViolation indicates that the peer violated the policy of the endpoint.
+	WSClosePolicyViolation = 1008
+	// WSCloseInternalError indicates that the server encountered an unexpected condition.
+	WSCloseInternalError = 1011
+)
+
+// ErrWebSocketClosed is the error returned when sending a message on a closed WebSocket
+// connection.
+var ErrWebSocketClosed = errors.New("websocket connection closed")
+
+// pingCodec writes WebSocket ping frames.
+var pingCodec = websocket.Codec{Marshal: func(interface{}) ([]byte, byte, error) {
+	return nil, websocket.PingFrame, nil
+}}
+
+// closeCodec writes WebSocket close frames, the value must be a *WebSocketCloseError.
+var closeCodec = websocket.Codec{Marshal: func(v interface{}) ([]byte, byte, error) {
+	e := v.(*WebSocketCloseError)
+	reason := e.Reason
+	if len(reason) > 123 {
+		// Control frame payloads are limited to 125 bytes.
+		reason = reason[:123]
+	}
+	data := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(data, uint16(e.Code))
+	return append(data, reason...), websocket.CloseFrame, nil
+}}
+
+type (
+	// WebSocketCloseError is the error returned by WebSocket connection handlers to close the
+	// connection with a specific close code. It is also the error returned by Recv when an
+	// inbound message cannot be decoded or is invalid.
+	WebSocketCloseError struct {
+		// Code is the close code, see the WSCloseXXX constants.
+		Code int
+		// Reason is the close reason.
+		Reason string
+	}
+
+	// WebSocketConn is a server WebSocket connection that decodes the inbound messages and
+	// encodes the outbound messages using the service decoders and encoders. Inbound messages
+	// use the decoder registered for the Content-Type header of the handshake request, outbound
+	// messages the encoder registered for its Accept header. The connection context is canceled
+	// when the connection is closed or a ping frame cannot be written.
+	WebSocketConn struct {
+		ctx         context.Context
+		cancel      context.CancelFunc
+		service     *Service
+		ws          *websocket.Conn
+		contentType string
+		accept      string
+
+		lock   sync.Mutex
+		closed bool
+	}
+
+	// finalizer is the interface implemented by the decoded types that initialize the default
+	// values of their fields.
+	finalizer interface {
+		Finalize()
+	}
+
+	// validator is the interface implemented by the decoded types that validate their fields.
+	validator interface {
+		Validate() error
+	}
+)
+
+// NewWebSocketCloseError returns a close error with the given code whose reason is the message of
+// the given error.
+func NewWebSocketCloseError(code int, err error) *WebSocketCloseError {
+	return &WebSocketCloseError{Code: code, Reason: err.Error()}
+}
+
+// Error returns the error message.
+func (e *WebSocketCloseError) Error() string {
+	return fmt.Sprintf("websocket closed with code %d: %s", e.Code, e.Reason)
+}
+
+// ServeWebSocket upgrades the request of the given goa request context to a WebSocket connection
+// and calls handler with the connection. The connection is closed when handler returns: with the
+// normal close code if it returns nil, with the code of the error if it returns a
+// *WebSocketCloseError and with the internal error close code otherwise. keepAlive is the
+// interval between the ping frames sent to the client, no ping frame is sent if it is zero.
+func ServeWebSocket(ctx context.Context, service *Service, keepAlive time.Duration, handler func(*WebSocketConn) error) error {
+	var herr error
+	websocket.Handler(func(ws *websocket.Conn) {
+		conn := NewWebSocketConn(ctx, service, ws, keepAlive)
+		herr = handler(conn)
+		switch actual := herr.(type) {
+		case nil:
+			conn.Close(WSCloseNormal, "")
+		case *WebSocketCloseError:
+			conn.Close(actual.Code, actual.Reason)
+		default:
+			conn.Close(WSCloseInternalError, "internal error")
+		}
+	}).ServeHTTP(ContextResponse(ctx).ResponseWriter, ContextRequest(ctx).Request)
+	return herr
+}
+
+// NewWebSocketConn wraps the given server WebSocket connection. keepAlive is the interval between
+// the ping frames sent to the client, no ping frame is sent if it is zero.
+func NewWebSocketConn(ctx context.Context, service *Service, ws *websocket.Conn, keepAlive time.Duration) *WebSocketConn {
+	cctx, cancel := context.WithCancel(ctx)
+	c := &WebSocketConn{ctx: cctx, cancel: cancel, service: service, ws: ws}
+	if req := ws.Request(); req != nil {
+		c.contentType = req.Header.Get("Content-Type")
+		c.accept = service.acceptedContentType(req.Header.Get("Accept"))
+	}
+	if keepAlive > 0 {
+		go c.keepAlive(keepAlive)
+	}
+	return c
+}
+
+// Context returns the connection context. The context is canceled when the connection is closed.
+func (c *WebSocketConn) Context() context.Context {
+	return c.ctx
+}
+
+// Conn returns the underlying WebSocket connection.
+func (c *WebSocketConn) Conn() *websocket.Conn {
+	return c.ws
+}
+
+// Recv reads the next message and decodes it into v. Recv initializes the default values of the
+// decoded message if v implements Finalize and validates it if v implements Validate. It returns
+// io.EOF when the client closes the connection and a *WebSocketCloseError using the invalid
+// payload close code when the message cannot be decoded or is invalid.
+func (c *WebSocketConn) Recv(v interface{}) error {
+	var data []byte
+	if err := websocket.Message.Receive(c.ws, &data); err != nil {
+		return err
+	}
+	if err := c.service.Decode(v, bytes.NewReader(data), c.contentType); err != nil {
+		return NewWebSocketCloseError(WSCloseInvalidPayload, err)
+	}
+	if f, ok := v.(finalizer); ok {
+		f.Finalize()
+	}
+	if val, ok := v.(validator); ok {
+		if err := val.Validate(); err != nil {
+			return NewWebSocketCloseError(WSCloseInvalidPayload, err)
+		}
+	}
+	return nil
+}
+
+// Send encodes v and writes it as a single message. Messages encoded using a textual content type
+// such as JSON are written as text frames, other messages as binary frames. Send returns
+// ErrWebSocketClosed if the connection is closed.
+func (c *WebSocketConn) Send(v interface{}) error {
+	var buf bytes.Buffer
+	if err := c.service.Encode(v, &buf, c.accept); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return ErrWebSocketClosed
+	}
+	if TextContentType(c.accept) {
+		return websocket.Message.Send(c.ws, buf.String())
+	}
+	return websocket.Message.Send(c.ws, buf.Bytes())
+}
+
+// Close writes a close frame with the given code and reason and cancels the connection context.
+// The underlying connection is closed once the connection handler returns. Close does nothing if
+// the connection is already closed.
+func (c *WebSocketConn) Close(code int, reason string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.cancel()
+	return closeCodec.Send(c.ws, &WebSocketCloseError{Code: code, Reason: reason})
+}
+
+// keepAlive writes a ping frame at each period until the connection is closed. It cancels the
+// connection context if a ping frame cannot be written.
+func (c *WebSocketConn) keepAlive(period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-ticker.C:
+			c.lock.Lock()
+			closed := c.closed
+			var err error
+			if !closed {
+				err = pingCodec.Send(c.ws, nil)
+			}
+			c.lock.Unlock()
+			if closed {
+				return
+			}
+			if err != nil {
+				c.cancel()
+				return
+			}
+		}
+	}
+}
+
+// TextContentType returns true if messages encoded with the encoder selected by the given Accept
+// header value are text. It uses the first media type of the list if accept lists more than one.
+func TextContentType(accept string) bool {
+	if i := strings.Index(accept, ","); i >= 0 {
+		accept = accept[:i]
+	}
+	accept = strings.TrimSpace(accept)
+	if accept == "" || accept == "*/*" {
+		// Default encoder is JSON
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(accept)
+	if err != nil {
+		mediaType = accept
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "json") ||
+		strings.HasSuffix(mediaType, "xml")
+}
+
+// acceptedContentType returns the first media type of the given Accept header value the service
+// has an encoder for, the empty string if there is none so that the default encoder is used.
+func (service *Service) acceptedContentType(accept string) string {
+	for _, t := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(t))
+		if err != nil {
+			continue
+		}
+		if _, ok := service.encoderPools[mediaType]; ok {
+			return mediaType
+		}
+	}
+	return ""
+}
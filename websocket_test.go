@@ -0,0 +1,213 @@
+package goa_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/net/websocket"
+
+	"github.com/goadesign/goa"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// chatMessage is the message type used to test WebSocket connections.
+type chatMessage struct {
+	Body *string `json:"body,omitempty"`
+}
+
+// Finalize sets the default body.
+func (m *chatMessage) Finalize() {
+	if m.Body == nil {
+		body := "default"
+		m.Body = &body
+	}
+}
+
+// Validate rejects empty bodies.
+func (m *chatMessage) Validate() error {
+	if *m.Body == "" {
+		return errors.New("body cannot be empty")
+	}
+	return nil
+}
+
+// readFrame reads the next frame sent by the server including control frames.
+func readFrame(ws *websocket.Conn) (byte, []byte) {
+	fr, err := ws.NewFrameReader()
+	Ω(err).ShouldNot(HaveOccurred())
+	data, err := ioutil.ReadAll(fr)
+	Ω(err).ShouldNot(HaveOccurred())
+	return fr.PayloadType(), data
+}
+
+var _ = Describe("WebSocketConn", func() {
+	var service *goa.Service
+	var keepAlive time.Duration
+	var handler func(*goa.WebSocketConn) error
+	var server *httptest.Server
+	var served chan error
+	var header http.Header
+	var ws *websocket.Conn
+
+	BeforeEach(func() {
+		header = nil
+		service = goa.New("test")
+		service.Decoder(goa.NewJSONDecoder, "*/*")
+		service.Encoder(goa.NewJSONEncoder, "*/*")
+		keepAlive = 0
+		served = make(chan error, 1)
+		handler = func(conn *goa.WebSocketConn) error {
+			for {
+				var msg chatMessage
+				if err := conn.Recv(&msg); err != nil {
+					if err == io.EOF {
+						return nil
+					}
+					return err
+				}
+				if err := conn.Send(&msg); err != nil {
+					return err
+				}
+			}
+		}
+	})
+
+	JustBeforeEach(func() {
+		service, keepAlive, handler, served := service, keepAlive, handler, served
+		server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ctx := goa.NewContext(context.Background(), rw, req, nil)
+			served <- goa.ServeWebSocket(ctx, service, keepAlive, handler)
+		}))
+		url := "ws" + strings.TrimPrefix(server.URL, "http")
+		cfg, err := websocket.NewConfig(url, server.URL)
+		Ω(err).ShouldNot(HaveOccurred())
+		for k, v := range header {
+			cfg.Header[k] = v
+		}
+		ws, err = websocket.DialConfig(cfg)
+		Ω(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		ws.Close()
+		server.Close()
+	})
+
+	It("decodes, finalizes and encodes the messages", func() {
+		Ω(websocket.Message.Send(ws, `{"body":"hello"}`)).ShouldNot(HaveOccurred())
+		payloadType, data := readFrame(ws)
+		Ω(payloadType).Should(BeEquivalentTo(websocket.TextFrame))
+		Ω(data).Should(MatchJSON(`{"body":"hello"}`))
+
+		Ω(websocket.Message.Send(ws, `{}`)).ShouldNot(HaveOccurred())
+		_, data = readFrame(ws)
+		Ω(data).Should(MatchJSON(`{"body":"default"}`))
+	})
+
+	It("closes the connection with the invalid payload code when a message is invalid", func() {
+		Ω(websocket.Message.Send(ws, `{"body":""}`)).ShouldNot(HaveOccurred())
+		payloadType, data := readFrame(ws)
+		Ω(payloadType).Should(BeEquivalentTo(websocket.CloseFrame))
+		Ω(binary.BigEndian.Uint16(data)).Should(BeEquivalentTo(goa.WSCloseInvalidPayload))
+		Ω(string(data[2:])).Should(Equal("body cannot be empty"))
+		var err error
+		Eventually(served).Should(Receive(&err))
+		Ω(err).Should(Equal(&goa.WebSocketCloseError{Code: goa.WSCloseInvalidPayload, Reason: "body cannot be empty"}))
+	})
+
+	It("closes the connection with the invalid payload code when a message cannot be decoded", func() {
+		Ω(websocket.Message.Send(ws, `not json`)).ShouldNot(HaveOccurred())
+		payloadType, data := readFrame(ws)
+		Ω(payloadType).Should(BeEquivalentTo(websocket.CloseFrame))
+		Ω(binary.BigEndian.Uint16(data)).Should(BeEquivalentTo(goa.WSCloseInvalidPayload))
+	})
+
+	Context("with an Accept header listing several media types", func() {
+		BeforeEach(func() {
+			service.Encoder(goa.NewGobEncoder, "application/gob")
+			header = http.Header{"Accept": {"application/unknown, application/gob, application/json"}}
+		})
+
+		It("encodes the messages with the first media type that has an encoder", func() {
+			Ω(websocket.Message.Send(ws, `{"body":"hello"}`)).ShouldNot(HaveOccurred())
+			payloadType, data := readFrame(ws)
+			Ω(payloadType).Should(BeEquivalentTo(websocket.BinaryFrame))
+			var msg chatMessage
+			Ω(gob.NewDecoder(bytes.NewReader(data)).Decode(&msg)).ShouldNot(HaveOccurred())
+			Ω(*msg.Body).Should(Equal("hello"))
+		})
+	})
+
+	Context("with a handler that fails", func() {
+		BeforeEach(func() {
+			handler = func(conn *goa.WebSocketConn) error {
+				return errors.New("boom")
+			}
+		})
+
+		It("closes the connection with the internal error code", func() {
+			payloadType, data := readFrame(ws)
+			Ω(payloadType).Should(BeEquivalentTo(websocket.CloseFrame))
+			Ω(binary.BigEndian.Uint16(data)).Should(BeEquivalentTo(goa.WSCloseInternalError))
+			var err error
+			Eventually(served).Should(Receive(&err))
+			Ω(err).Should(MatchError("boom"))
+		})
+	})
+
+	Context("with a handler that returns a close error", func() {
+		BeforeEach(func() {
+			handler = func(conn *goa.WebSocketConn) error {
+				return &goa.WebSocketCloseError{Code: goa.WSClosePolicyViolation, Reason: "go away"}
+			}
+		})
+
+		It("closes the connection with the error code", func() {
+			payloadType, data := readFrame(ws)
+			Ω(payloadType).Should(BeEquivalentTo(websocket.CloseFrame))
+			Ω(binary.BigEndian.Uint16(data)).Should(BeEquivalentTo(goa.WSClosePolicyViolation))
+			Ω(string(data[2:])).Should(Equal("go away"))
+		})
+	})
+
+	Context("with a keep-alive period", func() {
+		BeforeEach(func() {
+			keepAlive = 10 * time.Millisecond
+			handler = func(conn *goa.WebSocketConn) error {
+				<-conn.Context().Done()
+				return nil
+			}
+		})
+
+		It("sends ping frames", func() {
+			payloadType, _ := readFrame(ws)
+			Ω(payloadType).Should(BeEquivalentTo(websocket.PingFrame))
+			payloadType, _ = readFrame(ws)
+			Ω(payloadType).Should(BeEquivalentTo(websocket.PingFrame))
+		})
+	})
+})
+
+var _ = Describe("TextContentType", func() {
+	It("considers the default and textual media types as text", func() {
+		Ω(goa.TextContentType("")).Should(BeTrue())
+		Ω(goa.TextContentType("*/*")).Should(BeTrue())
+		Ω(goa.TextContentType("application/vnd.goa.error+json; charset=utf-8")).Should(BeTrue())
+		Ω(goa.TextContentType("application/gob")).Should(BeFalse())
+	})
+
+	It("uses the first media type of an Accept list", func() {
+		Ω(goa.TextContentType("application/gob, application/json")).Should(BeFalse())
+		Ω(goa.TextContentType("text/plain;q=0.9, application/gob")).Should(BeTrue())
+	})
+})